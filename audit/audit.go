@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/utils"
+)
+
+const (
+	// EventCertificateRetrieved records a certificate handed out to a client
+	EventCertificateRetrieved = "certificate_retrieved"
+	// EventAuthorizationFailed records a certificate request that was refused
+	EventAuthorizationFailed = "authorization_failed"
+	// EventLoginFailed records a login attempt with an invalid API key
+	EventLoginFailed = "login_failed"
+	// EventSessionRevoked records a session deleted by an operator
+	EventSessionRevoked = "session_revoked"
+)
+
+// Event is a single audit log entry, written as one JSON line
+type Event struct {
+	Time      time.Time `json:"time"`
+	Event     string    `json:"event"`
+	UserID    string    `json:"user_id,omitempty"`
+	Domain    string    `json:"domain,omitempty"`
+	ClientIP  string    `json:"client_ip,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+}
+
+// Logger writes audit events as JSON lines.
+// Writes are serialized so concurrent requests never interleave lines.
+type Logger struct {
+	writer io.Writer
+	closer io.Closer
+	mutex  sync.Mutex
+}
+
+// NewLogger creates an audit logger writing to w
+func NewLogger(w io.Writer) *Logger {
+	return &Logger{writer: w}
+}
+
+// OpenFile creates an audit logger appending to the file at path.
+// The file is created with 0600 permissions if it does not exist.
+func OpenFile(path string) (*Logger, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log file: %w", err)
+	}
+
+	return &Logger{writer: file, closer: file}, nil
+}
+
+// Log writes an event, stamping it with the current time if it has none.
+// The time is written in the display timezone (see utils.SetUTC).
+func (l *Logger) Log(event Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	event.Time = event.Time.In(utils.DisplayLocation())
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, err := l.writer.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the underlying file, if the logger owns one
+func (l *Logger) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closer == nil {
+		return nil
+	}
+
+	return l.closer.Close()
+}
@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestLoggerWritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf)
+
+	if err := logger.Log(Event{Event: EventCertificateRetrieved, UserID: "user", Domain: "example.com", ClientIP: "10.0.0.1"}); err != nil {
+		t.Fatalf("Failed to log event: %v", err)
+	}
+
+	var event Event
+	if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+		t.Fatalf("Audit line is not valid JSON: %v", err)
+	}
+
+	if event.Event != EventCertificateRetrieved || event.UserID != "user" || event.Domain != "example.com" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+
+	if event.Time.IsZero() {
+		t.Error("Event should be stamped with the current time")
+	}
+}
+
+func TestLoggerConcurrentWritesDoNotInterleave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	logger, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("Failed to open audit log: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := logger.Log(Event{Event: EventAuthorizationFailed, UserID: fmt.Sprintf("user-%d", i), Reason: "access denied"}); err != nil {
+				t.Errorf("Failed to log event: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Failed to close audit log: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open audit log for reading: %v", err)
+	}
+	defer file.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Line %d is not valid JSON: %v", lines+1, err)
+		}
+		lines++
+	}
+
+	if lines != 50 {
+		t.Errorf("Expected 50 lines, got %d", lines)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat audit log: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected 0600 permissions, got %v", info.Mode().Perm())
+	}
+}
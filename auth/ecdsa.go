@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AlgorithmES256 is the name of ECDSA P-256 signing, used with a key pair instead of a secret
+const AlgorithmES256 = "ES256"
+
+// CreateJWTWithECDSA creates a new JWT token with the specified claims, signed with ES256
+func CreateJWTWithECDSA(userID, description string, expiresAt time.Time, allowedDomains []string, key *ecdsa.PrivateKey) (string, error) {
+	return SignJWTWithECDSA(NewAccessClaims(userID, description, expiresAt, allowedDomains), key)
+}
+
+// SignJWTWithECDSA signs the claims with a P-256 private key using ES256
+func SignJWTWithECDSA(claims *JWTClaims, key *ecdsa.PrivateKey) (string, error) {
+	if err := checkP256(&key.PublicKey); err != nil {
+		return "", err
+	}
+
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+	return tokenString, nil
+}
+
+// ParseECPrivateKey parses a PEM P-256 private key in SEC 1 ("EC PRIVATE KEY") or
+// PKCS#8 ("PRIVATE KEY") form. Other key types, such as RSA, are rejected.
+func ParseECPrivateKey(pemData []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM private key found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported private key type %q: use an EC P-256 key for %s", block.Type, AlgorithmES256)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T: use an EC P-256 key for %s", key, AlgorithmES256)
+	}
+	if err := checkP256(&ecKey.PublicKey); err != nil {
+		return nil, err
+	}
+	return ecKey, nil
+}
+
+// ParseECPublicKey parses a PEM P-256 public key ("PUBLIC KEY"), or takes the public key
+// of a certificate or EC private key, so the signing key file can be reused for testing
+func ParseECPublicKey(pemData []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM public key found")
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "PUBLIC KEY":
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		key = parsed
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		key = cert.PublicKey
+	default:
+		privateKey, err := ParseECPrivateKey(pemData)
+		if err != nil {
+			return nil, err
+		}
+		key = &privateKey.PublicKey
+	}
+
+	ecKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T: use an EC P-256 key for %s", key, AlgorithmES256)
+	}
+	if err := checkP256(ecKey); err != nil {
+		return nil, err
+	}
+	return ecKey, nil
+}
+
+// checkP256 rejects keys on curves other than P-256, which ES256 requires
+func checkP256(key *ecdsa.PublicKey) error {
+	if key.Curve != elliptic.P256() {
+		return fmt.Errorf("%s requires a P-256 key, got %s", AlgorithmES256, key.Curve.Params().Name)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func generateP256Key(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate P-256 key: %v", err)
+	}
+	return key
+}
+
+func TestCreateJWTWithECDSA_RoundTrip(t *testing.T) {
+	key := generateP256Key(t)
+
+	token, err := CreateJWTWithECDSA("user", "desc", time.Now().Add(time.Hour), []string{"example.com"}, key)
+	if err != nil {
+		t.Fatalf("Failed to generate ES256 JWT: %v", err)
+	}
+
+	decoded, err := DecodeJWT(token)
+	if err != nil {
+		t.Fatalf("Failed to decode JWT: %v", err)
+	}
+	if decoded.Header["alg"] != AlgorithmES256 {
+		t.Errorf("Expected alg %s, got %v", AlgorithmES256, decoded.Header["alg"])
+	}
+
+	claims, err := ParseJWTWithOptions(token, "", ValidationOptions{PublicKey: &key.PublicKey})
+	if err != nil {
+		t.Fatalf("Expected ES256 token to verify, got: %v", err)
+	}
+	if claims.UserID != "user" || len(claims.AllowedDomains) != 1 || claims.AllowedDomains[0] != "example.com" {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+
+	if _, err := ParseJWTWithOptions(token, "", ValidationOptions{PublicKey: &generateP256Key(t).PublicKey}); err == nil {
+		t.Error("Expected error for a token verified with another public key, got nil")
+	}
+	if _, err := ParseJWT(token, "test-secret-key-32-bytes-long!!"); err == nil {
+		t.Error("Expected error for an ES256 token without a public key, got nil")
+	}
+}
+
+func TestParseJWTWithOptions_MixedKeys(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+	key := generateP256Key(t)
+	opts := ValidationOptions{PublicKey: &key.PublicKey}
+
+	hmacToken, err := CreateJWT("user", "desc", time.Now().Add(time.Hour), []string{"example.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+	if _, err := ParseJWTWithOptions(hmacToken, secretKey, opts); err != nil {
+		t.Errorf("Expected HMAC token to verify alongside a public key, got: %v", err)
+	}
+	if _, err := ParseJWTWithOptions(hmacToken, "", opts); err == nil {
+		t.Error("Expected error for an HMAC token without a secret, got nil")
+	}
+}
+
+func TestParseJWTWithOptions_RejectsAlgorithmConfusion(t *testing.T) {
+	key := generateP256Key(t)
+	opts := ValidationOptions{PublicKey: &key.PublicKey}
+	claims := NewAccessClaims("user", "desc", time.Now().Add(time.Hour), []string{"*"})
+
+	// An HMAC token keyed with the public key must not pass as signed by the key pair
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal public key: %v", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(publicPEM)
+	if err != nil {
+		t.Fatalf("Failed to sign forged token: %v", err)
+	}
+	if _, err := ParseJWTWithOptions(forged, "", opts); err == nil {
+		t.Error("Expected error for an HS256 token signed with the public key, got nil")
+	}
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("Failed to generate unsigned JWT: %v", err)
+	}
+	if _, err := ParseJWTWithOptions(unsigned, "", opts); err == nil {
+		t.Error("Expected error for an unsigned token, got nil")
+	}
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate P-384 key: %v", err)
+	}
+	es384, err := jwt.NewWithClaims(jwt.SigningMethodES384, claims).SignedString(p384Key)
+	if err != nil {
+		t.Fatalf("Failed to sign ES384 token: %v", err)
+	}
+	if _, err := ParseJWTWithOptions(es384, "", opts); err == nil {
+		t.Error("Expected error for an ES384 token, got nil")
+	}
+}
+
+func TestParseECKeys(t *testing.T) {
+	key := generateP256Key(t)
+
+	sec1, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal EC key: %v", err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal PKCS#8 key: %v", err)
+	}
+	for _, block := range []*pem.Block{{Type: "EC PRIVATE KEY", Bytes: sec1}, {Type: "PRIVATE KEY", Bytes: pkcs8}} {
+		parsed, err := ParseECPrivateKey(pem.EncodeToMemory(block))
+		if err != nil {
+			t.Fatalf("ParseECPrivateKey(%s) failed: %v", block.Type, err)
+		}
+		if !parsed.Equal(key) {
+			t.Errorf("ParseECPrivateKey(%s) returned a different key", block.Type)
+		}
+
+		// The private key file also yields its public key
+		public, err := ParseECPublicKey(pem.EncodeToMemory(block))
+		if err != nil {
+			t.Fatalf("ParseECPublicKey(%s) failed: %v", block.Type, err)
+		}
+		if !public.Equal(&key.PublicKey) {
+			t.Errorf("ParseECPublicKey(%s) returned a different key", block.Type)
+		}
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	rsaPKCS8, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal RSA key: %v", err)
+	}
+	if _, err := ParseECPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: rsaPKCS8})); err == nil {
+		t.Error("Expected error for an RSA private key, got nil")
+	}
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate P-384 key: %v", err)
+	}
+	p384DER, err := x509.MarshalPKIXPublicKey(&p384Key.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal P-384 public key: %v", err)
+	}
+	if _, err := ParseECPublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: p384DER})); err == nil {
+		t.Error("Expected error for a P-384 public key, got nil")
+	}
+}
@@ -1,34 +1,124 @@
 package auth
 
 import (
+	"crypto/ecdsa"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	// DefaultIssuer is the iss claim set on tokens issued by this service
+	DefaultIssuer = "go-cert-provider"
+	// DefaultClockSkew is the tolerance applied to time-based claims to absorb clock drift
+	DefaultClockSkew = 30 * time.Second
+
+	// TokenTypeAccess marks tokens that grant access to certificates
+	TokenTypeAccess = "access"
+	// TokenTypeRefresh marks tokens that can only be exchanged for new access tokens
+	TokenTypeRefresh = "refresh"
+)
+
 // JWTClaims represents the claims in the JWT token
 type JWTClaims struct {
 	UserID         string   `json:"user_id"`
 	Description    string   `json:"description"`
 	AllowedDomains []string `json:"allowed_domains"`
+	Scopes         []string `json:"scopes,omitempty"` // e.g. "list:example.com"; see ScopesAllow
+	TokenType      string   `json:"token_type,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// IsAccessToken reports whether the claims belong to an access token.
+// Tokens issued before token_type was introduced carry no type and are treated as access tokens.
+func (c *JWTClaims) IsAccessToken() bool {
+	return c.TokenType == "" || c.TokenType == TokenTypeAccess
+}
+
+// ValidationOptions holds optional claim checks applied on top of signature verification
+type ValidationOptions struct {
+	// ExpectedAudience, when set, requires the token's aud claim to contain this value.
+	// Tokens without an audience are rejected.
+	ExpectedAudience string
+	// ExpectedIssuer, when set, requires the token's iss claim to equal this value
+	ExpectedIssuer string
+	// ClockSkew is the leeway applied to exp, nbf and iat checks.
+	// Zero uses DefaultClockSkew; a negative value disables the leeway.
+	ClockSkew time.Duration
+	// PublicKey, when set, additionally accepts access tokens signed with ES256 by the
+	// matching private key. HMAC tokens are still verified with the secret, if one is given.
+	PublicKey *ecdsa.PublicKey
+}
+
+// parserOptions converts the validation options to jwt parser options
+func (o ValidationOptions) parserOptions() []jwt.ParserOption {
+	clockSkew := o.ClockSkew
+	switch {
+	case clockSkew == 0:
+		clockSkew = DefaultClockSkew
+	case clockSkew < 0:
+		clockSkew = 0
+	}
+
+	parserOptions := []jwt.ParserOption{jwt.WithLeeway(clockSkew)}
+	if o.ExpectedAudience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(o.ExpectedAudience))
+	}
+	if o.ExpectedIssuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(o.ExpectedIssuer))
+	}
+	return parserOptions
+}
+
 // ParseJWT parses and validates a JWT token with secret verification
 func ParseJWT(tokenString, secret string) (*JWTClaims, error) {
-	if secret == "" {
+	return ParseJWTWithOptions(tokenString, secret, ValidationOptions{})
+}
+
+// ParseJWTWithOptions parses and validates an access token with secret verification
+// and the additional claim checks in opts
+func ParseJWTWithOptions(tokenString, secret string, opts ValidationOptions) (*JWTClaims, error) {
+	if secret == "" && opts.PublicKey == nil {
 		return nil, fmt.Errorf("jwt secret key is required")
 	}
 
-	return ValidateJWTWithSecret(tokenString, secret)
+	claims, err := validateJWT(tokenString, secret, opts.PublicKey, opts.parserOptions()...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateAccessClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
 }
 
-// ParseJWTUnverified parses JWT without signature verification.
-// This must only be used in tests or debugging flows.
-func ParseJWTUnverified(tokenString string) (*JWTClaims, error) {
-	// Parse the token without verification
-	// In production, you should always verify the signature
+// validateAccessClaims checks the service-specific claims required on access tokens.
+// The description claim is informational and may be empty.
+func validateAccessClaims(claims *JWTClaims) error {
+	if claims.UserID == "" {
+		return fmt.Errorf("user_id is required in JWT")
+	}
+
+	if !claims.IsAccessToken() {
+		return fmt.Errorf("token type %q cannot be used for access", claims.TokenType)
+	}
+
+	return nil
+}
+
+// DecodedJWT holds the header and claims of a token decoded without verification
+type DecodedJWT struct {
+	Header map[string]interface{}
+	Claims *JWTClaims
+}
+
+// DecodeJWT decodes a JWT without verifying its signature or validating its claims.
+// The result must never be treated as trusted.
+func DecodeJWT(tokenString string) (*DecodedJWT, error) {
 	token, _, err := new(jwt.Parser).ParseUnverified(tokenString, &JWTClaims{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse JWT: %w", err)
@@ -39,30 +129,72 @@ func ParseJWTUnverified(tokenString string) (*JWTClaims, error) {
 		return nil, fmt.Errorf("invalid JWT claims")
 	}
 
-	if claims.ExpiresAt != nil && time.Now().After(claims.ExpiresAt.Time) {
-		return nil, fmt.Errorf("jwt token is expired")
+	return &DecodedJWT{
+		Header: token.Header,
+		Claims: claims,
+	}, nil
+}
+
+// ParseJWTUnverified parses JWT without signature verification.
+// This must only be used in tests or debugging flows.
+func ParseJWTUnverified(tokenString string) (*JWTClaims, error) {
+	return ParseJWTUnverifiedWithOptions(tokenString, ValidationOptions{})
+}
+
+// ParseJWTUnverifiedWithOptions parses JWT without signature verification, applying the
+// claim checks in opts. This must only be used in tests or debugging flows.
+func ParseJWTUnverifiedWithOptions(tokenString string, opts ValidationOptions) (*JWTClaims, error) {
+	// Parse the token without verification
+	// In production, you should always verify the signature
+	decoded, err := DecodeJWT(tokenString)
+	if err != nil {
+		return nil, err
 	}
 
-	if claims.UserID == "" {
-		return nil, fmt.Errorf("user_id is required in JWT")
+	claims := decoded.Claims
+
+	// Apply the same exp/nbf/iat checks as the verified path
+	if err := jwt.NewValidator(opts.parserOptions()...).Validate(claims); err != nil {
+		return nil, fmt.Errorf("invalid JWT claims: %w", err)
 	}
 
-	if claims.Description == "" {
-		return nil, fmt.Errorf("description is required in JWT")
+	if err := validateAccessClaims(claims); err != nil {
+		return nil, err
 	}
 
 	return claims, nil
 }
 
-// ValidateJWTWithSecret validates JWT with a secret key (for production use)
-func ValidateJWTWithSecret(tokenString, secret string) (*JWTClaims, error) {
+// ValidateJWTWithSecret validates JWT with a secret key (for production use).
+// Additional parser options (e.g. jwt.WithAudience) tighten claim validation.
+func ValidateJWTWithSecret(tokenString, secret string, parserOptions ...jwt.ParserOption) (*JWTClaims, error) {
+	return validateJWT(tokenString, secret, nil, parserOptions...)
+}
+
+// validateJWT verifies an HMAC token with the secret or, when publicKey is set, an ES256
+// token with the public key. Only those algorithms are allowed, so a token cannot switch
+// to "none" or have its HMAC signature checked against the public key.
+func validateJWT(tokenString, secret string, publicKey *ecdsa.PublicKey, parserOptions ...jwt.ParserOption) (*JWTClaims, error) {
+	var validMethods []string
+	if secret != "" {
+		validMethods = append(validMethods, "HS256", "HS384", "HS512")
+	}
+	if publicKey != nil {
+		validMethods = append(validMethods, AlgorithmES256)
+	}
+	parserOptions = append(parserOptions, jwt.WithValidMethods(validMethods))
+
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		// Verify the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			return []byte(secret), nil
+		case *jwt.SigningMethodECDSA:
+			return publicKey, nil
+		default:
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(secret), nil
-	})
+	}, parserOptions...)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to validate JWT: %w", err)
@@ -76,24 +208,69 @@ func ValidateJWTWithSecret(tokenString, secret string) (*JWTClaims, error) {
 	return claims, nil
 }
 
-// CreateJWT creates a new JWT token with the specified claims
+// CreateJWT creates a new JWT token with the specified claims, signed with HS256
 func CreateJWT(userID, description string, expiresAt time.Time, allowedDomains []string, secret string) (string, error) {
+	return CreateJWTWithMethod(userID, description, expiresAt, allowedDomains, secret, jwt.SigningMethodHS256)
+}
+
+// CreateJWTWithNotBefore creates a new HS256 JWT token that becomes valid at notBefore,
+// so tokens can be issued ahead of time. A zero notBefore means now.
+func CreateJWTWithNotBefore(userID, description string, notBefore, expiresAt time.Time, allowedDomains []string, secret string) (string, error) {
+	claims := NewAccessClaims(userID, description, expiresAt, allowedDomains)
+	if err := claims.SetNotBefore(notBefore); err != nil {
+		return "", err
+	}
+
+	return SignJWT(claims, secret)
+}
+
+// CreateJWTWithMethod creates a new JWT token with the specified claims and HMAC signing method
+func CreateJWTWithMethod(userID, description string, expiresAt time.Time, allowedDomains []string, secret string, method *jwt.SigningMethodHMAC) (string, error) {
+	return SignJWTWithMethod(NewAccessClaims(userID, description, expiresAt, allowedDomains), secret, method)
+}
+
+// NewAccessClaims builds access token claims issued now and valid until expiresAt
+func NewAccessClaims(userID, description string, expiresAt time.Time, allowedDomains []string) *JWTClaims {
 	issuedAt := time.Now()
 
-	claims := &JWTClaims{
+	return &JWTClaims{
 		UserID:         userID,
 		Description:    description,
 		AllowedDomains: allowedDomains,
+		TokenType:      TokenTypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(issuedAt),
 			NotBefore: jwt.NewNumericDate(issuedAt),
-			Issuer:    "go-cert-provider",
+			Issuer:    DefaultIssuer,
 			Subject:   userID,
 		},
 	}
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+// SetNotBefore makes the claims valid from notBefore instead of their issue time.
+// A zero notBefore leaves them unchanged; notBefore must be earlier than the expiry.
+func (c *JWTClaims) SetNotBefore(notBefore time.Time) error {
+	if notBefore.IsZero() {
+		return nil
+	}
+	if c.ExpiresAt != nil && !notBefore.Before(c.ExpiresAt.Time) {
+		return fmt.Errorf("not-before (%s) must be earlier than expires-at (%s)",
+			notBefore.Format(time.RFC3339), c.ExpiresAt.Time.Format(time.RFC3339))
+	}
+
+	c.NotBefore = jwt.NewNumericDate(notBefore)
+	return nil
+}
+
+// SignJWT signs the claims with the secret using HS256
+func SignJWT(claims *JWTClaims, secret string) (string, error) {
+	return SignJWTWithMethod(claims, secret, jwt.SigningMethodHS256)
+}
+
+// SignJWTWithMethod signs the claims with the secret using the given HMAC method
+func SignJWTWithMethod(claims *JWTClaims, secret string, method *jwt.SigningMethodHMAC) (string, error) {
+	token := jwt.NewWithClaims(method, claims)
 	tokenString, err := token.SignedString([]byte(secret))
 	if err != nil {
 		return "", fmt.Errorf("failed to sign JWT: %w", err)
@@ -101,3 +278,17 @@ func CreateJWT(userID, description string, expiresAt time.Time, allowedDomains [
 
 	return tokenString, nil
 }
+
+// ParseSigningMethod returns the HMAC signing method for an algorithm name (HS256, HS384, HS512)
+func ParseSigningMethod(name string) (*jwt.SigningMethodHMAC, error) {
+	switch strings.ToUpper(name) {
+	case "HS256":
+		return jwt.SigningMethodHS256, nil
+	case "HS384":
+		return jwt.SigningMethodHS384, nil
+	case "HS512":
+		return jwt.SigningMethodHS512, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s (supported: HS256, HS384, HS512)", name)
+	}
+}
@@ -3,6 +3,8 @@ package auth
 import (
 	"testing"
 	"time"
+
+	"github.com/golang-jwt/jwt/v5"
 )
 
 func TestCreateJWT(t *testing.T) {
@@ -141,6 +143,36 @@ func TestParseJWTUnverified(t *testing.T) {
 	}
 }
 
+func TestDecodeJWT(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+	token, err := CreateJWT("user", "desc", time.Now().Add(-time.Hour), []string{"example.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+
+	// Expired tokens must still decode so they can be inspected
+	decoded, err := DecodeJWT(token)
+	if err != nil {
+		t.Fatalf("Failed to decode JWT: %v", err)
+	}
+
+	if decoded.Header["alg"] != "HS256" {
+		t.Errorf("Expected alg HS256, got %v", decoded.Header["alg"])
+	}
+
+	if decoded.Header["typ"] != "JWT" {
+		t.Errorf("Expected typ JWT, got %v", decoded.Header["typ"])
+	}
+
+	if decoded.Claims.UserID != "user" {
+		t.Errorf("Expected user claim to round-trip, got %q", decoded.Claims.UserID)
+	}
+
+	if _, err := DecodeJWT("not-a-jwt"); err == nil {
+		t.Error("Expected error for malformed token, got nil")
+	}
+}
+
 func TestParseJWT_WrongSecretKey(t *testing.T) {
 	correctKey := "correct-secret-key-32-bytes!!"
 	wrongKey := "wrong-secret-key-32-bytes-long"
@@ -268,3 +300,240 @@ func TestJWT_RoundTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestParseJWTWithOptions_Audience(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+	expiresAt := time.Now().Add(time.Hour)
+
+	withAudience := NewAccessClaims("user", "desc", expiresAt, []string{"example.com"})
+	withAudience.Audience = jwt.ClaimStrings{"cert-service"}
+	audienceToken, err := SignJWT(withAudience, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+
+	noAudienceToken, err := CreateJWT("user", "desc", expiresAt, []string{"example.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		token            string
+		expectedAudience string
+		wantError        bool
+	}{
+		{"matching audience", audienceToken, "cert-service", false},
+		{"wrong audience", audienceToken, "other-service", true},
+		{"missing audience when expected", noAudienceToken, "cert-service", true},
+		{"audience not enforced", audienceToken, "", false},
+		{"no audience not enforced", noAudienceToken, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ParseJWTWithOptions(tt.token, secretKey, ValidationOptions{ExpectedAudience: tt.expectedAudience})
+			if tt.wantError {
+				if err == nil {
+					t.Error("Expected audience validation error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if tt.expectedAudience != "" && (len(claims.Audience) != 1 || claims.Audience[0] != tt.expectedAudience) {
+				t.Errorf("Expected audience %q to round-trip, got %v", tt.expectedAudience, claims.Audience)
+			}
+		})
+	}
+}
+
+func TestParseJWT_FutureNotBefore(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+
+	claims := NewAccessClaims("user", "desc", time.Now().Add(2*time.Hour), []string{"example.com"})
+	claims.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Hour))
+	token, err := SignJWT(claims, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+
+	if _, err := ParseJWT(token, secretKey); err == nil {
+		t.Error("Expected error for token with future nbf, got nil")
+	}
+
+	if _, err := ParseJWTUnverified(token); err == nil {
+		t.Error("Expected error for token with future nbf without verification, got nil")
+	}
+}
+
+func TestCreateJWTWithNotBefore(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+	notBefore := time.Now().Add(time.Hour).Truncate(time.Second)
+	expiresAt := notBefore.Add(24 * time.Hour)
+
+	token, err := CreateJWTWithNotBefore("user", "desc", notBefore, expiresAt, []string{"example.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+
+	// The token is minted now but only accepted from notBefore on
+	if _, err := ParseJWT(token, secretKey); err == nil {
+		t.Error("Expected error for token used before its nbf, got nil")
+	}
+	decoded, err := DecodeJWT(token)
+	if err != nil {
+		t.Fatalf("Failed to decode JWT: %v", err)
+	}
+	if decoded.Claims.NotBefore == nil || !decoded.Claims.NotBefore.Time.Equal(notBefore) {
+		t.Errorf("Expected nbf %v, got %v", notBefore, decoded.Claims.NotBefore)
+	}
+	if !decoded.Claims.ExpiresAt.Time.Equal(expiresAt) {
+		t.Errorf("Expected exp %v, got %v", expiresAt, decoded.Claims.ExpiresAt)
+	}
+
+	// A zero notBefore makes the token valid immediately
+	token, err = CreateJWTWithNotBefore("user", "desc", time.Time{}, expiresAt, []string{"example.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+	if _, err := ParseJWT(token, secretKey); err != nil {
+		t.Errorf("Expected token without future nbf to be valid, got: %v", err)
+	}
+
+	if _, err := CreateJWTWithNotBefore("user", "desc", expiresAt, expiresAt, []string{"example.com"}, secretKey); err == nil {
+		t.Error("Expected error for nbf not before exp, got nil")
+	}
+}
+
+func TestParseJWT_NotBeforeWithinClockSkew(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+
+	claims := NewAccessClaims("user", "desc", time.Now().Add(2*time.Hour), []string{"example.com"})
+	claims.NotBefore = jwt.NewNumericDate(time.Now().Add(10 * time.Second))
+	token, err := SignJWT(claims, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+
+	if _, err := ParseJWT(token, secretKey); err != nil {
+		t.Errorf("Expected nbf within default clock skew to be accepted, got: %v", err)
+	}
+
+	if _, err := ParseJWTUnverified(token); err != nil {
+		t.Errorf("Expected nbf within default clock skew to be accepted without verification, got: %v", err)
+	}
+
+	if _, err := ParseJWTWithOptions(token, secretKey, ValidationOptions{ClockSkew: time.Second}); err == nil {
+		t.Error("Expected error for nbf beyond configured clock skew, got nil")
+	}
+
+	if _, err := ParseJWTWithOptions(token, secretKey, ValidationOptions{ClockSkew: -1}); err == nil {
+		t.Error("Expected error for nbf in the future with the clock skew disabled, got nil")
+	}
+}
+
+func TestParseJWTWithOptions_Issuer(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+
+	token, err := CreateJWT("user", "desc", time.Now().Add(time.Hour), []string{"example.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+
+	if _, err := ParseJWTWithOptions(token, secretKey, ValidationOptions{ExpectedIssuer: DefaultIssuer}); err != nil {
+		t.Errorf("Expected matching issuer to be accepted, got: %v", err)
+	}
+
+	if _, err := ParseJWTWithOptions(token, secretKey, ValidationOptions{ExpectedIssuer: "someone-else"}); err == nil {
+		t.Error("Expected error for wrong issuer, got nil")
+	}
+}
+
+func TestParseJWT_EmptyDescription(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+
+	token, err := CreateJWT("user", "", time.Now().Add(time.Hour), []string{"example.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+
+	if _, err := ParseJWT(token, secretKey); err != nil {
+		t.Errorf("Expected token without description to verify, got: %v", err)
+	}
+
+	if _, err := ParseJWTUnverified(token); err != nil {
+		t.Errorf("Expected token without description to parse without verification, got: %v", err)
+	}
+}
+
+func TestParseJWT_RequiresUserID(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+
+	token, err := CreateJWT("", "desc", time.Now().Add(time.Hour), []string{"example.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+
+	if _, err := ParseJWT(token, secretKey); err == nil {
+		t.Error("Expected error for token without user_id, got nil")
+	}
+
+	if _, err := ParseJWTUnverified(token); err == nil {
+		t.Error("Expected error for token without user_id without verification, got nil")
+	}
+}
+
+func TestCreateJWTWithMethod(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+
+	for _, alg := range []string{"HS256", "HS384", "HS512", "hs512"} {
+		t.Run(alg, func(t *testing.T) {
+			method, err := ParseSigningMethod(alg)
+			if err != nil {
+				t.Fatalf("Failed to parse signing method: %v", err)
+			}
+
+			token, err := CreateJWTWithMethod("user", "desc", time.Now().Add(time.Hour), []string{"example.com"}, secretKey, method)
+			if err != nil {
+				t.Fatalf("Failed to generate JWT: %v", err)
+			}
+
+			decoded, err := DecodeJWT(token)
+			if err != nil {
+				t.Fatalf("Failed to decode JWT: %v", err)
+			}
+
+			if decoded.Header["alg"] != method.Alg() {
+				t.Errorf("Expected alg %s, got %v", method.Alg(), decoded.Header["alg"])
+			}
+
+			if _, err := ParseJWT(token, secretKey); err != nil {
+				t.Errorf("Expected %s token to verify, got: %v", alg, err)
+			}
+		})
+	}
+}
+
+func TestParseSigningMethod_Unsupported(t *testing.T) {
+	for _, alg := range []string{"", "none", "RS256", "ES256"} {
+		if _, err := ParseSigningMethod(alg); err == nil {
+			t.Errorf("Expected error for unsupported algorithm %q, got nil", alg)
+		}
+	}
+}
+
+func TestParseJWT_RejectsNonHMAC(t *testing.T) {
+	claims := NewAccessClaims("user", "desc", time.Now().Add(time.Hour), []string{"example.com"})
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("Failed to generate unsigned JWT: %v", err)
+	}
+
+	if _, err := ParseJWT(token, "test-secret-key-32-bytes-long!!"); err == nil {
+		t.Error("Expected error for non-HMAC token, got nil")
+	}
+}
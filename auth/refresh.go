@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultAccessTokenTTL is the lifetime of access tokens issued alongside or from a refresh token
+	DefaultAccessTokenTTL = time.Hour
+	// DefaultRefreshTokenTTL is the default lifetime of refresh tokens
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// TokenPair holds an access token and the refresh token that can renew it
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// CreateRefreshJWT creates a refresh token carrying the given claims.
+// Each refresh token gets a unique ID so it can be rotated and revoked.
+func CreateRefreshJWT(userID, description string, expiresAt time.Time, allowedDomains []string, secret string) (string, error) {
+	accessClaims := NewAccessClaims(userID, description, expiresAt, allowedDomains)
+	return SignJWT(NewRefreshClaims(accessClaims, expiresAt), secret)
+}
+
+// NewRefreshClaims derives refresh token claims from access token claims.
+// The refresh token keeps the identity, allowed domains and audience, and gets
+// its own ID and expiry. A not-before time in the future is kept too, so the
+// refresh token cannot be used to get access early.
+func NewRefreshClaims(accessClaims *JWTClaims, expiresAt time.Time) *JWTClaims {
+	issuedAt := time.Now()
+
+	refreshClaims := *accessClaims
+	refreshClaims.TokenType = TokenTypeRefresh
+	refreshClaims.ID = uuid.New().String()
+	refreshClaims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+	refreshClaims.IssuedAt = jwt.NewNumericDate(issuedAt)
+	if accessClaims.NotBefore == nil || accessClaims.NotBefore.Before(issuedAt) {
+		refreshClaims.NotBefore = jwt.NewNumericDate(issuedAt)
+	}
+
+	return &refreshClaims
+}
+
+// ParseRefreshJWT parses and validates a refresh token with secret verification
+func ParseRefreshJWT(tokenString, secret string) (*JWTClaims, error) {
+	return ParseRefreshJWTWithOptions(tokenString, secret, ValidationOptions{})
+}
+
+// ParseRefreshJWTWithOptions parses and validates a refresh token with secret verification
+// and the additional claim checks in opts
+func ParseRefreshJWTWithOptions(tokenString, secret string, opts ValidationOptions) (*JWTClaims, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("jwt secret key is required")
+	}
+
+	claims, err := ValidateJWTWithSecret(tokenString, secret, opts.parserOptions()...)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != TokenTypeRefresh {
+		return nil, fmt.Errorf("token is not a refresh token")
+	}
+
+	if claims.UserID == "" {
+		return nil, fmt.Errorf("user_id is required in JWT")
+	}
+
+	if claims.ID == "" {
+		return nil, fmt.Errorf("refresh token has no id")
+	}
+
+	if claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("refresh token has no expiry")
+	}
+
+	return claims, nil
+}
+
+// RefreshTokenStore tracks refresh tokens that have been rotated or revoked
+type RefreshTokenStore struct {
+	revoked map[string]time.Time // key: token ID, value: token expiry
+	mutex   sync.Mutex
+}
+
+// NewRefreshTokenStore creates a new refresh token store
+func NewRefreshTokenStore() *RefreshTokenStore {
+	return &RefreshTokenStore{
+		revoked: make(map[string]time.Time),
+	}
+}
+
+// Revoke marks a refresh token ID as no longer usable
+func (s *RefreshTokenStore) Revoke(tokenID string, expiresAt time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.revoked[tokenID] = expiresAt
+}
+
+// IsRevoked reports whether a refresh token ID has been rotated or revoked
+func (s *RefreshTokenStore) IsRevoked(tokenID string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, revoked := s.revoked[tokenID]
+	return revoked
+}
+
+// Exchange validates a refresh token and issues a new access token with the same
+// allowed domains. The refresh token is rotated: it is revoked and replaced by a new
+// one with the same absolute expiry, so each refresh token can only be used once.
+func (s *RefreshTokenStore) Exchange(refreshToken, secret string, accessTTL time.Duration, opts ValidationOptions) (*TokenPair, error) {
+	claims, err := ParseRefreshJWTWithOptions(refreshToken, secret, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshExpiresAt := claims.ExpiresAt.Time
+
+	// Re-issue tokens with the same algorithm the refresh token was signed with
+	method := jwt.SigningMethodHS256
+	if decoded, err := DecodeJWT(refreshToken); err == nil {
+		if alg, ok := decoded.Header["alg"].(string); ok {
+			if parsed, err := ParseSigningMethod(alg); err == nil {
+				method = parsed
+			}
+		}
+	}
+
+	s.mutex.Lock()
+	now := time.Now()
+	for tokenID, expiresAt := range s.revoked {
+		if now.After(expiresAt) {
+			delete(s.revoked, tokenID)
+		}
+	}
+	if _, revoked := s.revoked[claims.ID]; revoked {
+		s.mutex.Unlock()
+		return nil, fmt.Errorf("refresh token has already been used or revoked")
+	}
+	s.revoked[claims.ID] = refreshExpiresAt
+	s.mutex.Unlock()
+
+	// Access tokens never outlive the refresh token they were issued from
+	accessExpiresAt := now.Add(accessTTL)
+	if refreshExpiresAt.Before(accessExpiresAt) {
+		accessExpiresAt = refreshExpiresAt
+	}
+
+	// The new access token carries everything from the refresh token except its identity
+	accessClaims := *claims
+	accessClaims.TokenType = TokenTypeAccess
+	accessClaims.ID = ""
+	accessClaims.ExpiresAt = jwt.NewNumericDate(accessExpiresAt)
+	accessClaims.IssuedAt = jwt.NewNumericDate(now)
+	accessClaims.NotBefore = jwt.NewNumericDate(now)
+
+	accessToken, err := SignJWTWithMethod(&accessClaims, secret, method)
+	if err != nil {
+		return nil, err
+	}
+
+	newRefreshToken, err := SignJWTWithMethod(NewRefreshClaims(&accessClaims, refreshExpiresAt), secret, method)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     newRefreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// Global refresh token store instance
+var globalRefreshTokenStore *RefreshTokenStore
+var globalRefreshTokenStoreOnce sync.Once
+
+// GetGlobalRefreshTokenStore returns the global refresh token store instance
+func GetGlobalRefreshTokenStore() *RefreshTokenStore {
+	globalRefreshTokenStoreOnce.Do(func() {
+		globalRefreshTokenStore = NewRefreshTokenStore()
+	})
+	return globalRefreshTokenStore
+}
@@ -0,0 +1,226 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestCreateRefreshJWT(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+
+	token, err := CreateRefreshJWT("user", "desc", time.Now().Add(24*time.Hour), []string{"example.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate refresh JWT: %v", err)
+	}
+
+	claims, err := ParseRefreshJWT(token, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to parse refresh JWT: %v", err)
+	}
+
+	if claims.TokenType != TokenTypeRefresh {
+		t.Errorf("Expected token type %s, got %s", TokenTypeRefresh, claims.TokenType)
+	}
+
+	if claims.ID == "" {
+		t.Error("Refresh token should carry a token ID")
+	}
+}
+
+func TestRefreshTokenCannotBeUsedAsAccessToken(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+
+	token, err := CreateRefreshJWT("user", "desc", time.Now().Add(24*time.Hour), []string{"example.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate refresh JWT: %v", err)
+	}
+
+	if _, err := ParseJWT(token, secretKey); err == nil {
+		t.Error("Expected error when using refresh token as access token, got nil")
+	}
+
+	if _, err := ParseJWTUnverified(token); err == nil {
+		t.Error("Expected error when using refresh token as access token without verification, got nil")
+	}
+}
+
+func TestAccessTokenCannotBeUsedAsRefreshToken(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+
+	token, err := CreateJWT("user", "desc", time.Now().Add(time.Hour), []string{"example.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+
+	if _, err := ParseRefreshJWT(token, secretKey); err == nil {
+		t.Error("Expected error when using access token as refresh token, got nil")
+	}
+
+	store := NewRefreshTokenStore()
+	if _, err := store.Exchange(token, secretKey, DefaultAccessTokenTTL, ValidationOptions{}); err == nil {
+		t.Error("Expected error when exchanging an access token, got nil")
+	}
+}
+
+func TestRefreshTokenStore_Exchange(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+	allowedDomains := []string{"example.com", "*.test.com"}
+	refreshExpiresAt := time.Now().Add(24 * time.Hour)
+
+	refreshToken, err := CreateRefreshJWT("user", "desc", refreshExpiresAt, allowedDomains, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate refresh JWT: %v", err)
+	}
+
+	store := NewRefreshTokenStore()
+	pair, err := store.Exchange(refreshToken, secretKey, time.Hour, ValidationOptions{})
+	if err != nil {
+		t.Fatalf("Failed to exchange refresh token: %v", err)
+	}
+
+	claims, err := ParseJWT(pair.AccessToken, secretKey)
+	if err != nil {
+		t.Fatalf("Issued access token should verify: %v", err)
+	}
+
+	if claims.UserID != "user" {
+		t.Errorf("Expected userID user, got %s", claims.UserID)
+	}
+
+	if len(claims.AllowedDomains) != len(allowedDomains) {
+		t.Fatalf("Expected %d domains, got %d", len(allowedDomains), len(claims.AllowedDomains))
+	}
+
+	for i, domain := range allowedDomains {
+		if claims.AllowedDomains[i] != domain {
+			t.Errorf("Expected domain %s at index %d, got %s", domain, i, claims.AllowedDomains[i])
+		}
+	}
+
+	if diff := pair.RefreshExpiresAt.Sub(refreshExpiresAt).Abs(); diff > time.Second {
+		t.Errorf("Rotated refresh token should keep the original expiry, diff %v", diff)
+	}
+
+	// The old refresh token is rotated out and cannot be reused
+	if _, err := store.Exchange(refreshToken, secretKey, time.Hour, ValidationOptions{}); err == nil {
+		t.Error("Expected error when reusing a rotated refresh token, got nil")
+	}
+
+	// The rotated refresh token works exactly once
+	if _, err := store.Exchange(pair.RefreshToken, secretKey, time.Hour, ValidationOptions{}); err != nil {
+		t.Errorf("Expected rotated refresh token to be exchangeable, got: %v", err)
+	}
+}
+
+func TestRefreshTokenStore_AccessExpiryCappedByRefreshExpiry(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+	refreshExpiresAt := time.Now().Add(10 * time.Minute)
+
+	refreshToken, err := CreateRefreshJWT("user", "desc", refreshExpiresAt, []string{"example.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate refresh JWT: %v", err)
+	}
+
+	pair, err := NewRefreshTokenStore().Exchange(refreshToken, secretKey, time.Hour, ValidationOptions{})
+	if err != nil {
+		t.Fatalf("Failed to exchange refresh token: %v", err)
+	}
+
+	if pair.AccessExpiresAt.After(refreshExpiresAt) {
+		t.Errorf("Access token expiry %v should not exceed refresh expiry %v", pair.AccessExpiresAt, refreshExpiresAt)
+	}
+}
+
+func TestRefreshTokenStore_Revoke(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+
+	refreshToken, err := CreateRefreshJWT("user", "desc", time.Now().Add(time.Hour), []string{"example.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate refresh JWT: %v", err)
+	}
+
+	claims, err := ParseRefreshJWT(refreshToken, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to parse refresh JWT: %v", err)
+	}
+
+	store := NewRefreshTokenStore()
+	store.Revoke(claims.ID, claims.ExpiresAt.Time)
+
+	if !store.IsRevoked(claims.ID) {
+		t.Error("Token should be reported as revoked")
+	}
+
+	if _, err := store.Exchange(refreshToken, secretKey, time.Hour, ValidationOptions{}); err == nil {
+		t.Error("Expected error when exchanging a revoked refresh token, got nil")
+	}
+}
+
+func TestRefreshTokenStore_ExchangeKeepsAudience(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+
+	accessClaims := NewAccessClaims("user", "desc", time.Now().Add(time.Hour), []string{"example.com"})
+	accessClaims.Audience = jwt.ClaimStrings{"cert-service"}
+	refreshToken, err := SignJWT(NewRefreshClaims(accessClaims, time.Now().Add(24*time.Hour)), secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate refresh JWT: %v", err)
+	}
+
+	store := NewRefreshTokenStore()
+	if _, err := store.Exchange(refreshToken, secretKey, time.Hour, ValidationOptions{ExpectedAudience: "other-service"}); err == nil {
+		t.Error("Expected error when exchanging a refresh token for another audience, got nil")
+	}
+
+	pair, err := store.Exchange(refreshToken, secretKey, time.Hour, ValidationOptions{ExpectedAudience: "cert-service"})
+	if err != nil {
+		t.Fatalf("Failed to exchange refresh token: %v", err)
+	}
+
+	if _, err := ParseJWTWithOptions(pair.AccessToken, secretKey, ValidationOptions{ExpectedAudience: "cert-service"}); err != nil {
+		t.Errorf("Issued access token should keep the audience: %v", err)
+	}
+}
+
+func TestRefreshTokenStore_ExchangeBeforeNotBefore(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+
+	// A token scheduled to become valid tomorrow must not be refreshable today
+	accessClaims := NewAccessClaims("user", "desc", time.Now().Add(48*time.Hour), []string{"example.com"})
+	accessClaims.NotBefore = jwt.NewNumericDate(time.Now().Add(24 * time.Hour))
+	refreshToken, err := SignJWT(NewRefreshClaims(accessClaims, time.Now().Add(72*time.Hour)), secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate refresh JWT: %v", err)
+	}
+
+	if _, err := NewRefreshTokenStore().Exchange(refreshToken, secretKey, time.Hour, ValidationOptions{}); err == nil {
+		t.Error("Expected error when exchanging a refresh token before its not-before time, got nil")
+	}
+}
+
+func TestRefreshTokenStore_ExchangeKeepsAlgorithm(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+
+	accessClaims := NewAccessClaims("user", "desc", time.Now().Add(time.Hour), []string{"example.com"})
+	refreshToken, err := SignJWTWithMethod(NewRefreshClaims(accessClaims, time.Now().Add(24*time.Hour)), secretKey, jwt.SigningMethodHS512)
+	if err != nil {
+		t.Fatalf("Failed to generate refresh JWT: %v", err)
+	}
+
+	pair, err := NewRefreshTokenStore().Exchange(refreshToken, secretKey, time.Hour, ValidationOptions{})
+	if err != nil {
+		t.Fatalf("Failed to exchange refresh token: %v", err)
+	}
+
+	for _, token := range []string{pair.AccessToken, pair.RefreshToken} {
+		decoded, err := DecodeJWT(token)
+		if err != nil {
+			t.Fatalf("Failed to decode JWT: %v", err)
+		}
+
+		if decoded.Header["alg"] != "HS512" {
+			t.Errorf("Expected alg HS512, got %v", decoded.Header["alg"])
+		}
+	}
+}
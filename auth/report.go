@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"time"
+)
+
+// TokenReport is the machine-readable summary of a token printed by the jwt commands
+type TokenReport struct {
+	Valid            bool       `json:"valid"`
+	Error            string     `json:"error,omitempty"`
+	UserID           string     `json:"userId,omitempty"`
+	Description      string     `json:"description,omitempty"`
+	AllowedDomains   []string   `json:"allowedDomains,omitempty"`
+	Scopes           []string   `json:"scopes,omitempty"`
+	Audience         []string   `json:"audience,omitempty"`
+	Issuer           string     `json:"issuer,omitempty"`
+	Subject          string     `json:"subject,omitempty"`
+	TokenType        string     `json:"tokenType,omitempty"`
+	ExpiresAt        *time.Time `json:"expiresAt,omitempty"`
+	ExpiresInSeconds *int64     `json:"expiresInSeconds,omitempty"`
+	IssuedAt         *time.Time `json:"issuedAt,omitempty"`
+	NotBefore        *time.Time `json:"notBefore,omitempty"`
+}
+
+// NewTokenReport summarizes verified claims, computing the time to expiry relative to now
+func NewTokenReport(claims *JWTClaims, now time.Time) *TokenReport {
+	report := &TokenReport{
+		Valid:          true,
+		UserID:         claims.UserID,
+		Description:    claims.Description,
+		AllowedDomains: claims.AllowedDomains,
+		Scopes:         claims.Scopes,
+		Audience:       claims.Audience,
+		Issuer:         claims.Issuer,
+		Subject:        claims.Subject,
+		TokenType:      claims.TokenType,
+	}
+
+	if claims.ExpiresAt != nil {
+		expiresAt := claims.ExpiresAt.Time.UTC()
+		expiresIn := int64(expiresAt.Sub(now).Seconds())
+		if expiresIn < 0 {
+			expiresIn = 0
+			report.Valid = false
+			report.Error = "token is expired"
+		}
+		report.ExpiresAt = &expiresAt
+		report.ExpiresInSeconds = &expiresIn
+	}
+	if claims.IssuedAt != nil {
+		issuedAt := claims.IssuedAt.Time.UTC()
+		report.IssuedAt = &issuedAt
+	}
+	if claims.NotBefore != nil {
+		notBefore := claims.NotBefore.Time.UTC()
+		report.NotBefore = &notBefore
+	}
+
+	return report
+}
+
+// NewInvalidTokenReport reports a token that failed verification
+func NewInvalidTokenReport(err error) *TokenReport {
+	return &TokenReport{Valid: false, Error: err.Error()}
+}
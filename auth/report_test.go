@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenReportJSON(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+	expiresAt := time.Now().Add(2 * time.Hour)
+
+	token, err := CreateJWT("ci", "CI runner", expiresAt, []string{"example.com", "*.test.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+	claims, err := ParseJWT(token, secretKey)
+	if err != nil {
+		t.Fatalf("Failed to parse JWT: %v", err)
+	}
+
+	data, err := json.Marshal(NewTokenReport(claims, time.Now()))
+	if err != nil {
+		t.Fatalf("Failed to marshal report: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Report is not well-formed JSON: %v\n%s", err, data)
+	}
+
+	if decoded["valid"] != true {
+		t.Errorf("Expected valid=true, got %v", decoded["valid"])
+	}
+	if decoded["userId"] != "ci" {
+		t.Errorf("Expected userId=ci, got %v", decoded["userId"])
+	}
+
+	domains, ok := decoded["allowedDomains"].([]interface{})
+	if !ok || len(domains) != 2 || domains[0] != "example.com" || domains[1] != "*.test.com" {
+		t.Errorf("Unexpected allowedDomains: %v", decoded["allowedDomains"])
+	}
+
+	expiresAtValue, ok := decoded["expiresAt"].(string)
+	if !ok {
+		t.Fatalf("Expected expiresAt string, got %v", decoded["expiresAt"])
+	}
+	parsed, err := time.Parse(time.RFC3339, expiresAtValue)
+	if err != nil {
+		t.Fatalf("expiresAt is not RFC3339: %v", err)
+	}
+	if parsed.Unix() != expiresAt.Unix() {
+		t.Errorf("Expected expiresAt %v, got %v", expiresAt, parsed)
+	}
+
+	expiresIn, ok := decoded["expiresInSeconds"].(float64)
+	if !ok || expiresIn <= 7100 || expiresIn > 7200 {
+		t.Errorf("Expected expiresInSeconds close to 7200, got %v", decoded["expiresInSeconds"])
+	}
+}
+
+func TestTokenReportExpired(t *testing.T) {
+	claims := NewAccessClaims("user", "", time.Now().Add(-time.Minute), []string{"example.com"})
+
+	report := NewTokenReport(claims, time.Now())
+	if report.Valid {
+		t.Error("Expired claims should not be reported as valid")
+	}
+	if report.ExpiresInSeconds == nil || *report.ExpiresInSeconds != 0 {
+		t.Errorf("Expected expiresInSeconds=0, got %v", report.ExpiresInSeconds)
+	}
+}
+
+func TestInvalidTokenReportJSON(t *testing.T) {
+	data, err := json.Marshal(NewInvalidTokenReport(errors.New("signature is invalid")))
+	if err != nil {
+		t.Fatalf("Failed to marshal report: %v", err)
+	}
+
+	if string(data) != `{"valid":false,"error":"signature is invalid"}` {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ScopeList allows listing a domain and reading its metadata
+	ScopeList = "list"
+	// ScopeRetrieve allows retrieving a domain's certificate and private key; it implies ScopeList
+	ScopeRetrieve = "retrieve"
+)
+
+// ParseScope splits a scope such as "retrieve:*.example.com" into its action and
+// domain pattern
+func ParseScope(scope string) (action, pattern string, err error) {
+	action, pattern, found := strings.Cut(scope, ":")
+	if !found || pattern == "" {
+		return "", "", fmt.Errorf("invalid scope %q: expected <action>:<domain>, e.g. %s:example.com", scope, ScopeRetrieve)
+	}
+	if action != ScopeList && action != ScopeRetrieve {
+		return "", "", fmt.Errorf("invalid scope %q: action must be %s or %s", scope, ScopeList, ScopeRetrieve)
+	}
+	return action, pattern, nil
+}
+
+// ValidateScopes checks that every scope parses
+func ValidateScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if _, _, err := ParseScope(scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MatchDomain reports whether a domain pattern of allowed_domains or a scope covers the
+// candidate: "*" matches every domain, and "*.example.com" matches example.com and
+// all of its subdomains
+func MatchDomain(pattern, candidate string) bool {
+	if pattern == "*" || pattern == candidate {
+		return true
+	}
+
+	suffix, found := strings.CutPrefix(pattern, "*.")
+	if !found {
+		return false
+	}
+	return candidate == suffix || strings.HasSuffix(candidate, "."+suffix)
+}
+
+// ScopesAllow reports whether a caller with the given allowed domains and scopes may
+// perform action on domainName. Without scopes, the allowed domains grant every action,
+// as they did before scopes were introduced. With scopes, only the scopes decide.
+func ScopesAllow(allowedDomains, scopes []string, action, domainName string) bool {
+	allowed, _ := ExplainScope(allowedDomains, scopes, action, domainName)
+	return allowed
+}
+
+// ExplainScope decides like ScopesAllow and also returns the reason: the entry that
+// granted access, or why none did
+func ExplainScope(allowedDomains, scopes []string, action, domainName string) (bool, string) {
+	if len(scopes) == 0 {
+		for _, allowed := range allowedDomains {
+			if MatchDomain(allowed, domainName) {
+				return true, fmt.Sprintf("allowed domain %q covers %s", allowed, domainName)
+			}
+		}
+		return false, fmt.Sprintf("%s is not in the allowed domains (%s)", domainName, strings.Join(allowedDomains, ", "))
+	}
+
+	for _, scope := range scopes {
+		scopeAction, pattern, err := ParseScope(scope)
+		if err != nil {
+			continue
+		}
+		if scopeAction != action && !(scopeAction == ScopeRetrieve && action == ScopeList) {
+			continue
+		}
+		if MatchDomain(pattern, domainName) {
+			return true, fmt.Sprintf("scope %q covers %s", scope, domainName)
+		}
+	}
+	return false, fmt.Sprintf("no scope allows %s on %s (scopes: %s)", action, domainName, strings.Join(scopes, ", "))
+}
+
+// HasScope reports whether the token's claims allow action on domainName; see ScopesAllow
+func HasScope(claims *JWTClaims, action, domainName string) bool {
+	return ScopesAllow(claims.AllowedDomains, claims.Scopes, action, domainName)
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScopesAllow(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		allowedDomains []string
+		scopes         []string
+		action         string
+		candidate      string
+		want           bool
+	}{
+		{name: "exact match", allowedDomains: []string{"example.com"}, action: ScopeRetrieve, candidate: "example.com", want: true},
+		{name: "wildcard suffix", allowedDomains: []string{"*.example.com"}, action: ScopeRetrieve, candidate: "api.example.com", want: true},
+		{name: "wildcard apex", allowedDomains: []string{"*.example.com"}, action: ScopeRetrieve, candidate: "example.com", want: true},
+		{name: "global wildcard", allowedDomains: []string{"*"}, action: ScopeRetrieve, candidate: "anything.com", want: true},
+		{name: "not allowed", allowedDomains: []string{"test.com"}, action: ScopeList, candidate: "example.com", want: false},
+		{name: "list scope allows list", scopes: []string{"list:example.com"}, action: ScopeList, candidate: "example.com", want: true},
+		{name: "list scope denies retrieve", scopes: []string{"list:example.com"}, action: ScopeRetrieve, candidate: "example.com", want: false},
+		{name: "retrieve scope implies list", scopes: []string{"retrieve:*.example.com"}, action: ScopeList, candidate: "api.example.com", want: true},
+		{name: "scope pattern mismatch", scopes: []string{"retrieve:*.example.com"}, action: ScopeRetrieve, candidate: "test.com", want: false},
+		{name: "scopes override allowed domains", allowedDomains: []string{"*"}, scopes: []string{"list:example.com"}, action: ScopeRetrieve, candidate: "example.com", want: false},
+		{name: "invalid scope is ignored", scopes: []string{"write:example.com"}, action: ScopeList, candidate: "example.com", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ScopesAllow(tt.allowedDomains, tt.scopes, tt.action, tt.candidate); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	action, pattern, err := ParseScope("retrieve:*.example.com")
+	if err != nil || action != ScopeRetrieve || pattern != "*.example.com" {
+		t.Fatalf("unexpected result: %q %q %v", action, pattern, err)
+	}
+
+	for _, scope := range []string{"example.com", "list:", "delete:example.com"} {
+		if _, _, err := ParseScope(scope); err == nil {
+			t.Errorf("expected %q to be rejected", scope)
+		}
+	}
+}
+
+func TestScopesSurviveSigning(t *testing.T) {
+	secret := "test-secret-key-32-bytes-long!!"
+	claims := NewAccessClaims("user", "", time.Now().Add(time.Hour), []string{"example.com"})
+	claims.Scopes = []string{"list:example.com"}
+
+	token, err := SignJWT(claims, secret)
+	if err != nil {
+		t.Fatalf("Failed to sign JWT: %v", err)
+	}
+	parsed, err := ParseJWT(token, secret)
+	if err != nil {
+		t.Fatalf("Failed to parse JWT: %v", err)
+	}
+
+	if !HasScope(parsed, ScopeList, "example.com") || HasScope(parsed, ScopeRetrieve, "example.com") {
+		t.Errorf("Expected a list-only token, got scopes %v", parsed.Scopes)
+	}
+}
+
+func TestExplainScopeAgreesWithHasScope(t *testing.T) {
+	allowedDomainSets := [][]string{nil, {"example.com"}, {"*.example.com"}, {"*"}}
+	scopeSets := [][]string{nil, {"list:example.com"}, {"retrieve:*.example.com"}, {"write:test.com", "list:*"}}
+	actions := []string{ScopeList, ScopeRetrieve}
+	domains := []string{"example.com", "api.example.com", "test.com"}
+
+	for _, allowedDomains := range allowedDomainSets {
+		for _, scopes := range scopeSets {
+			for _, action := range actions {
+				for _, domainName := range domains {
+					name := fmt.Sprintf("%v/%v/%s/%s", allowedDomains, scopes, action, domainName)
+					claims := NewAccessClaims("user", "", time.Now().Add(time.Hour), allowedDomains)
+					claims.Scopes = scopes
+
+					allowed, reason := ExplainScope(allowedDomains, scopes, action, domainName)
+					if allowed != HasScope(claims, action, domainName) {
+						t.Errorf("%s: ExplainScope says %v (%s), HasScope disagrees", name, allowed, reason)
+						continue
+					}
+
+					// Grants name the entry that covers the domain; refusals say which list was checked
+					switch {
+					case allowed && !strings.HasSuffix(reason, " covers "+domainName):
+						t.Errorf("%s: unexpected reason for a grant: %q", name, reason)
+					case allowed && len(scopes) == 0 && !strings.HasPrefix(reason, "allowed domain "):
+						t.Errorf("%s: expected an allowed domain to grant access, got %q", name, reason)
+					case allowed && len(scopes) > 0 && !strings.HasPrefix(reason, "scope "):
+						t.Errorf("%s: expected a scope to grant access, got %q", name, reason)
+					case !allowed && len(scopes) == 0 && !strings.Contains(reason, domainName+" is not in the allowed domains"):
+						t.Errorf("%s: unexpected reason for a refusal: %q", name, reason)
+					case !allowed && len(scopes) > 0 && !strings.HasPrefix(reason, fmt.Sprintf("no scope allows %s on %s", action, domainName)):
+						t.Errorf("%s: unexpected reason for a refusal: %q", name, reason)
+					}
+				}
+			}
+		}
+	}
+
+	// Spot checks, so the agreement above is not vacuous
+	spotChecks := []struct {
+		allowedDomains, scopes []string
+		action, domainName     string
+		want                   bool
+		wantReason             string
+	}{
+		{[]string{"*.example.com"}, nil, ScopeRetrieve, "api.example.com", true, `allowed domain "*.example.com" covers api.example.com`},
+		{[]string{"*"}, []string{"list:example.com"}, ScopeRetrieve, "example.com", false, "no scope allows retrieve on example.com (scopes: list:example.com)"},
+		{nil, []string{"retrieve:*.example.com"}, ScopeList, "example.com", true, `scope "retrieve:*.example.com" covers example.com`},
+		{[]string{"example.com"}, nil, ScopeList, "test.com", false, "test.com is not in the allowed domains (example.com)"},
+	}
+	for _, check := range spotChecks {
+		allowed, reason := ExplainScope(check.allowedDomains, check.scopes, check.action, check.domainName)
+		if allowed != check.want || reason != check.wantReason {
+			t.Errorf("ExplainScope(%v, %v, %s, %s) = %v, %q; want %v, %q", check.allowedDomains, check.scopes,
+				check.action, check.domainName, allowed, reason, check.want, check.wantReason)
+		}
+	}
+}
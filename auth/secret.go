@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// MinSecretKeyLength is the minimum recommended JWT secret length in bytes
+const MinSecretKeyLength = 32
+
+// SecretKeyLength returns the effective length of a secret in bytes.
+// Secrets that are valid base64 (as produced by jwt create-secret-key) are measured after decoding.
+func SecretKeyLength(secret string) int {
+	if decoded, err := base64.StdEncoding.DecodeString(secret); err == nil && len(decoded) > 0 {
+		return len(decoded)
+	}
+	return len(secret)
+}
+
+// ValidateSecretKeyStrength returns an error when the secret is shorter than MinSecretKeyLength bytes
+func ValidateSecretKeyStrength(secret string) error {
+	if length := SecretKeyLength(secret); length < MinSecretKeyLength {
+		return fmt.Errorf("jwt secret key is too short: %d bytes, at least %d bytes recommended", length, MinSecretKeyLength)
+	}
+	return nil
+}
+
+// ResignJWT verifies a token under the old secret and signs identical claims under the
+// new secret with the same algorithm. Expired tokens are refused.
+func ResignJWT(tokenString, oldSecret, newSecret string) (string, *JWTClaims, error) {
+	if oldSecret == "" || newSecret == "" {
+		return "", nil, fmt.Errorf("both old and new jwt secret keys are required")
+	}
+
+	claims, err := ValidateJWTWithSecret(tokenString, oldSecret)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return "", nil, fmt.Errorf("refusing to re-sign an expired token: %w", err)
+		}
+		return "", nil, err
+	}
+
+	decoded, err := DecodeJWT(tokenString)
+	if err != nil {
+		return "", nil, err
+	}
+
+	alg, _ := decoded.Header["alg"].(string)
+	method, err := ParseSigningMethod(alg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	newToken, err := SignJWTWithMethod(claims, newSecret, method)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return newToken, claims, nil
+}
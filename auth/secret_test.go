@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestValidateSecretKeyStrength(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		wantError bool
+	}{
+		{"short raw secret", "secret", true},
+		{"31 byte raw secret", "test-secret-key-32-bytes-long!!", true},
+		{"32 byte raw secret", strings.Repeat("x!", 16), false},
+		{"32 base64 characters decode to 24 bytes", strings.Repeat("x", 32), true},
+		{"base64 of 32 bytes", base64.StdEncoding.EncodeToString(make([]byte, 32)), false},
+		{"base64 of 16 bytes", base64.StdEncoding.EncodeToString(make([]byte, 16)), true},
+		{"empty secret", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSecretKeyStrength(tt.secret)
+			if tt.wantError && err == nil {
+				t.Error("Expected error for weak secret, got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestResignJWT(t *testing.T) {
+	oldKey := "old-secret-key-32-bytes-long!!!"
+	newKey := "new-secret-key-32-bytes-long!!!"
+	expiresAt := time.Now().Add(time.Hour)
+
+	token, err := CreateJWTWithMethod("user", "desc", expiresAt, []string{"example.com", "*.test.com"}, oldKey, jwt.SigningMethodHS384)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+
+	original, err := ParseJWT(token, oldKey)
+	if err != nil {
+		t.Fatalf("Failed to parse JWT: %v", err)
+	}
+
+	newToken, _, err := ResignJWT(token, oldKey, newKey)
+	if err != nil {
+		t.Fatalf("Failed to re-sign JWT: %v", err)
+	}
+
+	if _, err := ParseJWT(newToken, oldKey); err == nil {
+		t.Error("Re-signed token should not verify under the old key")
+	}
+
+	claims, err := ParseJWT(newToken, newKey)
+	if err != nil {
+		t.Fatalf("Re-signed token should verify under the new key: %v", err)
+	}
+
+	if claims.UserID != original.UserID || claims.Description != original.Description {
+		t.Errorf("Identity claims changed: %+v", claims)
+	}
+
+	if strings.Join(claims.AllowedDomains, ",") != strings.Join(original.AllowedDomains, ",") {
+		t.Errorf("Allowed domains changed: %v", claims.AllowedDomains)
+	}
+
+	if !claims.ExpiresAt.Equal(original.ExpiresAt.Time) || !claims.IssuedAt.Equal(original.IssuedAt.Time) {
+		t.Error("exp and iat should be preserved")
+	}
+
+	decoded, err := DecodeJWT(newToken)
+	if err != nil {
+		t.Fatalf("Failed to decode JWT: %v", err)
+	}
+
+	if decoded.Header["alg"] != "HS384" {
+		t.Errorf("Expected algorithm to be preserved, got %v", decoded.Header["alg"])
+	}
+}
+
+func TestResignJWT_Rejects(t *testing.T) {
+	oldKey := "old-secret-key-32-bytes-long!!!"
+	newKey := "new-secret-key-32-bytes-long!!!"
+
+	expired, err := CreateJWT("user", "desc", time.Now().Add(-time.Hour), []string{"example.com"}, oldKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+
+	if _, _, err := ResignJWT(expired, oldKey, newKey); err == nil {
+		t.Error("Expected error when re-signing an expired token, got nil")
+	}
+
+	valid, err := CreateJWT("user", "desc", time.Now().Add(time.Hour), []string{"example.com"}, oldKey)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+
+	if _, _, err := ResignJWT(valid, "wrong-secret-key-32-bytes-long!", newKey); err == nil {
+		t.Error("Expected error when the old key does not match, got nil")
+	}
+
+	if _, _, err := ResignJWT(valid, oldKey, ""); err == nil {
+		t.Error("Expected error when the new key is empty, got nil")
+	}
+}
@@ -0,0 +1,63 @@
+package domain
+
+// ProvidersConfig is the typed form of a providers config file (providers.yaml)
+type ProvidersConfig struct {
+	Providers []ProviderConfig `yaml:"providers"`
+}
+
+// TagsConfig is the typed form of a domain tags file (tags.yaml)
+type TagsConfig struct {
+	// Tags maps a domain name or glob pattern (e.g., "*.example.com") to its tags.
+	// A domain gets the tags of every entry it matches.
+	Tags map[string][]string `yaml:"tags"`
+}
+
+// ProviderConfig declares one provider instance in a providers config file.
+// Which fields apply depends on the provider type.
+type ProviderConfig struct {
+	// Type selects the provider implementation (e.g., "porkbun", "digitalocean", "namecheap")
+	Type string `yaml:"type"`
+	// Name identifies the instance and defaults to Type. It must be unique, so a second
+	// account of the same type needs its own name (e.g., "porkbun-personal").
+	Name string `yaml:"name"`
+
+	APIKey          string `yaml:"apiKey"`
+	SecretKey       string `yaml:"secretKey"`
+	CredentialsFile string `yaml:"credentialsFile"`
+	// Token is a bearer API token (DigitalOcean)
+	Token string `yaml:"token"`
+	// APIUser and ClientIP identify the caller of APIs that require them (Namecheap)
+	APIUser  string `yaml:"apiUser"`
+	ClientIP string `yaml:"clientIp"`
+
+	// Domains lists the managed domains; when empty, providers that support it auto-discover them
+	Domains []string `yaml:"domains"`
+	// DomainsInclude limits auto-discovery to domains matching these names or glob patterns (Porkbun)
+	DomainsInclude []string `yaml:"domainsInclude"`
+	// DomainsExclude lists domains or glob patterns skipped during auto-discovery
+	DomainsExclude []string `yaml:"domainsExclude"`
+
+	// CertFile and KeyFile hold canned PEM material (mock provider)
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+}
+
+// InstanceName returns the configured name, or the type when no name is set
+func (c ProviderConfig) InstanceName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Type
+}
+
+// ConfigurableBootstrap is implemented by bootstraps that accept settings from a
+// providers config file. Flags and environment variables take precedence over them.
+type ConfigurableBootstrap interface {
+	ProviderBootstrap
+
+	// ProviderType returns the provider type this bootstrap creates (e.g., "porkbun")
+	ProviderType() string
+
+	// ApplyConfig sets the file-based settings of the bootstrap
+	ApplyConfig(cfg ProviderConfig) error
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"errors"
+)
+
+// Provider-neutral errors returned (wrapped) by CertificateProvider implementations,
+// so callers can react with errors.Is regardless of the provider
+var (
+	// ErrCertNotFound means the provider has no certificate for the domain (yet)
+	ErrCertNotFound = errors.New("certificate not found")
+	// ErrAuthFailed means the provider rejected the API credentials or access
+	ErrAuthFailed = errors.New("provider authentication failed")
+	// ErrRateLimited means the provider API is throttling requests
+	ErrRateLimited = errors.New("provider rate limit exceeded")
+	// ErrDomainNotManaged means the domain is not among the domains the provider (or registry) manages
+	ErrDomainNotManaged = errors.New("domain not managed")
+	// ErrRetrievalUnsupported means the provider API does not export certificates with their private keys
+	ErrRetrievalUnsupported = errors.New("provider does not export certificate private keys")
+	// ErrRefreshUnsupported means the provider cannot re-query the info of a single domain
+	ErrRefreshUnsupported = errors.New("provider does not support refreshing domain info")
+	// ErrReadOnly means certificate retrieval is disabled because the service runs read-only
+	ErrReadOnly = errors.New("certificate retrieval is disabled in read-only mode")
+)
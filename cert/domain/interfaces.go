@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -14,6 +15,24 @@ type Info struct {
 	CreateDate time.Time // When the domain was created
 	ExpireDate time.Time // When the domain expires
 	AutoRenew  bool      // Whether auto-renewal is enabled
+	Tags       []string  // Tags from the domain tags file, sorted
+
+	// Certificate details, filled in once a certificate for the domain has been retrieved
+	CertSerial            string    // Serial number of the leaf certificate (hex)
+	CertFingerprintSHA256 string    // SHA-256 fingerprint of the leaf certificate (colon-separated hex)
+	CertNotAfter          time.Time // When the leaf certificate expires
+}
+
+// CertInfo describes the leaf certificate currently served for a domain
+type CertInfo struct {
+	Domain            string
+	Serial            string    // Serial number (hex)
+	FingerprintSHA256 string    // SHA-256 fingerprint of the DER certificate (colon-separated hex)
+	Subject           string    // Subject common name
+	Issuer            string    // Issuer common name
+	NotBefore         time.Time // Start of the validity period
+	NotAfter          time.Time // End of the validity period
+	RetrievedAt       time.Time // When the certificate was retrieved from the provider
 }
 
 // CertificateProvider is the interface that all domain service providers must implement
@@ -33,13 +52,35 @@ type CertificateProvider interface {
 	ListDomainInfo() []Info
 
 	// RetrieveCertificate retrieves the SSL certificate for the specified domain
+	// The context bounds the retrieval; implementations must abort when it is cancelled
 	// Returns certificate chain, private key, and error
-	RetrieveCertificate(domain string) (certChain []byte, privateKey []byte, err error)
+	RetrieveCertificate(ctx context.Context, domain string) (certChain []byte, privateKey []byte, err error)
 
 	// ValidateConfiguration validates the provider's configuration
 	ValidateConfiguration() error
 }
 
+// ConnectivityResult describes a successful connectivity test
+type ConnectivityResult struct {
+	// ClientIP is the egress IP address seen by the provider API, if it reports one
+	ClientIP string
+}
+
+// ConnectivityChecker is implemented by providers and bootstraps that can verify
+// their credentials against the upstream API without retrieving a certificate
+type ConnectivityChecker interface {
+	// CheckConnectivity performs a lightweight authenticated request to the provider API
+	CheckConnectivity(ctx context.Context) (*ConnectivityResult, error)
+}
+
+// DomainInfoRefresher is implemented by providers that can re-query their upstream API
+// for a single domain, replacing the Info captured when the provider was created
+type DomainInfoRefresher interface {
+	// RefreshDomainInfo updates the cached Info of the domain from the provider API.
+	// It fails with an error wrapping ErrDomainNotManaged for domains the provider does not manage.
+	RefreshDomainInfo(ctx context.Context, domain string) error
+}
+
 // ProviderBootstrap is the interface for bootstrapping providers
 // Each provider implementation should have a corresponding bootstrap that knows
 // how to initialize the provider from environment variables and command-line options
@@ -55,6 +96,7 @@ type ProviderBootstrap interface {
 	IsConfigured() bool
 
 	// CreateProvider creates and returns a configured provider instance
+	// The context bounds setup work such as domain discovery; implementations must abort when it is cancelled
 	// Returns error if configuration is invalid
-	CreateProvider() (CertificateProvider, error)
+	CreateProvider(ctx context.Context) (CertificateProvider, error)
 }
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+)
+
+// LegacyCertificateProvider is the provider interface from before RetrieveCertificate
+// accepted a context. Existing implementations can be registered unchanged by wrapping
+// them with AdaptLegacyProvider until they are migrated.
+type LegacyCertificateProvider interface {
+	GetProviderName() string
+	GetDomains() []string
+	GetDomainInfo(domain string) *Info
+	ListDomainInfo() []Info
+	RetrieveCertificate(domain string) (certChain []byte, privateKey []byte, err error)
+	ValidateConfiguration() error
+}
+
+// AdaptLegacyProvider wraps a LegacyCertificateProvider as a CertificateProvider.
+// The context is only checked before the retrieval starts, since the legacy
+// implementation cannot be cancelled once running.
+func AdaptLegacyProvider(provider LegacyCertificateProvider) CertificateProvider {
+	return &legacyProviderAdapter{LegacyCertificateProvider: provider}
+}
+
+// legacyProviderAdapter implements CertificateProvider on top of a LegacyCertificateProvider
+type legacyProviderAdapter struct {
+	LegacyCertificateProvider
+}
+
+// RetrieveCertificate retrieves the certificate unless the context is already done
+func (a *legacyProviderAdapter) RetrieveCertificate(ctx context.Context, domain string) ([]byte, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return a.LegacyCertificateProvider.RetrieveCertificate(domain)
+}
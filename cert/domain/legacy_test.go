@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// legacyProvider implements LegacyCertificateProvider, counting retrievals
+type legacyProvider struct {
+	retrievals int
+}
+
+func (p *legacyProvider) GetProviderName() string { return "legacy" }
+func (p *legacyProvider) GetDomains() []string    { return []string{"example.com"} }
+func (p *legacyProvider) ListDomainInfo() []Info {
+	return []Info{{Name: "example.com", Provider: "legacy"}}
+}
+func (p *legacyProvider) ValidateConfiguration() error { return errors.New("not configured") }
+
+func (p *legacyProvider) GetDomainInfo(domain string) *Info {
+	if domain != "example.com" {
+		return nil
+	}
+	return &Info{Name: domain, Provider: "legacy"}
+}
+
+func (p *legacyProvider) RetrieveCertificate(domain string) ([]byte, []byte, error) {
+	p.retrievals++
+	if domain != "example.com" {
+		return nil, nil, ErrDomainNotManaged
+	}
+	return []byte("cert"), []byte("key"), nil
+}
+
+func TestAdaptLegacyProvider(t *testing.T) {
+	legacy := &legacyProvider{}
+	provider := AdaptLegacyProvider(legacy)
+
+	if provider.GetProviderName() != "legacy" || len(provider.GetDomains()) != 1 || len(provider.ListDomainInfo()) != 1 {
+		t.Errorf("Expected the provider methods to be delegated")
+	}
+	if info := provider.GetDomainInfo("example.com"); info == nil || info.Provider != "legacy" {
+		t.Errorf("GetDomainInfo = %+v, want the legacy info", info)
+	}
+	if err := provider.ValidateConfiguration(); err == nil || err.Error() != "not configured" {
+		t.Errorf("ValidateConfiguration = %v, want the legacy error", err)
+	}
+
+	certChain, privateKey, err := provider.RetrieveCertificate(context.Background(), "example.com")
+	if err != nil || string(certChain) != "cert" || string(privateKey) != "key" {
+		t.Fatalf("RetrieveCertificate = %q, %q, %v", certChain, privateKey, err)
+	}
+	if _, _, err := provider.RetrieveCertificate(context.Background(), "other.com"); !errors.Is(err, ErrDomainNotManaged) {
+		t.Errorf("Expected the legacy error to be returned, got %v", err)
+	}
+	if legacy.retrievals != 2 {
+		t.Errorf("Expected 2 retrievals, got %d", legacy.retrievals)
+	}
+}
+
+func TestAdaptLegacyProviderHonoursCancellation(t *testing.T) {
+	legacy := &legacyProvider{}
+	provider := AdaptLegacyProvider(legacy)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := provider.RetrieveCertificate(ctx, "example.com"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	if _, _, err := provider.RetrieveCertificate(ctx, "example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	// A done context never reaches the legacy implementation
+	if legacy.retrievals != 0 {
+		t.Errorf("Expected no retrieval, got %d", legacy.retrievals)
+	}
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"os"
+	"strings"
+
+	"github.com/dh-kam/go-cert-provider/utils"
+)
+
+// Getenv reads an environment variable for a provider bootstrap. It returns "" when
+// ignoreEnv is set, so an instance configured only by its providers config file does not
+// pick up the variables meant for the default instance of its type.
+func Getenv(ignoreEnv bool, key string) string {
+	if ignoreEnv {
+		return ""
+	}
+	return os.Getenv(key)
+}
+
+// ParseList splits a comma-separated list of domains or patterns, dropping blank entries
+func ParseList(list string) []string {
+	parts := strings.Split(list, ",")
+	values := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		if value := strings.TrimSpace(part); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// MatchesAny reports whether the domain matches any of the patterns (exact name or glob)
+func MatchesAny(domainName string, patterns []string) (bool, error) {
+	for _, pattern := range patterns {
+		matched, err := utils.MatchGlob(pattern, domainName)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
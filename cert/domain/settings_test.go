@@ -0,0 +1,96 @@
+package domain
+
+import "testing"
+
+func TestParseList(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "single domain",
+			input:    "example.com",
+			expected: []string{"example.com"},
+		},
+		{
+			name:     "multiple domains",
+			input:    "example.com,test.com,api.example.com",
+			expected: []string{"example.com", "test.com", "api.example.com"},
+		},
+		{
+			name:     "domains with spaces",
+			input:    "example.com, test.com , api.example.com",
+			expected: []string{"example.com", "test.com", "api.example.com"},
+		},
+		{
+			name:     "empty string",
+			input:    "",
+			expected: []string{},
+		},
+		{
+			name:     "trailing comma",
+			input:    "example.com,test.com,",
+			expected: []string{"example.com", "test.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseList(tt.input)
+
+			if len(result) != len(tt.expected) {
+				t.Errorf("Expected %d domains, got %d", len(tt.expected), len(result))
+				return
+			}
+
+			for i, value := range result {
+				if value != tt.expected[i] {
+					t.Errorf("Expected value[%d] = '%s', got '%s'", i, tt.expected[i], value)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchesAny(t *testing.T) {
+	patterns := []string{"legacy.com", "*.example.com"}
+
+	tests := []struct {
+		domain   string
+		expected bool
+	}{
+		{"legacy.com", true},
+		{"LEGACY.com", true},
+		{"shop.example.com", true},
+		{"example.com", false},
+		{"other.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			matched, err := MatchesAny(tt.domain, patterns)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if matched != tt.expected {
+				t.Errorf("Expected matched=%v for %s, got %v", tt.expected, tt.domain, matched)
+			}
+		})
+	}
+
+	if _, err := MatchesAny("example.com", []string{"[invalid"}); err == nil {
+		t.Error("Expected error for invalid pattern, got nil")
+	}
+}
+
+func TestGetenv(t *testing.T) {
+	t.Setenv("GO_CERT_PROVIDER_TEST_VALUE", "from-env")
+
+	if value := Getenv(false, "GO_CERT_PROVIDER_TEST_VALUE"); value != "from-env" {
+		t.Errorf("Getenv = %q, want %q", value, "from-env")
+	}
+	if value := Getenv(true, "GO_CERT_PROVIDER_TEST_VALUE"); value != "" {
+		t.Errorf("Getenv with ignoreEnv = %q, want it to be empty", value)
+	}
+}
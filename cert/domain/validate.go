@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"strings"
+)
+
+const (
+	maxNameLength  = 253
+	maxLabelLength = 63
+)
+
+// IsValid reports whether name is a fully qualified domain name such as "example.com",
+// optionally prefixed with a "*." wildcard label. URLs, ports, paths, single-label
+// names and empty strings are rejected.
+func IsValid(name string) bool {
+	name = strings.TrimPrefix(name, "*.")
+	if name == "" || len(name) > maxNameLength {
+		return false
+	}
+
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return false
+	}
+
+	for _, label := range labels {
+		if !isValidLabel(label) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isValidLabel checks a single DNS label: letters, digits and inner hyphens, 1-63 characters
+func isValidLabel(label string) bool {
+	if label == "" || len(label) > maxLabelLength {
+		return false
+	}
+	if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+		return false
+	}
+
+	for _, r := range label {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+
+	return true
+}
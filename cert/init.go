@@ -1,35 +1,78 @@
 package cert
 
 import (
+	"fmt"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/cert/providers/digitalocean"
+	"github.com/dh-kam/go-cert-provider/cert/providers/namecheap"
 	"github.com/dh-kam/go-cert-provider/cert/providers/porkbun"
 	"github.com/dh-kam/go-cert-provider/cert/registry"
-	"github.com/spf13/cobra"
 )
 
 var (
-	// Package-level instances to avoid re-initialization
-	globalProviderRegistry *registry.CertificateProviderRegistry
-	globalBootstrapManager *registry.BootstrapManager
-)
+	// Constructors of bootstraps added by files guarded with build tags (e.g. the mock provider)
+	optionalBootstraps []func() domain.ProviderBootstrap
 
-// InitializeCertificateSystem creates and configures the certificate provider system
-func InitializeCertificateSystem(cmd *cobra.Command) (*registry.CertificateProviderRegistry, *registry.BootstrapManager, error) {
-	// Return existing instances if already initialized
-	if globalProviderRegistry != nil && globalBootstrapManager != nil {
-		return globalProviderRegistry, globalBootstrapManager, nil
+	// Factories for provider instances declared in a providers config file, by provider type
+	bootstrapFactories = map[string]registry.BootstrapFactory{
+		"porkbun": func(cfg domain.ProviderConfig) (domain.ProviderBootstrap, error) {
+			return porkbun.NewBootstrapFromConfig(cfg)
+		},
+		"digitalocean": func(cfg domain.ProviderConfig) (domain.ProviderBootstrap, error) {
+			return digitalocean.NewBootstrapFromConfig(cfg)
+		},
+		"namecheap": func(cfg domain.ProviderConfig) (domain.ProviderBootstrap, error) {
+			return namecheap.NewBootstrapFromConfig(cfg)
+		},
 	}
+)
 
-	// Create registry
-	globalProviderRegistry = registry.NewCertificateProviderRegistry()
-
-	// Create bootstrap manager
-	globalBootstrapManager = registry.NewBootstrapManager(globalProviderRegistry)
+// NewCertificateSystem creates a provider registry and a bootstrap manager with a bootstrap
+// registered for every provider. Each call returns independent instances.
+func NewCertificateSystem() (*registry.CertificateProviderRegistry, *registry.BootstrapManager) {
+	providerRegistry := registry.NewCertificateProviderRegistry()
+	bootstrapManager := registry.NewBootstrapManager(providerRegistry)
 
 	// Register all provider bootstraps
-	globalBootstrapManager.RegisterBootstrap(porkbun.NewBootstrap())
+	bootstrapManager.RegisterBootstrap(porkbun.NewBootstrap())
+	bootstrapManager.RegisterBootstrap(digitalocean.NewBootstrap())
+	bootstrapManager.RegisterBootstrap(namecheap.NewBootstrap())
+	for _, newBootstrap := range optionalBootstraps {
+		bootstrapManager.RegisterBootstrap(newBootstrap())
+	}
 	// Future providers can be registered here:
-	// globalBootstrapManager.RegisterBootstrap(cloudflare.NewBootstrap())
-	// globalBootstrapManager.RegisterBootstrap(route53.NewBootstrap())
+	// bootstrapManager.RegisterBootstrap(cloudflare.NewBootstrap())
+	// bootstrapManager.RegisterBootstrap(route53.NewBootstrap())
+
+	return providerRegistry, bootstrapManager
+}
+
+// BootstrapFactories returns the factories creating provider bootstraps from a providers
+// config entry, by provider type. The mock type is only included in -tags mock builds.
+func BootstrapFactories() map[string]registry.BootstrapFactory {
+	factories := make(map[string]registry.BootstrapFactory, len(bootstrapFactories))
+	for providerType, factory := range bootstrapFactories {
+		factories[providerType] = factory
+	}
+	return factories
+}
+
+// ApplyProvidersConfig configures the providers declared in a providers config file
+// (see domain.ProvidersConfig) on top of the flag and environment configuration of the
+// manager's bootstraps. An empty path is ignored; the caller applies a file only once.
+func ApplyProvidersConfig(bootstrapManager *registry.BootstrapManager, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	cfg, err := registry.LoadProvidersConfig(path)
+	if err != nil {
+		return err
+	}
+	if err := bootstrapManager.ApplyProvidersConfig(cfg, bootstrapFactories); err != nil {
+		return fmt.Errorf("invalid providers config %s: %w", path, err)
+	}
 
-	return globalProviderRegistry, globalBootstrapManager, nil
+	return nil
 }
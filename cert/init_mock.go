@@ -0,0 +1,19 @@
+//go:build mock
+
+package cert
+
+import (
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/cert/providers/mock"
+)
+
+// The mock provider is only compiled into binaries built with -tags mock,
+// so it never appears in production builds
+func init() {
+	optionalBootstraps = append(optionalBootstraps, func() domain.ProviderBootstrap {
+		return mock.NewBootstrap()
+	})
+	bootstrapFactories["mock"] = func(cfg domain.ProviderConfig) (domain.ProviderBootstrap, error) {
+		return mock.NewBootstrapFromConfig(cfg)
+	}
+}
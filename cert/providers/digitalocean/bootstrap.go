@@ -0,0 +1,213 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/spf13/cobra"
+)
+
+// providerType is the type name of DigitalOcean providers in a providers config file,
+// and the default provider name
+const providerType = "digitalocean"
+
+const (
+	envToken   = "DIGITALOCEAN_TOKEN"   //nolint:gosec // not a credential
+	envDomains = "DIGITALOCEAN_DOMAINS" // Optional: manually specify domains
+	envExclude = "DIGITALOCEAN_DOMAINS_EXCLUDE"
+)
+
+var _ domain.ConnectivityChecker = (*Bootstrap)(nil)
+var _ domain.ConfigurableBootstrap = (*Bootstrap)(nil)
+
+// Bootstrap implements domain.ProviderBootstrap for DigitalOcean
+type Bootstrap struct {
+	name      string
+	config    domain.ProviderConfig // settings from a providers config file, overridden by flags and env
+	ignoreEnv bool                  // set for additional instances declared only in a config file
+
+	token   string
+	domains string // Comma-separated list of domains (optional)
+	exclude string // Comma-separated list of domain patterns to skip during auto-discovery (optional)
+}
+
+// NewBootstrap creates a new DigitalOcean bootstrap
+func NewBootstrap() *Bootstrap {
+	return &Bootstrap{name: providerType}
+}
+
+// NewBootstrapFromConfig creates a bootstrap for an additional DigitalOcean account declared in
+// a providers config file. It has no flags and ignores the DIGITALOCEAN_* environment variables.
+func NewBootstrapFromConfig(cfg domain.ProviderConfig) (*Bootstrap, error) {
+	b := &Bootstrap{name: cfg.InstanceName(), ignoreEnv: true}
+	if err := b.ApplyConfig(cfg); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// GetProviderName returns the provider name
+func (b *Bootstrap) GetProviderName() string {
+	return b.name
+}
+
+// ProviderType returns the provider type used in providers config files
+func (b *Bootstrap) ProviderType() string {
+	return providerType
+}
+
+// ApplyConfig sets the settings from a providers config file entry
+func (b *Bootstrap) ApplyConfig(cfg domain.ProviderConfig) error {
+	if cfg.APIKey != "" || cfg.SecretKey != "" || cfg.CredentialsFile != "" || cfg.CertFile != "" || cfg.KeyFile != "" ||
+		cfg.APIUser != "" || cfg.ClientIP != "" || len(cfg.DomainsInclude) > 0 {
+		return fmt.Errorf("provider %s: only token, domains and domainsExclude are supported by %s providers", cfg.InstanceName(), providerType)
+	}
+
+	b.config = cfg
+	return nil
+}
+
+// RegisterFlags registers command-line flags for DigitalOcean provider
+func (b *Bootstrap) RegisterFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+
+	flags.StringVar(&b.token, "digitalocean-token", "",
+		"DigitalOcean API token (overrides DIGITALOCEAN_TOKEN env var)")
+	flags.StringVar(&b.domains, "digitalocean-domains", "",
+		"Comma-separated list of DigitalOcean domains (optional, if not specified all domains from account will be used)")
+	flags.StringVar(&b.exclude, "digitalocean-domains-exclude", "",
+		"Comma-separated list of domains or glob patterns to skip during DigitalOcean auto-discovery (overrides DIGITALOCEAN_DOMAINS_EXCLUDE env var)")
+}
+
+// IsConfigured checks if the provider is configured
+func (b *Bootstrap) IsConfigured() bool {
+	// Only the token is required; domains are auto-discovered if not specified
+	return b.getToken() != ""
+}
+
+// CreateProvider creates a configured DigitalOcean provider instance
+func (b *Bootstrap) CreateProvider(ctx context.Context) (domain.CertificateProvider, error) {
+	token := b.getToken()
+	if token == "" {
+		return nil, fmt.Errorf("digitalocean API token not configured (set DIGITALOCEAN_TOKEN env var or --digitalocean-token flag)")
+	}
+
+	var domains []string
+	var domainInfos []domain.Info
+
+	if domainsStr := b.getDomains(); domainsStr != "" {
+		// User specified domains manually
+		domains = domain.ParseList(domainsStr)
+		if len(domains) == 0 {
+			return nil, fmt.Errorf("no valid domains specified for DigitalOcean")
+		}
+
+		for _, d := range domains {
+			domainInfos = append(domainInfos, domain.Info{
+				Name:     d,
+				Provider: b.name,
+				Status:   "CONFIGURED",
+			})
+		}
+	} else {
+		// Auto-discover domains from the DigitalOcean account
+		client := NewClient(token)
+
+		doDomains, err := client.ListDomains(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve domains from DigitalOcean: %w", err)
+		}
+		if len(doDomains) == 0 {
+			return nil, fmt.Errorf("no domains found in DigitalOcean account")
+		}
+
+		// Certificate metadata provides the domains' expiry
+		certificates, err := client.ListCertificates(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve certificates from DigitalOcean: %w", err)
+		}
+
+		excludePatterns := domain.ParseList(b.getExclude())
+		for _, d := range doDomains {
+			excluded, err := domain.MatchesAny(d.Name, excludePatterns)
+			if err != nil {
+				return nil, fmt.Errorf("invalid digitalocean-domains-exclude: %w", err)
+			}
+			if excluded {
+				continue
+			}
+
+			domains = append(domains, d.Name)
+			domainInfos = append(domainInfos, newDomainInfo(d.Name, b.name, certificates))
+		}
+
+		if len(domains) == 0 {
+			return nil, fmt.Errorf("all %d domains in DigitalOcean account are excluded by digitalocean-domains-exclude", len(doDomains))
+		}
+	}
+
+	provider := NewProvider(token, domains)
+	provider.name = b.name
+	provider.SetDomainInfos(domainInfos)
+
+	if err := provider.ValidateConfiguration(); err != nil {
+		return nil, fmt.Errorf("digitalocean provider validation failed: %w", err)
+	}
+
+	return provider, nil
+}
+
+// CheckConnectivity verifies the configured token against the DigitalOcean API,
+// without discovering domains
+func (b *Bootstrap) CheckConnectivity(ctx context.Context) (*domain.ConnectivityResult, error) {
+	if !b.IsConfigured() {
+		return nil, fmt.Errorf("digitalocean API token not configured")
+	}
+
+	return checkAccount(ctx, NewClient(b.getToken()))
+}
+
+// checkAccount tests the token by fetching the account it belongs to.
+// DigitalOcean does not report the caller's IP address.
+func checkAccount(ctx context.Context, client *Client) (*domain.ConnectivityResult, error) {
+	if _, err := client.GetAccount(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect to DigitalOcean API: %w", err)
+	}
+
+	return &domain.ConnectivityResult{}, nil
+}
+
+// getToken returns the API token from flag, environment or config file
+func (b *Bootstrap) getToken() string {
+	if b.token != "" {
+		return b.token
+	}
+	if token := domain.Getenv(b.ignoreEnv, envToken); token != "" {
+		return token
+	}
+	return b.config.Token
+}
+
+// getDomains returns the domains string from flag, environment or config file
+func (b *Bootstrap) getDomains() string {
+	if b.domains != "" {
+		return b.domains
+	}
+	if domains := domain.Getenv(b.ignoreEnv, envDomains); domains != "" {
+		return domains
+	}
+	return strings.Join(b.config.Domains, ",")
+}
+
+// getExclude returns the exclude patterns string from flag, environment or config file
+func (b *Bootstrap) getExclude() string {
+	if b.exclude != "" {
+		return b.exclude
+	}
+	if exclude := domain.Getenv(b.ignoreEnv, envExclude); exclude != "" {
+		return exclude
+	}
+	return strings.Join(b.config.DomainsExclude, ",")
+}
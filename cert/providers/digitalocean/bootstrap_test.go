@@ -0,0 +1,54 @@
+package digitalocean
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+func TestBootstrapTokenPrecedence(t *testing.T) {
+	t.Setenv(envToken, "env-token")
+
+	b := NewBootstrap()
+	if err := b.ApplyConfig(domain.ProviderConfig{Type: providerType, Token: "file-token"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got := b.getToken(); got != "env-token" {
+		t.Errorf("Expected the env token to override the config file, got %q", got)
+	}
+
+	b.token = "flag-token"
+	if got := b.getToken(); got != "flag-token" {
+		t.Errorf("Expected the flag token to take precedence, got %q", got)
+	}
+}
+
+func TestBootstrapFromConfig(t *testing.T) {
+	t.Setenv(envToken, "env-token")
+
+	b, err := NewBootstrapFromConfig(domain.ProviderConfig{
+		Type:    providerType,
+		Name:    "do-staging",
+		Token:   "file-token",
+		Domains: []string{"example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if b.GetProviderName() != "do-staging" || b.getToken() != "file-token" {
+		t.Errorf("Expected do-staging with the file token, got %s with %q", b.GetProviderName(), b.getToken())
+	}
+
+	provider, err := b.CreateProvider(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if domains := provider.GetDomains(); len(domains) != 1 || domains[0] != "example.com" {
+		t.Errorf("Expected [example.com], got %v", domains)
+	}
+
+	if _, err := NewBootstrapFromConfig(domain.ProviderConfig{Type: providerType, APIKey: "pk1"}); err == nil {
+		t.Error("Expected error for an unsupported apiKey setting")
+	}
+}
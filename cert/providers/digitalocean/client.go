@@ -0,0 +1,276 @@
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/config"
+	"github.com/dh-kam/go-cert-provider/utils"
+)
+
+const (
+	apiBaseURL            = "https://api.digitalocean.com/v2"
+	defaultRequestTimeout = 30 * time.Second
+	// pageSize is the largest page the DigitalOcean API returns
+	pageSize = 200
+)
+
+// Client represents a DigitalOcean API client
+type Client struct {
+	token      string
+	baseURL    string
+	userAgent  string
+	httpClient *http.Client
+}
+
+// ClientOption configures optional Client settings
+type ClientOption func(*Client)
+
+// WithUserAgent overrides the User-Agent header sent with every request
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// DefaultUserAgent returns the User-Agent sent when none is configured
+func DefaultUserAgent() string {
+	return "go-cert-provider/" + config.Version
+}
+
+// NewClient creates a new DigitalOcean API client authenticating with a personal access token
+func NewClient(token string, opts ...ClientOption) *Client {
+	c := &Client{
+		token:      token,
+		baseURL:    apiBaseURL,
+		userAgent:  DefaultUserAgent(),
+		httpClient: &http.Client{Timeout: defaultRequestTimeout},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// APIError is an error status reported by the DigitalOcean API.
+// It unwraps to domain.ErrAuthFailed or domain.ErrRateLimited when the failure can be classified.
+type APIError struct {
+	StatusCode int
+	ID         string
+	Message    string
+	kind       error
+}
+
+// Error returns the API error message
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = e.ID
+	}
+	return fmt.Sprintf("API returned status %d: %s", e.StatusCode, message)
+}
+
+// Unwrap returns the provider-neutral error kind, if classified
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// errorResponse is the body of a failed DigitalOcean API request
+type errorResponse struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+// newAPIError builds an APIError, classifying it by HTTP status code
+func newAPIError(statusCode int, id, message string) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, ID: id, Message: message}
+
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		apiErr.kind = domain.ErrRateLimited
+	case http.StatusUnauthorized, http.StatusForbidden:
+		apiErr.kind = domain.ErrAuthFailed
+	}
+
+	return apiErr
+}
+
+// Account represents the account owning the API token
+type Account struct {
+	Email  string `json:"email"`
+	UUID   string `json:"uuid"`
+	Status string `json:"status"`
+}
+
+// Domain represents a DNS domain hosted on DigitalOcean
+type Domain struct {
+	Name string `json:"name"`
+	TTL  int    `json:"ttl"`
+}
+
+// Certificate represents the metadata of a certificate stored on DigitalOcean.
+// The API never returns the certificate's PEM material or private key.
+type Certificate struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	NotAfter        time.Time `json:"not_after"`
+	SHA1Fingerprint string    `json:"sha1_fingerprint"`
+	CreatedAt       time.Time `json:"created_at"`
+	DNSNames        []string  `json:"dns_names"`
+	State           string    `json:"state"` // pending, verified or error
+	Type            string    `json:"type"`  // lets_encrypt or custom
+}
+
+// Covers reports whether the certificate lists domainName among its DNS names
+func (c Certificate) Covers(domainName string) bool {
+	for _, name := range c.DNSNames {
+		if name == domainName {
+			return true
+		}
+	}
+	return false
+}
+
+// pageLinks holds the pagination links of a list response
+type pageLinks struct {
+	Pages struct {
+		Next string `json:"next"`
+	} `json:"pages"`
+}
+
+type accountResponse struct {
+	Account Account `json:"account"`
+}
+
+type domainsResponse struct {
+	Domains []Domain  `json:"domains"`
+	Links   pageLinks `json:"links"`
+}
+
+type certificatesResponse struct {
+	Certificates []Certificate `json:"certificates"`
+	Links        pageLinks     `json:"links"`
+}
+
+// makeRequest makes an authenticated GET request to the DigitalOcean API
+func (c *Client) makeRequest(ctx context.Context, endpoint string, query url.Values, result interface{}) error {
+	requestURL := c.baseURL + endpoint
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		utils.ReportHTTPTiming(ctx, req.Method, requestURL, 0, time.Since(start))
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+	utils.ReportHTTPTiming(ctx, req.Method, requestURL, resp.StatusCode, time.Since(start))
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("API returned status %d (failed to read body: %w)", resp.StatusCode, err)
+		}
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr errorResponse
+		if err := json.Unmarshal(body, &apiErr); err != nil || apiErr.Message == "" {
+			apiErr.Message = string(body)
+		}
+		return newAPIError(resp.StatusCode, apiErr.ID, apiErr.Message)
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
+
+// pageQuery returns the query parameters requesting the given page
+func pageQuery(page int) url.Values {
+	return url.Values{
+		"page":     {strconv.Itoa(page)},
+		"per_page": {strconv.Itoa(pageSize)},
+	}
+}
+
+// GetAccount returns the account owning the token, verifying the token on the way
+func (c *Client) GetAccount(ctx context.Context) (*Account, error) {
+	var result accountResponse
+	if err := c.makeRequest(ctx, "/account", nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Account, nil
+}
+
+// ListDomains retrieves all domains in the account, following pagination
+func (c *Client) ListDomains(ctx context.Context) ([]Domain, error) {
+	var domains []Domain
+	for page := 1; ; page++ {
+		var result domainsResponse
+		if err := c.makeRequest(ctx, "/domains", pageQuery(page), &result); err != nil {
+			return nil, err
+		}
+
+		domains = append(domains, result.Domains...)
+		if result.Links.Pages.Next == "" || len(result.Domains) == 0 {
+			return domains, nil
+		}
+	}
+}
+
+// ListCertificates retrieves the metadata of all certificates in the account, following pagination
+func (c *Client) ListCertificates(ctx context.Context) ([]Certificate, error) {
+	var certificates []Certificate
+	for page := 1; ; page++ {
+		var result certificatesResponse
+		if err := c.makeRequest(ctx, "/certificates", pageQuery(page), &result); err != nil {
+			return nil, err
+		}
+
+		certificates = append(certificates, result.Certificates...)
+		if result.Links.Pages.Next == "" || len(result.Certificates) == 0 {
+			return certificates, nil
+		}
+	}
+}
+
+// latestCertificate returns the certificate covering domainName that expires last, if any
+func latestCertificate(certificates []Certificate, domainName string) *Certificate {
+	var latest *Certificate
+	for i := range certificates {
+		if !certificates[i].Covers(domainName) {
+			continue
+		}
+		if latest == nil || certificates[i].NotAfter.After(latest.NotAfter) {
+			latest = &certificates[i]
+		}
+	}
+	return latest
+}
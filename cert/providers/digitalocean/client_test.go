@@ -0,0 +1,119 @@
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+	return client
+}
+
+func TestClientClassifiesErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       error
+	}{
+		{"unauthorized", http.StatusUnauthorized, `{"id":"unauthorized","message":"Unable to authenticate you"}`, domain.ErrAuthFailed},
+		{"forbidden", http.StatusForbidden, `{"id":"forbidden","message":"You are not authorized to perform this operation"}`, domain.ErrAuthFailed},
+		{"rate limited", http.StatusTooManyRequests, `{"id":"too_many_requests","message":"API Rate limit exceeded."}`, domain.ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			_, err := client.GetAccount(context.Background())
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected error wrapping %v, got %v", tt.want, err)
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.statusCode {
+				t.Errorf("Expected APIError with status %d, got %v", tt.statusCode, err)
+			}
+		})
+	}
+}
+
+func TestClientSendsToken(t *testing.T) {
+	var gotAuth, gotUserAgent string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotUserAgent = r.Header.Get("User-Agent")
+		_, _ = w.Write([]byte(`{"account":{"email":"ops@example.com","status":"active"}}`))
+	})
+
+	account, err := client.GetAccount(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if account.Email != "ops@example.com" {
+		t.Errorf("Expected account email ops@example.com, got %q", account.Email)
+	}
+	if gotAuth != "Bearer test-token" {
+		t.Errorf("Expected bearer token header, got %q", gotAuth)
+	}
+	if gotUserAgent != DefaultUserAgent() {
+		t.Errorf("Expected User-Agent %q, got %q", DefaultUserAgent(), gotUserAgent)
+	}
+}
+
+func TestListDomainsFollowsPagination(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/domains" {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprint(w, `{"domains":[{"name":"example.com"}],"links":{"pages":{"next":"https://api.digitalocean.com/v2/domains?page=2"}}}`)
+		case "2":
+			fmt.Fprint(w, `{"domains":[{"name":"example.org"}],"links":{}}`)
+		default:
+			t.Errorf("Unexpected page %q", r.URL.Query().Get("page"))
+		}
+	})
+
+	domains, err := client.ListDomains(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(domains) != 2 || domains[0].Name != "example.com" || domains[1].Name != "example.org" {
+		t.Errorf("Expected example.com and example.org, got %v", domains)
+	}
+}
+
+func TestLatestCertificate(t *testing.T) {
+	certificates := []Certificate{
+		{Name: "old", DNSNames: []string{"example.com", "*.example.com"}, NotAfter: mustParseTime(t, "2026-01-01T00:00:00Z")},
+		{Name: "new", DNSNames: []string{"example.com", "*.example.com"}, NotAfter: mustParseTime(t, "2026-04-01T00:00:00Z")},
+		{Name: "other", DNSNames: []string{"example.org"}, NotAfter: mustParseTime(t, "2027-01-01T00:00:00Z")},
+	}
+
+	if cert := latestCertificate(certificates, "example.com"); cert == nil || cert.Name != "new" {
+		t.Errorf("Expected the later certificate, got %v", cert)
+	}
+	if cert := latestCertificate(certificates, "example.net"); cert != nil {
+		t.Errorf("Expected no certificate for an uncovered domain, got %v", cert)
+	}
+}
@@ -0,0 +1,194 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/utils"
+)
+
+var _ domain.CertificateProvider = (*Provider)(nil)
+var _ domain.ConnectivityChecker = (*Provider)(nil)
+var _ domain.DomainInfoRefresher = (*Provider)(nil)
+
+// Provider implements domain.CertificateProvider for DigitalOcean
+type Provider struct {
+	name    string
+	token   string
+	domains []string
+	client  *Client
+
+	mu          sync.RWMutex            // guards domainInfos, which RefreshDomainInfo updates
+	domainInfos map[string]*domain.Info // Map of domain name to info
+}
+
+// NewProvider creates a new DigitalOcean certificate provider
+func NewProvider(token string, domains []string) *Provider {
+	return &Provider{
+		name:        providerType,
+		token:       token,
+		domains:     domains,
+		domainInfos: make(map[string]*domain.Info),
+		client:      NewClient(token),
+	}
+}
+
+// SetDomainInfos sets the domain information (called by bootstrap)
+func (p *Provider) SetDomainInfos(infos []domain.Info) {
+	domainInfos := make(map[string]*domain.Info)
+	for i := range infos {
+		domainInfos[infos[i].Name] = &infos[i]
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.domainInfos = domainInfos
+}
+
+// GetProviderName returns the provider name
+func (p *Provider) GetProviderName() string {
+	return p.name
+}
+
+// GetDomains returns the list of domains this provider manages
+func (p *Provider) GetDomains() []string {
+	return p.domains
+}
+
+// GetDomainInfo returns detailed information about a specific domain
+func (p *Provider) GetDomainInfo(domainName string) *domain.Info {
+	p.mu.RLock()
+	info, exists := p.domainInfos[domainName]
+	p.mu.RUnlock()
+	if exists {
+		return info
+	}
+
+	for _, d := range p.domains {
+		if d == domainName {
+			return &domain.Info{
+				Name:     domainName,
+				Provider: p.GetProviderName(),
+				Status:   "UNKNOWN",
+			}
+		}
+	}
+	return nil
+}
+
+// ListDomainInfo returns detailed information for all managed domains
+func (p *Provider) ListDomainInfo() []domain.Info {
+	infos := make([]domain.Info, 0, len(p.domains))
+	for _, domainName := range p.domains {
+		if info := p.GetDomainInfo(domainName); info != nil {
+			infos = append(infos, *info)
+		}
+	}
+	return infos
+}
+
+// RefreshDomainInfo re-reads the domain and its certificates from DigitalOcean and
+// replaces its cached info, e.g. to pick up a renewed certificate's expiry
+func (p *Provider) RefreshDomainInfo(ctx context.Context, domainName string) error {
+	managed := false
+	for _, d := range p.domains {
+		if d == domainName {
+			managed = true
+			break
+		}
+	}
+	if !managed {
+		return fmt.Errorf("%s is not managed by this provider: %w", domainName, domain.ErrDomainNotManaged)
+	}
+
+	doDomains, err := p.client.ListDomains(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve domains from DigitalOcean: %w", err)
+	}
+	found := false
+	for _, d := range doDomains {
+		if d.Name == domainName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s is no longer in the DigitalOcean account", domainName)
+	}
+
+	certificates, err := p.client.ListCertificates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve certificates from DigitalOcean: %w", err)
+	}
+	info := newDomainInfo(domainName, p.name, certificates)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.domainInfos[domainName] = &info
+	return nil
+}
+
+// newDomainInfo builds the info of an account domain. DigitalOcean does not register
+// domains, so the latest certificate's expiry is the only expiry there is.
+func newDomainInfo(domainName, providerName string, certificates []Certificate) domain.Info {
+	info := domain.Info{
+		Name:     domainName,
+		Provider: providerName,
+		Status:   "ACTIVE",
+	}
+	if cert := latestCertificate(certificates, domainName); cert != nil {
+		info.ExpireDate = cert.NotAfter
+		info.CertNotAfter = cert.NotAfter
+	}
+	return info
+}
+
+// RetrieveCertificate looks up the certificate of the specified domain. DigitalOcean only
+// exposes certificate metadata, so an existing certificate yields an error wrapping
+// domain.ErrRetrievalUnsupported and a missing one an error wrapping domain.ErrCertNotFound.
+func (p *Provider) RetrieveCertificate(ctx context.Context, domainName string) ([]byte, []byte, error) {
+	if p.GetDomainInfo(domainName) == nil {
+		return nil, nil, fmt.Errorf("%s is not managed by this provider: %w", domainName, domain.ErrDomainNotManaged)
+	}
+
+	certificates, err := p.client.ListCertificates(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list certificates: %w", err)
+	}
+
+	cert := latestCertificate(certificates, domainName)
+	if cert == nil {
+		return nil, nil, fmt.Errorf("no DigitalOcean certificate covers %s: %w", domainName, domain.ErrCertNotFound)
+	}
+
+	return nil, nil, fmt.Errorf("certificate %q for %s (%s, valid until %s) cannot be downloaded from DigitalOcean: %w",
+		cert.Name, domainName, cert.Type, utils.FormatDateTime(cert.NotAfter), domain.ErrRetrievalUnsupported)
+}
+
+// CheckConnectivity verifies the API token with the DigitalOcean account endpoint
+func (p *Provider) CheckConnectivity(ctx context.Context) (*domain.ConnectivityResult, error) {
+	return checkAccount(ctx, p.client)
+}
+
+// ValidateConfiguration validates the provider's configuration
+func (p *Provider) ValidateConfiguration() error {
+	if p.token == "" {
+		return fmt.Errorf("missing required DigitalOcean fields: token")
+	}
+
+	var invalidDomains []string
+	for _, domainName := range p.domains {
+		if !domain.IsValid(domainName) {
+			invalidDomains = append(invalidDomains, fmt.Sprintf("%q", domainName))
+		}
+	}
+
+	if len(invalidDomains) > 0 {
+		return fmt.Errorf("invalid DigitalOcean domains: %s", strings.Join(invalidDomains, ", "))
+	}
+
+	return nil
+}
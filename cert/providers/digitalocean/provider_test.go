@@ -0,0 +1,87 @@
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("Failed to parse time %q: %v", value, err)
+	}
+	return parsed
+}
+
+func newTestProvider(t *testing.T, certificatesBody string) *Provider {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, certificatesBody)
+	}))
+	t.Cleanup(server.Close)
+
+	provider := NewProvider("test-token", []string{"example.com"})
+	provider.client.baseURL = server.URL
+	return provider
+}
+
+func TestProviderRetrieveCertificate(t *testing.T) {
+	tests := []struct {
+		name   string
+		domain string
+		body   string
+		want   error
+	}{
+		{
+			name:   "certificate exists",
+			domain: "example.com",
+			body:   `{"certificates":[{"name":"le-example","dns_names":["example.com"],"not_after":"2026-04-01T00:00:00Z","type":"lets_encrypt"}]}`,
+			want:   domain.ErrRetrievalUnsupported,
+		},
+		{
+			name:   "no certificate",
+			domain: "example.com",
+			body:   `{"certificates":[]}`,
+			want:   domain.ErrCertNotFound,
+		},
+		{
+			name:   "unmanaged domain",
+			domain: "example.org",
+			body:   `{"certificates":[]}`,
+			want:   domain.ErrDomainNotManaged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTestProvider(t, tt.body)
+
+			_, _, err := provider.RetrieveCertificate(context.Background(), tt.domain)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected error wrapping %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestProviderValidation(t *testing.T) {
+	if err := NewProvider("", nil).ValidateConfiguration(); err == nil {
+		t.Error("Expected error for a missing token")
+	}
+	if err := NewProvider("token", []string{"bad domain"}).ValidateConfiguration(); err == nil {
+		t.Error("Expected error for an invalid domain")
+	}
+	if err := NewProvider("token", []string{"example.com"}).ValidateConfiguration(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
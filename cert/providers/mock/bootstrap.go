@@ -0,0 +1,153 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/spf13/cobra"
+)
+
+// providerType is the type name of mock providers in a providers config file,
+// and the default provider name
+const providerType = "mock"
+
+const (
+	envDomains  = "MOCK_DOMAINS"
+	envCertFile = "MOCK_CERT_FILE"
+	envKeyFile  = "MOCK_KEY_FILE"
+)
+
+var _ domain.ConfigurableBootstrap = (*Bootstrap)(nil)
+
+// Bootstrap implements domain.ProviderBootstrap for the mock provider
+type Bootstrap struct {
+	name      string
+	config    domain.ProviderConfig // settings from a providers config file, overridden by flags and env
+	ignoreEnv bool                  // set for additional instances declared only in a config file
+
+	domains  string
+	certFile string
+	keyFile  string
+}
+
+// NewBootstrap creates a new mock bootstrap
+func NewBootstrap() *Bootstrap {
+	return &Bootstrap{name: providerType}
+}
+
+// NewBootstrapFromConfig creates a bootstrap for an additional mock provider declared in
+// a providers config file. It has no flags and ignores the MOCK_* environment variables.
+func NewBootstrapFromConfig(cfg domain.ProviderConfig) (*Bootstrap, error) {
+	b := &Bootstrap{name: cfg.InstanceName(), ignoreEnv: true}
+	if err := b.ApplyConfig(cfg); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// GetProviderName returns the provider name
+func (b *Bootstrap) GetProviderName() string {
+	return b.name
+}
+
+// ProviderType returns the provider type used in providers config files
+func (b *Bootstrap) ProviderType() string {
+	return providerType
+}
+
+// ApplyConfig sets the settings from a providers config file entry
+func (b *Bootstrap) ApplyConfig(cfg domain.ProviderConfig) error {
+	if cfg.APIKey != "" || cfg.SecretKey != "" || cfg.CredentialsFile != "" || cfg.Token != "" || cfg.APIUser != "" || cfg.ClientIP != "" ||
+		len(cfg.DomainsInclude) > 0 || len(cfg.DomainsExclude) > 0 {
+		return fmt.Errorf("provider %s: only domains, certFile and keyFile are supported by %s providers", cfg.InstanceName(), providerType)
+	}
+
+	b.config = cfg
+	return nil
+}
+
+// RegisterFlags registers command-line flags for the mock provider
+func (b *Bootstrap) RegisterFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+
+	flags.StringVar(&b.domains, "mock-domains", "",
+		"Comma-separated list of domains served by the mock provider (overrides MOCK_DOMAINS env var)")
+	flags.StringVar(&b.certFile, "mock-cert-file", "",
+		"PEM certificate chain served for every mock domain (overrides MOCK_CERT_FILE env var; default: self-signed)")
+	flags.StringVar(&b.keyFile, "mock-key-file", "",
+		"PEM private key served with --mock-cert-file (overrides MOCK_KEY_FILE env var)")
+}
+
+// IsConfigured checks if the provider is configured
+func (b *Bootstrap) IsConfigured() bool {
+	return b.getDomains() != ""
+}
+
+// CreateProvider creates a configured mock provider instance
+func (b *Bootstrap) CreateProvider(ctx context.Context) (domain.CertificateProvider, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	domains := domain.ParseList(b.getDomains())
+
+	var certChain, privateKey []byte
+	if certFile := b.getCertFile(); certFile != "" {
+		var err error
+		certChain, err = os.ReadFile(certFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mock certificate file: %w", err)
+		}
+	}
+	if keyFile := b.getKeyFile(); keyFile != "" {
+		var err error
+		privateKey, err = os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mock key file: %w", err)
+		}
+	}
+
+	provider := NewProvider(domains, certChain, privateKey)
+	provider.name = b.name
+	if err := provider.ValidateConfiguration(); err != nil {
+		return nil, fmt.Errorf("mock provider validation failed: %w", err)
+	}
+
+	return provider, nil
+}
+
+// getDomains returns the domains string from flag, environment or config file
+func (b *Bootstrap) getDomains() string {
+	if b.domains != "" {
+		return b.domains
+	}
+	if domains := domain.Getenv(b.ignoreEnv, envDomains); domains != "" {
+		return domains
+	}
+	return strings.Join(b.config.Domains, ",")
+}
+
+// getCertFile returns the certificate file from flag, environment or config file
+func (b *Bootstrap) getCertFile() string {
+	if b.certFile != "" {
+		return b.certFile
+	}
+	if certFile := domain.Getenv(b.ignoreEnv, envCertFile); certFile != "" {
+		return certFile
+	}
+	return b.config.CertFile
+}
+
+// getKeyFile returns the key file from flag, environment or config file
+func (b *Bootstrap) getKeyFile() string {
+	if b.keyFile != "" {
+		return b.keyFile
+	}
+	if keyFile := domain.Getenv(b.ignoreEnv, envKeyFile); keyFile != "" {
+		return keyFile
+	}
+	return b.config.KeyFile
+}
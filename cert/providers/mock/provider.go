@@ -0,0 +1,160 @@
+package mock
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+var _ domain.CertificateProvider = (*Provider)(nil)
+var _ domain.ConnectivityChecker = (*Provider)(nil)
+var _ domain.DomainInfoRefresher = (*Provider)(nil)
+
+// Provider implements domain.CertificateProvider with canned, in-memory data.
+// It is meant for tests and local development and never talks to a network.
+type Provider struct {
+	name       string
+	domains    []string
+	certChain  []byte
+	privateKey []byte
+}
+
+// NewProvider creates a mock provider serving the given domains.
+// When certChain and privateKey are empty, a self-signed certificate is generated
+// for each retrieval.
+func NewProvider(domains []string, certChain, privateKey []byte) *Provider {
+	return &Provider{
+		name:       "mock",
+		domains:    domains,
+		certChain:  certChain,
+		privateKey: privateKey,
+	}
+}
+
+// GetProviderName returns the provider name
+func (p *Provider) GetProviderName() string {
+	return p.name
+}
+
+// GetDomains returns the list of domains this provider manages
+func (p *Provider) GetDomains() []string {
+	return p.domains
+}
+
+// GetDomainInfo returns detailed information about a specific domain
+func (p *Provider) GetDomainInfo(domainName string) *domain.Info {
+	for _, d := range p.domains {
+		if d == domainName {
+			return &domain.Info{
+				Name:     domainName,
+				Provider: p.name,
+				Status:   "ACTIVE",
+			}
+		}
+	}
+	return nil
+}
+
+// ListDomainInfo returns detailed information for all managed domains
+func (p *Provider) ListDomainInfo() []domain.Info {
+	infos := make([]domain.Info, 0, len(p.domains))
+	for _, domainName := range p.domains {
+		infos = append(infos, *p.GetDomainInfo(domainName))
+	}
+	return infos
+}
+
+// RefreshDomainInfo has nothing to refresh, since the mock data never changes
+func (p *Provider) RefreshDomainInfo(ctx context.Context, domainName string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if p.GetDomainInfo(domainName) == nil {
+		return fmt.Errorf("%s is not managed by this provider: %w", domainName, domain.ErrDomainNotManaged)
+	}
+	return nil
+}
+
+// RetrieveCertificate returns the canned certificate, or a freshly generated
+// self-signed certificate for the domain when none is configured
+func (p *Provider) RetrieveCertificate(ctx context.Context, domainName string) ([]byte, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if p.GetDomainInfo(domainName) == nil {
+		return nil, nil, fmt.Errorf("%s is not managed by this provider: %w", domainName, domain.ErrDomainNotManaged)
+	}
+
+	if len(p.certChain) > 0 {
+		return p.certChain, p.privateKey, nil
+	}
+
+	return generateSelfSigned(domainName)
+}
+
+// ValidateConfiguration validates the provider's configuration
+func (p *Provider) ValidateConfiguration() error {
+	if len(p.domains) == 0 {
+		return fmt.Errorf("mock provider requires at least one domain")
+	}
+
+	if (len(p.certChain) == 0) != (len(p.privateKey) == 0) {
+		return fmt.Errorf("mock provider requires both a certificate and a private key, or neither")
+	}
+
+	return nil
+}
+
+// CheckConnectivity always succeeds, since the mock provider has no upstream API
+func (p *Provider) CheckConnectivity(ctx context.Context) (*domain.ConnectivityResult, error) {
+	return &domain.ConnectivityResult{ClientIP: "127.0.0.1"}, nil
+}
+
+// generateSelfSigned creates a self-signed ECDSA certificate valid for 90 days
+func generateSelfSigned(domainName string) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: domainName},
+		DNSNames:     []string{domainName},
+		NotBefore:    now,
+		NotAfter:     now.Add(90 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	return certPEM, keyPEM, nil
+}
@@ -0,0 +1,101 @@
+package mock
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+func TestProviderServesSelfSignedCertificate(t *testing.T) {
+	provider := NewProvider([]string{"example.com"}, nil, nil)
+
+	if err := provider.ValidateConfiguration(); err != nil {
+		t.Fatalf("Expected valid configuration, got: %v", err)
+	}
+
+	certChain, privateKey, err := provider.RetrieveCertificate(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Failed to retrieve certificate: %v", err)
+	}
+
+	block, _ := pem.Decode(certChain)
+	if block == nil {
+		t.Fatal("Certificate chain is not PEM encoded")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	if err := cert.VerifyHostname("example.com"); err != nil {
+		t.Errorf("Certificate should be valid for example.com: %v", err)
+	}
+
+	if keyBlock, _ := pem.Decode(privateKey); keyBlock == nil || keyBlock.Type != "PRIVATE KEY" {
+		t.Error("Private key should be a PEM encoded PKCS#8 key")
+	}
+}
+
+func TestProviderRejectsUnmanagedDomain(t *testing.T) {
+	provider := NewProvider([]string{"example.com"}, nil, nil)
+
+	if _, _, err := provider.RetrieveCertificate(context.Background(), "other.com"); !errors.Is(err, domain.ErrDomainNotManaged) {
+		t.Errorf("Expected ErrDomainNotManaged for unmanaged domain, got %v", err)
+	}
+
+	if provider.GetDomainInfo("other.com") != nil {
+		t.Error("Expected no domain info for unmanaged domain")
+	}
+}
+
+func TestProviderValidation(t *testing.T) {
+	if err := NewProvider(nil, nil, nil).ValidateConfiguration(); err == nil {
+		t.Error("Expected error without domains, got nil")
+	}
+
+	if err := NewProvider([]string{"example.com"}, []byte("cert"), nil).ValidateConfiguration(); err == nil {
+		t.Error("Expected error for certificate without key, got nil")
+	}
+}
+
+func TestBootstrapServesCannedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, []byte("canned-cert"), 0600); err != nil {
+		t.Fatalf("Failed to write cert file: %v", err)
+	}
+	if err := os.WriteFile(keyFile, []byte("canned-key"), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+
+	bootstrap := &Bootstrap{domains: "example.com, test.com", certFile: certFile, keyFile: keyFile}
+	if !bootstrap.IsConfigured() {
+		t.Fatal("Bootstrap should be configured when domains are set")
+	}
+
+	provider, err := bootstrap.CreateProvider(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	if domains := provider.GetDomains(); len(domains) != 2 || domains[1] != "test.com" {
+		t.Errorf("Expected [example.com test.com], got %v", domains)
+	}
+
+	certChain, privateKey, err := provider.RetrieveCertificate(context.Background(), "test.com")
+	if err != nil {
+		t.Fatalf("Failed to retrieve certificate: %v", err)
+	}
+
+	if string(certChain) != "canned-cert" || string(privateKey) != "canned-key" {
+		t.Errorf("Expected canned certificate, got %q / %q", certChain, privateKey)
+	}
+}
@@ -0,0 +1,256 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/spf13/cobra"
+)
+
+// providerType is the type name of Namecheap providers in a providers config file,
+// and the default provider name
+const providerType = "namecheap"
+
+const (
+	envAPIUser  = "NAMECHEAP_API_USER"
+	envAPIKey   = "NAMECHEAP_API_KEY" //nolint:gosec // not a credential
+	envClientIP = "NAMECHEAP_CLIENT_IP"
+	envDomains  = "NAMECHEAP_DOMAINS" // Optional: manually specify domains
+	envExclude  = "NAMECHEAP_DOMAINS_EXCLUDE"
+)
+
+var _ domain.ConnectivityChecker = (*Bootstrap)(nil)
+var _ domain.ConfigurableBootstrap = (*Bootstrap)(nil)
+
+// Bootstrap implements domain.ProviderBootstrap for Namecheap
+type Bootstrap struct {
+	name      string
+	config    domain.ProviderConfig // settings from a providers config file, overridden by flags and env
+	ignoreEnv bool                  // set for additional instances declared only in a config file
+
+	apiUser  string
+	apiKey   string
+	clientIP string
+	domains  string // Comma-separated list of domains (optional)
+	exclude  string // Comma-separated list of domain patterns to skip during auto-discovery (optional)
+}
+
+// NewBootstrap creates a new Namecheap bootstrap
+func NewBootstrap() *Bootstrap {
+	return &Bootstrap{name: providerType}
+}
+
+// NewBootstrapFromConfig creates a bootstrap for an additional Namecheap account declared in
+// a providers config file. It has no flags and ignores the NAMECHEAP_* environment variables.
+func NewBootstrapFromConfig(cfg domain.ProviderConfig) (*Bootstrap, error) {
+	b := &Bootstrap{name: cfg.InstanceName(), ignoreEnv: true}
+	if err := b.ApplyConfig(cfg); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// GetProviderName returns the provider name
+func (b *Bootstrap) GetProviderName() string {
+	return b.name
+}
+
+// ProviderType returns the provider type used in providers config files
+func (b *Bootstrap) ProviderType() string {
+	return providerType
+}
+
+// ApplyConfig sets the settings from a providers config file entry
+func (b *Bootstrap) ApplyConfig(cfg domain.ProviderConfig) error {
+	if cfg.SecretKey != "" || cfg.CredentialsFile != "" || cfg.Token != "" || cfg.CertFile != "" || cfg.KeyFile != "" ||
+		len(cfg.DomainsInclude) > 0 {
+		return fmt.Errorf("provider %s: only apiUser, apiKey, clientIp, domains and domainsExclude are supported by %s providers",
+			cfg.InstanceName(), providerType)
+	}
+
+	b.config = cfg
+	return nil
+}
+
+// RegisterFlags registers command-line flags for Namecheap provider
+func (b *Bootstrap) RegisterFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+
+	flags.StringVar(&b.apiUser, "namecheap-api-user", "",
+		"Namecheap API user (overrides NAMECHEAP_API_USER env var)")
+	flags.StringVar(&b.apiKey, "namecheap-api-key", "",
+		"Namecheap API key (overrides NAMECHEAP_API_KEY env var)")
+	flags.StringVar(&b.clientIP, "namecheap-client-ip", "",
+		"Public IPv4 address of this host, whitelisted for Namecheap API access (overrides NAMECHEAP_CLIENT_IP env var)")
+	flags.StringVar(&b.domains, "namecheap-domains", "",
+		"Comma-separated list of Namecheap domains (optional, if not specified all domains from account will be used)")
+	flags.StringVar(&b.exclude, "namecheap-domains-exclude", "",
+		"Comma-separated list of domains or glob patterns to skip during Namecheap auto-discovery (overrides NAMECHEAP_DOMAINS_EXCLUDE env var)")
+}
+
+// IsConfigured checks if the provider is configured. The client IP is not required here,
+// so that a missing one is reported by ValidateConfiguration instead of being ignored.
+func (b *Bootstrap) IsConfigured() bool {
+	return b.getAPIUser() != "" && b.getAPIKey() != ""
+}
+
+// CreateProvider creates a configured Namecheap provider instance
+func (b *Bootstrap) CreateProvider(ctx context.Context) (domain.CertificateProvider, error) {
+	apiUser := b.getAPIUser()
+	apiKey := b.getAPIKey()
+	clientIP := b.getClientIP()
+
+	// Validate before any request, since Namecheap rejects calls without a valid client IP
+	provider := NewProvider(apiUser, apiKey, clientIP, nil)
+	provider.name = b.name
+	if err := provider.ValidateConfiguration(); err != nil {
+		return nil, fmt.Errorf("namecheap provider validation failed: %w", err)
+	}
+
+	var domains []string
+	var domainInfos []domain.Info
+
+	if domainsStr := b.getDomains(); domainsStr != "" {
+		// User specified domains manually
+		domains = domain.ParseList(domainsStr)
+		if len(domains) == 0 {
+			return nil, fmt.Errorf("no valid domains specified for Namecheap")
+		}
+
+		for _, d := range domains {
+			domainInfos = append(domainInfos, domain.Info{
+				Name:     d,
+				Provider: b.name,
+				Status:   "CONFIGURED",
+			})
+		}
+	} else {
+		// Auto-discover domains via namecheap.domains.getList
+		ncDomains, err := provider.client.ListDomains(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve domains from Namecheap: %w", err)
+		}
+		if len(ncDomains) == 0 {
+			return nil, fmt.Errorf("no domains found in Namecheap account")
+		}
+
+		excludePatterns := domain.ParseList(b.getExclude())
+		activeCount := 0
+
+		for _, d := range ncDomains {
+			if d.IsExpired {
+				continue
+			}
+			activeCount++
+
+			excluded, err := domain.MatchesAny(d.Name, excludePatterns)
+			if err != nil {
+				return nil, fmt.Errorf("invalid namecheap-domains-exclude: %w", err)
+			}
+			if excluded {
+				continue
+			}
+
+			domains = append(domains, d.Name)
+			domainInfos = append(domainInfos, newDomainInfo(d, b.name))
+		}
+
+		if activeCount == 0 {
+			return nil, fmt.Errorf("no active domains found in Namecheap account")
+		}
+		if len(domains) == 0 {
+			return nil, fmt.Errorf("all %d active domains in Namecheap account are excluded by namecheap-domains-exclude", activeCount)
+		}
+	}
+
+	provider.domains = domains
+	provider.SetDomainInfos(domainInfos)
+
+	if err := provider.ValidateConfiguration(); err != nil {
+		return nil, fmt.Errorf("namecheap provider validation failed: %w", err)
+	}
+
+	return provider, nil
+}
+
+// CheckConnectivity verifies the credentials and client IP against the Namecheap API,
+// without discovering domains
+func (b *Bootstrap) CheckConnectivity(ctx context.Context) (*domain.ConnectivityResult, error) {
+	if !b.IsConfigured() {
+		return nil, fmt.Errorf("namecheap API credentials not configured")
+	}
+
+	provider := NewProvider(b.getAPIUser(), b.getAPIKey(), b.getClientIP(), nil)
+	if err := provider.ValidateConfiguration(); err != nil {
+		return nil, err
+	}
+
+	return checkBalances(ctx, provider.client)
+}
+
+// checkBalances tests the credentials with a cheap account call. A rejected client IP
+// means the IP is not whitelisted; the result reports the IP that was sent.
+func checkBalances(ctx context.Context, client *Client) (*domain.ConnectivityResult, error) {
+	if err := client.GetBalances(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect to Namecheap API: %w", err)
+	}
+
+	return &domain.ConnectivityResult{ClientIP: client.clientIP}, nil
+}
+
+// getAPIUser returns the API user from flag, environment or config file
+func (b *Bootstrap) getAPIUser() string {
+	if b.apiUser != "" {
+		return b.apiUser
+	}
+	if apiUser := domain.Getenv(b.ignoreEnv, envAPIUser); apiUser != "" {
+		return apiUser
+	}
+	return b.config.APIUser
+}
+
+// getAPIKey returns the API key from flag, environment or config file
+func (b *Bootstrap) getAPIKey() string {
+	if b.apiKey != "" {
+		return b.apiKey
+	}
+	if apiKey := domain.Getenv(b.ignoreEnv, envAPIKey); apiKey != "" {
+		return apiKey
+	}
+	return b.config.APIKey
+}
+
+// getClientIP returns the client IP from flag, environment or config file
+func (b *Bootstrap) getClientIP() string {
+	if b.clientIP != "" {
+		return b.clientIP
+	}
+	if clientIP := domain.Getenv(b.ignoreEnv, envClientIP); clientIP != "" {
+		return clientIP
+	}
+	return b.config.ClientIP
+}
+
+// getDomains returns the domains string from flag, environment or config file
+func (b *Bootstrap) getDomains() string {
+	if b.domains != "" {
+		return b.domains
+	}
+	if domains := domain.Getenv(b.ignoreEnv, envDomains); domains != "" {
+		return domains
+	}
+	return strings.Join(b.config.Domains, ",")
+}
+
+// getExclude returns the exclude patterns string from flag, environment or config file
+func (b *Bootstrap) getExclude() string {
+	if b.exclude != "" {
+		return b.exclude
+	}
+	if exclude := domain.Getenv(b.ignoreEnv, envExclude); exclude != "" {
+		return exclude
+	}
+	return strings.Join(b.config.DomainsExclude, ",")
+}
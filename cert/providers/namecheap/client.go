@@ -0,0 +1,274 @@
+package namecheap
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/config"
+	"github.com/dh-kam/go-cert-provider/utils"
+)
+
+const (
+	apiBaseURL            = "https://api.namecheap.com/xml.response"
+	defaultRequestTimeout = 30 * time.Second
+	// pageSize is the largest page namecheap.domains.getList returns
+	pageSize = 100
+	// dateLayout is the date format of Namecheap responses (MM/DD/YYYY)
+	dateLayout = "01/02/2006"
+)
+
+// Namecheap error numbers that can be classified
+const (
+	errInvalidAPIKey  = "1011102"
+	errInvalidAPIUser = "1011101"
+	errInvalidIP      = "1011150"
+	errTooManyCalls   = "500000"
+)
+
+// Client represents a Namecheap API client
+type Client struct {
+	apiUser    string
+	apiKey     string
+	username   string
+	clientIP   string
+	baseURL    string
+	userAgent  string
+	httpClient *http.Client
+}
+
+// ClientOption configures optional Client settings
+type ClientOption func(*Client)
+
+// WithUserAgent overrides the User-Agent header sent with every request
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithUsername sets the account the commands act on, when it differs from the API user
+func WithUsername(username string) ClientOption {
+	return func(c *Client) {
+		c.username = username
+	}
+}
+
+// DefaultUserAgent returns the User-Agent sent when none is configured
+func DefaultUserAgent() string {
+	return "go-cert-provider/" + config.Version
+}
+
+// NewClient creates a new Namecheap API client. Namecheap only accepts requests from
+// whitelisted IP addresses and requires the caller to state its IP as clientIP.
+func NewClient(apiUser, apiKey, clientIP string, opts ...ClientOption) *Client {
+	c := &Client{
+		apiUser:    apiUser,
+		apiKey:     apiKey,
+		username:   apiUser,
+		clientIP:   clientIP,
+		baseURL:    apiBaseURL,
+		userAgent:  DefaultUserAgent(),
+		httpClient: &http.Client{Timeout: defaultRequestTimeout},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// APIError is an error reported by the Namecheap API.
+// It unwraps to domain.ErrAuthFailed or domain.ErrRateLimited when the failure can be classified.
+type APIError struct {
+	Number  string
+	Message string
+	kind    error
+}
+
+// Error returns the API error message
+func (e *APIError) Error() string {
+	if e.Number == errInvalidIP {
+		return fmt.Sprintf("API error %s: %s (the client IP must be whitelisted under Profile > Tools > API Access)", e.Number, e.Message)
+	}
+	return fmt.Sprintf("API error %s: %s", e.Number, e.Message)
+}
+
+// Unwrap returns the provider-neutral error kind, if classified
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// newAPIError builds an APIError, classifying it by error number
+func newAPIError(number, message string) *APIError {
+	apiErr := &APIError{Number: number, Message: strings.TrimSpace(message)}
+
+	switch number {
+	case errInvalidAPIKey, errInvalidAPIUser, errInvalidIP:
+		apiErr.kind = domain.ErrAuthFailed
+	case errTooManyCalls:
+		apiErr.kind = domain.ErrRateLimited
+	}
+
+	return apiErr
+}
+
+// Domain represents a domain from namecheap.domains.getList
+type Domain struct {
+	ID        string `xml:"ID,attr"`
+	Name      string `xml:"Name,attr"`
+	Created   string `xml:"Created,attr"`
+	Expires   string `xml:"Expires,attr"`
+	IsExpired bool   `xml:"IsExpired,attr"`
+	AutoRenew bool   `xml:"AutoRenew,attr"`
+}
+
+// SSLCertificate represents a certificate from namecheap.ssl.getList
+type SSLCertificate struct {
+	CertificateID string `xml:"CertificateID,attr"`
+	HostName      string `xml:"HostName,attr"`
+	SSLType       string `xml:"SSLType,attr"`
+	ExpireDate    string `xml:"ExpireDate,attr"`
+	Status        string `xml:"Status,attr"`
+}
+
+// apiResponse is the envelope of every Namecheap response
+type apiResponse struct {
+	Status string `xml:"Status,attr"`
+	Errors []struct {
+		Number  string `xml:"Number,attr"`
+		Message string `xml:",chardata"`
+	} `xml:"Errors>Error"`
+	CommandResponse struct {
+		Domains         []Domain         `xml:"DomainGetListResult>Domain"`
+		SSLCertificates []SSLCertificate `xml:"SSLListResult>SSL"`
+		Paging          struct {
+			TotalItems int `xml:"TotalItems"`
+		} `xml:"Paging"`
+	} `xml:"CommandResponse"`
+}
+
+// makeRequest runs a Namecheap API command with the authentication parameters
+func (c *Client) makeRequest(ctx context.Context, command string, params url.Values) (*apiResponse, error) {
+	query := url.Values{
+		"ApiUser":  {c.apiUser},
+		"ApiKey":   {c.apiKey},
+		"UserName": {c.username},
+		"ClientIp": {c.clientIP},
+		"Command":  {command},
+	}
+	for key, values := range params {
+		query[key] = values
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	// The query string carries the API key, so only the command is reported
+	reportURL := c.baseURL + "?Command=" + command
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		utils.ReportHTTPTiming(ctx, req.Method, reportURL, 0, time.Since(start))
+		// url.Error includes the request URL with the API key
+		return nil, fmt.Errorf("failed to make %s request: %w", command, unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+	utils.ReportHTTPTiming(ctx, req.Method, reportURL, resp.StatusCode, time.Since(start))
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	var result apiResponse
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if !strings.EqualFold(result.Status, "OK") {
+		if len(result.Errors) > 0 {
+			return nil, newAPIError(result.Errors[0].Number, result.Errors[0].Message)
+		}
+		return nil, fmt.Errorf("%s failed: status %s", command, result.Status)
+	}
+
+	return &result, nil
+}
+
+// unwrapURLError strips the request URL from an HTTP client error
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// GetBalances runs namecheap.users.getBalances, a cheap call verifying the credentials and client IP
+func (c *Client) GetBalances(ctx context.Context) error {
+	_, err := c.makeRequest(ctx, "namecheap.users.getBalances", nil)
+	return err
+}
+
+// ListDomains retrieves all domains in the account, following pagination
+func (c *Client) ListDomains(ctx context.Context) ([]Domain, error) {
+	var domains []Domain
+	for page := 1; ; page++ {
+		result, err := c.makeRequest(ctx, "namecheap.domains.getList", url.Values{
+			"Page":     {strconv.Itoa(page)},
+			"PageSize": {strconv.Itoa(pageSize)},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		pageDomains := result.CommandResponse.Domains
+		domains = append(domains, pageDomains...)
+		if len(pageDomains) == 0 || len(domains) >= result.CommandResponse.Paging.TotalItems {
+			return domains, nil
+		}
+	}
+}
+
+// ListActiveSSL retrieves the active SSL certificates whose host name matches searchTerm
+func (c *Client) ListActiveSSL(ctx context.Context, searchTerm string) ([]SSLCertificate, error) {
+	result, err := c.makeRequest(ctx, "namecheap.ssl.getList", url.Values{
+		"ListType":   {"Active"},
+		"SearchTerm": {searchTerm},
+		"PageSize":   {strconv.Itoa(pageSize)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.CommandResponse.SSLCertificates, nil
+}
+
+// parseDate parses the Namecheap date format (MM/DD/YYYY)
+func parseDate(value string) time.Time {
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
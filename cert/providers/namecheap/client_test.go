@@ -0,0 +1,117 @@
+package namecheap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClient("test-user", "test-key", "203.0.113.10")
+	client.baseURL = server.URL
+	return client
+}
+
+func errorResponse(number, message string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<ApiResponse Status="ERROR" xmlns="http://api.namecheap.com/xml.response">
+  <Errors><Error Number="%s">%s</Error></Errors>
+</ApiResponse>`, number, message)
+}
+
+func TestClientClassifiesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		number string
+		want   error
+	}{
+		{"invalid api key", errInvalidAPIKey, domain.ErrAuthFailed},
+		{"client ip not whitelisted", errInvalidIP, domain.ErrAuthFailed},
+		{"too many requests", errTooManyCalls, domain.ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, errorResponse(tt.number, "request rejected"))
+			})
+
+			err := client.GetBalances(context.Background())
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected error wrapping %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestClientSendsAuthParameters(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		for key, want := range map[string]string{
+			"ApiUser":  "test-user",
+			"ApiKey":   "test-key",
+			"UserName": "test-user",
+			"ClientIp": "203.0.113.10",
+			"Command":  "namecheap.users.getBalances",
+		} {
+			if got := query.Get(key); got != want {
+				t.Errorf("Expected %s=%q, got %q", key, want, got)
+			}
+		}
+		fmt.Fprint(w, `<ApiResponse Status="OK"><Errors/><CommandResponse Type="namecheap.users.getBalances"/></ApiResponse>`)
+	})
+
+	if err := client.GetBalances(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+}
+
+func TestClientErrorsDoNotLeakAPIKey(t *testing.T) {
+	client := NewClient("test-user", "secret-api-key", "203.0.113.10")
+	client.baseURL = "http://127.0.0.1:1/xml.response"
+
+	err := client.GetBalances(context.Background())
+	if err == nil {
+		t.Fatal("Expected error for an unreachable API")
+	}
+	if strings.Contains(err.Error(), "secret-api-key") {
+		t.Errorf("Expected the API key to be redacted, got %v", err)
+	}
+}
+
+func TestListDomainsFollowsPagination(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		name := "example.com"
+		if r.URL.Query().Get("Page") == "2" {
+			name = "example.org"
+		}
+		fmt.Fprintf(w, `<ApiResponse Status="OK"><CommandResponse Type="namecheap.domains.getList">
+  <DomainGetListResult>
+    <Domain ID="1" Name="%s" Created="02/15/2016" Expires="02/15/2027" IsExpired="false" AutoRenew="true"/>
+  </DomainGetListResult>
+  <Paging><TotalItems>2</TotalItems><CurrentPage>1</CurrentPage><PageSize>1</PageSize></Paging>
+</CommandResponse></ApiResponse>`, name)
+	})
+
+	domains, err := client.ListDomains(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(domains) != 2 || domains[0].Name != "example.com" || domains[1].Name != "example.org" {
+		t.Fatalf("Expected example.com and example.org, got %v", domains)
+	}
+	if !domains[0].AutoRenew || parseDate(domains[0].Expires).Year() != 2027 {
+		t.Errorf("Expected auto-renew and a 2027 expiry, got %+v", domains[0])
+	}
+}
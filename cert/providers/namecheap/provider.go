@@ -0,0 +1,217 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+var _ domain.CertificateProvider = (*Provider)(nil)
+var _ domain.ConnectivityChecker = (*Provider)(nil)
+var _ domain.DomainInfoRefresher = (*Provider)(nil)
+
+// Provider implements domain.CertificateProvider for Namecheap
+type Provider struct {
+	name     string
+	apiUser  string
+	apiKey   string
+	clientIP string
+	domains  []string
+	client   *Client
+
+	mu          sync.RWMutex            // guards domainInfos, which RefreshDomainInfo updates
+	domainInfos map[string]*domain.Info // Map of domain name to info
+}
+
+// NewProvider creates a new Namecheap certificate provider
+func NewProvider(apiUser, apiKey, clientIP string, domains []string) *Provider {
+	return &Provider{
+		name:        providerType,
+		apiUser:     apiUser,
+		apiKey:      apiKey,
+		clientIP:    clientIP,
+		domains:     domains,
+		domainInfos: make(map[string]*domain.Info),
+		client:      NewClient(apiUser, apiKey, clientIP),
+	}
+}
+
+// SetDomainInfos sets the domain information (called by bootstrap)
+func (p *Provider) SetDomainInfos(infos []domain.Info) {
+	domainInfos := make(map[string]*domain.Info)
+	for i := range infos {
+		domainInfos[infos[i].Name] = &infos[i]
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.domainInfos = domainInfos
+}
+
+// GetProviderName returns the provider name
+func (p *Provider) GetProviderName() string {
+	return p.name
+}
+
+// GetDomains returns the list of domains this provider manages
+func (p *Provider) GetDomains() []string {
+	return p.domains
+}
+
+// GetDomainInfo returns detailed information about a specific domain
+func (p *Provider) GetDomainInfo(domainName string) *domain.Info {
+	p.mu.RLock()
+	info, exists := p.domainInfos[domainName]
+	p.mu.RUnlock()
+	if exists {
+		return info
+	}
+
+	for _, d := range p.domains {
+		if d == domainName {
+			return &domain.Info{
+				Name:     domainName,
+				Provider: p.GetProviderName(),
+				Status:   "UNKNOWN",
+			}
+		}
+	}
+	return nil
+}
+
+// ListDomainInfo returns detailed information for all managed domains
+func (p *Provider) ListDomainInfo() []domain.Info {
+	infos := make([]domain.Info, 0, len(p.domains))
+	for _, domainName := range p.domains {
+		if info := p.GetDomainInfo(domainName); info != nil {
+			infos = append(infos, *info)
+		}
+	}
+	return infos
+}
+
+// RefreshDomainInfo re-reads the domain from the Namecheap domain list and replaces its
+// cached info; a domain that expired since startup is reported as EXPIRED
+func (p *Provider) RefreshDomainInfo(ctx context.Context, domainName string) error {
+	managed := false
+	for _, d := range p.domains {
+		if d == domainName {
+			managed = true
+			break
+		}
+	}
+	if !managed {
+		return fmt.Errorf("%s is not managed by this provider: %w", domainName, domain.ErrDomainNotManaged)
+	}
+
+	ncDomains, err := p.client.ListDomains(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve domains from Namecheap: %w", err)
+	}
+
+	for _, d := range ncDomains {
+		if d.Name == domainName {
+			info := newDomainInfo(d, p.name)
+
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.domainInfos[domainName] = &info
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is no longer in the Namecheap account", domainName)
+}
+
+// newDomainInfo converts a domain from the Namecheap domain list
+func newDomainInfo(d Domain, providerName string) domain.Info {
+	status := "ACTIVE"
+	if d.IsExpired {
+		status = "EXPIRED"
+	}
+
+	return domain.Info{
+		Name:       d.Name,
+		Provider:   providerName,
+		Status:     status,
+		CreateDate: parseDate(d.Created),
+		ExpireDate: parseDate(d.Expires),
+		AutoRenew:  d.AutoRenew,
+	}
+}
+
+// RetrieveCertificate never returns certificate material. Namecheap SSL certificates are
+// issued for a CSR generated by the customer, so the private key never reaches Namecheap
+// and the SSL API cannot return it. The active certificate is looked up instead: if one
+// exists the error wraps domain.ErrRetrievalUnsupported and names it, otherwise it wraps
+// domain.ErrCertNotFound.
+func (p *Provider) RetrieveCertificate(ctx context.Context, domainName string) ([]byte, []byte, error) {
+	if p.GetDomainInfo(domainName) == nil {
+		return nil, nil, fmt.Errorf("%s is not managed by this provider: %w", domainName, domain.ErrDomainNotManaged)
+	}
+
+	certificates, err := p.client.ListActiveSSL(ctx, domainName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list SSL certificates: %w", err)
+	}
+
+	for _, cert := range certificates {
+		if cert.HostName == domainName || cert.HostName == "*."+domainName {
+			return nil, nil, fmt.Errorf("%s certificate %s for %s (expires %s) cannot be downloaded with its private key from Namecheap: %w",
+				cert.SSLType, cert.CertificateID, cert.HostName, cert.ExpireDate, domain.ErrRetrievalUnsupported)
+		}
+	}
+
+	return nil, nil, fmt.Errorf("no active Namecheap SSL certificate covers %s: %w", domainName, domain.ErrCertNotFound)
+}
+
+// CheckConnectivity verifies the API credentials and whitelisted client IP
+func (p *Provider) CheckConnectivity(ctx context.Context) (*domain.ConnectivityResult, error) {
+	return checkBalances(ctx, p.client)
+}
+
+// ValidateConfiguration validates the provider's configuration. Namecheap rejects every
+// request whose client IP is missing or not whitelisted, so the IP is checked up front.
+func (p *Provider) ValidateConfiguration() error {
+	var missingFields []string
+
+	if p.apiUser == "" {
+		missingFields = append(missingFields, "api-user")
+	}
+	if p.apiKey == "" {
+		missingFields = append(missingFields, "api-key")
+	}
+	if p.clientIP == "" {
+		missingFields = append(missingFields, "client-ip")
+	}
+
+	if len(missingFields) > 0 {
+		message := fmt.Sprintf("missing required Namecheap fields: %s", strings.Join(missingFields, ", "))
+		if p.clientIP == "" {
+			message += "; the client IP is the public IPv4 address of this host, which must be whitelisted " +
+				"under Profile > Tools > API Access (set NAMECHEAP_CLIENT_IP or --namecheap-client-ip)"
+		}
+		return fmt.Errorf("%s", message)
+	}
+
+	if ip := net.ParseIP(p.clientIP); ip == nil || ip.To4() == nil {
+		return fmt.Errorf("invalid Namecheap client IP %q: must be the whitelisted public IPv4 address of this host", p.clientIP)
+	}
+
+	var invalidDomains []string
+	for _, domainName := range p.domains {
+		if !domain.IsValid(domainName) {
+			invalidDomains = append(invalidDomains, fmt.Sprintf("%q", domainName))
+		}
+	}
+
+	if len(invalidDomains) > 0 {
+		return fmt.Errorf("invalid Namecheap domains: %s", strings.Join(invalidDomains, ", "))
+	}
+
+	return nil
+}
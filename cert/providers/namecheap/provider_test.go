@@ -0,0 +1,103 @@
+package namecheap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+func TestProviderValidationRequiresClientIP(t *testing.T) {
+	tests := []struct {
+		name     string
+		clientIP string
+		wantErr  string
+	}{
+		{"missing", "", "whitelisted"},
+		{"not an IP", "my-host", "invalid Namecheap client IP"},
+		{"IPv6", "2001:db8::1", "invalid Namecheap client IP"},
+		{"valid", "203.0.113.10", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewProvider("user", "key", tt.clientIP, []string{"example.com"}).ValidateConfiguration()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestProviderRetrieveCertificate(t *testing.T) {
+	tests := []struct {
+		name   string
+		domain string
+		ssl    string
+		want   error
+	}{
+		{
+			name:   "active certificate",
+			domain: "example.com",
+			ssl:    `<SSL CertificateID="42" HostName="*.example.com" SSLType="PositiveSSL Wildcard" ExpireDate="02/15/2027" Status="active"/>`,
+			want:   domain.ErrRetrievalUnsupported,
+		},
+		{
+			name:   "no certificate",
+			domain: "example.com",
+			want:   domain.ErrCertNotFound,
+		},
+		{
+			name:   "unmanaged domain",
+			domain: "example.org",
+			want:   domain.ErrDomainNotManaged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `<ApiResponse Status="OK"><CommandResponse Type="namecheap.ssl.getList"><SSLListResult>%s</SSLListResult></CommandResponse></ApiResponse>`, tt.ssl)
+			}))
+			defer server.Close()
+
+			provider := NewProvider("user", "key", "203.0.113.10", []string{"example.com"})
+			provider.client.baseURL = server.URL
+
+			_, _, err := provider.RetrieveCertificate(context.Background(), tt.domain)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected error wrapping %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestCreateProviderRejectsMissingClientIP(t *testing.T) {
+	b, err := NewBootstrapFromConfig(domain.ProviderConfig{
+		Type:    providerType,
+		APIUser: "user",
+		APIKey:  "key",
+		Domains: []string{"example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !b.IsConfigured() {
+		t.Fatal("Expected the bootstrap to be configured without a client IP")
+	}
+
+	if _, err := b.CreateProvider(context.Background()); err == nil || !strings.Contains(err.Error(), "client-ip") {
+		t.Errorf("Expected a client IP error, got %v", err)
+	}
+}
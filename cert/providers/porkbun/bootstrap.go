@@ -1,36 +1,106 @@
 package porkbun
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dh-kam/go-cert-provider/cert/domain"
 	"github.com/spf13/cobra"
 )
 
+// providerType is the type name of Porkbun providers in a providers config file,
+// and the default provider name
+const providerType = "porkbun"
+
 const (
 	envAPIKey    = "PORKBUN_API_KEY"    //nolint:gosec // not a credential
 	envSecretKey = "PORKBUN_SECRET_KEY" //nolint:gosec // not a credential
 	envDomains   = "PORKBUN_DOMAINS"    // Optional: manually specify domains
+	envExclude   = "PORKBUN_DOMAINS_EXCLUDE"
+	envInclude   = "PORKBUN_DOMAINS_INCLUDE"
+	envCredsFile = "PORKBUN_CREDENTIALS_FILE"
+	envStatuses  = "PORKBUN_INCLUDE_STATUSES"
+
+	envPingTimeout     = "PORKBUN_PING_TIMEOUT"
+	envListTimeout     = "PORKBUN_LIST_TIMEOUT"
+	envRetrieveTimeout = "PORKBUN_RETRIEVE_TIMEOUT"
 )
 
+// defaultIncludeStatuses are the statuses of the domains registered by auto-discovery
+// unless --porkbun-include-statuses says otherwise
+const defaultIncludeStatuses = "ACTIVE"
+
+var _ domain.ConnectivityChecker = (*Bootstrap)(nil)
+var _ domain.ConfigurableBootstrap = (*Bootstrap)(nil)
+
 // Bootstrap implements domain.ProviderBootstrap for Porkbun
 type Bootstrap struct {
+	name      string
+	config    domain.ProviderConfig // settings from a providers config file, overridden by flags and env
+	ignoreEnv bool                  // set for additional instances declared only in a config file
+
 	apiKey    string
 	secretKey string
 	domains   string // Comma-separated list of domains (optional)
+	exclude   string // Comma-separated list of domain patterns to skip during auto-discovery (optional)
+	include   string // Comma-separated list of domain patterns auto-discovery is limited to (optional)
+	credsFile string // Path to a file holding the API key and secret key (optional)
+	statuses  string // Comma-separated statuses of the domains registered by auto-discovery (optional)
+
+	pingTimeout     time.Duration // Per-operation API timeouts (optional, 0 uses env or the default)
+	listTimeout     time.Duration
+	retrieveTimeout time.Duration
+
+	permissionWarning sync.Once
+}
+
+// credentials holds the keys read from a credentials file.
+// JSON files use the field names of the Porkbun API request body.
+type credentials struct {
+	APIKey    string `json:"apikey"`
+	SecretKey string `json:"secretapikey"`
 }
 
 // NewBootstrap creates a new Porkbun bootstrap
 func NewBootstrap() *Bootstrap {
-	return &Bootstrap{}
+	return &Bootstrap{name: providerType}
+}
+
+// NewBootstrapFromConfig creates a bootstrap for an additional Porkbun account declared in
+// a providers config file. It has no flags and ignores the PORKBUN_* environment variables.
+func NewBootstrapFromConfig(cfg domain.ProviderConfig) (*Bootstrap, error) {
+	b := &Bootstrap{name: cfg.InstanceName(), ignoreEnv: true}
+	if err := b.ApplyConfig(cfg); err != nil {
+		return nil, err
+	}
+	return b, nil
 }
 
 // GetProviderName returns the provider name
 func (b *Bootstrap) GetProviderName() string {
-	return "porkbun"
+	return b.name
+}
+
+// ProviderType returns the provider type used in providers config files
+func (b *Bootstrap) ProviderType() string {
+	return providerType
+}
+
+// ApplyConfig sets the settings from a providers config file entry
+func (b *Bootstrap) ApplyConfig(cfg domain.ProviderConfig) error {
+	if cfg.CertFile != "" || cfg.KeyFile != "" || cfg.Token != "" || cfg.APIUser != "" || cfg.ClientIP != "" {
+		return fmt.Errorf("provider %s: token, apiUser, clientIp, certFile and keyFile are not supported by %s providers", cfg.InstanceName(), providerType)
+	}
+
+	b.config = cfg
+	return nil
 }
 
 // RegisterFlags registers command-line flags for Porkbun provider
@@ -43,6 +113,21 @@ func (b *Bootstrap) RegisterFlags(cmd *cobra.Command) {
 		"Porkbun secret key (overrides PORKBUN_SECRET_KEY env var)")
 	flags.StringVar(&b.domains, "porkbun-domains", "",
 		"Comma-separated list of domains (optional, if not specified all domains from account will be used)")
+	flags.StringVar(&b.credsFile, "porkbun-credentials-file", "",
+		"File with the Porkbun API key and secret key, one per line or as JSON (overrides PORKBUN_CREDENTIALS_FILE env var)")
+	flags.StringVar(&b.exclude, "porkbun-domains-exclude", "",
+		"Comma-separated list of domains or glob patterns (e.g. *.example.com) to skip during auto-discovery (overrides PORKBUN_DOMAINS_EXCLUDE env var)")
+	flags.StringVar(&b.include, "porkbun-domains-include", "",
+		"Comma-separated list of domains or glob patterns (e.g. *.prod.example.com); auto-discovery registers only matching domains, before applying the exclusions (overrides PORKBUN_DOMAINS_INCLUDE env var)")
+	flags.StringVar(&b.statuses, "porkbun-include-statuses", "",
+		"Comma-separated domain statuses registered by auto-discovery, e.g. ACTIVE,TRANSFER (default ACTIVE; overrides PORKBUN_INCLUDE_STATUSES env var)")
+	flags.DurationVar(&b.pingTimeout, "porkbun-ping-timeout", 0,
+		fmt.Sprintf("Timeout for Porkbun API pings (default %s; overrides %s env var)", DefaultPingTimeout, envPingTimeout))
+	flags.DurationVar(&b.listTimeout, "porkbun-list-timeout", 0,
+		fmt.Sprintf("Timeout for listing the Porkbun account's domains (default %s; overrides %s env var)", DefaultListTimeout, envListTimeout))
+	flags.DurationVar(&b.retrieveTimeout, "porkbun-retrieve-timeout", 0,
+		fmt.Sprintf("Timeout for retrieving an SSL bundle from Porkbun, which can be slow while a certificate is provisioned (default %s; overrides %s env var)",
+			DefaultRetrieveTimeout, envRetrieveTimeout))
 }
 
 // IsConfigured checks if the provider is configured
@@ -56,17 +141,26 @@ func (b *Bootstrap) IsConfigured() bool {
 }
 
 // CreateProvider creates a configured Porkbun provider instance
-func (b *Bootstrap) CreateProvider() (domain.CertificateProvider, error) {
+func (b *Bootstrap) CreateProvider(ctx context.Context) (domain.CertificateProvider, error) {
+	if _, err := b.getFileCredentials(); err != nil {
+		return nil, err
+	}
+
+	timeouts, err := b.getTimeouts()
+	if err != nil {
+		return nil, err
+	}
+
 	apiKey := b.getAPIKey()
 	secretKey := b.getSecretKey()
 	domainsStr := b.getDomains()
 
 	if apiKey == "" {
-		return nil, fmt.Errorf("porkbun API key not configured (set PORKBUN_API_KEY env var or --porkbun-api-key flag)")
+		return nil, fmt.Errorf("porkbun API key not configured (set PORKBUN_API_KEY env var, --porkbun-api-key flag or --porkbun-credentials-file)")
 	}
 
 	if secretKey == "" {
-		return nil, fmt.Errorf("porkbun secret key not configured (set PORKBUN_SECRET_KEY env var or --porkbun-secret-key flag)")
+		return nil, fmt.Errorf("porkbun secret key not configured (set PORKBUN_SECRET_KEY env var, --porkbun-secret-key flag or --porkbun-credentials-file)")
 	}
 
 	var domains []string
@@ -74,7 +168,7 @@ func (b *Bootstrap) CreateProvider() (domain.CertificateProvider, error) {
 
 	if domainsStr != "" {
 		// User specified domains manually
-		domains = parseDomains(domainsStr)
+		domains = domain.ParseList(domainsStr)
 		if len(domains) == 0 {
 			return nil, fmt.Errorf("no valid domains specified for Porkbun")
 		}
@@ -83,21 +177,21 @@ func (b *Bootstrap) CreateProvider() (domain.CertificateProvider, error) {
 		for _, d := range domains {
 			domainInfos = append(domainInfos, domain.Info{
 				Name:     d,
-				Provider: "porkbun",
+				Provider: b.name,
 				Status:   "CONFIGURED",
 			})
 		}
 	} else {
 		// Auto-discover domains from Porkbun account
-		client := NewClient(apiKey, secretKey)
+		client := NewClient(apiKey, secretKey, WithTimeouts(timeouts))
 
 		// Test connection first
-		if _, err := client.Ping(); err != nil {
+		if _, err := client.Ping(ctx); err != nil {
 			return nil, fmt.Errorf("failed to connect to Porkbun API: %w", err)
 		}
 
 		// Retrieve all domains
-		porkbunDomains, err := client.ListDomains()
+		porkbunDomains, err := client.ListDomains(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to retrieve domains from Porkbun: %w", err)
 		}
@@ -106,32 +200,15 @@ func (b *Bootstrap) CreateProvider() (domain.CertificateProvider, error) {
 			return nil, fmt.Errorf("no domains found in Porkbun account")
 		}
 
-		// Extract domain names (only ACTIVE domains) and create domain info
-		for _, d := range porkbunDomains {
-			if d.Status == "ACTIVE" {
-				domains = append(domains, d.Domain)
-
-				// Parse dates
-				createDate := parseDate(d.CreateDate)
-				expireDate := parseDate(d.ExpireDate)
-
-				domainInfos = append(domainInfos, domain.Info{
-					Name:       d.Domain,
-					Provider:   "porkbun",
-					Status:     d.Status,
-					CreateDate: createDate,
-					ExpireDate: expireDate,
-					AutoRenew:  false, // Porkbun API doesn't provide this in listAll
-				})
-			}
-		}
-
-		if len(domains) == 0 {
-			return nil, fmt.Errorf("no active domains found in Porkbun account")
+		domains, domainInfos, err = selectDomains(porkbunDomains, parseStatuses(b.getStatuses()),
+			domain.ParseList(b.getInclude()), domain.ParseList(b.getExclude()), b.name)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	provider := NewProvider(apiKey, secretKey, domains)
+	provider := NewProvider(apiKey, secretKey, domains, WithTimeouts(timeouts))
+	provider.name = b.name
 
 	// Set domain info
 	provider.SetDomainInfos(domainInfos)
@@ -144,43 +221,279 @@ func (b *Bootstrap) CreateProvider() (domain.CertificateProvider, error) {
 	return provider, nil
 }
 
-// getAPIKey returns the API key from flag or environment
+// CheckConnectivity pings the Porkbun API with the configured credentials,
+// without discovering domains
+func (b *Bootstrap) CheckConnectivity(ctx context.Context) (*domain.ConnectivityResult, error) {
+	if _, err := b.getFileCredentials(); err != nil {
+		return nil, err
+	}
+	if !b.IsConfigured() {
+		return nil, fmt.Errorf("porkbun API credentials not configured")
+	}
+	timeouts, err := b.getTimeouts()
+	if err != nil {
+		return nil, err
+	}
+
+	return ping(ctx, NewClient(b.getAPIKey(), b.getSecretKey(), WithTimeouts(timeouts)))
+}
+
+// ping tests the credentials and reports the egress IP Porkbun saw,
+// which must be allowlisted for API access
+func ping(ctx context.Context, client *Client) (*domain.ConnectivityResult, error) {
+	resp, err := client.Ping(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Porkbun API: %w", err)
+	}
+
+	return &domain.ConnectivityResult{ClientIP: resp.YourIP}, nil
+}
+
+// getAPIKey returns the API key from flag, credentials file or environment
 func (b *Bootstrap) getAPIKey() string {
 	if b.apiKey != "" {
 		return b.apiKey
 	}
-	return os.Getenv(envAPIKey)
+	if creds, err := b.getFileCredentials(); err == nil && creds.APIKey != "" {
+		return creds.APIKey
+	}
+	if apiKey := domain.Getenv(b.ignoreEnv, envAPIKey); apiKey != "" {
+		return apiKey
+	}
+	return b.config.APIKey
 }
 
-// getSecretKey returns the secret key from flag or environment
+// getSecretKey returns the secret key from flag, credentials file or environment
 func (b *Bootstrap) getSecretKey() string {
 	if b.secretKey != "" {
 		return b.secretKey
 	}
-	return os.Getenv(envSecretKey)
+	if creds, err := b.getFileCredentials(); err == nil && creds.SecretKey != "" {
+		return creds.SecretKey
+	}
+	if secretKey := domain.Getenv(b.ignoreEnv, envSecretKey); secretKey != "" {
+		return secretKey
+	}
+	return b.config.SecretKey
+}
+
+// getCredentialsFile returns the credentials file path from flag, environment or config file
+func (b *Bootstrap) getCredentialsFile() string {
+	if b.credsFile != "" {
+		return b.credsFile
+	}
+	if credsFile := domain.Getenv(b.ignoreEnv, envCredsFile); credsFile != "" {
+		return credsFile
+	}
+	return b.config.CredentialsFile
+}
+
+// getFileCredentials reads the credentials file, if one is configured. The file is read
+// on every call so that a provider reload picks up rotated keys.
+func (b *Bootstrap) getFileCredentials() (*credentials, error) {
+	path := b.getCredentialsFile()
+	if path == "" {
+		return &credentials{}, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read porkbun credentials file: %w", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o004 != 0 {
+		b.permissionWarning.Do(func() {
+			fmt.Fprintf(os.Stderr, "Warning: porkbun credentials file %s is world-readable (mode %04o); run chmod 600 on it\n",
+				path, info.Mode().Perm())
+		})
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read porkbun credentials file: %w", err)
+	}
+
+	creds, err := parseCredentials(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid porkbun credentials file %s: %w", path, err)
+	}
+
+	return creds, nil
 }
 
-// getDomains returns the domains string from flag or environment
+// parseCredentials parses either a JSON object with "apikey" and "secretapikey", or the
+// API key and secret key on the first two non-empty lines. Lines starting with '#' are ignored.
+func parseCredentials(data []byte) (*credentials, error) {
+	content := strings.TrimSpace(string(data))
+
+	creds := &credentials{}
+	if strings.HasPrefix(content, "{") {
+		if err := json.Unmarshal([]byte(content), creds); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+	} else {
+		var values []string
+		for _, line := range strings.Split(content, "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, "#") {
+				values = append(values, line)
+			}
+		}
+		if len(values) != 2 {
+			return nil, fmt.Errorf("expected the API key and secret key on two lines, found %d values", len(values))
+		}
+		creds.APIKey, creds.SecretKey = values[0], values[1]
+	}
+
+	if creds.APIKey == "" || creds.SecretKey == "" {
+		return nil, fmt.Errorf("both the API key and the secret key are required")
+	}
+
+	return creds, nil
+}
+
+// getDomains returns the domains string from flag, environment or config file
 func (b *Bootstrap) getDomains() string {
 	if b.domains != "" {
 		return b.domains
 	}
-	return os.Getenv(envDomains)
+	if domains := domain.Getenv(b.ignoreEnv, envDomains); domains != "" {
+		return domains
+	}
+	return strings.Join(b.config.Domains, ",")
+}
+
+// getExclude returns the exclude patterns string from flag, environment or config file
+func (b *Bootstrap) getExclude() string {
+	if b.exclude != "" {
+		return b.exclude
+	}
+	if exclude := domain.Getenv(b.ignoreEnv, envExclude); exclude != "" {
+		return exclude
+	}
+	return strings.Join(b.config.DomainsExclude, ",")
+}
+
+// getInclude returns the include patterns string from flag, environment or config file
+func (b *Bootstrap) getInclude() string {
+	if b.include != "" {
+		return b.include
+	}
+	if include := domain.Getenv(b.ignoreEnv, envInclude); include != "" {
+		return include
+	}
+	return strings.Join(b.config.DomainsInclude, ",")
+}
+
+// getStatuses returns the statuses to register from flag or environment, defaulting to ACTIVE
+func (b *Bootstrap) getStatuses() string {
+	if b.statuses != "" {
+		return b.statuses
+	}
+	if statuses := domain.Getenv(b.ignoreEnv, envStatuses); statuses != "" {
+		return statuses
+	}
+	return defaultIncludeStatuses
 }
 
-// parseDomains parses a comma-separated list of domains
-func parseDomains(domainsStr string) []string {
-	parts := strings.Split(domainsStr, ",")
-	domains := make([]string, 0, len(parts))
+// getTimeouts returns the per-operation API timeouts from flags or environment.
+// Unset timeouts are left zero, so the client defaults apply.
+func (b *Bootstrap) getTimeouts() (Timeouts, error) {
+	var timeouts Timeouts
+	for _, setting := range []struct {
+		target *time.Duration
+		flag   time.Duration
+		env    string
+	}{
+		{&timeouts.Ping, b.pingTimeout, envPingTimeout},
+		{&timeouts.List, b.listTimeout, envListTimeout},
+		{&timeouts.Retrieve, b.retrieveTimeout, envRetrieveTimeout},
+	} {
+		if setting.flag < 0 {
+			return Timeouts{}, fmt.Errorf("porkbun timeouts must be positive, got %s", setting.flag)
+		}
+		if setting.flag > 0 {
+			*setting.target = setting.flag
+			continue
+		}
+
+		value := domain.Getenv(b.ignoreEnv, setting.env)
+		if value == "" {
+			continue
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return Timeouts{}, fmt.Errorf("invalid %s %q: expected a positive duration such as 30s", setting.env, value)
+		}
+		*setting.target = timeout
+	}
+	return timeouts, nil
+}
+
+// parseStatuses splits a comma-separated status list, uppercased as the Porkbun API reports them
+func parseStatuses(statusesStr string) []string {
+	var statuses []string
+	for _, status := range strings.Split(statusesStr, ",") {
+		if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// selectDomains picks the discovered domains to register: those whose status is one of
+// includeStatuses, that match an include pattern (if any are given) and then match no
+// exclude pattern. Each keeps its real status.
+func selectDomains(discovered []Domain, includeStatuses, includePatterns, excludePatterns []string, providerName string) ([]string, []domain.Info, error) {
+	var domains []string
+	var domainInfos []domain.Info
+	statusCount, includedCount := 0, 0
 
-	for _, part := range parts {
-		domain := strings.TrimSpace(part)
-		if domain != "" {
-			domains = append(domains, domain)
+	for _, d := range discovered {
+		if !slices.Contains(includeStatuses, strings.ToUpper(d.Status)) {
+			continue
+		}
+		statusCount++
+
+		if len(includePatterns) > 0 {
+			// Include patterns match like exclude patterns: exact names or globs
+			included, err := domain.MatchesAny(d.Domain, includePatterns)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid porkbun-domains-include: %w", err)
+			}
+			if !included {
+				continue
+			}
+		}
+		includedCount++
+
+		excluded, err := domain.MatchesAny(d.Domain, excludePatterns)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid porkbun-domains-exclude: %w", err)
+		}
+		if excluded {
+			continue
+		}
+
+		domains = append(domains, d.Domain)
+		domainInfos = append(domainInfos, newDomainInfo(d, providerName))
+	}
+
+	statuses := strings.Join(includeStatuses, ", ")
+	if statusCount == 0 {
+		return nil, nil, fmt.Errorf("no domains with status %s found in Porkbun account (see --porkbun-include-statuses)", statuses)
+	}
+	if includedCount == 0 {
+		return nil, nil, fmt.Errorf("none of the %d domains with status %s in Porkbun account match porkbun-domains-include %s; "+
+			"note that *.example.com does not match example.com itself", statusCount, statuses, strings.Join(includePatterns, ","))
+	}
+	if len(domains) == 0 {
+		if len(includePatterns) > 0 {
+			return nil, nil, fmt.Errorf("all %d domains matching porkbun-domains-include in Porkbun account are excluded by porkbun-domains-exclude", includedCount)
 		}
+		return nil, nil, fmt.Errorf("all %d domains with status %s in Porkbun account are excluded by porkbun-domains-exclude", includedCount, statuses)
 	}
 
-	return domains
+	return domains, domainInfos, nil
 }
 
 // parseDate parses Porkbun date format (YYYY-MM-DD HH:MM:SS)
@@ -0,0 +1,344 @@
+package porkbun
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+func writeCredentialsFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "porkbun-credentials")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write credentials file: %v", err)
+	}
+	return path
+}
+
+func TestCredentialsPrecedence(t *testing.T) {
+	filePath := writeCredentialsFile(t, "file-api-key\nfile-secret-key\n")
+
+	testCases := []struct {
+		name       string
+		flagAPIKey string
+		flagSecret string
+		credsFile  string
+		envAPIKey  string
+		envSecret  string
+		wantAPIKey string
+		wantSecret string
+	}{
+		{
+			name:       "flag wins over file and env",
+			flagAPIKey: "flag-api-key", flagSecret: "flag-secret-key",
+			credsFile: filePath,
+			envAPIKey: "env-api-key", envSecret: "env-secret-key",
+			wantAPIKey: "flag-api-key", wantSecret: "flag-secret-key",
+		},
+		{
+			name:      "file wins over env",
+			credsFile: filePath,
+			envAPIKey: "env-api-key", envSecret: "env-secret-key",
+			wantAPIKey: "file-api-key", wantSecret: "file-secret-key",
+		},
+		{
+			name:       "flag and file mix per key",
+			flagAPIKey: "flag-api-key",
+			credsFile:  filePath,
+			envSecret:  "env-secret-key",
+			wantAPIKey: "flag-api-key", wantSecret: "file-secret-key",
+		},
+		{
+			name:      "env without file",
+			envAPIKey: "env-api-key", envSecret: "env-secret-key",
+			wantAPIKey: "env-api-key", wantSecret: "env-secret-key",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(envAPIKey, tc.envAPIKey)
+			t.Setenv(envSecretKey, tc.envSecret)
+			t.Setenv(envCredsFile, "")
+
+			bootstrap := &Bootstrap{apiKey: tc.flagAPIKey, secretKey: tc.flagSecret, credsFile: tc.credsFile}
+
+			if got := bootstrap.getAPIKey(); got != tc.wantAPIKey {
+				t.Errorf("Expected API key %q, got %q", tc.wantAPIKey, got)
+			}
+			if got := bootstrap.getSecretKey(); got != tc.wantSecret {
+				t.Errorf("Expected secret key %q, got %q", tc.wantSecret, got)
+			}
+		})
+	}
+}
+
+func TestCredentialsFileFromEnv(t *testing.T) {
+	t.Setenv(envAPIKey, "")
+	t.Setenv(envSecretKey, "")
+	t.Setenv(envCredsFile, writeCredentialsFile(t, `{"apikey": "json-api-key", "secretapikey": "json-secret-key"}`))
+
+	bootstrap := NewBootstrap()
+	if !bootstrap.IsConfigured() {
+		t.Fatal("Bootstrap should be configured from the credentials file")
+	}
+
+	if bootstrap.getAPIKey() != "json-api-key" || bootstrap.getSecretKey() != "json-secret-key" {
+		t.Errorf("Unexpected credentials: %q, %q", bootstrap.getAPIKey(), bootstrap.getSecretKey())
+	}
+}
+
+func TestParseCredentials(t *testing.T) {
+	testCases := []struct {
+		name       string
+		content    string
+		wantErr    bool
+		wantAPIKey string
+		wantSecret string
+	}{
+		{name: "two lines", content: "pk1_abc\nsk1_def\n", wantAPIKey: "pk1_abc", wantSecret: "sk1_def"},
+		{name: "comments and blank lines", content: "# porkbun\n\n  pk1_abc  \n\nsk1_def", wantAPIKey: "pk1_abc", wantSecret: "sk1_def"},
+		{name: "json", content: `{"apikey":"pk1_abc","secretapikey":"sk1_def"}`, wantAPIKey: "pk1_abc", wantSecret: "sk1_def"},
+		{name: "single line", content: "pk1_abc\n", wantErr: true},
+		{name: "three lines", content: "a\nb\nc\n", wantErr: true},
+		{name: "json missing secret", content: `{"apikey":"pk1_abc"}`, wantErr: true},
+		{name: "invalid json", content: `{"apikey":`, wantErr: true},
+		{name: "empty", content: "", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			creds, err := parseCredentials([]byte(tc.content))
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got credentials %+v", creds)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if creds.APIKey != tc.wantAPIKey || creds.SecretKey != tc.wantSecret {
+				t.Errorf("Expected %q/%q, got %q/%q", tc.wantAPIKey, tc.wantSecret, creds.APIKey, creds.SecretKey)
+			}
+		})
+	}
+}
+
+func TestCreateProviderReportsUnreadableCredentialsFile(t *testing.T) {
+	t.Setenv(envCredsFile, "")
+
+	bootstrap := &Bootstrap{credsFile: filepath.Join(t.TempDir(), "missing")}
+	if _, err := bootstrap.CreateProvider(context.Background()); err == nil {
+		t.Error("Expected error for a missing credentials file, got nil")
+	}
+}
+
+func TestBootstrapConfigFileSettings(t *testing.T) {
+	t.Setenv(envAPIKey, "env-api-key")
+	t.Setenv(envSecretKey, "")
+	t.Setenv(envDomains, "")
+	t.Setenv(envCredsFile, "")
+
+	cfg := domain.ProviderConfig{
+		Type:      "porkbun",
+		APIKey:    "file-api-key",
+		SecretKey: "file-secret-key",
+		Domains:   []string{"example.com", "test.com"},
+	}
+
+	// The built-in instance takes the file settings as defaults under env and flags
+	bootstrap := NewBootstrap()
+	if err := bootstrap.ApplyConfig(cfg); err != nil {
+		t.Fatalf("ApplyConfig failed: %v", err)
+	}
+	if got := bootstrap.getAPIKey(); got != "env-api-key" {
+		t.Errorf("Expected env to override the file API key, got %q", got)
+	}
+	if got := bootstrap.getSecretKey(); got != "file-secret-key" {
+		t.Errorf("Expected the file secret key, got %q", got)
+	}
+	if got := bootstrap.getDomains(); got != "example.com,test.com" {
+		t.Errorf("Expected the file domains, got %q", got)
+	}
+
+	// Additional instances ignore the environment and carry their own name
+	cfg.Name = "porkbun-personal"
+	extra, err := NewBootstrapFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("NewBootstrapFromConfig failed: %v", err)
+	}
+	if got := extra.getAPIKey(); got != "file-api-key" {
+		t.Errorf("Expected the file API key for an additional instance, got %q", got)
+	}
+
+	provider, err := extra.CreateProvider(context.Background())
+	if err != nil {
+		t.Fatalf("CreateProvider failed: %v", err)
+	}
+	if provider.GetProviderName() != "porkbun-personal" {
+		t.Errorf("Expected provider name porkbun-personal, got %q", provider.GetProviderName())
+	}
+	if info := provider.GetDomainInfo("example.com"); info == nil || info.Provider != "porkbun-personal" {
+		t.Errorf("Expected domain info attributed to porkbun-personal, got %v", info)
+	}
+
+	if _, err := NewBootstrapFromConfig(domain.ProviderConfig{Type: "porkbun", CertFile: "cert.pem"}); err == nil {
+		t.Error("Expected an error for settings Porkbun does not support")
+	}
+}
+
+func TestBootstrapConfigFileIncludeExclude(t *testing.T) {
+	t.Setenv(envInclude, "env.example.com")
+	t.Setenv(envExclude, "")
+
+	cfg := domain.ProviderConfig{
+		Type:           "porkbun",
+		Name:           "porkbun-shared",
+		DomainsInclude: []string{"*.team.example.net", "example.org"},
+		DomainsExclude: []string{"legacy.team.example.net"},
+	}
+
+	// An additional instance is limited by its file settings alone
+	extra, err := NewBootstrapFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("NewBootstrapFromConfig failed: %v", err)
+	}
+	if got := extra.getInclude(); got != "*.team.example.net,example.org" {
+		t.Errorf("Expected the file include patterns, got %q", got)
+	}
+	if got := extra.getExclude(); got != "legacy.team.example.net" {
+		t.Errorf("Expected the file exclude patterns, got %q", got)
+	}
+
+	// For the built-in instance, env and flags override the file
+	cfg.Name = ""
+	bootstrap := NewBootstrap()
+	if err := bootstrap.ApplyConfig(cfg); err != nil {
+		t.Fatalf("ApplyConfig failed: %v", err)
+	}
+	if got := bootstrap.getInclude(); got != "env.example.com" {
+		t.Errorf("Expected env to override the file include patterns, got %q", got)
+	}
+	bootstrap.include = "flag.example.com"
+	if got := bootstrap.getInclude(); got != "flag.example.com" {
+		t.Errorf("Expected the flag to override the include patterns, got %q", got)
+	}
+}
+
+func TestSelectDomainsByStatus(t *testing.T) {
+	discovered := []Domain{
+		{Domain: "active.com", Status: "ACTIVE"},
+		{Domain: "moving.com", Status: "TRANSFER"},
+		{Domain: "pending.com", Status: "PENDING"},
+		{Domain: "skipped.com", Status: "TRANSFER"},
+	}
+
+	tests := []struct {
+		name     string
+		statuses string
+		exclude  []string
+		want     []string
+		wantErr  bool
+	}{
+		{"default is active only", defaultIncludeStatuses, nil, []string{"active.com"}, false},
+		{"transitional statuses included", "active, transfer", nil, []string{"active.com", "moving.com", "skipped.com"}, false},
+		{"exclusion still applies", "TRANSFER", []string{"skipped.com"}, []string{"moving.com"}, false},
+		{"no matching status", "EXPIRED", nil, nil, true},
+		{"all excluded", "PENDING", []string{"*.com"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			domains, infos, err := selectDomains(discovered, parseStatuses(tt.statuses), nil, tt.exclude, "porkbun")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectDomains() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(domains, tt.want) {
+				t.Errorf("selectDomains() = %v, want %v", domains, tt.want)
+			}
+
+			// The real status is kept, not normalized to ACTIVE
+			for _, info := range infos {
+				i := slices.IndexFunc(discovered, func(d Domain) bool { return d.Domain == info.Name })
+				if i < 0 || info.Status != discovered[i].Status {
+					t.Errorf("Unexpected domain info %+v", info)
+				}
+			}
+		})
+	}
+}
+
+func TestSelectDomainsIncludeExclude(t *testing.T) {
+	discovered := []Domain{
+		{Domain: "api.prod.example.com", Status: "ACTIVE"},
+		{Domain: "web.prod.example.com", Status: "ACTIVE"},
+		{Domain: "web.staging.example.com", Status: "ACTIVE"},
+		{Domain: "other.org", Status: "ACTIVE"},
+	}
+
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+		wantErr string
+	}{
+		{"include only", []string{"*.prod.example.com"}, nil, []string{"api.prod.example.com", "web.prod.example.com"}, ""},
+		{"exclude only", nil, []string{"*.example.com"}, []string{"other.org"}, ""},
+		{"include then exclude", []string{"*.prod.example.com", "other.org"}, []string{"api.*"}, []string{"web.prod.example.com", "other.org"}, ""},
+		{"include matches nothing", []string{"*.dev.example.com"}, nil, nil, "match porkbun-domains-include"},
+		{"include all excluded", []string{"*.prod.example.com"}, []string{"*.example.com"}, nil, "excluded by porkbun-domains-exclude"},
+		{"invalid include pattern", []string{"[invalid"}, nil, nil, "invalid porkbun-domains-include"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			domains, _, err := selectDomains(discovered, parseStatuses(defaultIncludeStatuses), tt.include, tt.exclude, "porkbun")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("selectDomains() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectDomains() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(domains, tt.want) {
+				t.Errorf("selectDomains() = %v, want %v", domains, tt.want)
+			}
+		})
+	}
+}
+
+func TestBootstrapTimeouts(t *testing.T) {
+	t.Setenv(envPingTimeout, "5s")
+	t.Setenv(envListTimeout, "")
+	t.Setenv(envRetrieveTimeout, "1m")
+
+	bootstrap := NewBootstrap()
+	bootstrap.retrieveTimeout = 5 * time.Minute
+
+	timeouts, err := bootstrap.getTimeouts()
+	if err != nil {
+		t.Fatalf("getTimeouts failed: %v", err)
+	}
+	want := Timeouts{Ping: 5 * time.Second, Retrieve: 5 * time.Minute}
+	if timeouts != want {
+		t.Errorf("Expected %+v (env for ping, flag over env for retrieve, unset list), got %+v", want, timeouts)
+	}
+
+	t.Setenv(envListTimeout, "soon")
+	if _, err := bootstrap.getTimeouts(); err == nil || !strings.Contains(err.Error(), envListTimeout) {
+		t.Errorf("Expected an error naming %s, got: %v", envListTimeout, err)
+	}
+}
@@ -2,32 +2,172 @@ package porkbun
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/config"
+	"github.com/dh-kam/go-cert-provider/utils"
 )
 
 const (
-	apiBaseURL            = "https://api.porkbun.com/api/json/v3"
-	defaultRequestTimeout = 30 * time.Second
+	apiBaseURL = "https://api.porkbun.com/api/json/v3"
+
+	// DefaultPingTimeout bounds a ping, which should answer quickly
+	DefaultPingTimeout = 10 * time.Second
+	// DefaultListTimeout bounds listing the domains of the account
+	DefaultListTimeout = 30 * time.Second
+	// DefaultRetrieveTimeout bounds an SSL bundle retrieval, which can take longer
+	// while a certificate is being provisioned
+	DefaultRetrieveTimeout = 2 * time.Minute
 )
 
+// Timeouts bounds each kind of API operation. A zero field keeps the default.
+type Timeouts struct {
+	Ping     time.Duration
+	List     time.Duration
+	Retrieve time.Duration
+}
+
 // Client represents a Porkbun API client
 type Client struct {
 	apiKey     string
 	secretKey  string
+	baseURL    string
+	userAgent  string
+	timeouts   Timeouts
 	httpClient *http.Client
 }
 
+// ClientOption configures optional Client settings
+type ClientOption func(*Client)
+
+// WithUserAgent overrides the User-Agent header sent with every request
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithTimeouts overrides the per-operation timeouts; zero fields keep their defaults
+func WithTimeouts(timeouts Timeouts) ClientOption {
+	return func(c *Client) {
+		if timeouts.Ping > 0 {
+			c.timeouts.Ping = timeouts.Ping
+		}
+		if timeouts.List > 0 {
+			c.timeouts.List = timeouts.List
+		}
+		if timeouts.Retrieve > 0 {
+			c.timeouts.Retrieve = timeouts.Retrieve
+		}
+	}
+}
+
+// DefaultUserAgent returns the User-Agent sent when none is configured
+func DefaultUserAgent() string {
+	return "go-cert-provider/" + config.Version
+}
+
 // NewClient creates a new Porkbun API client
-func NewClient(apiKey, secretKey string) *Client {
-	return &Client{
-		apiKey:     apiKey,
-		secretKey:  secretKey,
-		httpClient: &http.Client{Timeout: defaultRequestTimeout},
+func NewClient(apiKey, secretKey string, opts ...ClientOption) *Client {
+	c := &Client{
+		apiKey:    apiKey,
+		secretKey: secretKey,
+		baseURL:   apiBaseURL,
+		userAgent: DefaultUserAgent(),
+		timeouts: Timeouts{
+			Ping:     DefaultPingTimeout,
+			List:     DefaultListTimeout,
+			Retrieve: DefaultRetrieveTimeout,
+		},
+		// Each operation sets its own deadline on the request context
+		httpClient: &http.Client{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	return c
+}
+
+// APIError is an error status reported by the Porkbun API.
+// It unwraps to domain.ErrAuthFailed, domain.ErrRateLimited or domain.ErrCertNotFound
+// when the failure can be classified.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Message    string
+	kind       error
+}
+
+// Error returns the API error message
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = e.Status
+	}
+	if e.StatusCode != http.StatusOK {
+		return fmt.Sprintf("API returned status %d: %s", e.StatusCode, message)
+	}
+	return message
+}
+
+// Unwrap returns the provider-neutral error kind, if classified
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// statusResponse holds the fields every Porkbun response carries
+type statusResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// newAPIError builds an APIError, classifying it by HTTP status code and message
+func newAPIError(statusCode int, status, message string) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, Status: status, Message: message}
+
+	lower := strings.ToLower(message)
+	switch {
+	case statusCode == http.StatusTooManyRequests ||
+		strings.Contains(lower, "rate limit") || strings.Contains(lower, "too many"):
+		apiErr.kind = domain.ErrRateLimited
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden ||
+		strings.Contains(lower, "api key") || strings.Contains(lower, "opted in") ||
+		strings.Contains(lower, "authentication") || strings.Contains(lower, "not authorized"):
+		apiErr.kind = domain.ErrAuthFailed
+	}
+
+	return apiErr
+}
+
+// missingBundleMessages are parts of the messages Porkbun answers /ssl/retrieve with when
+// a domain has no certificate bundle yet, e.g. while it is still being provisioned
+var missingBundleMessages = []string{"is not ready", "could not find ssl bundle"}
+
+// isMissingBundle reports whether an SSL retrieval error says the bundle does not exist yet.
+// Porkbun reports it as an ERROR status with HTTP 200 or 400; other failures, such as
+// outages or bad requests, are not mistaken for it.
+func isMissingBundle(apiErr *APIError) bool {
+	if apiErr.StatusCode != http.StatusOK && apiErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+
+	lower := strings.ToLower(apiErr.Message)
+	for _, message := range missingBundleMessages {
+		if strings.Contains(lower, message) {
+			return true
+		}
+	}
+	return false
 }
 
 // Domain represents a domain from Porkbun API
@@ -66,7 +206,7 @@ type authRequest struct {
 }
 
 // makeRequest makes an authenticated request to Porkbun API
-func (c *Client) makeRequest(endpoint string, result interface{}) error {
+func (c *Client) makeRequest(ctx context.Context, endpoint string, result interface{}) error {
 	reqBody := authRequest{
 		SecretAPIKey: c.secretKey,
 		APIKey:       c.apiKey,
@@ -77,31 +217,46 @@ func (c *Client) makeRequest(endpoint string, result interface{}) error {
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	url := apiBaseURL + endpoint
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	url := c.baseURL + endpoint
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		utils.ReportHTTPTiming(ctx, req.Method, url, 0, time.Since(start))
 		return fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
+	utils.ReportHTTPTiming(ctx, req.Method, url, resp.StatusCode, time.Since(start))
 
-	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if resp.StatusCode != http.StatusOK {
 			return fmt.Errorf("API returned status %d (failed to read body: %w)", resp.StatusCode, err)
 		}
-		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		return fmt.Errorf("failed to read response: %w", err)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+	if resp.StatusCode != http.StatusOK {
+		var status statusResponse
+		if err := json.Unmarshal(body, &status); err != nil || status.Message == "" {
+			status.Message = string(body)
+		}
+		return newAPIError(resp.StatusCode, status.Status, status.Message)
+	}
+
+	// Porkbun may also report errors with HTTP 200 and a non-SUCCESS status
+	var status statusResponse
+	if err := json.Unmarshal(body, &status); err == nil && status.Status != "" && status.Status != "SUCCESS" {
+		return newAPIError(resp.StatusCode, status.Status, status.Message)
 	}
 
 	if err := json.Unmarshal(body, result); err != nil {
@@ -111,10 +266,23 @@ func (c *Client) makeRequest(endpoint string, result interface{}) error {
 	return nil
 }
 
+// makeRequestWithTimeout makes the request with a deadline of timeout. An error caused by
+// that deadline, rather than by the caller's context, says how long the request took.
+func (c *Client) makeRequestWithTimeout(ctx context.Context, timeout time.Duration, endpoint string, result interface{}) error {
+	requestCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := c.makeRequest(requestCtx, endpoint, result)
+	if err != nil && ctx.Err() == nil && errors.Is(requestCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", timeout, err)
+	}
+	return err
+}
+
 // Ping tests the API connection and returns the client's IP address
-func (c *Client) Ping() (*PingResponse, error) {
+func (c *Client) Ping(ctx context.Context) (*PingResponse, error) {
 	var result PingResponse
-	if err := c.makeRequest("/ping", &result); err != nil {
+	if err := c.makeRequestWithTimeout(ctx, c.timeouts.Ping, "/ping", &result); err != nil {
 		return nil, err
 	}
 
@@ -126,9 +294,9 @@ func (c *Client) Ping() (*PingResponse, error) {
 }
 
 // ListDomains retrieves all domains in the account
-func (c *Client) ListDomains() ([]Domain, error) {
+func (c *Client) ListDomains(ctx context.Context) ([]Domain, error) {
 	var result ListDomainsResponse
-	if err := c.makeRequest("/domain/listAll", &result); err != nil {
+	if err := c.makeRequestWithTimeout(ctx, c.timeouts.List, "/domain/listAll", &result); err != nil {
 		return nil, err
 	}
 
@@ -140,12 +308,16 @@ func (c *Client) ListDomains() ([]Domain, error) {
 }
 
 // RetrieveSSL retrieves the SSL certificate for a domain
-func (c *Client) RetrieveSSL(domain string) (*SSLResponse, error) {
+func (c *Client) RetrieveSSL(ctx context.Context, domainName string) (*SSLResponse, error) {
 	var result SSLResponse
-	endpoint := fmt.Sprintf("/ssl/retrieve/%s", domain)
+	endpoint := fmt.Sprintf("/ssl/retrieve/%s", domainName)
 
-	if err := c.makeRequest(endpoint, &result); err != nil {
-		return nil, err
+	if err := c.makeRequestWithTimeout(ctx, c.timeouts.Retrieve, endpoint, &result); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.kind == nil && isMissingBundle(apiErr) {
+			apiErr.kind = domain.ErrCertNotFound
+		}
+		return nil, fmt.Errorf("SSL retrieval failed: %w", err)
 	}
 
 	if result.Status != "SUCCESS" {
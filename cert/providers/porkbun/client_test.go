@@ -0,0 +1,218 @@
+package porkbun
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+func newTestClient(t *testing.T, statusCode int, body string) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("test-api-key", "test-secret")
+	client.baseURL = server.URL
+	return client
+}
+
+func TestRetrieveSSLClassifiesErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       error
+	}{
+		{
+			name:       "invalid api key",
+			statusCode: http.StatusBadRequest,
+			body:       `{"status":"ERROR","message":"Invalid API key. (002)"}`,
+			want:       domain.ErrAuthFailed,
+		},
+		{
+			name:       "domain not opted in",
+			statusCode: http.StatusBadRequest,
+			body:       `{"status":"ERROR","message":"Domain is not opted in to API access."}`,
+			want:       domain.ErrAuthFailed,
+		},
+		{
+			name:       "rate limited",
+			statusCode: http.StatusServiceUnavailable,
+			body:       `{"status":"ERROR","message":"Rate limit exceeded."}`,
+			want:       domain.ErrRateLimited,
+		},
+		{
+			name:       "too many requests status",
+			statusCode: http.StatusTooManyRequests,
+			body:       `slow down`,
+			want:       domain.ErrRateLimited,
+		},
+		{
+			name:       "no certificate yet",
+			statusCode: http.StatusBadRequest,
+			body:       `{"status":"ERROR","message":"The SSL certificate is not ready for this domain."}`,
+			want:       domain.ErrCertNotFound,
+		},
+		{
+			name:       "error status with HTTP 200",
+			statusCode: http.StatusOK,
+			body:       `{"status":"ERROR","message":"Could not find SSL bundle."}`,
+			want:       domain.ErrCertNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, tt.statusCode, tt.body)
+
+			_, err := client.RetrieveSSL(context.Background(), "example.com")
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected error to be %v, got: %v", tt.want, err)
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Errorf("Expected an APIError, got %T", err)
+			}
+		})
+	}
+}
+
+func TestRetrieveSSLOtherErrorsAreNotCertNotFound(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+	}{
+		{"service unavailable", http.StatusServiceUnavailable, `{"status":"ERROR","message":"Service temporarily unavailable."}`},
+		{"bad gateway page", http.StatusBadGateway, `<html>502 Bad Gateway</html>`},
+		{"internal server error", http.StatusInternalServerError, `{"status":"ERROR","message":"An error occurred."}`},
+		{"invalid domain", http.StatusBadRequest, `{"status":"ERROR","message":"Invalid domain."}`},
+		{"unknown error status with HTTP 200", http.StatusOK, `{"status":"ERROR","message":"Something went wrong."}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, tt.statusCode, tt.body)
+
+			_, err := client.RetrieveSSL(context.Background(), "example.com")
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if errors.Is(err, domain.ErrCertNotFound) {
+				t.Errorf("Expected %s not to be reported as a missing certificate: %v", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestRetrieveSSLSuccess(t *testing.T) {
+	client := newTestClient(t, http.StatusOK,
+		`{"status":"SUCCESS","certificatechain":"chain","privatekey":"key","publickey":"pub"}`)
+
+	resp, err := client.RetrieveSSL(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if resp.CertificateChain != "chain" || resp.PrivateKey != "key" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestPingAuthFailureIsNotCertNotFound(t *testing.T) {
+	client := newTestClient(t, http.StatusBadRequest, `{"status":"ERROR","message":"Invalid API key. (002)"}`)
+
+	_, err := client.Ping(context.Background())
+	if !errors.Is(err, domain.ErrAuthFailed) {
+		t.Errorf("Expected ErrAuthFailed, got: %v", err)
+	}
+
+	if errors.Is(err, domain.ErrCertNotFound) {
+		t.Error("Ping errors should never be classified as certificate not found")
+	}
+}
+
+func TestRequestsSendUserAgent(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ClientOption
+		want string
+	}{
+		{name: "default", want: DefaultUserAgent()},
+		{name: "override", opts: []ClientOption{WithUserAgent("my-agent/1.0")}, want: "my-agent/1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var userAgents []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userAgents = append(userAgents, r.Header.Get("User-Agent"))
+				_, _ = w.Write([]byte(`{"status":"SUCCESS","yourIp":"127.0.0.1","domains":[],"certificatechain":"chain","privatekey":"key"}`))
+			}))
+			defer server.Close()
+
+			client := NewClient("test-api-key", "test-secret", tt.opts...)
+			client.baseURL = server.URL
+
+			ctx := context.Background()
+			if _, err := client.Ping(ctx); err != nil {
+				t.Fatalf("Ping failed: %v", err)
+			}
+			if _, err := client.ListDomains(ctx); err != nil {
+				t.Fatalf("ListDomains failed: %v", err)
+			}
+			if _, err := client.RetrieveSSL(ctx, "example.com"); err != nil {
+				t.Fatalf("RetrieveSSL failed: %v", err)
+			}
+
+			if len(userAgents) != 3 {
+				t.Fatalf("Expected 3 requests, got %d", len(userAgents))
+			}
+			for _, userAgent := range userAgents {
+				if userAgent != tt.want {
+					t.Errorf("Expected User-Agent %q, got %q", tt.want, userAgent)
+				}
+			}
+		})
+	}
+}
+
+func TestPerOperationTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"status":"SUCCESS","yourIp":"127.0.0.1","certificatechain":"chain","privatekey":"key"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-api-key", "test-secret", WithTimeouts(Timeouts{Ping: 50 * time.Millisecond}))
+	client.baseURL = server.URL
+	if client.timeouts.List != DefaultListTimeout || client.timeouts.Retrieve != DefaultRetrieveTimeout {
+		t.Errorf("Expected unset timeouts to keep their defaults, got %+v", client.timeouts)
+	}
+
+	ctx := context.Background()
+	_, err := client.Ping(ctx)
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Errorf("Expected the ping to time out after 50ms, got: %v", err)
+	}
+
+	// The slow retrieval is not cut short by the ping timeout
+	if _, err := client.RetrieveSSL(ctx, "example.com"); err != nil {
+		t.Errorf("Expected the retrieval to succeed within its timeout, got: %v", err)
+	}
+}
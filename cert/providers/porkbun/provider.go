@@ -1,45 +1,59 @@
 package porkbun
 
 import (
+	"bytes"
+	"context"
+	"encoding/pem"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/dh-kam/go-cert-provider/cert/domain"
 )
 
 var _ domain.CertificateProvider = (*Provider)(nil)
+var _ domain.ConnectivityChecker = (*Provider)(nil)
+var _ domain.DomainInfoRefresher = (*Provider)(nil)
 
 // Provider implements domain.CertificateProvider for Porkbun domain service
 type Provider struct {
-	apiKey      string
-	secretKey   string
-	domains     []string
+	name      string
+	apiKey    string
+	secretKey string
+	domains   []string
+	client    *Client
+
+	mu          sync.RWMutex            // guards domainInfos, which RefreshDomainInfo updates
 	domainInfos map[string]*domain.Info // Map of domain name to info
-	client      *Client
 }
 
-// NewProvider creates a new Porkbun certificate provider
-func NewProvider(apiKey, secretKey string, domains []string) *Provider {
+// NewProvider creates a new Porkbun certificate provider. The options configure its API client.
+func NewProvider(apiKey, secretKey string, domains []string, opts ...ClientOption) *Provider {
 	return &Provider{
+		name:        providerType,
 		apiKey:      apiKey,
 		secretKey:   secretKey,
 		domains:     domains,
 		domainInfos: make(map[string]*domain.Info),
-		client:      NewClient(apiKey, secretKey),
+		client:      NewClient(apiKey, secretKey, opts...),
 	}
 }
 
 // SetDomainInfos sets the domain information (called by bootstrap)
 func (p *Provider) SetDomainInfos(infos []domain.Info) {
-	p.domainInfos = make(map[string]*domain.Info)
+	domainInfos := make(map[string]*domain.Info)
 	for i := range infos {
-		p.domainInfos[infos[i].Name] = &infos[i]
+		domainInfos[infos[i].Name] = &infos[i]
 	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.domainInfos = domainInfos
 }
 
 // GetProviderName returns the provider name
 func (p *Provider) GetProviderName() string {
-	return "porkbun"
+	return p.name
 }
 
 // GetDomains returns the list of domains this provider manages
@@ -49,7 +63,9 @@ func (p *Provider) GetDomains() []string {
 
 // GetDomainInfo returns detailed information about a specific domain
 func (p *Provider) GetDomainInfo(domainName string) *domain.Info {
+	p.mu.RLock()
 	info, exists := p.domainInfos[domainName]
+	p.mu.RUnlock()
 	if !exists {
 		// Return basic info if detailed info not available
 		for _, d := range p.domains {
@@ -77,22 +93,63 @@ func (p *Provider) ListDomainInfo() []domain.Info {
 	return infos
 }
 
-// RetrieveCertificate retrieves the SSL certificate for the specified domain
-func (p *Provider) RetrieveCertificate(domain string) ([]byte, []byte, error) {
-	// Check if domain is managed by this provider
-	found := false
+// RefreshDomainInfo re-reads the domain from the Porkbun domain list and replaces its
+// cached info, e.g. to pick up a status that changed since startup
+func (p *Provider) RefreshDomainInfo(ctx context.Context, domainName string) error {
+	if !p.managesDomain(domainName) {
+		return fmt.Errorf("%s is not managed by this provider: %w", domainName, domain.ErrDomainNotManaged)
+	}
+
+	porkbunDomains, err := p.client.ListDomains(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve domains from Porkbun: %w", err)
+	}
+
+	for _, d := range porkbunDomains {
+		if d.Domain == domainName {
+			info := newDomainInfo(d, p.name)
+
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.domainInfos[domainName] = &info
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is no longer in the Porkbun account", domainName)
+}
+
+// managesDomain reports whether the domain is one of the provider's domains
+func (p *Provider) managesDomain(domainName string) bool {
 	for _, d := range p.domains {
-		if d == domain {
-			found = true
-			break
+		if d == domainName {
+			return true
 		}
 	}
-	if !found {
-		return nil, nil, fmt.Errorf("domain %s is not managed by this provider", domain)
+	return false
+}
+
+// newDomainInfo converts a domain from the Porkbun domain list
+func newDomainInfo(d Domain, providerName string) domain.Info {
+	return domain.Info{
+		Name:       d.Domain,
+		Provider:   providerName,
+		Status:     d.Status,
+		CreateDate: parseDate(d.CreateDate),
+		ExpireDate: parseDate(d.ExpireDate),
+		AutoRenew:  false, // Porkbun API doesn't provide this in listAll
+	}
+}
+
+// RetrieveCertificate retrieves the SSL certificate for the specified domain
+func (p *Provider) RetrieveCertificate(ctx context.Context, domainName string) ([]byte, []byte, error) {
+	// Check if domain is managed by this provider
+	if !p.managesDomain(domainName) {
+		return nil, nil, fmt.Errorf("%s is not managed by this provider: %w", domainName, domain.ErrDomainNotManaged)
 	}
 
 	// Retrieve certificate from Porkbun API
-	sslResp, err := p.client.RetrieveSSL(domain)
+	sslResp, err := p.client.RetrieveSSL(ctx, domainName)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to retrieve SSL certificate: %w", err)
 	}
@@ -101,9 +158,43 @@ func (p *Provider) RetrieveCertificate(domain string) ([]byte, []byte, error) {
 	certChain := []byte(sslResp.CertificateChain)
 	privateKey := []byte(sslResp.PrivateKey)
 
+	if err := checkPEMResponse(domainName, certChain, privateKey); err != nil {
+		return nil, nil, err
+	}
+
 	return certChain, privateKey, nil
 }
 
+// checkPEMResponse rejects a SUCCESS response whose chain or key is missing or not PEM.
+// Porkbun answers with empty fields while a certificate is still being provisioned,
+// so those are reported as domain.ErrCertNotFound.
+func checkPEMResponse(domainName string, certChain, privateKey []byte) error {
+	var missing []string
+	if len(bytes.TrimSpace(certChain)) == 0 {
+		missing = append(missing, "certificate chain")
+	}
+	if len(bytes.TrimSpace(privateKey)) == 0 {
+		missing = append(missing, "private key")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("porkbun returned an empty %s for %s; the certificate may still be provisioning: %w",
+			strings.Join(missing, " and "), domainName, domain.ErrCertNotFound)
+	}
+
+	if block, _ := pem.Decode(certChain); block == nil {
+		return fmt.Errorf("porkbun returned a certificate chain for %s that is not PEM", domainName)
+	}
+	if block, _ := pem.Decode(privateKey); block == nil {
+		return fmt.Errorf("porkbun returned a private key for %s that is not PEM", domainName)
+	}
+	return nil
+}
+
+// CheckConnectivity verifies the API credentials with the Porkbun ping endpoint
+func (p *Provider) CheckConnectivity(ctx context.Context) (*domain.ConnectivityResult, error) {
+	return ping(ctx, p.client)
+}
+
 // ValidateConfiguration validates the provider's configuration
 func (p *Provider) ValidateConfiguration() error {
 	var missingFields []string
@@ -116,12 +207,22 @@ func (p *Provider) ValidateConfiguration() error {
 		missingFields = append(missingFields, "secret-key")
 	}
 
-	// Note: domains can be empty - they will be auto-discovered
-
 	if len(missingFields) > 0 {
 		return fmt.Errorf("missing required Porkbun fields: %s", strings.Join(missingFields, ", "))
 	}
 
+	// Note: domains can be empty - they will be auto-discovered
+	var invalidDomains []string
+	for _, domainName := range p.domains {
+		if !domain.IsValid(domainName) {
+			invalidDomains = append(invalidDomains, fmt.Sprintf("%q", domainName))
+		}
+	}
+
+	if len(invalidDomains) > 0 {
+		return fmt.Errorf("invalid Porkbun domains: %s", strings.Join(invalidDomains, ", "))
+	}
+
 	return nil
 }
 
@@ -1,16 +1,66 @@
 package registry
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/dh-kam/go-cert-provider/cert/domain"
 	"github.com/spf13/cobra"
 )
 
+// BootstrapStatus summarizes a registered bootstrap and the provider it created
+type BootstrapStatus struct {
+	Name        string `json:"name"`
+	Configured  bool   `json:"configured"`
+	Registered  bool   `json:"registered"`
+	DomainCount int    `json:"domainCount"`
+}
+
+// ProviderCheck reports the outcome of checking a configured provider
+type ProviderCheck struct {
+	Name        string `json:"name"`
+	DomainCount int    `json:"domainCount"`
+	ClientIP    string `json:"clientIp,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// OK reports whether the provider was created and passed its connectivity test
+func (c ProviderCheck) OK() bool {
+	return c.Error == ""
+}
+
+// ProviderPing reports the outcome of pinging a provider API
+type ProviderPing struct {
+	Name     string
+	ClientIP string
+	Latency  time.Duration
+	Error    string
+}
+
+// OK reports whether the ping succeeded
+func (p ProviderPing) OK() bool {
+	return p.Error == ""
+}
+
+// ProviderFailure records a configured provider that failed to come up
+type ProviderFailure struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
 // BootstrapManager manages provider bootstraps
 type BootstrapManager struct {
-	bootstraps []domain.ProviderBootstrap
-	registry   *CertificateProviderRegistry
+	bootstraps      []domain.ProviderBootstrap
+	registry        *CertificateProviderRegistry
+	continueOnError bool
+	readOnly        bool
+	wildcards       bool
+	failures        map[string]error // key: provider name, from the last initialize or reload
+	failuresMutex   sync.RWMutex
 }
 
 // NewBootstrapManager creates a new bootstrap manager
@@ -18,9 +68,34 @@ func NewBootstrapManager(registry *CertificateProviderRegistry) *BootstrapManage
 	return &BootstrapManager{
 		bootstraps: make([]domain.ProviderBootstrap, 0),
 		registry:   registry,
+		failures:   make(map[string]error),
 	}
 }
 
+// Registry returns the registry the providers are registered with
+func (bm *BootstrapManager) Registry() *CertificateProviderRegistry {
+	return bm.registry
+}
+
+// SetContinueOnProviderError makes InitializeProviders skip providers that fail to be
+// created or registered instead of failing as a whole. Initialization still fails if
+// no provider comes up.
+func (bm *BootstrapManager) SetContinueOnProviderError(continueOnError bool) {
+	bm.continueOnError = continueOnError
+}
+
+// SetReadOnly makes the providers created from now on refuse certificate retrieval,
+// while their domains can still be listed; see NewReadOnlyProvider
+func (bm *BootstrapManager) SetReadOnly(readOnly bool) {
+	bm.readOnly = readOnly
+}
+
+// SetRegisterWildcards makes the providers created from now on also serve "*.<domain>"
+// for each of their domains; see NewWildcardProvider
+func (bm *BootstrapManager) SetRegisterWildcards(wildcards bool) {
+	bm.wildcards = wildcards
+}
+
 // RegisterBootstrap registers a provider bootstrap
 func (bm *BootstrapManager) RegisterBootstrap(bootstrap domain.ProviderBootstrap) {
 	bm.bootstraps = append(bm.bootstraps, bootstrap)
@@ -33,36 +108,172 @@ func (bm *BootstrapManager) RegisterFlags(cmd *cobra.Command) {
 	}
 }
 
-// InitializeProviders initializes all configured providers and registers them
-func (bm *BootstrapManager) InitializeProviders() error {
-	configuredCount := 0
+// providerCreation holds the outcome of creating one configured provider
+type providerCreation struct {
+	bootstrap domain.ProviderBootstrap
+	provider  domain.CertificateProvider
+	err       error
+}
 
+// createConfiguredProviders creates all configured providers concurrently, since
+// creation may involve network round trips for auto-discovery. Results are returned
+// in bootstrap registration order. Providers not created by the time ctx is done
+// fail with the context error; their bootstraps are expected to abort as well.
+func (bm *BootstrapManager) createConfiguredProviders(ctx context.Context) []*providerCreation {
+	creations := make([]*providerCreation, 0, len(bm.bootstraps))
 	for _, bootstrap := range bm.bootstraps {
-		if !bootstrap.IsConfigured() {
-			continue
+		if bootstrap.IsConfigured() {
+			creations = append(creations, &providerCreation{bootstrap: bootstrap})
 		}
+	}
 
-		provider, err := bootstrap.CreateProvider()
-		if err != nil {
-			return fmt.Errorf("failed to create provider %s: %w",
-				bootstrap.GetProviderName(), err)
+	type result struct {
+		index    int
+		provider domain.CertificateProvider
+		err      error
+	}
+
+	results := make(chan result, len(creations))
+	for i, c := range creations {
+		go func(i int, bootstrap domain.ProviderBootstrap) {
+			provider, err := bootstrap.CreateProvider(ctx)
+			if err == nil && bm.wildcards {
+				provider = NewWildcardProvider(provider)
+			}
+			if err == nil && bm.readOnly {
+				provider = NewReadOnlyProvider(provider)
+			}
+			results <- result{index: i, provider: provider, err: err}
+		}(i, c.bootstrap)
+	}
+
+	done := make([]bool, len(creations))
+	for pending := len(creations); pending > 0; pending-- {
+		select {
+		case r := <-results:
+			creations[r.index].provider, creations[r.index].err = r.provider, r.err
+			done[r.index] = true
+		case <-ctx.Done():
+			for i, c := range creations {
+				if !done[i] {
+					c.err = fmt.Errorf("gave up waiting for domain discovery: %w", ctx.Err())
+				}
+			}
+			return creations
 		}
+	}
+
+	return creations
+}
+
+// InitializeProviders initializes all configured providers and registers them.
+// Providers are created concurrently and then registered in bootstrap registration order.
+// By default any failure aborts initialization; see SetContinueOnProviderError.
+// Cancelling ctx, or reaching its deadline, aborts the providers still being created.
+func (bm *BootstrapManager) InitializeProviders(ctx context.Context) error {
+	creations := bm.createConfiguredProviders(ctx)
+	if len(creations) == 0 {
+		return fmt.Errorf("no certificate providers configured")
+	}
 
-		if err := bm.registry.Register(provider); err != nil {
-			return fmt.Errorf("failed to register provider %s: %w",
-				bootstrap.GetProviderName(), err)
+	var errs []error
+	for _, c := range creations {
+		if c.err != nil {
+			c.err = fmt.Errorf("failed to create provider %s: %w", c.bootstrap.GetProviderName(), c.err)
+			errs = append(errs, c.err)
 		}
+	}
+	if len(errs) > 0 && !bm.continueOnError {
+		return errors.Join(errs...)
+	}
 
-		configuredCount++
+	registered := 0
+	for _, c := range creations {
+		if c.err == nil {
+			if err := bm.registry.Register(c.provider); err != nil {
+				c.err = fmt.Errorf("failed to register provider %s: %w", c.bootstrap.GetProviderName(), err)
+				if !bm.continueOnError {
+					return c.err
+				}
+				errs = append(errs, c.err)
+			} else {
+				registered++
+			}
+		}
+		bm.setFailure(c.bootstrap.GetProviderName(), c.err)
 	}
 
-	if configuredCount == 0 {
-		return fmt.Errorf("no certificate providers configured")
+	if registered == 0 {
+		return errors.Join(errs...)
 	}
 
 	return nil
 }
 
+// setFailure records or clears the failure of a provider
+func (bm *BootstrapManager) setFailure(providerName string, err error) {
+	bm.failuresMutex.Lock()
+	defer bm.failuresMutex.Unlock()
+
+	if err == nil {
+		delete(bm.failures, providerName)
+	} else {
+		bm.failures[providerName] = err
+	}
+}
+
+// FailedProviders returns the configured providers whose last initialization or reload
+// failed, in bootstrap registration order. With SetContinueOnProviderError, these are
+// the providers the service is running without.
+func (bm *BootstrapManager) FailedProviders() []ProviderFailure {
+	bm.failuresMutex.RLock()
+	defer bm.failuresMutex.RUnlock()
+
+	failures := make([]ProviderFailure, 0, len(bm.failures))
+	for _, bootstrap := range bm.bootstraps {
+		if err, failed := bm.failures[bootstrap.GetProviderName()]; failed {
+			failures = append(failures, ProviderFailure{Name: bootstrap.GetProviderName(), Error: err.Error()})
+		}
+	}
+
+	return failures
+}
+
+// ReloadProviders re-creates all configured providers, re-running their domain
+// auto-discovery, and swaps them into the registry. A provider that fails to reload
+// keeps serving its previous domains; its error is returned alongside the changes
+// applied for the other providers.
+func (bm *BootstrapManager) ReloadProviders(ctx context.Context) (*DomainChanges, error) {
+	changes := &DomainChanges{}
+	var errs []error
+
+	for _, c := range bm.createConfiguredProviders(ctx) {
+		if c.err != nil {
+			err := fmt.Errorf("failed to reload provider %s: %w", c.bootstrap.GetProviderName(), c.err)
+			bm.setFailure(c.bootstrap.GetProviderName(), err)
+			errs = append(errs, err)
+			continue
+		}
+
+		providerChanges, err := bm.registry.Reload(c.provider)
+		if err != nil {
+			err = fmt.Errorf("failed to reload provider %s: %w", c.bootstrap.GetProviderName(), err)
+			bm.setFailure(c.bootstrap.GetProviderName(), err)
+			errs = append(errs, err)
+			continue
+		}
+		bm.setFailure(c.bootstrap.GetProviderName(), nil)
+
+		changes.Added = append(changes.Added, providerChanges.Added...)
+		changes.Removed = append(changes.Removed, providerChanges.Removed...)
+	}
+
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+
+	return changes, errors.Join(errs...)
+}
+
 // GetConfiguredProviders returns a list of configured provider names
 func (bm *BootstrapManager) GetConfiguredProviders() []string {
 	configured := make([]string, 0)
@@ -75,3 +286,120 @@ func (bm *BootstrapManager) GetConfiguredProviders() []string {
 
 	return configured
 }
+
+// ListBootstraps returns the status of every registered bootstrap in registration order.
+// Registered and DomainCount reflect the registry, so they are only populated after InitializeProviders.
+func (bm *BootstrapManager) ListBootstraps() []BootstrapStatus {
+	statuses := make([]BootstrapStatus, 0, len(bm.bootstraps))
+
+	for _, bootstrap := range bm.bootstraps {
+		status := BootstrapStatus{
+			Name:       bootstrap.GetProviderName(),
+			Configured: bootstrap.IsConfigured(),
+		}
+
+		if provider, err := bm.registry.GetProvider(status.Name); err == nil {
+			status.Registered = true
+			status.DomainCount = len(provider.GetDomains())
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// CheckProviders creates every configured provider and tests its connectivity
+// without registering it. Unlike InitializeProviders, a failing provider does not
+// stop the remaining ones from being checked.
+func (bm *BootstrapManager) CheckProviders(ctx context.Context) []ProviderCheck {
+	checks := make([]ProviderCheck, 0, len(bm.bootstraps))
+
+	for _, bootstrap := range bm.bootstraps {
+		if !bootstrap.IsConfigured() {
+			continue
+		}
+
+		check := ProviderCheck{Name: bootstrap.GetProviderName()}
+
+		provider, err := bootstrap.CreateProvider(ctx)
+		if err != nil {
+			check.Error = fmt.Sprintf("failed to create provider: %v", err)
+			checks = append(checks, check)
+			continue
+		}
+
+		check.DomainCount = len(provider.GetDomains())
+
+		if checker, ok := provider.(domain.ConnectivityChecker); ok {
+			result, err := checker.CheckConnectivity(ctx)
+			if err != nil {
+				check.Error = fmt.Sprintf("connectivity check failed: %v", err)
+			} else {
+				check.ClientIP = result.ClientIP
+			}
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+// PingProviders tests the API connectivity of the configured providers, or only of
+// providerName when it is not empty. Bootstraps that implement domain.ConnectivityChecker
+// are pinged directly, so no domain discovery is needed.
+func (bm *BootstrapManager) PingProviders(ctx context.Context, providerName string) ([]ProviderPing, error) {
+	pings := make([]ProviderPing, 0, len(bm.bootstraps))
+	found := false
+
+	for _, bootstrap := range bm.bootstraps {
+		if providerName != "" && bootstrap.GetProviderName() != providerName {
+			continue
+		}
+		found = true
+
+		ping := ProviderPing{Name: bootstrap.GetProviderName()}
+		if !bootstrap.IsConfigured() {
+			if providerName != "" {
+				ping.Error = "provider is not configured"
+				pings = append(pings, ping)
+			}
+			continue
+		}
+
+		checker, ok := bootstrap.(domain.ConnectivityChecker)
+		if !ok {
+			provider, err := bootstrap.CreateProvider(ctx)
+			if err != nil {
+				ping.Error = fmt.Sprintf("failed to create provider: %v", err)
+				pings = append(pings, ping)
+				continue
+			}
+
+			checker, ok = provider.(domain.ConnectivityChecker)
+			if !ok {
+				ping.Error = "provider does not support connectivity checks"
+				pings = append(pings, ping)
+				continue
+			}
+		}
+
+		start := time.Now()
+		result, err := checker.CheckConnectivity(ctx)
+		ping.Latency = time.Since(start)
+		if err != nil {
+			ping.Error = err.Error()
+		} else {
+			ping.ClientIP = result.ClientIP
+		}
+
+		pings = append(pings, ping)
+	}
+
+	if providerName != "" && !found {
+		return nil, fmt.Errorf("unknown provider: %s", providerName)
+	}
+
+	return pings, nil
+}
@@ -0,0 +1,97 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+// cachedCertificate is a retrieved certificate kept until expiresAt
+type cachedCertificate struct {
+	certChain  []byte
+	privateKey []byte
+	certInfo   *domain.CertInfo
+	expiresAt  time.Time
+}
+
+// SetCertificateCacheTTL keeps retrieved certificates for ttl, so later retrievals of the
+// same domain are answered without calling its provider. A certificate is never kept past
+// its expiry, and is dropped when its domain moves to another provider or its provider is
+// reloaded. Zero, the default, disables the cache and drops the cached certificates.
+func (r *CertificateProviderRegistry) SetCertificateCacheTTL(ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cacheTTL = ttl
+	r.certCache = make(map[string]*cachedCertificate)
+}
+
+// retrieve returns the certificate of a domain from the cache, or else from its provider,
+// and remembers its details. certInfo is nil if the chain cannot be parsed.
+func (r *CertificateProviderRegistry) retrieve(ctx context.Context, domainName string) (certChain, privateKey []byte, certInfo *domain.CertInfo, err error) {
+	if entry := r.cachedCertificate(domainName, time.Now()); entry != nil {
+		info := *entry.certInfo
+		return bytes.Clone(entry.certChain), bytes.Clone(entry.privateKey), &info, nil
+	}
+
+	provider, err := r.GetProviderForDomain(domainName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	certChain, privateKey, err = provider.RetrieveCertificate(ctx, domainName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Unparsable chains are returned, but neither remembered nor cached
+	certInfo, err = parseCertInfo(domainName, certChain)
+	if err != nil {
+		return certChain, privateKey, nil, nil
+	}
+	r.recordCertInfo(certInfo)
+	r.cacheCertificate(provider.GetProviderName(), certChain, privateKey, certInfo)
+
+	return certChain, privateKey, certInfo, nil
+}
+
+// cachedCertificate returns the cached certificate of a domain, or nil if there is none
+// or it expired before now
+func (r *CertificateProviderRegistry) cachedCertificate(domainName string, now time.Time) *cachedCertificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, exists := r.certCache[domainName]
+	if !exists || !now.Before(entry.expiresAt) {
+		return nil
+	}
+	return entry
+}
+
+// cacheCertificate caches a certificate retrieved from the named provider. Nothing is cached
+// when the cache is disabled, or when the domain was removed or moved to another provider
+// during the retrieval.
+func (r *CertificateProviderRegistry) cacheCertificate(providerName string, certChain, privateKey []byte, certInfo *domain.CertInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cacheTTL <= 0 {
+		return
+	}
+	if current, exists := r.domainMap[certInfo.Domain]; !exists || current.GetProviderName() != providerName {
+		return
+	}
+
+	expiresAt := certInfo.RetrievedAt.Add(r.cacheTTL)
+	if certInfo.NotAfter.Before(expiresAt) {
+		expiresAt = certInfo.NotAfter
+	}
+	r.certCache[certInfo.Domain] = &cachedCertificate{
+		certChain:  bytes.Clone(certChain),
+		privateKey: bytes.Clone(privateKey),
+		certInfo:   certInfo,
+		expiresAt:  expiresAt,
+	}
+}
@@ -0,0 +1,119 @@
+package registry
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/goccy/go-yaml"
+)
+
+// BootstrapFactory creates the bootstrap of an additional provider instance declared
+// in a providers config file
+type BootstrapFactory func(cfg domain.ProviderConfig) (domain.ProviderBootstrap, error)
+
+// LoadProvidersConfig reads and validates a providers config file
+func LoadProvidersConfig(path string) (*domain.ProvidersConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read providers config: %w", err)
+	}
+
+	cfg, err := ParseProvidersConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid providers config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// ParseProvidersConfig parses a providers config document. Unknown fields are rejected,
+// and the result is checked with ValidateProvidersConfig.
+func ParseProvidersConfig(data []byte) (*domain.ProvidersConfig, error) {
+	cfg := &domain.ProvidersConfig{}
+	if err := yaml.UnmarshalWithOptions(data, cfg, yaml.DisallowUnknownField()); err != nil {
+		return nil, fmt.Errorf("%s", yaml.FormatError(err, false, true))
+	}
+
+	if err := ValidateProvidersConfig(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ValidateProvidersConfig checks that every provider has a type and that instance names are unique
+func ValidateProvidersConfig(cfg *domain.ProvidersConfig) error {
+	names := make(map[string]bool, len(cfg.Providers))
+	for i, provider := range cfg.Providers {
+		if strings.TrimSpace(provider.Type) == "" {
+			return fmt.Errorf("provider #%d: type is required", i+1)
+		}
+
+		name := provider.InstanceName()
+		if names[name] {
+			return fmt.Errorf("provider #%d: duplicate provider name %q; give each instance a unique name", i+1, name)
+		}
+		names[name] = true
+	}
+
+	return nil
+}
+
+// ApplyProvidersConfig configures bootstraps from a providers config. An entry whose name
+// matches a registered bootstrap (e.g. "porkbun") configures that bootstrap, so its flags and
+// environment variables still take precedence. Other entries add a bootstrap created by the
+// factory for their type. Unknown types are rejected before anything is changed.
+func (bm *BootstrapManager) ApplyProvidersConfig(cfg *domain.ProvidersConfig, factories map[string]BootstrapFactory) error {
+	for i, provider := range cfg.Providers {
+		if _, ok := factories[provider.Type]; !ok {
+			return fmt.Errorf("provider #%d (%s): unknown provider type %q (supported: %s)",
+				i+1, provider.InstanceName(), provider.Type, strings.Join(factoryTypes(factories), ", "))
+		}
+	}
+
+	for _, provider := range cfg.Providers {
+		if existing := bm.findBootstrap(provider.InstanceName()); existing != nil {
+			configurable, ok := existing.(domain.ConfigurableBootstrap)
+			if !ok {
+				return fmt.Errorf("provider %s cannot be configured from a providers config", provider.InstanceName())
+			}
+			if configurable.ProviderType() != provider.Type {
+				return fmt.Errorf("provider name %q is already used by a %s provider", provider.InstanceName(), configurable.ProviderType())
+			}
+			if err := configurable.ApplyConfig(provider); err != nil {
+				return err
+			}
+			continue
+		}
+
+		bootstrap, err := factories[provider.Type](provider)
+		if err != nil {
+			return err
+		}
+		bm.RegisterBootstrap(bootstrap)
+	}
+
+	return nil
+}
+
+// findBootstrap returns the registered bootstrap with the given provider name, or nil
+func (bm *BootstrapManager) findBootstrap(name string) domain.ProviderBootstrap {
+	for _, bootstrap := range bm.bootstraps {
+		if bootstrap.GetProviderName() == name {
+			return bootstrap
+		}
+	}
+	return nil
+}
+
+// factoryTypes returns the provider types with a factory, sorted
+func factoryTypes(factories map[string]BootstrapFactory) []string {
+	types := make([]string, 0, len(factories))
+	for providerType := range factories {
+		types = append(types, providerType)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,30 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+// readOnlyProvider wraps a provider so its domains can be listed but no certificate
+// (and thus no private key) can be retrieved
+type readOnlyProvider struct {
+	domain.CertificateProvider
+}
+
+// NewReadOnlyProvider returns provider with certificate retrieval disabled.
+// Retrieval fails with an error wrapping domain.ErrReadOnly; listing is unaffected.
+func NewReadOnlyProvider(provider domain.CertificateProvider) domain.CertificateProvider {
+	return &readOnlyProvider{CertificateProvider: provider}
+}
+
+// RefreshDomainInfo refreshes the wrapped provider; read-only mode only restricts retrieval
+func (p *readOnlyProvider) RefreshDomainInfo(ctx context.Context, domainName string) error {
+	return refreshDomainInfo(ctx, p.CertificateProvider, domainName)
+}
+
+// RetrieveCertificate always fails with domain.ErrReadOnly
+func (p *readOnlyProvider) RetrieveCertificate(ctx context.Context, domainName string) ([]byte, []byte, error) {
+	return nil, nil, fmt.Errorf("cannot retrieve certificate for %s: %w", domainName, domain.ErrReadOnly)
+}
@@ -1,16 +1,27 @@
 package registry
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/utils"
 )
 
 // CertificateProviderRegistry manages all registered certificate providers
 type CertificateProviderRegistry struct {
 	providers map[string]domain.CertificateProvider // key: provider name
 	domainMap map[string]domain.CertificateProvider // key: domain name
+	overrides map[string]string                     // key: domain name, value: provider name
+	certInfos map[string]*domain.CertInfo           // key: domain name, last retrieved certificate
+	certCache map[string]*cachedCertificate         // key: domain name, see SetCertificateCacheTTL
+	cacheTTL  time.Duration                         // zero disables the certificate cache
+	tags      map[string][]string                   // key: domain name or glob pattern, value: tags
+	priority  map[string]int                        // key: provider name, value: rank, lower wins
 	mu        sync.RWMutex
 }
 
@@ -19,9 +30,42 @@ func NewCertificateProviderRegistry() *CertificateProviderRegistry {
 	return &CertificateProviderRegistry{
 		providers: make(map[string]domain.CertificateProvider),
 		domainMap: make(map[string]domain.CertificateProvider),
+		overrides: make(map[string]string),
+		certInfos: make(map[string]*domain.CertInfo),
+		certCache: make(map[string]*cachedCertificate),
 	}
 }
 
+// SetProviderPriority ranks providers, highest priority first, so a domain listed by
+// several ranked providers resolves to the highest-ranked one instead of being rejected as
+// a duplicate. A domain override still takes precedence. Set it before registering providers.
+func (r *CertificateProviderRegistry) SetProviderPriority(providerNames []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.priority = make(map[string]int, len(providerNames))
+	for i, name := range providerNames {
+		if _, exists := r.priority[name]; !exists {
+			r.priority[name] = i
+		}
+	}
+	for domainName := range r.domainMap {
+		r.resolveDomainLocked(domainName)
+	}
+}
+
+// sharedLocked reports whether providerName may list domainName although existing already
+// manages it: the domain has an override, or both providers are ranked by priority.
+// The caller holds the lock.
+func (r *CertificateProviderRegistry) sharedLocked(domainName, providerName string, existing domain.CertificateProvider) bool {
+	if r.overrides[domainName] != "" {
+		return true
+	}
+	_, ranked := r.priority[providerName]
+	_, existingRanked := r.priority[existing.GetProviderName()]
+	return ranked && existingRanked
+}
+
 // Register registers a new certificate provider
 func (r *CertificateProviderRegistry) Register(provider domain.CertificateProvider) error {
 	r.mu.Lock()
@@ -37,27 +81,234 @@ func (r *CertificateProviderRegistry) Register(provider domain.CertificateProvid
 		return fmt.Errorf("provider %s configuration invalid: %w", providerName, err)
 	}
 
-	r.providers[providerName] = provider
-
+	// Check for overlaps before mutating so a rejected provider leaves no partial state.
+	// Domains with an override or ranked providers may be claimed by several providers.
 	for _, domain := range provider.GetDomains() {
-		if existingProvider, exists := r.domainMap[domain]; exists {
+		if existingProvider, exists := r.domainMap[domain]; exists && !r.sharedLocked(domain, providerName, existingProvider) {
 			return fmt.Errorf("domain %s is already managed by provider %s",
 				domain, existingProvider.GetProviderName())
 		}
-		r.domainMap[domain] = provider
 	}
 
+	r.providers[providerName] = provider
+
+	// A provider listing the same domain twice is deduplicated here
+	for _, domain := range provider.GetDomains() {
+		r.resolveDomainLocked(domain)
+	}
+
+	return nil
+}
+
+// RegisterDomainOverride makes domainName resolve to the named provider even if other
+// providers also list it, for example when one domain's certificates live in a second
+// account. The provider may be registered later, but once registered it must list the domain.
+func (r *CertificateProviderRegistry) RegisterDomainOverride(domainName, providerName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if provider, exists := r.providers[providerName]; exists && !claimsDomain(provider, domainName) {
+		return fmt.Errorf("provider %s does not manage domain %s", providerName, domainName)
+	}
+
+	r.overrides[domainName] = providerName
+	r.resolveDomainLocked(domainName)
+	return nil
+}
+
+// RemoveDomainOverride drops the override of domainName. It fails if several registered
+// providers list the domain and they are not all ranked by priority, since it would no
+// longer resolve to a single provider.
+func (r *CertificateProviderRegistry) RemoveDomainOverride(domainName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.overrides[domainName]; !exists {
+		return fmt.Errorf("no override registered for domain %s", domainName)
+	}
+
+	if claimants := r.claimantsLocked(domainName); len(claimants) > 1 && !r.allRankedLocked(claimants) {
+		names := make([]string, len(claimants))
+		for i, provider := range claimants {
+			names[i] = provider.GetProviderName()
+		}
+		return fmt.Errorf("domain %s is listed by providers %s; unregister all but one before removing its override",
+			domainName, strings.Join(names, ", "))
+	}
+
+	delete(r.overrides, domainName)
+	r.resolveDomainLocked(domainName)
+	return nil
+}
+
+// claimantsLocked returns the registered providers listing domainName, sorted by name;
+// the caller holds the lock
+func (r *CertificateProviderRegistry) claimantsLocked(domainName string) []domain.CertificateProvider {
+	var claimants []domain.CertificateProvider
+	for _, provider := range r.providers {
+		if claimsDomain(provider, domainName) {
+			claimants = append(claimants, provider)
+		}
+	}
+	sort.Slice(claimants, func(i, j int) bool {
+		return claimants[i].GetProviderName() < claimants[j].GetProviderName()
+	})
+	return claimants
+}
+
+// allRankedLocked reports whether every provider has a priority; the caller holds the lock
+func (r *CertificateProviderRegistry) allRankedLocked(providers []domain.CertificateProvider) bool {
+	for _, provider := range providers {
+		if _, ranked := r.priority[provider.GetProviderName()]; !ranked {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveDomainLocked points domainName at its override provider when that provider is
+// registered and lists it, otherwise at the highest-priority provider listing it, and drops
+// the domain when no provider lists it; the caller holds the lock
+func (r *CertificateProviderRegistry) resolveDomainLocked(domainName string) {
+	claimants := r.claimantsLocked(domainName)
+	if len(claimants) == 0 {
+		delete(r.domainMap, domainName)
+		return
+	}
+
+	resolved := claimants[0]
+	for _, provider := range claimants[1:] {
+		rank, ranked := r.priority[provider.GetProviderName()]
+		resolvedRank, resolvedRanked := r.priority[resolved.GetProviderName()]
+		if ranked && (!resolvedRanked || rank < resolvedRank) {
+			resolved = provider
+		}
+	}
+	if overrideName, exists := r.overrides[domainName]; exists {
+		for _, provider := range claimants {
+			if provider.GetProviderName() == overrideName {
+				resolved = provider
+				break
+			}
+		}
+	}
+
+	if current, exists := r.domainMap[domainName]; exists && current.GetProviderName() != resolved.GetProviderName() {
+		// The remembered certificate came from the previous provider
+		delete(r.certInfos, domainName)
+		delete(r.certCache, domainName)
+	}
+	r.domainMap[domainName] = resolved
+}
+
+// claimsDomain reports whether provider lists domainName among its domains
+func claimsDomain(provider domain.CertificateProvider, domainName string) bool {
+	for _, d := range provider.GetDomains() {
+		if d == domainName {
+			return true
+		}
+	}
+	return false
+}
+
+// DomainChanges lists the domains added and removed by a reload
+type DomainChanges struct {
+	Added   []string
+	Removed []string
+}
+
+// IsEmpty reports whether the reload left the managed domains unchanged
+func (c *DomainChanges) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0
+}
+
+// Unregister removes a provider and all domains it manages
+func (r *CertificateProviderRegistry) Unregister(providerName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.providers[providerName]; !exists {
+		return fmt.Errorf("provider not found: %s", providerName)
+	}
+
+	r.removeProviderLocked(providerName)
 	return nil
 }
 
-// GetProviderForDomain returns the provider managing the specified domain
-func (r *CertificateProviderRegistry) GetProviderForDomain(domain string) (domain.CertificateProvider, error) {
+// Reload replaces the registered provider with the same name, or registers it if it
+// is new, and reports which domains were added and removed. The swap is atomic:
+// on error the previous provider stays registered.
+func (r *CertificateProviderRegistry) Reload(provider domain.CertificateProvider) (*DomainChanges, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	providerName := provider.GetProviderName()
+
+	if err := provider.ValidateConfiguration(); err != nil {
+		return nil, fmt.Errorf("provider %s configuration invalid: %w", providerName, err)
+	}
+
+	newDomains := make(map[string]bool)
+	for _, domain := range provider.GetDomains() {
+		if existingProvider, exists := r.domainMap[domain]; exists && existingProvider.GetProviderName() != providerName && !r.sharedLocked(domain, providerName, existingProvider) {
+			return nil, fmt.Errorf("domain %s is already managed by provider %s",
+				domain, existingProvider.GetProviderName())
+		}
+		newDomains[domain] = true
+	}
+
+	oldDomains := make(map[string]bool)
+	if oldProvider, exists := r.providers[providerName]; exists {
+		for _, domain := range oldProvider.GetDomains() {
+			oldDomains[domain] = true
+		}
+		r.removeProviderLocked(providerName)
+	}
+
+	changes := &DomainChanges{}
+	for domain := range newDomains {
+		if !oldDomains[domain] {
+			changes.Added = append(changes.Added, domain)
+		}
+	}
+	for domain := range oldDomains {
+		if !newDomains[domain] {
+			changes.Removed = append(changes.Removed, domain)
+		}
+	}
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+
+	r.providers[providerName] = provider
+	for domain := range newDomains {
+		r.resolveDomainLocked(domain)
+	}
+
+	return changes, nil
+}
+
+// removeProviderLocked removes a provider and its domains; domains also listed by another
+// provider fall back to it. The caller holds the lock.
+func (r *CertificateProviderRegistry) removeProviderLocked(providerName string) {
+	delete(r.providers, providerName)
+	for domain, provider := range r.domainMap {
+		if provider.GetProviderName() == providerName {
+			delete(r.certInfos, domain)
+			delete(r.certCache, domain)
+			r.resolveDomainLocked(domain)
+		}
+	}
+}
+
+// GetProviderForDomain returns the provider managing the specified domain.
+// It fails with an error wrapping domain.ErrDomainNotManaged if no provider manages it.
+func (r *CertificateProviderRegistry) GetProviderForDomain(domainName string) (domain.CertificateProvider, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	provider, exists := r.domainMap[domain]
+	provider, exists := r.domainMap[domainName]
 	if !exists {
-		return nil, fmt.Errorf("no provider found for domain: %s", domain)
+		return nil, fmt.Errorf("no provider found for %s: %w", domainName, domain.ErrDomainNotManaged)
 	}
 
 	return provider, nil
@@ -88,7 +339,9 @@ func (r *CertificateProviderRegistry) ListProviders() []string {
 	return names
 }
 
-// ListDomains returns all managed domains
+// ListDomains returns all managed domains.
+// Each domain appears once and the result is sorted lexically, so repeated calls
+// return the same order.
 func (r *CertificateProviderRegistry) ListDomains() []string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -97,20 +350,161 @@ func (r *CertificateProviderRegistry) ListDomains() []string {
 	for domain := range r.domainMap {
 		domains = append(domains, domain)
 	}
+	sort.Strings(domains)
 	return domains
 }
 
-// RetrieveCertificate retrieves the certificate for the specified domain
-func (r *CertificateProviderRegistry) RetrieveCertificate(domain string) ([]byte, []byte, error) {
-	provider, err := r.GetProviderForDomain(domain)
+// RetrieveCertificate retrieves the certificate for the specified domain, from the cache
+// if SetCertificateCacheTTL enabled it. Provider errors, including domain.ErrDomainNotManaged,
+// are returned unchanged.
+func (r *CertificateProviderRegistry) RetrieveCertificate(ctx context.Context, domain string) ([]byte, []byte, error) {
+	certChain, privateKey, _, err := r.retrieve(ctx, domain)
 	if err != nil {
 		return nil, nil, err
 	}
+	return certChain, privateKey, nil
+}
+
+// GetCertInfo retrieves the current certificate for a domain, like RetrieveCertificate,
+// and describes its leaf. The result is also remembered, so later GetDomainInfo calls include it.
+func (r *CertificateProviderRegistry) GetCertInfo(ctx context.Context, domainName string) (*domain.CertInfo, error) {
+	certChain, _, certInfo, err := r.retrieve(ctx, domainName)
+	if err != nil {
+		return nil, err
+	}
+	if certInfo == nil {
+		_, err := parseCertInfo(domainName, certChain)
+		return nil, fmt.Errorf("failed to parse certificate for %s: %w", domainName, err)
+	}
+	return certInfo, nil
+}
+
+// RefreshDomainInfo asks the provider managing the domain to re-query its info, so later
+// GetDomainInfo calls reflect changes made since startup. It fails with an error wrapping
+// domain.ErrRefreshUnsupported if the provider does not implement domain.DomainInfoRefresher.
+func (r *CertificateProviderRegistry) RefreshDomainInfo(ctx context.Context, domainName string) error {
+	provider, err := r.GetProviderForDomain(domainName)
+	if err != nil {
+		return err
+	}
+
+	// The provider guards its own cache; the registry lock is not held during the API call
+	return refreshDomainInfo(ctx, provider, domainName)
+}
+
+// refreshDomainInfo refreshes the domain if the provider supports it
+func refreshDomainInfo(ctx context.Context, provider domain.CertificateProvider, domainName string) error {
+	refresher, ok := provider.(domain.DomainInfoRefresher)
+	if !ok {
+		return fmt.Errorf("cannot refresh %s with provider %s: %w", domainName, provider.GetProviderName(), domain.ErrRefreshUnsupported)
+	}
+	return refresher.RefreshDomainInfo(ctx, domainName)
+}
+
+// parseCertInfo describes the leaf certificate of a PEM chain
+func parseCertInfo(domainName string, certChain []byte) (*domain.CertInfo, error) {
+	leaf, err := utils.ParseLeafCertificate(certChain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CertInfo{
+		Domain:            domainName,
+		Serial:            utils.CertificateSerial(leaf),
+		FingerprintSHA256: utils.CertificateFingerprintSHA256(leaf),
+		Subject:           leaf.Subject.CommonName,
+		Issuer:            leaf.Issuer.CommonName,
+		NotBefore:         leaf.NotBefore,
+		NotAfter:          leaf.NotAfter,
+		RetrievedAt:       time.Now(),
+	}, nil
+}
+
+// recordCertInfo stores the certificate details of a domain that is still managed
+func (r *CertificateProviderRegistry) recordCertInfo(certInfo *domain.CertInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.domainMap[certInfo.Domain]; exists {
+		r.certInfos[certInfo.Domain] = certInfo
+	}
+}
+
+// withCertInfoLocked returns a copy of info with the remembered certificate details
+// filled in; the caller holds the lock
+func (r *CertificateProviderRegistry) withCertInfoLocked(info domain.Info) domain.Info {
+	if certInfo, exists := r.certInfos[info.Name]; exists {
+		info.CertSerial = certInfo.Serial
+		info.CertFingerprintSHA256 = certInfo.FingerprintSHA256
+		info.CertNotAfter = certInfo.NotAfter
+	}
+	return info
+}
+
+// SetDomainTags sets the tags of the domains matching each name or glob pattern,
+// replacing any previously set; see domain.TagsConfig
+func (r *CertificateProviderRegistry) SetDomainTags(tags map[string][]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	return provider.RetrieveCertificate(domain)
+	r.tags = tags
 }
 
-// GetDomainInfo returns detailed information about a specific domain
+// tagsLocked returns the sorted tags of every entry the domain matches; the caller holds the lock
+func (r *CertificateProviderRegistry) tagsLocked(domainName string) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for pattern, patternTags := range r.tags {
+		// Patterns are validated when the tags config is parsed
+		if matched, _ := utils.MatchGlob(pattern, domainName); !matched {
+			continue
+		}
+		for _, tag := range patternTags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// ListDomainsWithTags returns the managed domains carrying every given tag, sorted
+func (r *CertificateProviderRegistry) ListDomainsWithTags(tags ...string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	domains := make([]string, 0)
+	for domainName := range r.domainMap {
+		if hasAllTags(r.tagsLocked(domainName), tags) {
+			domains = append(domains, domainName)
+		}
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+// hasAllTags reports whether every wanted tag is among tags
+func hasAllTags(tags, wanted []string) bool {
+	for _, want := range wanted {
+		found := false
+		for _, tag := range tags {
+			if tag == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// GetDomainInfo returns detailed information about a specific domain.
+// Certificate fields are filled in once a certificate has been retrieved through the registry,
+// and tags from SetDomainTags.
 func (r *CertificateProviderRegistry) GetDomainInfo(domainName string) *domain.Info {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -120,7 +514,14 @@ func (r *CertificateProviderRegistry) GetDomainInfo(domainName string) *domain.I
 		return nil
 	}
 
-	return provider.GetDomainInfo(domainName)
+	info := provider.GetDomainInfo(domainName)
+	if info == nil {
+		return nil
+	}
+
+	withCert := r.withCertInfoLocked(*info)
+	withCert.Tags = r.tagsLocked(domainName)
+	return &withCert
 }
 
 // ListAllDomainInfo returns detailed information for all managed domains
@@ -131,8 +532,15 @@ func (r *CertificateProviderRegistry) ListAllDomainInfo() []domain.Info {
 	var allInfos []domain.Info
 
 	for _, provider := range r.providers {
-		infos := provider.ListDomainInfo()
-		allInfos = append(allInfos, infos...)
+		for _, info := range provider.ListDomainInfo() {
+			// A domain listed by several providers is reported for the one it resolves to
+			if resolved, exists := r.domainMap[info.Name]; !exists || resolved.GetProviderName() != provider.GetProviderName() {
+				continue
+			}
+			info = r.withCertInfoLocked(info)
+			info.Tags = r.tagsLocked(info.Name)
+			allInfos = append(allInfos, info)
+		}
 	}
 
 	return allInfos
@@ -1,9 +1,19 @@
 package registry
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/cert/providers/mock"
 	"github.com/dh-kam/go-cert-provider/cert/providers/porkbun"
+	"github.com/spf13/cobra"
 )
 
 func TestRegistryRegisterProvider(t *testing.T) {
@@ -72,10 +82,215 @@ func TestRegistryDuplicateProvider(t *testing.T) {
 }
 
 func TestRegistryDuplicateDomain(t *testing.T) {
-	// For this test, we need to create a different provider type
-	// Since we only have porkbun, we'll skip this test for now
-	// In a real scenario, you'd have multiple provider implementations
-	t.Skip("Skipping duplicate domain test - requires multiple provider types")
+	registry := NewCertificateProviderRegistry()
+
+	if err := registry.Register(&fakeProvider{name: "alpha", domains: []string{"example.com"}}); err != nil {
+		t.Fatalf("Failed to register first provider: %v", err)
+	}
+
+	err := registry.Register(&fakeProvider{name: "beta", domains: []string{"other.com", "example.com"}})
+	if err == nil {
+		t.Fatal("Expected error when registering overlapping domain, got nil")
+	}
+
+	// The rejected provider must not leave any partial state behind
+	if _, err := registry.GetProvider("beta"); err == nil {
+		t.Error("Rejected provider should not be registered")
+	}
+
+	if _, err := registry.GetProviderForDomain("other.com"); err == nil {
+		t.Error("Domains of a rejected provider should not be registered")
+	}
+}
+
+func TestRegistryDuplicateDomainAcrossRealProviders(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+
+	if err := registry.Register(porkbun.NewProvider("test-api-key", "test-secret", []string{"example.com"})); err != nil {
+		t.Fatalf("Failed to register porkbun provider: %v", err)
+	}
+
+	if err := registry.Register(mock.NewProvider([]string{"example.com"}, nil, nil)); err == nil {
+		t.Fatal("Expected error when mock provider claims a porkbun domain, got nil")
+	}
+
+	if err := registry.Register(mock.NewProvider([]string{"mock.test"}, nil, nil)); err != nil {
+		t.Fatalf("Failed to register mock provider with a distinct domain: %v", err)
+	}
+}
+
+func TestRegistryDomainOverridePrecedence(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+
+	// The override may be registered before its provider
+	if err := registry.RegisterDomainOverride("shared.com", "personal"); err != nil {
+		t.Fatalf("Failed to register override: %v", err)
+	}
+
+	if err := registry.Register(&fakeProvider{name: "work", domains: []string{"work.com", "shared.com"}}); err != nil {
+		t.Fatalf("Failed to register work provider: %v", err)
+	}
+	if err := registry.Register(&fakeProvider{name: "personal", domains: []string{"shared.com", "personal.com"}}); err != nil {
+		t.Fatalf("Expected overridden domain to be claimable by a second provider, got: %v", err)
+	}
+
+	assertProvider := func(domainName, expected string) {
+		t.Helper()
+		provider, err := registry.GetProviderForDomain(domainName)
+		if err != nil {
+			t.Fatalf("Failed to get provider for %s: %v", domainName, err)
+		}
+		if provider.GetProviderName() != expected {
+			t.Errorf("Expected %s to resolve to %s, got %s", domainName, expected, provider.GetProviderName())
+		}
+	}
+
+	assertProvider("shared.com", "personal")
+	assertProvider("work.com", "work")
+
+	if domains := registry.ListDomains(); len(domains) != 3 {
+		t.Errorf("Expected 3 distinct domains, got %v", domains)
+	}
+	for _, info := range registry.ListAllDomainInfo() {
+		if info.Name == "shared.com" && info.Provider != "personal" {
+			t.Errorf("Expected shared.com to be listed for personal, got %s", info.Provider)
+		}
+	}
+	if infos := registry.ListAllDomainInfo(); len(infos) != 3 {
+		t.Errorf("Expected shared.com to be listed once, got %v", infos)
+	}
+
+	// Re-pointing the override switches providers
+	if err := registry.RegisterDomainOverride("shared.com", "work"); err != nil {
+		t.Fatalf("Failed to re-point override: %v", err)
+	}
+	assertProvider("shared.com", "work")
+
+	// A registered provider must list the domain
+	if err := registry.RegisterDomainOverride("work.com", "personal"); err == nil {
+		t.Error("Expected error for an override to a provider that does not list the domain")
+	}
+
+	// Unregistering the override target falls back to the remaining provider
+	if err := registry.Unregister("work"); err != nil {
+		t.Fatalf("Failed to unregister work provider: %v", err)
+	}
+	assertProvider("shared.com", "personal")
+}
+
+func TestRegistryRemoveDomainOverride(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+
+	if err := registry.Register(&fakeProvider{name: "work", domains: []string{"shared.com"}}); err != nil {
+		t.Fatalf("Failed to register work provider: %v", err)
+	}
+	if err := registry.RegisterDomainOverride("shared.com", "personal"); err != nil {
+		t.Fatalf("Failed to register override: %v", err)
+	}
+	if err := registry.Register(&fakeProvider{name: "personal", domains: []string{"shared.com"}}); err != nil {
+		t.Fatalf("Failed to register personal provider: %v", err)
+	}
+
+	// Removing the override would leave two providers claiming the domain
+	if err := registry.RemoveDomainOverride("shared.com"); err == nil {
+		t.Fatal("Expected error removing an override that resolves a conflict")
+	}
+
+	if err := registry.Unregister("personal"); err != nil {
+		t.Fatalf("Failed to unregister personal provider: %v", err)
+	}
+	if err := registry.RemoveDomainOverride("shared.com"); err != nil {
+		t.Fatalf("Failed to remove override: %v", err)
+	}
+	if err := registry.RemoveDomainOverride("shared.com"); err == nil {
+		t.Error("Expected error removing a missing override")
+	}
+
+	// Without the override, a second claim is a conflict again
+	if err := registry.Register(&fakeProvider{name: "personal", domains: []string{"shared.com"}}); err == nil {
+		t.Error("Expected conflict after the override was removed")
+	}
+}
+
+func TestRegistryProviderPriority(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	registry.SetProviderPriority([]string{"porkbun2", "porkbun"})
+
+	if err := registry.Register(&fakeProvider{name: "porkbun", domains: []string{"shared.com", "old.com"}}); err != nil {
+		t.Fatalf("Failed to register porkbun provider: %v", err)
+	}
+	if err := registry.Register(&fakeProvider{name: "porkbun2", domains: []string{"shared.com", "new.com"}}); err != nil {
+		t.Fatalf("Expected a ranked provider to share a domain, got: %v", err)
+	}
+
+	for domainName, want := range map[string]string{"shared.com": "porkbun2", "old.com": "porkbun", "new.com": "porkbun2"} {
+		provider, err := registry.GetProviderForDomain(domainName)
+		if err != nil {
+			t.Fatalf("Failed to get provider for %s: %v", domainName, err)
+		}
+		if provider.GetProviderName() != want {
+			t.Errorf("Expected %s to resolve to %s, got %s", domainName, want, provider.GetProviderName())
+		}
+	}
+	if infos := registry.ListAllDomainInfo(); len(infos) != 3 {
+		t.Errorf("Expected shared.com to be listed once, got %v", infos)
+	}
+
+	// An unranked provider still conflicts
+	if err := registry.Register(&fakeProvider{name: "digitalocean", domains: []string{"shared.com"}}); err == nil {
+		t.Error("Expected conflict for a provider without a priority")
+	}
+
+	// Dropping the preferred provider falls back to the next one
+	if err := registry.Unregister("porkbun2"); err != nil {
+		t.Fatalf("Failed to unregister porkbun2 provider: %v", err)
+	}
+	provider, err := registry.GetProviderForDomain("shared.com")
+	if err != nil || provider.GetProviderName() != "porkbun" {
+		t.Errorf("Expected shared.com to fall back to porkbun, got %v, %v", provider, err)
+	}
+}
+
+func TestRegistryDuplicateDomainWithoutPriority(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+
+	if err := registry.Register(&fakeProvider{name: "porkbun", domains: []string{"shared.com"}}); err != nil {
+		t.Fatalf("Failed to register porkbun provider: %v", err)
+	}
+
+	// Ranking only one of the providers is not enough to resolve the conflict
+	registry.SetProviderPriority([]string{"porkbun2"})
+	if err := registry.Register(&fakeProvider{name: "porkbun2", domains: []string{"shared.com"}}); err == nil {
+		t.Fatal("Expected conflict when the existing provider has no priority")
+	}
+	if _, err := registry.Reload(&fakeProvider{name: "porkbun2", domains: []string{"shared.com"}}); err == nil {
+		t.Error("Expected conflict on reload when the existing provider has no priority")
+	}
+}
+
+func TestRegistryListDomainsSortedAndDeduplicated(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+
+	if err := registry.Register(&fakeProvider{name: "alpha", domains: []string{"zeta.com", "alpha.com", "zeta.com"}}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	if err := registry.Register(&fakeProvider{name: "beta", domains: []string{"mid.com", "beta.com"}}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	expected := []string{"alpha.com", "beta.com", "mid.com", "zeta.com"}
+
+	for i := 0; i < 20; i++ {
+		domains := registry.ListDomains()
+		if len(domains) != len(expected) {
+			t.Fatalf("Expected %v, got %v", expected, domains)
+		}
+		for j := range expected {
+			if domains[j] != expected[j] {
+				t.Fatalf("Expected %v, got %v", expected, domains)
+			}
+		}
+	}
 }
 
 func TestBootstrapManager(t *testing.T) {
@@ -95,3 +310,843 @@ func TestBootstrapManager(t *testing.T) {
 	// Verify the manager is properly initialized
 	_ = registry // Use the registry variable
 }
+
+type fakeProvider struct {
+	name    string
+	domains []string
+	pingErr error
+}
+
+func (p *fakeProvider) GetProviderName() string { return p.name }
+
+func (p *fakeProvider) GetDomains() []string { return p.domains }
+
+func (p *fakeProvider) GetDomainInfo(domainName string) *domain.Info {
+	for _, d := range p.domains {
+		if d == domainName {
+			return &domain.Info{Name: d, Provider: p.name, Status: "ACTIVE"}
+		}
+	}
+	return nil
+}
+
+func (p *fakeProvider) ListDomainInfo() []domain.Info {
+	infos := make([]domain.Info, 0, len(p.domains))
+	for _, d := range p.domains {
+		infos = append(infos, *p.GetDomainInfo(d))
+	}
+	return infos
+}
+
+func (p *fakeProvider) RetrieveCertificate(ctx context.Context, domainName string) ([]byte, []byte, error) {
+	return []byte("cert"), []byte("key"), nil
+}
+
+func (p *fakeProvider) ValidateConfiguration() error { return nil }
+
+func (p *fakeProvider) CheckConnectivity(ctx context.Context) (*domain.ConnectivityResult, error) {
+	if p.pingErr != nil {
+		return nil, p.pingErr
+	}
+	return &domain.ConnectivityResult{ClientIP: "192.0.2.1"}, nil
+}
+
+type fakeBootstrap struct {
+	name       string
+	configured bool
+	domains    []string
+	createErr  error
+	pingErr    error
+	delay      time.Duration
+}
+
+func (b *fakeBootstrap) GetProviderName() string { return b.name }
+
+func (b *fakeBootstrap) RegisterFlags(cmd *cobra.Command) {}
+
+func (b *fakeBootstrap) IsConfigured() bool { return b.configured }
+
+func (b *fakeBootstrap) CreateProvider(ctx context.Context) (domain.CertificateProvider, error) {
+	time.Sleep(b.delay)
+	if b.createErr != nil {
+		return nil, b.createErr
+	}
+	return &fakeProvider{name: b.name, domains: b.domains, pingErr: b.pingErr}, nil
+}
+
+func TestBootstrapManagerListBootstraps(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	manager := NewBootstrapManager(registry)
+
+	manager.RegisterBootstrap(&fakeBootstrap{name: "alpha", configured: true, domains: []string{"a.com", "b.com"}})
+	manager.RegisterBootstrap(&fakeBootstrap{name: "beta", configured: false})
+
+	if err := manager.InitializeProviders(context.Background()); err != nil {
+		t.Fatalf("Failed to initialize providers: %v", err)
+	}
+
+	statuses := manager.ListBootstraps()
+	if len(statuses) != 2 {
+		t.Fatalf("Expected 2 bootstrap statuses, got %d", len(statuses))
+	}
+
+	alpha := statuses[0]
+	if alpha.Name != "alpha" || !alpha.Configured || !alpha.Registered || alpha.DomainCount != 2 {
+		t.Errorf("Unexpected status for alpha: %+v", alpha)
+	}
+
+	beta := statuses[1]
+	if beta.Name != "beta" || beta.Configured || beta.Registered || beta.DomainCount != 0 {
+		t.Errorf("Unexpected status for beta: %+v", beta)
+	}
+}
+
+func TestBootstrapManagerCheckProviders(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	manager := NewBootstrapManager(registry)
+
+	manager.RegisterBootstrap(&fakeBootstrap{name: "alpha", configured: true, domains: []string{"a.com", "b.com"}})
+	manager.RegisterBootstrap(&fakeBootstrap{name: "beta", configured: true, createErr: fmt.Errorf("bad credentials")})
+	manager.RegisterBootstrap(&fakeBootstrap{name: "gamma", configured: true, domains: []string{"c.com"}, pingErr: fmt.Errorf("unreachable")})
+	manager.RegisterBootstrap(&fakeBootstrap{name: "delta", configured: false})
+
+	checks := manager.CheckProviders(context.Background())
+	if len(checks) != 3 {
+		t.Fatalf("Expected 3 checks for configured providers, got %d", len(checks))
+	}
+
+	if checks[0].Name != "alpha" || !checks[0].OK() || checks[0].DomainCount != 2 || checks[0].ClientIP != "192.0.2.1" {
+		t.Errorf("Unexpected check for alpha: %+v", checks[0])
+	}
+
+	if checks[1].Name != "beta" || checks[1].OK() {
+		t.Errorf("Expected beta to fail creation: %+v", checks[1])
+	}
+
+	if checks[2].Name != "gamma" || checks[2].OK() || checks[2].DomainCount != 1 {
+		t.Errorf("Expected gamma to fail connectivity check: %+v", checks[2])
+	}
+
+	// Checking must not register anything
+	if len(registry.ListProviders()) != 0 {
+		t.Errorf("Expected no registered providers after check, got %v", registry.ListProviders())
+	}
+}
+
+func TestBootstrapManagerPingProviders(t *testing.T) {
+	manager := NewBootstrapManager(NewCertificateProviderRegistry())
+
+	manager.RegisterBootstrap(&fakeBootstrap{name: "alpha", configured: true, domains: []string{"a.com"}})
+	manager.RegisterBootstrap(&fakeBootstrap{name: "beta", configured: true, pingErr: fmt.Errorf("unreachable")})
+	manager.RegisterBootstrap(&fakeBootstrap{name: "gamma", configured: false})
+
+	pings, err := manager.PingProviders(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Failed to ping providers: %v", err)
+	}
+
+	if len(pings) != 2 {
+		t.Fatalf("Expected 2 pings for configured providers, got %d", len(pings))
+	}
+
+	if !pings[0].OK() || pings[0].ClientIP != "192.0.2.1" {
+		t.Errorf("Unexpected ping for alpha: %+v", pings[0])
+	}
+
+	if pings[1].OK() {
+		t.Errorf("Expected beta ping to fail: %+v", pings[1])
+	}
+
+	// Pinging a single unconfigured provider reports it instead of skipping it
+	pings, err = manager.PingProviders(context.Background(), "gamma")
+	if err != nil {
+		t.Fatalf("Failed to ping gamma: %v", err)
+	}
+	if len(pings) != 1 || pings[0].OK() {
+		t.Errorf("Expected gamma to be reported as not configured: %+v", pings)
+	}
+
+	if _, err := manager.PingProviders(context.Background(), "unknown"); err == nil {
+		t.Error("Expected error for unknown provider, got nil")
+	}
+}
+
+func TestBootstrapManagerInitializeProvidersConcurrently(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	manager := NewBootstrapManager(registry)
+
+	delay := 200 * time.Millisecond
+	manager.RegisterBootstrap(&fakeBootstrap{name: "alpha", configured: true, domains: []string{"a.com"}, delay: delay})
+	manager.RegisterBootstrap(&fakeBootstrap{name: "beta", configured: true, domains: []string{"b.com"}, delay: delay})
+
+	start := time.Now()
+	if err := manager.InitializeProviders(context.Background()); err != nil {
+		t.Fatalf("Failed to initialize providers: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	if elapsed >= 2*delay {
+		t.Errorf("Expected concurrent creation to take about %v, took %v", delay, elapsed)
+	}
+
+	providers := registry.ListProviders()
+	if len(providers) != 2 {
+		t.Fatalf("Expected 2 registered providers, got %v", providers)
+	}
+}
+
+func TestBootstrapManagerInitializeProvidersDeadline(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	manager := NewBootstrapManager(registry)
+
+	manager.RegisterBootstrap(&fakeBootstrap{name: "slow", configured: true, domains: []string{"a.com"}, delay: 2 * time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := manager.InitializeProviders(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("Expected initialization to give up at the deadline, took %v", elapsed)
+	}
+}
+
+func TestBootstrapManagerInitializeProvidersReportsAllErrors(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	manager := NewBootstrapManager(registry)
+
+	manager.RegisterBootstrap(&fakeBootstrap{name: "alpha", configured: true, createErr: fmt.Errorf("alpha down")})
+	manager.RegisterBootstrap(&fakeBootstrap{name: "beta", configured: true, domains: []string{"b.com"}})
+	manager.RegisterBootstrap(&fakeBootstrap{name: "gamma", configured: true, createErr: fmt.Errorf("gamma down")})
+
+	err := manager.InitializeProviders(context.Background())
+	if err == nil {
+		t.Fatal("Expected error when providers fail to initialize, got nil")
+	}
+
+	for _, want := range []string{"alpha down", "gamma down"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %q, got %v", want, err)
+		}
+	}
+
+	if len(registry.ListProviders()) != 0 {
+		t.Errorf("Expected no providers registered after a failure, got %v", registry.ListProviders())
+	}
+}
+
+func TestBootstrapManagerInitializeProvidersContinueOnError(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	manager := NewBootstrapManager(registry)
+	manager.SetContinueOnProviderError(true)
+
+	manager.RegisterBootstrap(&fakeBootstrap{name: "alpha", configured: true, createErr: fmt.Errorf("alpha down")})
+	manager.RegisterBootstrap(&fakeBootstrap{name: "beta", configured: true, domains: []string{"b.com"}})
+	manager.RegisterBootstrap(&fakeBootstrap{name: "gamma", configured: true, domains: []string{"b.com"}})
+
+	if err := manager.InitializeProviders(context.Background()); err != nil {
+		t.Fatalf("Expected initialization to continue past failed providers, got: %v", err)
+	}
+
+	if _, err := registry.GetProviderForDomain("b.com"); err != nil {
+		t.Errorf("Healthy provider should be registered: %v", err)
+	}
+
+	failures := manager.FailedProviders()
+	if len(failures) != 2 || failures[0].Name != "alpha" || failures[1].Name != "gamma" {
+		t.Fatalf("Expected alpha and gamma to be reported as failed, got %+v", failures)
+	}
+	if !strings.Contains(failures[0].Error, "alpha down") || !strings.Contains(failures[1].Error, "already managed") {
+		t.Errorf("Unexpected failure reasons: %+v", failures)
+	}
+}
+
+func TestBootstrapManagerInitializeProvidersContinueOnErrorAllFail(t *testing.T) {
+	manager := NewBootstrapManager(NewCertificateProviderRegistry())
+	manager.SetContinueOnProviderError(true)
+
+	manager.RegisterBootstrap(&fakeBootstrap{name: "alpha", configured: true, createErr: fmt.Errorf("alpha down")})
+	manager.RegisterBootstrap(&fakeBootstrap{name: "beta", configured: true, createErr: fmt.Errorf("beta down")})
+
+	err := manager.InitializeProviders(context.Background())
+	if err == nil {
+		t.Fatal("Expected error when no provider comes up, got nil")
+	}
+
+	for _, want := range []string{"alpha down", "beta down"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %q, got %v", want, err)
+		}
+	}
+}
+
+func TestBootstrapManagerInitializeProvidersNoneConfigured(t *testing.T) {
+	manager := NewBootstrapManager(NewCertificateProviderRegistry())
+	manager.RegisterBootstrap(&fakeBootstrap{name: "alpha", configured: false})
+
+	if err := manager.InitializeProviders(context.Background()); err == nil {
+		t.Error("Expected error when no providers are configured, got nil")
+	}
+}
+
+func TestRegistryUnregister(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+
+	if err := registry.Register(&fakeProvider{name: "alpha", domains: []string{"a.com", "b.com"}}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	if err := registry.Unregister("alpha"); err != nil {
+		t.Fatalf("Failed to unregister provider: %v", err)
+	}
+
+	if _, err := registry.GetProviderForDomain("a.com"); err == nil {
+		t.Error("Expected unregistered provider's domains to be removed")
+	}
+
+	if err := registry.Unregister("alpha"); err == nil {
+		t.Error("Expected error when unregistering an unknown provider, got nil")
+	}
+}
+
+func TestRegistryReload(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+
+	if err := registry.Register(&fakeProvider{name: "alpha", domains: []string{"a.com", "b.com"}}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	if err := registry.Register(&fakeProvider{name: "beta", domains: []string{"x.com"}}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	changes, err := registry.Reload(&fakeProvider{name: "alpha", domains: []string{"b.com", "c.com"}})
+	if err != nil {
+		t.Fatalf("Failed to reload provider: %v", err)
+	}
+
+	if len(changes.Added) != 1 || changes.Added[0] != "c.com" {
+		t.Errorf("Expected added [c.com], got %v", changes.Added)
+	}
+	if len(changes.Removed) != 1 || changes.Removed[0] != "a.com" {
+		t.Errorf("Expected removed [a.com], got %v", changes.Removed)
+	}
+
+	if _, err := registry.GetProviderForDomain("a.com"); err == nil {
+		t.Error("Removed domain should no longer resolve")
+	}
+	if _, err := registry.GetProviderForDomain("c.com"); err != nil {
+		t.Errorf("Added domain should resolve: %v", err)
+	}
+
+	// A reload claiming another provider's domain is rejected and leaves the registry untouched
+	if _, err := registry.Reload(&fakeProvider{name: "alpha", domains: []string{"x.com"}}); err == nil {
+		t.Fatal("Expected error when reload claims another provider's domain, got nil")
+	}
+	if _, err := registry.GetProviderForDomain("b.com"); err != nil {
+		t.Errorf("Failed reload should keep previous domains: %v", err)
+	}
+}
+
+func TestBootstrapManagerReloadProviders(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	manager := NewBootstrapManager(registry)
+
+	bootstrap := &fakeBootstrap{name: "alpha", configured: true, domains: []string{"a.com"}}
+	manager.RegisterBootstrap(bootstrap)
+
+	if err := manager.InitializeProviders(context.Background()); err != nil {
+		t.Fatalf("Failed to initialize providers: %v", err)
+	}
+
+	bootstrap.domains = []string{"a.com", "new.com"}
+	changes, err := manager.ReloadProviders(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to reload providers: %v", err)
+	}
+
+	if len(changes.Added) != 1 || changes.Added[0] != "new.com" || len(changes.Removed) != 0 {
+		t.Errorf("Unexpected changes: %+v", changes)
+	}
+
+	bootstrap.createErr = fmt.Errorf("discovery failed")
+	if _, err := manager.ReloadProviders(context.Background()); err == nil {
+		t.Fatal("Expected error when discovery fails, got nil")
+	}
+
+	if _, err := registry.GetProviderForDomain("new.com"); err != nil {
+		t.Errorf("Provider should keep its domains when reload fails: %v", err)
+	}
+
+	if failures := manager.FailedProviders(); len(failures) != 1 || failures[0].Name != "alpha" {
+		t.Errorf("Expected alpha to be reported as failed after reload, got %+v", failures)
+	}
+
+	bootstrap.createErr = nil
+	if _, err := manager.ReloadProviders(context.Background()); err != nil {
+		t.Fatalf("Failed to reload providers: %v", err)
+	}
+
+	if failures := manager.FailedProviders(); len(failures) != 0 {
+		t.Errorf("Expected failure to clear after a successful reload, got %+v", failures)
+	}
+}
+
+func TestRegistryGetCertInfo(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	if err := registry.Register(mock.NewProvider([]string{"example.com", "test.com"}, nil, nil)); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	if info := registry.GetDomainInfo("example.com"); info == nil || info.CertFingerprintSHA256 != "" {
+		t.Fatalf("Expected domain info without certificate details before retrieval, got %+v", info)
+	}
+
+	certInfo, err := registry.GetCertInfo(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("GetCertInfo failed: %v", err)
+	}
+	if certInfo.Serial == "" || certInfo.FingerprintSHA256 == "" || certInfo.NotAfter.IsZero() {
+		t.Fatalf("Expected populated certificate info, got %+v", certInfo)
+	}
+
+	info := registry.GetDomainInfo("example.com")
+	if info.CertSerial != certInfo.Serial || info.CertFingerprintSHA256 != certInfo.FingerprintSHA256 || !info.CertNotAfter.Equal(certInfo.NotAfter) {
+		t.Errorf("Expected domain info to carry the retrieved certificate, got %+v", info)
+	}
+
+	// Retrieving through the registry refreshes the remembered certificate
+	if _, _, err := registry.RetrieveCertificate(context.Background(), "test.com"); err != nil {
+		t.Fatalf("RetrieveCertificate failed: %v", err)
+	}
+	for _, listed := range registry.ListAllDomainInfo() {
+		if listed.CertFingerprintSHA256 == "" {
+			t.Errorf("Expected certificate details for %s after retrieval", listed.Name)
+		}
+	}
+
+	if _, _, err := registry.RetrieveCertificate(context.Background(), "unknown.com"); !errors.Is(err, domain.ErrDomainNotManaged) {
+		t.Errorf("Expected ErrDomainNotManaged for unmanaged domain, got %v", err)
+	}
+	if _, err := registry.GetCertInfo(context.Background(), "unknown.com"); err == nil {
+		t.Error("Expected error for unmanaged domain")
+	}
+
+	// Certificate details are forgotten with the provider
+	if err := registry.Unregister("mock"); err != nil {
+		t.Fatalf("Unregister failed: %v", err)
+	}
+	if len(registry.certInfos) != 0 {
+		t.Errorf("Expected certificate details to be dropped, got %d", len(registry.certInfos))
+	}
+}
+
+func TestRegistryGetCertInfoUnparsableChain(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	if err := registry.Register(&fakeProvider{name: "fake", domains: []string{"example.com"}}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	if _, err := registry.GetCertInfo(context.Background(), "example.com"); err == nil {
+		t.Error("Expected error for a chain that is not PEM")
+	}
+
+	// RetrieveCertificate still succeeds; the chain is handed out unchanged
+	if _, _, err := registry.RetrieveCertificate(context.Background(), "example.com"); err != nil {
+		t.Errorf("RetrieveCertificate failed: %v", err)
+	}
+}
+
+// countingProvider counts the certificate retrievals reaching the wrapped provider
+type countingProvider struct {
+	domain.CertificateProvider
+	retrievals int
+}
+
+func (p *countingProvider) RetrieveCertificate(ctx context.Context, domainName string) ([]byte, []byte, error) {
+	p.retrievals++
+	return p.CertificateProvider.RetrieveCertificate(ctx, domainName)
+}
+
+func TestRegistryCertificateCache(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	provider := &countingProvider{CertificateProvider: mock.NewProvider([]string{"example.com"}, nil, nil)}
+	if err := registry.Register(provider); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	ctx := context.Background()
+
+	retrieve := func(wantRetrievals int) []byte {
+		t.Helper()
+		certChain, _, err := registry.RetrieveCertificate(ctx, "example.com")
+		if err != nil {
+			t.Fatalf("RetrieveCertificate failed: %v", err)
+		}
+		if provider.retrievals != wantRetrievals {
+			t.Errorf("Expected %d provider retrievals, got %d", wantRetrievals, provider.retrievals)
+		}
+		return certChain
+	}
+
+	// The cache is disabled by default
+	retrieve(1)
+	retrieve(2)
+
+	registry.SetCertificateCacheTTL(time.Hour)
+	first := retrieve(3)
+	if cached := retrieve(3); string(cached) != string(first) {
+		t.Error("Expected the cached certificate chain")
+	}
+	certInfo, err := registry.GetCertInfo(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("GetCertInfo failed: %v", err)
+	}
+	if provider.retrievals != 3 || certInfo.Serial == "" {
+		t.Errorf("Expected GetCertInfo to use the cache, got %d retrievals and %+v", provider.retrievals, certInfo)
+	}
+
+	// An expired entry is retrieved again
+	registry.certCache["example.com"].expiresAt = time.Now().Add(-time.Second)
+	retrieve(4)
+
+	// A certificate is never cached past its expiry
+	registry.SetCertificateCacheTTL(100 * 365 * 24 * time.Hour)
+	retrieve(5)
+	if entry := registry.certCache["example.com"]; !entry.expiresAt.Equal(entry.certInfo.NotAfter) {
+		t.Errorf("Expected the entry to expire with the certificate at %v, got %v", entry.certInfo.NotAfter, entry.expiresAt)
+	}
+
+	// Reloading the provider drops its cached certificates
+	if _, err := registry.Reload(provider); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	retrieve(6)
+	retrieve(6)
+
+	registry.SetCertificateCacheTTL(0)
+	retrieve(7)
+	if len(registry.certCache) != 0 {
+		t.Errorf("Expected nothing cached with the cache disabled, got %d entries", len(registry.certCache))
+	}
+}
+
+func TestReadOnlyProviderBlocksRetrieval(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	provider := &fakeProvider{name: "fake", domains: []string{"example.com", "test.com"}}
+	if err := registry.Register(NewReadOnlyProvider(provider)); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	if domains := registry.ListDomains(); len(domains) != 2 {
+		t.Errorf("Expected 2 listed domains, got %v", domains)
+	}
+	if infos := registry.ListAllDomainInfo(); len(infos) != 2 {
+		t.Errorf("Expected 2 domain infos, got %v", infos)
+	}
+	if info := registry.GetDomainInfo("example.com"); info == nil || info.Provider != "fake" {
+		t.Errorf("Expected domain info for example.com, got %v", info)
+	}
+
+	certChain, privateKey, err := registry.RetrieveCertificate(context.Background(), "example.com")
+	if !errors.Is(err, domain.ErrReadOnly) {
+		t.Fatalf("Expected ErrReadOnly, got %v", err)
+	}
+	if certChain != nil || privateKey != nil {
+		t.Error("Expected no certificate material in read-only mode")
+	}
+}
+
+func TestBootstrapManagerReadOnly(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	manager := NewBootstrapManager(registry)
+	manager.SetReadOnly(true)
+	manager.RegisterBootstrap(&fakeBootstrap{name: "alpha", configured: true, domains: []string{"a.com"}})
+
+	if err := manager.InitializeProviders(context.Background()); err != nil {
+		t.Fatalf("Failed to initialize providers: %v", err)
+	}
+	if domains := registry.ListDomains(); len(domains) != 1 || domains[0] != "a.com" {
+		t.Errorf("Expected a.com to be listed, got %v", domains)
+	}
+	if _, _, err := registry.RetrieveCertificate(context.Background(), "a.com"); !errors.Is(err, domain.ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly after initialize, got %v", err)
+	}
+
+	// Reloaded providers stay read-only
+	if _, err := manager.ReloadProviders(context.Background()); err != nil {
+		t.Fatalf("Failed to reload providers: %v", err)
+	}
+	if _, _, err := registry.RetrieveCertificate(context.Background(), "a.com"); !errors.Is(err, domain.ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly after reload, got %v", err)
+	}
+}
+
+// recordingProvider records the domain of the last certificate retrieval
+type recordingProvider struct {
+	fakeProvider
+	retrieved string
+}
+
+func (p *recordingProvider) RetrieveCertificate(ctx context.Context, domainName string) ([]byte, []byte, error) {
+	p.retrieved = domainName
+	return []byte("cert"), []byte("key"), nil
+}
+
+func TestWildcardProvider(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	provider := &recordingProvider{fakeProvider: fakeProvider{name: "fake", domains: []string{"example.com", "*.test.com", "test.com"}}}
+	if err := registry.Register(NewWildcardProvider(provider)); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	domains := registry.ListDomains()
+	sort.Strings(domains)
+	if want := []string{"*.example.com", "*.test.com", "example.com", "test.com"}; !reflect.DeepEqual(domains, want) {
+		t.Errorf("Expected %v, got %v", want, domains)
+	}
+
+	info := registry.GetDomainInfo("*.example.com")
+	if info == nil || info.Name != "*.example.com" || info.Provider != "fake" || info.Status != "ACTIVE" {
+		t.Errorf("Expected wildcard info copied from the apex, got %+v", info)
+	}
+	if apex := registry.GetDomainInfo("example.com"); apex == nil || apex.Name != "example.com" {
+		t.Errorf("Expected apex info to be unchanged, got %+v", apex)
+	}
+
+	if _, _, err := registry.RetrieveCertificate(context.Background(), "*.example.com"); err != nil {
+		t.Fatalf("RetrieveCertificate failed: %v", err)
+	}
+	if provider.retrieved != "example.com" {
+		t.Errorf("Expected the wildcard to be retrieved as example.com, got %q", provider.retrieved)
+	}
+
+	// A wildcard the provider lists itself is passed through
+	if _, _, err := registry.RetrieveCertificate(context.Background(), "*.test.com"); err != nil {
+		t.Fatalf("RetrieveCertificate failed: %v", err)
+	}
+	if provider.retrieved != "*.test.com" {
+		t.Errorf("Expected *.test.com to be passed through, got %q", provider.retrieved)
+	}
+}
+
+// refreshingProvider records the domain whose info was refreshed
+type refreshingProvider struct {
+	fakeProvider
+	refreshed string
+}
+
+func (p *refreshingProvider) RefreshDomainInfo(ctx context.Context, domainName string) error {
+	p.refreshed = domainName
+	return nil
+}
+
+func TestRegistryRefreshDomainInfo(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	provider := &refreshingProvider{fakeProvider: fakeProvider{name: "fake", domains: []string{"example.com"}}}
+	if err := registry.Register(NewReadOnlyProvider(NewWildcardProvider(provider))); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	if err := registry.Register(&fakeProvider{name: "static", domains: []string{"static.com"}}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	// The wrappers pass the refresh through, an added wildcard as its apex
+	if err := registry.RefreshDomainInfo(context.Background(), "*.example.com"); err != nil {
+		t.Fatalf("RefreshDomainInfo failed: %v", err)
+	}
+	if provider.refreshed != "example.com" {
+		t.Errorf("Expected example.com to be refreshed, got %q", provider.refreshed)
+	}
+
+	if err := registry.RefreshDomainInfo(context.Background(), "static.com"); !errors.Is(err, domain.ErrRefreshUnsupported) {
+		t.Errorf("Expected ErrRefreshUnsupported, got %v", err)
+	}
+	if err := registry.RefreshDomainInfo(context.Background(), "other.com"); !errors.Is(err, domain.ErrDomainNotManaged) {
+		t.Errorf("Expected ErrDomainNotManaged, got %v", err)
+	}
+}
+
+func TestWildcardProviderNoApexConflict(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	if err := registry.Register(NewWildcardProvider(&fakeProvider{name: "alpha", domains: []string{"example.com"}})); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	// Neither a subdomain covered by the wildcard nor its own wildcard conflict with the pair
+	if err := registry.Register(NewWildcardProvider(&fakeProvider{name: "beta", domains: []string{"www.example.com"}})); err != nil {
+		t.Fatalf("Expected no conflict with *.example.com, got %v", err)
+	}
+
+	if provider, err := registry.GetProviderForDomain("*.www.example.com"); err != nil || provider.GetProviderName() != "beta" {
+		t.Errorf("Expected *.www.example.com to be served by beta, got %v", err)
+	}
+	if provider, err := registry.GetProviderForDomain("*.example.com"); err != nil || provider.GetProviderName() != "alpha" {
+		t.Errorf("Expected *.example.com to be served by alpha, got %v", err)
+	}
+}
+
+func TestBootstrapManagerRegisterWildcards(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	manager := NewBootstrapManager(registry)
+	manager.SetRegisterWildcards(true)
+	manager.RegisterBootstrap(&fakeBootstrap{name: "alpha", configured: true, domains: []string{"a.com"}})
+
+	if err := manager.InitializeProviders(context.Background()); err != nil {
+		t.Fatalf("Failed to initialize providers: %v", err)
+	}
+	if info := registry.GetDomainInfo("*.a.com"); info == nil || info.Provider != "alpha" {
+		t.Errorf("Expected *.a.com to be registered for alpha, got %+v", info)
+	}
+
+	// Reloaded providers keep their wildcards
+	if _, err := manager.ReloadProviders(context.Background()); err != nil {
+		t.Fatalf("Failed to reload providers: %v", err)
+	}
+	if info := registry.GetDomainInfo("*.a.com"); info == nil {
+		t.Error("Expected *.a.com to be registered after reload")
+	}
+}
+
+func TestDomainTagsFiltering(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	provider := &fakeProvider{name: "fake", domains: []string{"a.example.com", "b.example.com", "example.org", "untagged.net"}}
+	if err := registry.Register(provider); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	cfg, err := ParseTagsConfig([]byte(`
+tags:
+  "*.example.com": [team-a]
+  a.example.com: [prod, team-a]
+  example.org: [team-b, prod]
+`))
+	if err != nil {
+		t.Fatalf("ParseTagsConfig failed: %v", err)
+	}
+	registry.SetDomainTags(cfg.Tags)
+
+	tests := []struct {
+		tags []string
+		want []string
+	}{
+		{nil, []string{"a.example.com", "b.example.com", "example.org", "untagged.net"}},
+		{[]string{"team-a"}, []string{"a.example.com", "b.example.com"}},
+		{[]string{"prod"}, []string{"a.example.com", "example.org"}},
+		{[]string{"team-a", "prod"}, []string{"a.example.com"}},
+		{[]string{"team-b", "prod"}, []string{"example.org"}},
+		{[]string{"team-a", "team-b"}, []string{}},
+	}
+	for _, tt := range tests {
+		if got := registry.ListDomainsWithTags(tt.tags...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ListDomainsWithTags(%v) = %v, want %v", tt.tags, got, tt.want)
+		}
+	}
+
+	// Tags of overlapping entries are merged, deduplicated and sorted
+	if info := registry.GetDomainInfo("a.example.com"); info == nil || !reflect.DeepEqual(info.Tags, []string{"prod", "team-a"}) {
+		t.Errorf("Expected tags [prod team-a], got %+v", info)
+	}
+	for _, info := range registry.ListAllDomainInfo() {
+		if info.Name == "untagged.net" && len(info.Tags) != 0 {
+			t.Errorf("Expected no tags for untagged.net, got %v", info.Tags)
+		}
+		if info.Name == "example.org" && !reflect.DeepEqual(info.Tags, []string{"prod", "team-b"}) {
+			t.Errorf("Expected tags [prod team-b] for example.org, got %v", info.Tags)
+		}
+	}
+}
+
+func TestParseTagsConfigInvalid(t *testing.T) {
+	invalid := map[string]string{
+		"unknown field": "domains:\n  example.com: [a]\n",
+		"bad pattern":   "tags:\n  \"[example.com\": [a]\n",
+		"empty tag":     "tags:\n  example.com: [a, \" \"]\n",
+	}
+	for name, doc := range invalid {
+		if _, err := ParseTagsConfig([]byte(doc)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestParseProvidersConfig(t *testing.T) {
+	cfg, err := ParseProvidersConfig([]byte(`
+providers:
+  - type: porkbun
+    apiKey: pk1
+    secretKey: sk1
+    domains: [example.com]
+  - type: porkbun
+    name: porkbun-personal
+    credentialsFile: /etc/porkbun-personal.key
+    domainsExclude: ["*.internal.example.org"]
+`))
+	if err != nil {
+		t.Fatalf("ParseProvidersConfig failed: %v", err)
+	}
+	if len(cfg.Providers) != 2 {
+		t.Fatalf("Expected 2 providers, got %d", len(cfg.Providers))
+	}
+	if cfg.Providers[0].InstanceName() != "porkbun" || cfg.Providers[1].InstanceName() != "porkbun-personal" {
+		t.Errorf("Unexpected instance names: %q, %q", cfg.Providers[0].InstanceName(), cfg.Providers[1].InstanceName())
+	}
+	if cfg.Providers[1].CredentialsFile != "/etc/porkbun-personal.key" || len(cfg.Providers[1].DomainsExclude) != 1 {
+		t.Errorf("Unexpected second provider: %+v", cfg.Providers[1])
+	}
+
+	invalid := map[string]string{
+		"unknown field":   "providers:\n  - type: porkbun\n    apikey: pk1\n",
+		"missing type":    "providers:\n  - name: porkbun\n",
+		"duplicate names": "providers:\n  - type: porkbun\n  - type: porkbun\n",
+	}
+	for name, doc := range invalid {
+		if _, err := ParseProvidersConfig([]byte(doc)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestBootstrapManagerApplyProvidersConfig(t *testing.T) {
+	registry := NewCertificateProviderRegistry()
+	manager := NewBootstrapManager(registry)
+	manager.RegisterBootstrap(&fakeBootstrap{name: "alpha", configured: true, domains: []string{"a.com"}})
+
+	factories := map[string]BootstrapFactory{
+		"fake": func(cfg domain.ProviderConfig) (domain.ProviderBootstrap, error) {
+			return &fakeBootstrap{name: cfg.InstanceName(), configured: true, domains: cfg.Domains}, nil
+		},
+	}
+
+	cfg := &domain.ProvidersConfig{Providers: []domain.ProviderConfig{
+		{Type: "fake", Name: "beta", Domains: []string{"b.com"}},
+	}}
+	if err := manager.ApplyProvidersConfig(cfg, factories); err != nil {
+		t.Fatalf("ApplyProvidersConfig failed: %v", err)
+	}
+	if err := manager.InitializeProviders(context.Background()); err != nil {
+		t.Fatalf("Failed to initialize providers: %v", err)
+	}
+	if providers := registry.ListProviders(); len(providers) != 2 {
+		t.Errorf("Expected the flag-based and the file-based provider, got %v", providers)
+	}
+
+	// Unknown types are reported with the supported ones, before anything is registered
+	unknown := &domain.ProvidersConfig{Providers: []domain.ProviderConfig{
+		{Type: "fake", Name: "gamma"},
+		{Type: "cloudflare"},
+	}}
+	err := manager.ApplyProvidersConfig(unknown, factories)
+	if err == nil || !strings.Contains(err.Error(), `unknown provider type "cloudflare"`) || !strings.Contains(err.Error(), "supported: fake") {
+		t.Fatalf("Expected unknown provider type error, got %v", err)
+	}
+	if manager.findBootstrap("gamma") != nil {
+		t.Error("No bootstrap should be added when the config has an unknown type")
+	}
+
+	// fakeBootstrap does not accept file settings
+	existing := &domain.ProvidersConfig{Providers: []domain.ProviderConfig{{Type: "fake", Name: "alpha"}}}
+	if err := manager.ApplyProvidersConfig(existing, factories); err == nil {
+		t.Error("Expected an error configuring a bootstrap that is not configurable")
+	}
+}
@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/goccy/go-yaml"
+)
+
+// LoadTagsConfig reads and validates a domain tags file
+func LoadTagsConfig(path string) (*domain.TagsConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tags config: %w", err)
+	}
+
+	cfg, err := ParseTagsConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tags config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// ParseTagsConfig parses a domain tags document. Unknown fields are rejected,
+// and the tags are checked with ValidateTags.
+func ParseTagsConfig(data []byte) (*domain.TagsConfig, error) {
+	cfg := &domain.TagsConfig{}
+	if err := yaml.UnmarshalWithOptions(data, cfg, yaml.DisallowUnknownField()); err != nil {
+		return nil, fmt.Errorf("%s", yaml.FormatError(err, false, true))
+	}
+
+	if err := ValidateTags(cfg.Tags); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ValidateTags checks that every key is a domain name or valid glob pattern and that no
+// tag is empty. Surrounding whitespace is trimmed from the tags in place.
+func ValidateTags(tags map[string][]string) error {
+	for pattern, patternTags := range tags {
+		if _, err := utils.MatchGlob(pattern, ""); err != nil {
+			return err
+		}
+		for i, tag := range patternTags {
+			tag = strings.TrimSpace(tag)
+			if tag == "" {
+				return fmt.Errorf("%s: tags must not be empty", pattern)
+			}
+			patternTags[i] = tag
+		}
+	}
+	return nil
+}
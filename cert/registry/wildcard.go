@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+// wildcardProvider wraps a provider so each of its domains is also served in wildcard
+// form: "*.example.com" next to "example.com". Providers such as Porkbun issue one
+// certificate covering both, so the wildcard is retrieved as its apex.
+type wildcardProvider struct {
+	domain.CertificateProvider
+}
+
+// NewWildcardProvider returns provider with a "*." domain added for every domain it lists,
+// unless the provider already lists that wildcard itself
+func NewWildcardProvider(provider domain.CertificateProvider) domain.CertificateProvider {
+	return &wildcardProvider{CertificateProvider: provider}
+}
+
+// GetDomains returns the provider's domains, each followed by its wildcard
+func (p *wildcardProvider) GetDomains() []string {
+	inner := p.CertificateProvider.GetDomains()
+
+	listed := make(map[string]bool, len(inner))
+	for _, d := range inner {
+		listed[d] = true
+	}
+
+	domains := make([]string, 0, 2*len(inner))
+	for _, d := range inner {
+		domains = append(domains, d)
+		if wildcard := "*." + d; !strings.HasPrefix(d, "*.") && !listed[wildcard] {
+			domains = append(domains, wildcard)
+			listed[wildcard] = true
+		}
+	}
+	return domains
+}
+
+// GetDomainInfo returns the info of a listed domain, or of the apex of an added wildcard
+func (p *wildcardProvider) GetDomainInfo(domainName string) *domain.Info {
+	if info := p.CertificateProvider.GetDomainInfo(domainName); info != nil {
+		return info
+	}
+
+	apex, ok := p.apexOf(domainName)
+	if !ok {
+		return nil
+	}
+	info := p.CertificateProvider.GetDomainInfo(apex)
+	if info == nil {
+		return nil
+	}
+
+	wildcardInfo := *info
+	wildcardInfo.Name = domainName
+	return &wildcardInfo
+}
+
+// ListDomainInfo returns the info of every domain, including the added wildcards
+func (p *wildcardProvider) ListDomainInfo() []domain.Info {
+	domains := p.GetDomains()
+	infos := make([]domain.Info, 0, len(domains))
+	for _, d := range domains {
+		if info := p.GetDomainInfo(d); info != nil {
+			infos = append(infos, *info)
+		}
+	}
+	return infos
+}
+
+// RetrieveCertificate retrieves an added wildcard as its apex certificate
+func (p *wildcardProvider) RetrieveCertificate(ctx context.Context, domainName string) ([]byte, []byte, error) {
+	if apex, ok := p.apexOf(domainName); ok {
+		domainName = apex
+	}
+	return p.CertificateProvider.RetrieveCertificate(ctx, domainName)
+}
+
+// RefreshDomainInfo refreshes an added wildcard as its apex, whose info it shares
+func (p *wildcardProvider) RefreshDomainInfo(ctx context.Context, domainName string) error {
+	if apex, ok := p.apexOf(domainName); ok {
+		domainName = apex
+	}
+	return refreshDomainInfo(ctx, p.CertificateProvider, domainName)
+}
+
+// apexOf returns the apex of a wildcard this wrapper added, i.e. one the provider
+// does not list itself while listing its apex
+func (p *wildcardProvider) apexOf(domainName string) (string, bool) {
+	apex, found := strings.CutPrefix(domainName, "*.")
+	if !found {
+		return "", false
+	}
+
+	inner := p.CertificateProvider.GetDomains()
+	listsApex := false
+	for _, d := range inner {
+		if d == domainName {
+			return "", false
+		}
+		if d == apex {
+			listsApex = true
+		}
+	}
+	return apex, listsApex
+}
@@ -0,0 +1,79 @@
+// Package certprovider builds a certificate provider registry from explicit options, so it
+// can be embedded in other Go programs without the CLI, its flags or environment variables.
+package certprovider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dh-kam/go-cert-provider/cert"
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/cert/registry"
+)
+
+// Options configures a registry built by New
+type Options struct {
+	// Providers declares the provider instances with their credentials and domains, like the
+	// entries of a providers config file. Providers without domains auto-discover them.
+	Providers []domain.ProviderConfig
+
+	// ContinueOnProviderError skips providers that fail to come up instead of failing New;
+	// New still fails if no provider comes up
+	ContinueOnProviderError bool
+	// ReadOnly lists domains but refuses certificate retrieval
+	ReadOnly bool
+	// RegisterWildcards also serves "*.<domain>" for every managed domain
+	RegisterWildcards bool
+	// DomainTags maps domain names or glob patterns to tags (see domain.TagsConfig)
+	DomainTags map[string][]string
+	// ProviderPriority ranks provider names, highest first; a domain listed by several
+	// ranked providers resolves to the highest-ranked one instead of failing as a duplicate
+	ProviderPriority []string
+}
+
+// New creates a registry with the providers declared in opts and initializes them,
+// discovering their domains. The context bounds initialization. Unlike the CLI, New reads
+// no flags or environment variables, so independent registries can coexist in a process.
+func New(ctx context.Context, opts Options) (*registry.CertificateProviderRegistry, error) {
+	if len(opts.Providers) == 0 {
+		return nil, fmt.Errorf("no providers configured")
+	}
+
+	manager := registry.NewBootstrapManager(registry.NewCertificateProviderRegistry())
+	if err := Configure(manager, opts); err != nil {
+		return nil, err
+	}
+
+	if err := manager.InitializeProviders(ctx); err != nil {
+		return nil, fmt.Errorf("failed to initialize providers: %w", err)
+	}
+
+	return manager.Registry(), nil
+}
+
+// Configure applies opts to a bootstrap manager before its providers are initialized.
+// A provider entry named like a bootstrap already registered with the manager configures
+// that bootstrap; other entries add a bootstrap configured by the entry alone.
+func Configure(manager *registry.BootstrapManager, opts Options) error {
+	manager.SetContinueOnProviderError(opts.ContinueOnProviderError)
+	manager.SetReadOnly(opts.ReadOnly)
+	manager.SetRegisterWildcards(opts.RegisterWildcards)
+
+	if err := registry.ValidateTags(opts.DomainTags); err != nil {
+		return fmt.Errorf("invalid domain tags: %w", err)
+	}
+	manager.Registry().SetDomainTags(opts.DomainTags)
+	manager.Registry().SetProviderPriority(opts.ProviderPriority)
+
+	if len(opts.Providers) > 0 {
+		cfg := &domain.ProvidersConfig{Providers: opts.Providers}
+		if err := registry.ValidateProvidersConfig(cfg); err != nil {
+			return err
+		}
+		if err := manager.ApplyProvidersConfig(cfg, cert.BootstrapFactories()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package certprovider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+func TestNew(t *testing.T) {
+	// The environment is ignored; only the options configure the providers
+	t.Setenv("PORKBUN_DOMAINS", "env.example.com")
+
+	providerRegistry, err := New(context.Background(), Options{
+		Providers: []domain.ProviderConfig{
+			{Type: "porkbun", APIKey: "pk1", SecretKey: "sk1", Domains: []string{"example.com", "test.com"}},
+			{Type: "porkbun", Name: "porkbun-personal", APIKey: "pk2", SecretKey: "sk2", Domains: []string{"example.org"}},
+		},
+		ReadOnly:   true,
+		DomainTags: map[string][]string{"example.*": {"prod"}},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if domains := providerRegistry.ListDomains(); !reflect.DeepEqual(domains, []string{"example.com", "example.org", "test.com"}) {
+		t.Errorf("Unexpected domains: %v", domains)
+	}
+	if info := providerRegistry.GetDomainInfo("example.org"); info == nil || info.Provider != "porkbun-personal" || !reflect.DeepEqual(info.Tags, []string{"prod"}) {
+		t.Errorf("Unexpected info for example.org: %+v", info)
+	}
+	if _, _, err := providerRegistry.RetrieveCertificate(context.Background(), "example.com"); !errors.Is(err, domain.ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly, got %v", err)
+	}
+}
+
+func TestNewIndependentRegistries(t *testing.T) {
+	first, err := New(context.Background(), Options{
+		Providers: []domain.ProviderConfig{{Type: "porkbun", APIKey: "pk1", SecretKey: "sk1", Domains: []string{"first.com"}}},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	second, err := New(context.Background(), Options{
+		Providers: []domain.ProviderConfig{{Type: "porkbun", APIKey: "pk2", SecretKey: "sk2", Domains: []string{"second.com"}}},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if domains := first.ListDomains(); !reflect.DeepEqual(domains, []string{"first.com"}) {
+		t.Errorf("Unexpected domains in the first registry: %v", domains)
+	}
+	if domains := second.ListDomains(); !reflect.DeepEqual(domains, []string{"second.com"}) {
+		t.Errorf("Unexpected domains in the second registry: %v", domains)
+	}
+}
+
+func TestNewInvalidOptions(t *testing.T) {
+	invalid := map[string]Options{
+		"no providers":    {},
+		"unknown type":    {Providers: []domain.ProviderConfig{{Type: "unknown"}}},
+		"missing type":    {Providers: []domain.ProviderConfig{{Name: "porkbun"}}},
+		"duplicate names": {Providers: []domain.ProviderConfig{{Type: "porkbun"}, {Type: "porkbun"}}},
+		"invalid tags": {
+			Providers:  []domain.ProviderConfig{{Type: "porkbun", APIKey: "pk1", SecretKey: "sk1", Domains: []string{"example.com"}}},
+			DomainTags: map[string][]string{"[example.com": {"prod"}},
+		},
+		"not configured": {Providers: []domain.ProviderConfig{{Type: "porkbun", Domains: []string{"example.com"}}}},
+	}
+	for name, opts := range invalid {
+		if _, err := New(context.Background(), opts); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
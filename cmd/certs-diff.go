@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/spf13/cobra"
+)
+
+// certificateBaseline is the state recorded by certs diff --update-baseline.
+// The JSON field names are part of the baseline file format; keep them stable.
+type certificateBaseline struct {
+	Domain      string    `json:"domain"`
+	Fingerprint string    `json:"fingerprint"`
+	Serial      string    `json:"serial"`
+	Issuer      string    `json:"issuer"`
+	NotAfter    time.Time `json:"notAfter"`
+	RecordedAt  time.Time `json:"recordedAt"`
+}
+
+// baselineChange is one field that differs between the baseline and the current certificate
+type baselineChange struct {
+	field    string
+	baseline string
+	current  string
+}
+
+// diffCmd represents the certs diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <domain>",
+	Short: "Compare a domain's current certificate with a stored baseline",
+	Long: `Retrieve the current certificate of a managed domain and compare its fingerprint,
+serial, issuer and expiry with a baseline file, to detect unexpected rotations.
+
+The command exits with a non-zero status and prints what changed when the certificate
+differs from the baseline. Use --update-baseline to record the current certificate,
+e.g. after an expected renewal. The private key is not recorded.
+
+Examples:
+  # Record the current certificate
+  go-cert-provider certs diff example.com --baseline example.com.json --update-baseline
+
+  # Check for changes, e.g. from cron
+  go-cert-provider certs diff example.com --baseline example.com.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		domain := args[0]
+
+		baselineFile, err := cmd.Flags().GetString("baseline")
+		if err != nil {
+			return err
+		}
+		updateBaseline, err := cmd.Flags().GetBool("update-baseline")
+		if err != nil {
+			return err
+		}
+		if baselineFile == "" {
+			return fmt.Errorf("--baseline is required")
+		}
+
+		if !appState.initialized {
+			return fmt.Errorf("certificate system not initialized")
+		}
+		providerRegistry := appState.providerRegistry
+
+		provider, err := providerRegistry.GetProviderForDomain(domain)
+		if err != nil {
+			return domainNotManagedError(domain, providerRegistry.ListProviders(), providerRegistry.ListDomains())
+		}
+
+		// From here on, an error is a retrieval failure or a changed certificate
+		cmd.SilenceUsage = true
+
+		certChain, _, err := provider.RetrieveCertificate(cmd.Context(), domain)
+		if err != nil {
+			if hint := retrievalErrorHint(err, provider.GetProviderName()); hint != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Hint: %s\n", hint)
+			}
+			return fmt.Errorf("failed to retrieve certificate: %w", err)
+		}
+
+		leaf, err := utils.ParseLeafCertificate(certChain)
+		if err != nil {
+			return fmt.Errorf("failed to parse certificate for %s: %w", domain, err)
+		}
+		current := certificateBaseline{
+			Domain:      domain,
+			Fingerprint: utils.CertificateFingerprintSHA256(leaf),
+			Serial:      utils.CertificateSerial(leaf),
+			Issuer:      leaf.Issuer.String(),
+			NotAfter:    leaf.NotAfter.UTC(),
+			RecordedAt:  time.Now().UTC().Truncate(time.Second),
+		}
+
+		out := cmd.OutOrStdout()
+		if updateBaseline {
+			if err := writeBaseline(baselineFile, &current); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Baseline for %s written to %s (fingerprint %s)\n", domain, baselineFile, current.Fingerprint)
+			return nil
+		}
+
+		return checkBaseline(out, baselineFile, &current)
+	},
+}
+
+// checkBaseline compares the current certificate with the baseline file and prints the
+// outcome. It fails if the baseline is missing, invalid, recorded for another domain,
+// or differs from the current certificate.
+func checkBaseline(out io.Writer, baselineFile string, current *certificateBaseline) error {
+	baseline, err := readBaseline(baselineFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("baseline %s does not exist; record it with --update-baseline", baselineFile)
+	}
+	if err != nil {
+		return err
+	}
+	if baseline.Domain != current.Domain {
+		return fmt.Errorf("baseline %s was recorded for %s, not %s", baselineFile, baseline.Domain, current.Domain)
+	}
+
+	changes := compareBaseline(baseline, current)
+	if len(changes) == 0 {
+		fmt.Fprintf(out, "✅ Certificate for %s matches the baseline recorded at %s\n", current.Domain,
+			utils.FormatDateTime(baseline.RecordedAt.Local()))
+		return nil
+	}
+
+	fmt.Fprintf(out, "❌ Certificate for %s changed since the baseline recorded at %s:\n", current.Domain,
+		utils.FormatDateTime(baseline.RecordedAt.Local()))
+	for _, change := range changes {
+		fmt.Fprintf(out, "  %s: %s -> %s\n", change.field, change.baseline, change.current)
+	}
+	return fmt.Errorf("certificate for %s differs from the baseline", current.Domain)
+}
+
+// compareBaseline lists the recorded fields that differ, in a fixed order
+func compareBaseline(baseline, current *certificateBaseline) []baselineChange {
+	fields := []baselineChange{
+		{"Fingerprint", baseline.Fingerprint, current.Fingerprint},
+		{"Serial", baseline.Serial, current.Serial},
+		{"Issuer", baseline.Issuer, current.Issuer},
+		{"Not After", baseline.NotAfter.UTC().Format(time.RFC3339), current.NotAfter.UTC().Format(time.RFC3339)},
+	}
+
+	var changes []baselineChange
+	for _, field := range fields {
+		if field.baseline != field.current {
+			changes = append(changes, field)
+		}
+	}
+	return changes
+}
+
+// readBaseline loads a baseline file written by writeBaseline
+func readBaseline(path string) (*certificateBaseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline certificateBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("invalid baseline file %s: %w", path, err)
+	}
+	if baseline.Fingerprint == "" {
+		return nil, fmt.Errorf("invalid baseline file %s: no fingerprint recorded", path)
+	}
+	return &baseline, nil
+}
+
+// writeBaseline stores the baseline as indented JSON
+func writeBaseline(path string, baseline *certificateBaseline) error {
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	diffCmd.Flags().String("baseline", "", "JSON file holding the recorded certificate state (required)")
+	diffCmd.Flags().Bool("update-baseline", false, "Record the current certificate in the baseline file instead of comparing")
+
+	certsCmd.AddCommand(diffCmd)
+}
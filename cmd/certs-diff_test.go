@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testBaseline returns the recorded state of a certificate for example.com
+func testBaseline() certificateBaseline {
+	return certificateBaseline{
+		Domain:      "example.com",
+		Fingerprint: "AA:BB:CC",
+		Serial:      "01:02",
+		Issuer:      "CN=Test CA",
+		NotAfter:    time.Date(2027, 1, 2, 3, 4, 5, 0, time.UTC),
+		RecordedAt:  time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWriteBaselineFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	baseline := testBaseline()
+	if err := writeBaseline(path, &baseline); err != nil {
+		t.Fatalf("writeBaseline failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The field names are the baseline file format; existing files must stay readable
+	want := `{
+  "domain": "example.com",
+  "fingerprint": "AA:BB:CC",
+  "serial": "01:02",
+  "issuer": "CN=Test CA",
+  "notAfter": "2027-01-02T03:04:05Z",
+  "recordedAt": "2026-10-01T12:00:00Z"
+}
+`
+	if string(data) != want {
+		t.Errorf("Baseline file =\n%s\nwant\n%s", data, want)
+	}
+
+	read, err := readBaseline(path)
+	if err != nil {
+		t.Fatalf("readBaseline failed: %v", err)
+	}
+	if *read != baseline {
+		t.Errorf("Read %+v, want %+v", *read, baseline)
+	}
+}
+
+func TestCheckBaseline(t *testing.T) {
+	dir := t.TempDir()
+	baselineFile := filepath.Join(dir, "baseline.json")
+	baseline := testBaseline()
+	if err := writeBaseline(baselineFile, &baseline); err != nil {
+		t.Fatalf("writeBaseline failed: %v", err)
+	}
+
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name       string
+		file       string
+		current    func(*certificateBaseline)
+		wantErr    string
+		wantOutput []string
+	}{
+		{
+			name:       "unchanged",
+			file:       baselineFile,
+			current:    func(c *certificateBaseline) { c.RecordedAt = time.Now() },
+			wantOutput: []string{"matches the baseline"},
+		},
+		{
+			name: "renewed certificate",
+			file: baselineFile,
+			current: func(c *certificateBaseline) {
+				c.Fingerprint = "DD:EE:FF"
+				c.Serial = "03:04"
+				c.NotAfter = c.NotAfter.AddDate(0, 3, 0)
+			},
+			wantErr: "differs from the baseline",
+			wantOutput: []string{
+				"changed since the baseline",
+				"Fingerprint: AA:BB:CC -> DD:EE:FF",
+				"Serial: 01:02 -> 03:04",
+				"Not After: 2027-01-02T03:04:05Z -> 2027-04-02T03:04:05Z",
+			},
+		},
+		{
+			name:    "new issuer",
+			file:    baselineFile,
+			current: func(c *certificateBaseline) { c.Issuer = "CN=Other CA" },
+			wantErr: "differs from the baseline",
+			wantOutput: []string{
+				"Issuer: CN=Test CA -> CN=Other CA",
+			},
+		},
+		{
+			name:    "baseline for another domain",
+			file:    baselineFile,
+			current: func(c *certificateBaseline) { c.Domain = "test.com" },
+			wantErr: "was recorded for example.com, not test.com",
+		},
+		{
+			name:    "missing baseline",
+			file:    filepath.Join(dir, "missing.json"),
+			wantErr: "does not exist; record it with --update-baseline",
+		},
+		{
+			name:    "malformed baseline",
+			file:    writeFile("malformed.json", "{not json"),
+			wantErr: "invalid baseline file",
+		},
+		{
+			name:    "baseline without fingerprint",
+			file:    writeFile("empty.json", `{"domain": "example.com"}`),
+			wantErr: "no fingerprint recorded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := testBaseline()
+			if tt.current != nil {
+				tt.current(&current)
+			}
+
+			var out bytes.Buffer
+			err := checkBaseline(&out, tt.file, &current)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("checkBaseline failed: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Error = %v, want one containing %q", err, tt.wantErr)
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("Output does not contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
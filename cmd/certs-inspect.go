@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/spf13/cobra"
+)
+
+// ocspTimeout bounds the request to the OCSP responder
+const ocspTimeout = 10 * time.Second
+
+// inspectCmd represents the certs inspect command
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <domain>",
+	Short: "Show the details of a domain's current certificate",
+	Long: `Retrieve the current certificate of a managed domain and print its subject,
+issuer, SANs, serial, fingerprint and validity. The private key is not printed.
+
+With --check-ocsp, the OCSP responder listed in the leaf certificate is asked for
+the revocation status (good, revoked or unknown). The command fails if the
+certificate is revoked, so it can gate a deployment.
+
+Examples:
+  go-cert-provider certs inspect example.com
+  go-cert-provider certs inspect example.com --check-ocsp`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		domain := args[0]
+
+		checkOCSP, err := cmd.Flags().GetBool("check-ocsp")
+		if err != nil {
+			return err
+		}
+
+		if !appState.initialized {
+			return fmt.Errorf("certificate system not initialized")
+		}
+		providerRegistry := appState.providerRegistry
+
+		provider, err := providerRegistry.GetProviderForDomain(domain)
+		if err != nil {
+			return domainNotManagedError(domain, providerRegistry.ListProviders(), providerRegistry.ListDomains())
+		}
+
+		certChain, _, err := provider.RetrieveCertificate(cmd.Context(), domain)
+		if err != nil {
+			if hint := retrievalErrorHint(err, provider.GetProviderName()); hint != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Hint: %s\n", hint)
+			}
+			return fmt.Errorf("failed to retrieve certificate: %w", err)
+		}
+
+		leaf, err := utils.ParseLeafCertificate(certChain)
+		if err != nil {
+			return fmt.Errorf("failed to parse certificate for %s: %w", domain, err)
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Domain:       %s (%s)\n", domain, provider.GetProviderName())
+		// The same summary as certs retrieve --print-info, so the two outputs cannot drift
+		if err := writeCertificateSummary(out, certChain, time.Now()); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Issuer:       %s\n", leaf.Issuer.CommonName)
+		fmt.Fprintf(out, "Serial:       %s\n", utils.CertificateSerial(leaf))
+		fmt.Fprintf(out, "Fingerprint:  %s\n", utils.CertificateFingerprintSHA256(leaf))
+		fmt.Fprintf(out, "Not Before:   %s\n", utils.FormatDateTime(leaf.NotBefore.Local()))
+
+		if !checkOCSP {
+			return nil
+		}
+
+		return printOCSPStatus(cmd, certChain)
+	},
+}
+
+// writeCertificateSummary writes the subject, SANs and expiry of a chain's leaf, so the
+// user can confirm which certificate was retrieved
+func writeCertificateSummary(w io.Writer, certChain []byte, now time.Time) error {
+	leaf, err := utils.ParseLeafCertificate(certChain)
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	fmt.Fprintf(w, "Subject:      %s\n", leaf.Subject.CommonName)
+	fmt.Fprintf(w, "SANs:         %s\n", strings.Join(leaf.DNSNames, ", "))
+	fmt.Fprintf(w, "Not After:    %s (%s left)\n", utils.FormatDateTime(leaf.NotAfter.Local()),
+		utils.FormatDuration(leaf.NotAfter.Sub(now)))
+	return nil
+}
+
+// printOCSPStatus queries the OCSP responder of the chain's leaf and prints the result.
+// It fails only when the responder reports the certificate as revoked or cannot be queried.
+func printOCSPStatus(cmd *cobra.Command, certChain []byte) error {
+	out := cmd.OutOrStdout()
+
+	leaf, issuer, err := utils.LeafAndIssuer(certChain)
+	if leaf != nil && len(leaf.OCSPServer) == 0 {
+		err = utils.ErrNoOCSPServer
+	}
+	if err != nil {
+		fmt.Fprintf(out, "OCSP:         not checked (%v)\n", err)
+		return nil
+	}
+
+	client := &http.Client{Timeout: ocspTimeout}
+	result, err := utils.CheckOCSP(cmd.Context(), client, leaf, issuer)
+	if err != nil {
+		return fmt.Errorf("OCSP check failed: %w", err)
+	}
+
+	fmt.Fprintf(out, "OCSP:         %s (responder %s)\n", result.Status, result.Responder)
+	if !result.NextUpdate.IsZero() {
+		fmt.Fprintf(out, "Next Update:  %s\n", utils.FormatDateTime(result.NextUpdate.Local()))
+	}
+
+	if result.Status == "revoked" {
+		return fmt.Errorf("certificate for %s was revoked at %s", leaf.Subject.CommonName,
+			utils.FormatDateTime(result.RevokedAt.Local()))
+	}
+	return nil
+}
+
+func init() {
+	inspectCmd.Flags().Bool("check-ocsp", false, "Query the certificate's OCSP responder for its revocation status; fails if revoked")
+
+	certsCmd.AddCommand(inspectCmd)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/cert/providers/mock"
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/spf13/cobra"
+)
+
+func TestWriteCertificateSummary(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	notAfter := now.Add(90 * 24 * time.Hour)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com", "www.example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	certChain := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	var out bytes.Buffer
+	if err := writeCertificateSummary(&out, certChain, now); err != nil {
+		t.Fatalf("writeCertificateSummary failed: %v", err)
+	}
+
+	want := "Subject:      example.com\n" +
+		"SANs:         example.com, www.example.com\n" +
+		"Not After:    " + utils.FormatDateTime(notAfter.Local()) + " (" + utils.FormatDuration(90*24*time.Hour) + " left)\n"
+	if out.String() != want {
+		t.Errorf("Summary = %q, want %q", out.String(), want)
+	}
+
+	if err := writeCertificateSummary(&out, []byte("not a certificate"), now); err == nil {
+		t.Error("Expected error for a chain without certificates")
+	}
+}
+
+func TestInspectIncludesCertificateSummary(t *testing.T) {
+	certChain := certificateExpiringIn(t, 90*24*time.Hour)
+	useTestProviders(t, mock.NewProvider([]string{"example.com"}, certChain, []byte("key")))
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	cmd.Flags().Bool("check-ocsp", false, "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := inspectCmd.RunE(cmd, []string{"example.com"}); err != nil {
+		t.Fatalf("certs inspect failed: %v", err)
+	}
+
+	var summary bytes.Buffer
+	if err := writeCertificateSummary(&summary, certChain, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	// The remaining time may differ by the moment each was formatted
+	want, _, _ := strings.Cut(summary.String(), " left)")
+	want, _, _ = strings.Cut(want, " (")
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("Inspect output does not contain the certificate summary %q:\n%s", want, out.String())
+	}
+	for _, line := range []string{"Domain:       example.com (mock)", "Issuer:", "Serial:", "Fingerprint:", "Not Before:"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Inspect output does not contain %q:\n%s", line, out.String())
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dh-kam/go-cert-provider/kube"
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/spf13/cobra"
+)
+
+// secretOptions controls certs retrieve --k8s-secret
+type secretOptions struct {
+	namespace  string
+	name       string
+	kubeconfig string
+	dryRun     bool
+}
+
+// getSecretOptions reads the Kubernetes Secret flags; it returns nil without --k8s-secret
+func getSecretOptions(cmd *cobra.Command) (*secretOptions, error) {
+	target, err := cmd.Flags().GetString("k8s-secret")
+	if err != nil {
+		return nil, err
+	}
+	kubeconfig, err := cmd.Flags().GetString("kubeconfig")
+	if err != nil {
+		return nil, err
+	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return nil, err
+	}
+
+	if target == "" {
+		if dryRun {
+			return nil, fmt.Errorf("--dry-run requires --k8s-secret")
+		}
+		return nil, nil
+	}
+
+	namespace, name, found := strings.Cut(target, "/")
+	if !found || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid --k8s-secret %q: expected <namespace>/<name>", target)
+	}
+
+	return &secretOptions{
+		namespace:  namespace,
+		name:       name,
+		kubeconfig: kubeconfig,
+		dryRun:     dryRun,
+	}, nil
+}
+
+// outputToSecret stores the certificate chain and key in a TLS Secret as tls.crt and tls.key,
+// or prints the Secret with --dry-run. With --chain leaf-only, the CA certificates go to ca.crt.
+func outputToSecret(cmd *cobra.Command, certChain, privateKey []byte, files *fileOptions, opts *secretOptions) error {
+	var caChain []byte
+	if files.leafOnly {
+		leaf, rest, err := utils.SplitLeaf(certChain)
+		if err != nil {
+			return fmt.Errorf("failed to split certificate chain: %w", err)
+		}
+		certChain, caChain = leaf, rest
+	}
+
+	secret := kube.NewTLSSecret(opts.namespace, opts.name, certChain, privateKey, caChain)
+
+	if opts.dryRun {
+		fmt.Fprintf(infoOut(cmd), "Dry run: secret %s/%s not applied\n", opts.namespace, opts.name)
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(secret)
+	}
+
+	cfg, err := kube.LoadConfig(opts.kubeconfig)
+	if err != nil {
+		return err
+	}
+	client, err := kube.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	result, err := client.ApplySecret(cmd.Context(), secret)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(infoOut(cmd), "Secret %s/%s %s (%s)\n", opts.namespace, opts.name, result, keyLabel(privateKey))
+	return nil
+}
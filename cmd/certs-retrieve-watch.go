@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	certdomain "github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/notify"
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/spf13/cobra"
+)
+
+// watchOptions controls the renewal loop of certs retrieve --watch
+type watchOptions struct {
+	renewBefore time.Duration
+	interval    time.Duration
+	reloadCmd   string
+	notifier    *notify.Async
+	expiry      *notify.ExpiryChecker
+	material    *materialOptions
+}
+
+func getWatchOptions(cmd *cobra.Command) (*watchOptions, error) {
+	renewBeforeStr, err := cmd.Flags().GetString("renew-before")
+	if err != nil {
+		return nil, err
+	}
+	intervalStr, err := cmd.Flags().GetString("watch-interval")
+	if err != nil {
+		return nil, err
+	}
+	reloadCmd, err := cmd.Flags().GetString("reload-cmd")
+	if err != nil {
+		return nil, err
+	}
+
+	renewBefore, err := utils.ParseDurationString(renewBeforeStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --renew-before: %w", err)
+	}
+	interval, err := utils.ParseDurationString(intervalStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --watch-interval: %w", err)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("--watch-interval must be positive")
+	}
+
+	opts := &watchOptions{
+		renewBefore: renewBefore,
+		interval:    interval,
+		reloadCmd:   reloadCmd,
+	}
+
+	notifier, err := getWebhookNotifier(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if notifier != nil {
+		// The renewal window doubles as the expiry warning threshold
+		opts.notifier = notifier
+		opts.expiry = notify.NewExpiryChecker(notifier, renewBefore)
+	}
+
+	return opts, nil
+}
+
+// runWatch checks the certificate on disk every interval and renews it when it nears
+// expiry, until SIGINT or SIGTERM is received
+func runWatch(cmd *cobra.Command, domain string, provider certdomain.CertificateProvider,
+	certPath string, opts *watchOptions, write func(certChain, privateKey []byte) error) error {
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if opts.notifier != nil {
+		// Let in-flight notifications finish; their retries are bounded
+		defer opts.notifier.Wait()
+	}
+
+	fmt.Fprintf(infoOut(cmd), "Watching %s for %s (renew before: %s, check interval: %s)\n",
+		certPath, domain, utils.FormatDuration(opts.renewBefore), utils.FormatDuration(opts.interval))
+
+	for {
+		if err := renewIfDue(ctx, cmd, domain, provider, certPath, opts, write); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "[%s] Renewal failed: %v\n", utils.FormatCurrentTime(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(infoOut(cmd), "Stopping certificate watch")
+			return nil
+		case <-time.After(opts.interval):
+		}
+	}
+}
+
+// renewIfDue re-fetches and rewrites the certificate when the file on disk is missing,
+// unreadable or expires within the renewal window
+func renewIfDue(ctx context.Context, cmd *cobra.Command, domain string, provider certdomain.CertificateProvider,
+	certPath string, opts *watchOptions, write func(certChain, privateKey []byte) error) error {
+
+	current, err := os.ReadFile(certPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", certPath, err)
+	}
+
+	var oldLeaf *x509.Certificate
+	if len(current) > 0 {
+		leaf, err := utils.ParseLeafCertificate(current)
+		if err == nil {
+			oldLeaf = leaf
+			remaining := time.Until(leaf.NotAfter)
+			if remaining > opts.renewBefore {
+				fmt.Fprintf(infoOut(cmd), "[%s] Certificate valid until %s (%s left), no renewal needed\n",
+					utils.FormatCurrentTime(), utils.FormatDateTime(leaf.NotAfter), utils.FormatDuration(remaining))
+				return nil
+			}
+			if opts.expiry != nil {
+				opts.expiry.Check(domain, provider.GetProviderName(), leaf.NotAfter)
+			}
+		} else {
+			fmt.Fprintf(cmd.ErrOrStderr(), "[%s] Cannot parse %s, fetching a fresh copy: %v\n",
+				utils.FormatCurrentTime(), certPath, err)
+		}
+	}
+
+	fmt.Fprintf(infoOut(cmd), "[%s] Retrieving certificate for %s from %s provider...\n",
+		utils.FormatCurrentTime(), domain, provider.GetProviderName())
+
+	certChain, privateKey, err := provider.RetrieveCertificate(ctx, domain)
+	if err != nil {
+		if hint := retrievalErrorHint(err, provider.GetProviderName()); hint != "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Hint: %s\n", hint)
+		}
+		return fmt.Errorf("failed to retrieve certificate: %w", err)
+	}
+
+	if opts.material != nil {
+		certChain, privateKey, err = opts.material.apply(certChain, privateKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The provider may not have renewed yet; avoid rewriting files and reloading for nothing.
+	// Only the leaf is compared, since the file may hold just the leaf (--chain leaf-only).
+	if oldLeaf != nil {
+		if newLeaf, err := utils.ParseLeafCertificate(certChain); err == nil && newLeaf.Equal(oldLeaf) {
+			fmt.Fprintf(infoOut(cmd), "[%s] Provider returned the same certificate, will retry later\n",
+				utils.FormatCurrentTime())
+			return nil
+		}
+	}
+
+	if err := write(certChain, privateKey); err != nil {
+		return err
+	}
+	notifyRenewed(opts, domain, provider.GetProviderName(), oldLeaf, certChain)
+
+	if opts.reloadCmd == "" {
+		return nil
+	}
+
+	fmt.Fprintf(infoOut(cmd), "[%s] Running reload command: %s\n", utils.FormatCurrentTime(), opts.reloadCmd)
+	reload := exec.CommandContext(ctx, "sh", "-c", opts.reloadCmd)
+	reload.Stdout = cmd.ErrOrStderr()
+	reload.Stderr = cmd.ErrOrStderr()
+	if err := reload.Run(); err != nil {
+		return fmt.Errorf("reload command failed: %w", err)
+	}
+
+	return nil
+}
+
+// notifyRenewed sends a renewal event with the old and new expiry, if a notifier is configured
+func notifyRenewed(opts *watchOptions, domain, providerName string, oldLeaf *x509.Certificate, certChain []byte) {
+	if opts.notifier == nil {
+		return
+	}
+
+	event := notify.Event{
+		Type:     notify.EventCertificateRenewed,
+		Domain:   domain,
+		Provider: providerName,
+	}
+	if oldLeaf != nil {
+		event.OldNotAfter = &oldLeaf.NotAfter
+	}
+	if newLeaf, err := utils.ParseLeafCertificate(certChain); err == nil {
+		event.NewNotAfter = &newLeaf.NotAfter
+	}
+
+	opts.notifier.Send(event)
+}
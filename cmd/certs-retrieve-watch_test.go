@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/cert/providers/mock"
+	"github.com/spf13/cobra"
+)
+
+// certificateExpiringIn returns a self-signed PEM certificate for example.com that expires after d
+func certificateExpiringIn(t *testing.T, d time.Duration) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(now.UnixNano()),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(d),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestRenewIfDue(t *testing.T) {
+	const renewBefore = 30 * 24 * time.Hour
+	sameCert := certificateExpiringIn(t, 10*24*time.Hour)
+
+	tests := []struct {
+		name         string
+		existing     []byte
+		served       []byte
+		wantAttempts int
+		wantWrite    bool
+		wantStderr   string
+	}{
+		{"not due", certificateExpiringIn(t, 60*24*time.Hour), nil, 0, false, "no renewal needed"},
+		{"due", certificateExpiringIn(t, 10*24*time.Hour), nil, 1, true, "Retrieving certificate"},
+		{"expired", certificateExpiringIn(t, -time.Hour), nil, 1, true, "Retrieving certificate"},
+		{"missing", nil, nil, 1, true, "Retrieving certificate"},
+		{"unparsable", []byte("not a certificate"), nil, 1, true, "Cannot parse"},
+		{"provider has not renewed yet", sameCert, sameCert, 1, false, "same certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			certPath := filepath.Join(dir, "cert.pem")
+			if tt.existing != nil {
+				if err := os.WriteFile(certPath, tt.existing, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var servedKey []byte
+			if tt.served != nil {
+				servedKey = []byte("key")
+			}
+			provider := &provisioningProvider{Provider: mock.NewProvider([]string{"example.com"}, tt.served, servedKey)}
+
+			cmd := &cobra.Command{}
+			var stderr bytes.Buffer
+			cmd.SetErr(&stderr)
+
+			reloadMarker := filepath.Join(dir, "reloaded")
+			opts := &watchOptions{renewBefore: renewBefore, interval: time.Hour, reloadCmd: "touch " + reloadMarker}
+			var written []byte
+			write := func(certChain, privateKey []byte) error {
+				written = certChain
+				return nil
+			}
+
+			if err := renewIfDue(context.Background(), cmd, "example.com", provider, certPath, opts, write); err != nil {
+				t.Fatalf("renewIfDue failed: %v", err)
+			}
+
+			if provider.attempts != tt.wantAttempts {
+				t.Errorf("Expected %d retrievals, got %d", tt.wantAttempts, provider.attempts)
+			}
+			if (written != nil) != tt.wantWrite {
+				t.Errorf("Expected write = %v, got %v", tt.wantWrite, written != nil)
+			}
+			// The reload command only runs after the files were rewritten
+			if _, err := os.Stat(reloadMarker); (err == nil) != tt.wantWrite {
+				t.Errorf("Expected reload = %v, got stat error %v", tt.wantWrite, err)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("Output does not contain %q:\n%s", tt.wantStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunWatchStopsWithContext(t *testing.T) {
+	certPath := filepath.Join(t.TempDir(), "cert.pem")
+	provider := &provisioningProvider{Provider: mock.NewProvider([]string{"example.com"}, nil, nil)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+
+	// The first check fetches the missing certificate; stopping then ends the loop
+	// without waiting for the next interval
+	opts := &watchOptions{renewBefore: 24 * time.Hour, interval: time.Hour}
+	write := func(certChain, privateKey []byte) error {
+		cancel()
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- runWatch(cmd, "example.com", provider, certPath, opts, write) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("runWatch failed: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("runWatch did not stop with its context")
+	}
+
+	if provider.attempts != 1 || !strings.Contains(stderr.String(), "Stopping certificate watch") {
+		t.Errorf("Expected one retrieval and a stop message, got %d:\n%s", provider.attempts, stderr.String())
+	}
+}
@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	certdomain "github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -29,6 +34,38 @@ Examples:
     --output-dir ./certs \
     --separate-files
 
+  # Write the private key as PKCS#8 ("BEGIN PRIVATE KEY")
+  go-cert-provider certs retrieve example.com --output-dir ./certs --key-format pkcs8
+
+  # Order the chain leaf first and drop the root CA
+  go-cert-provider certs retrieve example.com --output-dir ./certs --strip-root
+
+  # Write only the leaf to example.com.crt and the intermediates to ca.crt
+  go-cert-provider certs retrieve example.com --output-dir ./certs --separate-files \
+    --chain leaf-only --ca-file ca.crt
+
+  # Write DER files for embedded systems: example.com.der, example.com.key.der and
+  # example.com.chain-1.der, ... for the CA certificates
+  go-cert-provider certs retrieve example.com --output-dir ./certs --encoding der
+
+  # Create or update the TLS secret web/example-com-tls in the current cluster
+  go-cert-provider certs retrieve example.com --k8s-secret web/example-com-tls
+
+  # Print the subject, SANs and expiry of the written certificate to stderr
+  go-cert-provider certs retrieve example.com --output-dir ./certs --print-info
+
+  # Write a HAProxy-style bundle with the private key first
+  go-cert-provider certs retrieve example.com --output-dir /etc/haproxy/certs --key-first
+
+  # Wait up to 10 minutes for a certificate that is still being provisioned
+  go-cert-provider certs retrieve example.com --output-dir ./certs --wait 10m --poll-interval 30s
+
+  # Keep the files renewed, reloading nginx after each renewal
+  go-cert-provider certs retrieve example.com \
+    --output-dir /etc/nginx/certs \
+    --watch --renew-before 720h \
+    --reload-cmd "nginx -s reload"
+
   # With Porkbun provider
   go-cert-provider certs retrieve example.com \
     --porkbun-api-key "your-key" \
@@ -58,9 +95,112 @@ Examples:
 		if err != nil {
 			return err
 		}
+		watch, err := cmd.Flags().GetBool("watch")
+		if err != nil {
+			return err
+		}
+		keyFormat, err := cmd.Flags().GetString("key-format")
+		if err != nil {
+			return err
+		}
+		if keyFormat != "" && keyFormat != utils.KeyFormatPKCS1 && keyFormat != utils.KeyFormatPKCS8 {
+			return fmt.Errorf("invalid --key-format %q: must be %s or %s", keyFormat, utils.KeyFormatPKCS1, utils.KeyFormatPKCS8)
+		}
+		normalizeChain, err := cmd.Flags().GetBool("normalize-chain")
+		if err != nil {
+			return err
+		}
+		stripRoot, err := cmd.Flags().GetBool("strip-root")
+		if err != nil {
+			return err
+		}
+		chainMode, err := cmd.Flags().GetString("chain")
+		if err != nil {
+			return err
+		}
+		if chainMode != chainFull && chainMode != chainLeafOnly {
+			return fmt.Errorf("invalid --chain %q: must be %s or %s", chainMode, chainFull, chainLeafOnly)
+		}
+		caFileName, err := cmd.Flags().GetString("ca-file")
+		if err != nil {
+			return err
+		}
+		keyFirst, err := cmd.Flags().GetBool("key-first")
+		if err != nil {
+			return err
+		}
+		requireKeyType, err := cmd.Flags().GetString("require-key-type")
+		if err != nil {
+			return err
+		}
+		if requireKeyType != "" && requireKeyType != utils.KeyTypeRSA && requireKeyType != utils.KeyTypeEC {
+			return fmt.Errorf("invalid --require-key-type %q: must be %s or %s", requireKeyType, utils.KeyTypeRSA, utils.KeyTypeEC)
+		}
+		if keyFirst && separateFiles {
+			return fmt.Errorf("--key-first applies to bundles and cannot be combined with --separate-files")
+		}
+		if caFileName != "" && chainMode != chainLeafOnly {
+			return fmt.Errorf("--ca-file requires --chain %s", chainLeafOnly)
+		}
+		if caFileName != "" && outputDir == "" {
+			return fmt.Errorf("--ca-file requires --output-dir")
+		}
+		encoding, err := cmd.Flags().GetString("encoding")
+		if err != nil {
+			return err
+		}
+		if encoding != encodingPEM && encoding != encodingDER {
+			return fmt.Errorf("invalid --encoding %q: must be %s or %s", encoding, encodingPEM, encodingDER)
+		}
+		if encoding == encodingDER {
+			if err := checkDEROptions(outputDir, bundleFileName, caFileName, keyFirst, watch); err != nil {
+				return err
+			}
+		}
+		printInfo, err := cmd.Flags().GetBool("print-info")
+		if err != nil {
+			return err
+		}
+		wait, pollInterval, err := getWaitOptions(cmd)
+		if err != nil {
+			return err
+		}
+		secret, err := getSecretOptions(cmd)
+		if err != nil {
+			return err
+		}
+		if secret != nil {
+			switch {
+			case outputDir != "":
+				return fmt.Errorf("--k8s-secret cannot be combined with --output-dir")
+			case watch:
+				return fmt.Errorf("--k8s-secret cannot be combined with --watch; run it from a CronJob instead")
+			case encoding == encodingDER:
+				return fmt.Errorf("--k8s-secret stores PEM and cannot be combined with --encoding %s", encodingDER)
+			}
+		}
+
+		files := &fileOptions{
+			outputDir:      outputDir,
+			separateFiles:  separateFiles,
+			certFileName:   certFileName,
+			keyFileName:    keyFileName,
+			bundleFileName: bundleFileName,
+			caFileName:     caFileName,
+			leafOnly:       chainMode == chainLeafOnly,
+			keyFirst:       keyFirst,
+			der:            encoding == encodingDER,
+		}
+
+		material := &materialOptions{
+			keyFormat:      keyFormat,
+			normalizeChain: normalizeChain || stripRoot,
+			stripRoot:      stripRoot,
+			requireKeyType: requireKeyType,
+		}
 
 		// Use global app state (initialized in PersistentPreRunE)
-		if appState == nil {
+		if !appState.initialized {
 			return fmt.Errorf("certificate system not initialized")
 		}
 
@@ -68,79 +208,418 @@ Examples:
 
 		provider, err := providerRegistry.GetProviderForDomain(domain)
 		if err != nil {
-			return fmt.Errorf("no provider found for domain %s: %w", domain, err)
+			return domainNotManagedError(domain, providerRegistry.ListProviders(), providerRegistry.ListDomains())
+		}
+
+		if watch {
+			if outputDir == "" {
+				return fmt.Errorf("--watch requires --output-dir")
+			}
+
+			opts, err := getWatchOptions(cmd)
+			if err != nil {
+				return err
+			}
+			opts.material = material
+
+			certPath := certificateFilePath(domain, files)
+			write := func(certChain, privateKey []byte) error {
+				if err := outputToFiles(cmd, domain, certChain, privateKey, files); err != nil {
+					return err
+				}
+				if printInfo {
+					return writeCertificateSummary(cmd.ErrOrStderr(), certChain, time.Now())
+				}
+				return nil
+			}
+			return runWatch(cmd, domain, provider, certPath, opts, write)
 		}
 
-		fmt.Fprintf(cmd.OutOrStderr(), "Retrieving certificate for %s from %s provider...\n",
+		fmt.Fprintf(infoOut(cmd), "Retrieving certificate for %s from %s provider...\n",
 			domain, provider.GetProviderName())
 
-		certChain, privateKey, err := provider.RetrieveCertificate(domain)
+		certChain, privateKey, err := retrieveWithWait(cmd, domain, provider, wait, pollInterval)
 		if err != nil {
+			if hint := retrievalErrorHint(err, provider.GetProviderName()); hint != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Hint: %s\n", hint)
+			}
 			return fmt.Errorf("failed to retrieve certificate: %w", err)
 		}
 
-		if outputDir == "" {
-			return outputToStdout(cmd, certChain, privateKey, separateFiles)
+		certChain, privateKey, err = material.apply(certChain, privateKey)
+		if err != nil {
+			return err
 		}
 
-		return outputToFiles(cmd, domain, outputDir, certChain, privateKey,
-			separateFiles, certFileName, keyFileName, bundleFileName)
+		switch {
+		case secret != nil:
+			err = outputToSecret(cmd, certChain, privateKey, files, secret)
+		case outputDir == "":
+			err = outputToStdout(cmd, certChain, privateKey, files)
+		default:
+			err = outputToFiles(cmd, domain, certChain, privateKey, files)
+		}
+		if err != nil || !printInfo {
+			return err
+		}
+
+		// The summary goes to stderr, so it never mixes with PEM written to stdout
+		return writeCertificateSummary(cmd.ErrOrStderr(), certChain, time.Now())
 	},
 }
 
-func outputToStdout(cmd *cobra.Command, certChain, privateKey []byte, separateFiles bool) error {
-	if separateFiles {
+const (
+	// chainFull writes the chain as returned by the provider (after --normalize-chain/--strip-root)
+	chainFull = "full"
+	// chainLeafOnly writes only the leaf certificate; the rest of the chain goes to --ca-file
+	chainLeafOnly = "leaf-only"
+)
+
+const (
+	// encodingPEM writes PEM files and bundles, as returned by the provider
+	encodingPEM = "pem"
+	// encodingDER writes the certificates and the key as separate binary DER files
+	encodingDER = "der"
+)
+
+// fileOptions describes where and how retrieved certificate material is written
+type fileOptions struct {
+	outputDir      string
+	separateFiles  bool
+	certFileName   string
+	keyFileName    string
+	bundleFileName string
+	caFileName     string
+	leafOnly       bool
+	keyFirst       bool
+	der            bool
+}
+
+// materialOptions controls how retrieved certificate material is rewritten before output
+type materialOptions struct {
+	keyFormat      string
+	normalizeChain bool
+	stripRoot      bool
+	requireKeyType string // utils.KeyTypeRSA or utils.KeyTypeEC; empty accepts any key
+}
+
+// apply normalizes the chain and re-encodes the private key as requested.
+// With the zero value, the material is passed through unchanged.
+func (o *materialOptions) apply(certChain, privateKey []byte) ([]byte, []byte, error) {
+	if o.normalizeChain {
+		normalized, err := utils.NormalizeChain(certChain, o.stripRoot)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to normalize certificate chain: %w", err)
+		}
+		certChain = normalized
+	}
+
+	if o.requireKeyType != "" {
+		keyInfo, err := utils.DescribePrivateKey(privateKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot check the private key type: %w", err)
+		}
+		if keyInfo.Type != o.requireKeyType {
+			return nil, nil, fmt.Errorf("retrieved %s private key, but --require-key-type is %s", keyInfo, o.requireKeyType)
+		}
+	}
+
+	privateKey, err := utils.ConvertPrivateKey(privateKey, o.keyFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return certChain, privateKey, nil
+}
+
+// getWaitOptions returns the --wait and --poll-interval durations
+func getWaitOptions(cmd *cobra.Command) (time.Duration, time.Duration, error) {
+	waitStr, err := cmd.Flags().GetString("wait")
+	if err != nil {
+		return 0, 0, err
+	}
+	pollIntervalStr, err := cmd.Flags().GetString("poll-interval")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	wait, err := utils.ParseDurationString(waitStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid --wait: %w", err)
+	}
+	if wait < 0 {
+		return 0, 0, fmt.Errorf("--wait must not be negative")
+	}
+	pollInterval, err := utils.ParseDurationString(pollIntervalStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid --poll-interval: %w", err)
+	}
+	if pollInterval <= 0 {
+		return 0, 0, fmt.Errorf("--poll-interval must be positive")
+	}
+
+	return wait, pollInterval, nil
+}
+
+// retrieveWithWait retrieves the certificate, retrying every pollInterval while the
+// provider reports it as not found until wait elapses. Other errors are returned at once.
+func retrieveWithWait(cmd *cobra.Command, domain string, provider certdomain.CertificateProvider,
+	wait, pollInterval time.Duration) ([]byte, []byte, error) {
+
+	ctx := cmd.Context()
+	deadline := time.Now().Add(wait)
+	for attempt := 1; ; attempt++ {
+		certChain, privateKey, err := provider.RetrieveCertificate(ctx, domain)
+		if err == nil || !errors.Is(err, certdomain.ErrCertNotFound) {
+			return certChain, privateKey, err
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			if wait > 0 {
+				return nil, nil, fmt.Errorf("gave up after %d attempts in %s: %w", attempt, utils.FormatDuration(wait), err)
+			}
+			return nil, nil, err
+		}
+
+		delay := min(pollInterval, remaining)
+		fmt.Fprintf(infoOut(cmd), "[%s] Certificate for %s not found yet (attempt %d); retrying in %s, %s left\n",
+			utils.FormatCurrentTime(), domain, attempt, utils.FormatDuration(delay), utils.FormatDuration(remaining))
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// keyLabel describes a private key for status messages, e.g. "EC P-256 private key"
+func keyLabel(privateKey []byte) string {
+	keyInfo, err := utils.DescribePrivateKey(privateKey)
+	if err != nil {
+		return "private key"
+	}
+	return keyInfo.String() + " private key"
+}
+
+// retrievalErrorHint returns a user-facing suggestion for typed provider errors
+func retrievalErrorHint(err error, providerName string) string {
+	switch {
+	case errors.Is(err, certdomain.ErrCertNotFound):
+		return "the certificate may still be provisioning; try again in a few minutes"
+	case errors.Is(err, certdomain.ErrAuthFailed):
+		return fmt.Sprintf("check the %s API credentials and that API access is enabled for the domain; "+
+			"'go-cert-provider providers ping' verifies the credentials", providerName)
+	case errors.Is(err, certdomain.ErrRateLimited):
+		return "the provider is rate limiting requests; wait a moment and retry"
+	case errors.Is(err, certdomain.ErrRetrievalUnsupported):
+		return fmt.Sprintf("the %s API only exposes certificate metadata; use 'domain list --detail' for its expiry", providerName)
+	default:
+		return ""
+	}
+}
+
+// domainNotManagedError explains why domain has no provider: either no provider is
+// registered, or the domain is not among the managed ones, suggesting the closest match
+func domainNotManagedError(domain string, providers, managed []string) error {
+	if len(providers) == 0 {
+		return fmt.Errorf("domain %s is not managed: no certificate provider is configured "+
+			"(see 'go-cert-provider providers list')", domain)
+	}
+
+	message := fmt.Sprintf("domain %s is not managed by any configured provider (%s; %d domain(s) managed)",
+		domain, strings.Join(providers, ", "), len(managed))
+	if suggestion := suggestManagedDomain(domain, managed); suggestion != "" {
+		message += fmt.Sprintf("; did you mean %s?", suggestion)
+	}
+	return errors.New(message)
+}
+
+// suggestManagedDomain returns the managed domain the user most likely meant: the apex
+// of a subdomain, or the closest domain by edit distance
+func suggestManagedDomain(domain string, managed []string) string {
+	lower := strings.ToLower(strings.TrimSuffix(domain, "."))
+	for _, candidate := range managed {
+		if strings.HasSuffix(lower, "."+strings.ToLower(candidate)) {
+			return candidate
+		}
+	}
+
+	// Allow roughly one typo per three characters, so short domains do not match everything
+	if match, ok := utils.ClosestMatch(lower, managed, max(2, len(lower)/3)); ok {
+		return match
+	}
+	return ""
+}
+
+func outputToStdout(cmd *cobra.Command, certChain, privateKey []byte, files *fileOptions) error {
+	if files.leafOnly {
+		leaf, _, err := utils.SplitLeaf(certChain)
+		if err != nil {
+			return fmt.Errorf("failed to split certificate chain: %w", err)
+		}
+		certChain = leaf
+	}
+
+	fmt.Fprintf(infoOut(cmd), "Retrieved %s\n", keyLabel(privateKey))
+
+	if files.separateFiles {
 		fmt.Fprintln(cmd.OutOrStdout(), "=== Certificate Chain ===")
 		fmt.Fprintln(cmd.OutOrStdout(), string(certChain))
 		fmt.Fprintln(cmd.OutOrStdout(), "\n=== Private Key ===")
 		fmt.Fprintln(cmd.OutOrStdout(), string(privateKey))
 	} else {
-		fmt.Fprint(cmd.OutOrStdout(), string(certChain))
-		fmt.Fprint(cmd.OutOrStdout(), string(privateKey))
+		bundle, err := utils.BuildPEMBundle(certChain, privateKey, files.keyFirst)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), string(bundle))
 	}
 	return nil
 }
 
-func outputToFiles(cmd *cobra.Command, domain, outputDir string, certChain, privateKey []byte,
-	separateFiles bool, certFileName, keyFileName, bundleFileName string) error {
+// certificateFilePath returns the file that holds the certificate (the leaf comes first)
+func certificateFilePath(domain string, files *fileOptions) string {
+	if files.separateFiles {
+		certFileName := files.certFileName
+		if certFileName == "" {
+			certFileName = fmt.Sprintf("%s.crt", domain)
+		}
+		return filepath.Join(files.outputDir, certFileName)
+	}
+
+	bundleFileName := files.bundleFileName
+	if bundleFileName == "" {
+		bundleFileName = fmt.Sprintf("%s-bundle.pem", domain)
+	}
+	return filepath.Join(files.outputDir, bundleFileName)
+}
 
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
+func outputToFiles(cmd *cobra.Command, domain string, certChain, privateKey []byte, files *fileOptions) error {
+	if err := os.MkdirAll(files.outputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	if separateFiles {
-		if certFileName == "" {
-			certFileName = fmt.Sprintf("%s.crt", domain)
+	if files.der {
+		return outputDERFiles(cmd, domain, certChain, privateKey, files)
+	}
+
+	var caChain []byte
+	if files.leafOnly {
+		leaf, rest, err := utils.SplitLeaf(certChain)
+		if err != nil {
+			return fmt.Errorf("failed to split certificate chain: %w", err)
 		}
+		certChain, caChain = leaf, rest
+	}
+
+	certPath := certificateFilePath(domain, files)
+
+	if files.separateFiles {
+		keyFileName := files.keyFileName
 		if keyFileName == "" {
 			keyFileName = fmt.Sprintf("%s.key", domain)
 		}
-
-		certPath := filepath.Join(outputDir, certFileName)
-		keyPath := filepath.Join(outputDir, keyFileName)
+		keyPath := filepath.Join(files.outputDir, keyFileName)
 
 		if err := os.WriteFile(certPath, certChain, 0600); err != nil {
 			return fmt.Errorf("failed to write certificate file: %w", err)
 		}
-		fmt.Fprintf(cmd.OutOrStderr(), "Certificate saved to: %s\n", certPath)
+		fmt.Fprintf(infoOut(cmd), "Certificate saved to: %s\n", certPath)
 
 		if err := os.WriteFile(keyPath, privateKey, 0600); err != nil {
 			return fmt.Errorf("failed to write private key file: %w", err)
 		}
-		fmt.Fprintf(cmd.OutOrStderr(), "Private key saved to: %s\n", keyPath)
+		fmt.Fprintf(infoOut(cmd), "Saved %s to: %s\n", keyLabel(privateKey), keyPath)
 
 	} else {
-		if bundleFileName == "" {
-			bundleFileName = fmt.Sprintf("%s-bundle.pem", domain)
+		bundle, err := utils.BuildPEMBundle(certChain, privateKey, files.keyFirst)
+		if err != nil {
+			return err
 		}
 
-		bundlePath := filepath.Join(outputDir, bundleFileName)
-		bundle := append(certChain, privateKey...)
-
-		if err := os.WriteFile(bundlePath, bundle, 0600); err != nil {
+		if err := os.WriteFile(certPath, bundle, 0600); err != nil {
 			return fmt.Errorf("failed to write bundle file: %w", err)
 		}
-		fmt.Fprintf(cmd.OutOrStderr(), "Certificate bundle saved to: %s\n", bundlePath)
+		fmt.Fprintf(infoOut(cmd), "Certificate bundle with %s saved to: %s\n", keyLabel(privateKey), certPath)
+	}
+
+	if files.caFileName != "" {
+		if len(caChain) == 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: the provider returned no intermediate certificates; %s not written\n", files.caFileName)
+			return nil
+		}
+
+		caPath := filepath.Join(files.outputDir, files.caFileName)
+		if err := os.WriteFile(caPath, caChain, 0644); err != nil {
+			return fmt.Errorf("failed to write CA file: %w", err)
+		}
+		fmt.Fprintf(infoOut(cmd), "CA chain saved to: %s\n", caPath)
+	}
+
+	return nil
+}
+
+// checkDEROptions rejects the options that --encoding der cannot honor
+func checkDEROptions(outputDir, bundleFileName, caFileName string, keyFirst, watch bool) error {
+	switch {
+	case outputDir == "":
+		return fmt.Errorf("--encoding %s writes binary files and requires --output-dir", encodingDER)
+	case bundleFileName != "":
+		return fmt.Errorf("--bundle-file cannot be used with --encoding %s: a DER file holds a single certificate or key, so the files are always separate", encodingDER)
+	case keyFirst:
+		return fmt.Errorf("--key-first applies to PEM bundles and cannot be used with --encoding %s", encodingDER)
+	case caFileName != "":
+		return fmt.Errorf("--ca-file cannot be used with --encoding %s; the CA certificates are written as <domain>.chain-<n>.der", encodingDER)
+	case watch:
+		return fmt.Errorf("--watch cannot be used with --encoding %s", encodingDER)
+	}
+	return nil
+}
+
+// outputDERFiles writes the leaf certificate to <domain>.der (or --cert-file), the private key
+// to <domain>.key.der (or --key-file) and, unless --chain leaf-only, each CA certificate of
+// the chain to <domain>.chain-<n>.der
+func outputDERFiles(cmd *cobra.Command, domain string, certChain, privateKey []byte, files *fileOptions) error {
+	certs, err := utils.CertificatesDER(certChain)
+	if err != nil {
+		return fmt.Errorf("failed to decode certificate chain: %w", err)
+	}
+	keyDER, err := utils.PrivateKeyDER(privateKey)
+	if err != nil {
+		return fmt.Errorf("failed to decode private key: %w", err)
+	}
+
+	certFileName := files.certFileName
+	if certFileName == "" {
+		certFileName = fmt.Sprintf("%s.der", domain)
+	}
+	certPath := filepath.Join(files.outputDir, certFileName)
+	if err := os.WriteFile(certPath, certs[0], 0600); err != nil {
+		return fmt.Errorf("failed to write certificate file: %w", err)
+	}
+	fmt.Fprintf(infoOut(cmd), "DER certificate saved to: %s\n", certPath)
+
+	keyFileName := files.keyFileName
+	if keyFileName == "" {
+		keyFileName = fmt.Sprintf("%s.key.der", domain)
+	}
+	keyPath := filepath.Join(files.outputDir, keyFileName)
+	if err := os.WriteFile(keyPath, keyDER, 0600); err != nil {
+		return fmt.Errorf("failed to write private key file: %w", err)
+	}
+	fmt.Fprintf(infoOut(cmd), "Saved DER %s to: %s\n", keyLabel(privateKey), keyPath)
+
+	if files.leafOnly {
+		return nil
+	}
+	for i, ca := range certs[1:] {
+		caPath := filepath.Join(files.outputDir, fmt.Sprintf("%s.chain-%d.der", domain, i+1))
+		if err := os.WriteFile(caPath, ca, 0644); err != nil {
+			return fmt.Errorf("failed to write CA certificate file: %w", err)
+		}
+		fmt.Fprintf(infoOut(cmd), "DER CA certificate saved to: %s\n", caPath)
 	}
 
 	return nil
@@ -152,6 +631,26 @@ func init() {
 	retrieveCmd.Flags().String("cert-file", "", "Certificate file name (default: <domain>.crt)")
 	retrieveCmd.Flags().String("key-file", "", "Private key file name (default: <domain>.key)")
 	retrieveCmd.Flags().String("bundle-file", "", "Bundle file name (default: <domain>-bundle.pem)")
+	retrieveCmd.Flags().String("key-format", "", "Re-encode the private key as pkcs1 (RSA/EC traditional PEM) or pkcs8 (default: as returned by the provider)")
+	retrieveCmd.Flags().Bool("normalize-chain", false, "Reorder the certificate chain from leaf to root (fails if the chain is incomplete)")
+	retrieveCmd.Flags().Bool("strip-root", false, "Remove the self-signed root CA from the chain (implies --normalize-chain)")
+	retrieveCmd.Flags().String("chain", chainFull, "Chain to write with the certificate: full (leaf and CA certificates) or leaf-only")
+	retrieveCmd.Flags().String("ca-file", "", "With --chain leaf-only, write the CA certificates to this file in --output-dir")
+	retrieveCmd.Flags().String("require-key-type", "", "Fail unless the retrieved private key is of this type: rsa or ec")
+	retrieveCmd.Flags().String("encoding", encodingPEM, "File encoding: pem, or der for separate binary DER files (<domain>.der, <domain>.key.der)")
+	retrieveCmd.Flags().Bool("print-info", false, "After writing, print the certificate's subject, SANs and expiry to stderr")
+	retrieveCmd.Flags().Bool("key-first", false, "Put the private key before the certificate chain in the bundle (HAProxy style)")
+	retrieveCmd.Flags().String("wait", "0", "Retry while the certificate is not found yet, for up to this duration (e.g., 10m)")
+	retrieveCmd.Flags().String("poll-interval", "15s", "With --wait, how long to wait between attempts")
+	retrieveCmd.Flags().String("k8s-secret", "", "Create or update this Kubernetes TLS secret (<namespace>/<name>) instead of writing files")
+	retrieveCmd.Flags().String("kubeconfig", "", "With --k8s-secret, kubeconfig file to use (default: in-cluster service account, KUBECONFIG or ~/.kube/config)")
+	retrieveCmd.Flags().Bool("dry-run", false, "With --k8s-secret, print the secret as JSON instead of applying it")
+	retrieveCmd.Flags().Bool("watch", false, "Keep running and rewrite the files when the certificate nears expiry (requires --output-dir)")
+	retrieveCmd.Flags().String("renew-before", "720h", "With --watch, renew when the certificate on disk expires within this duration (e.g., 720h, 30d)")
+	retrieveCmd.Flags().String("watch-interval", "1h", "With --watch, how often to check the certificate on disk")
+	retrieveCmd.Flags().String("reload-cmd", "", "With --watch, shell command to run after the files are renewed (e.g., \"nginx -s reload\")")
+
+	retrieveCmd.MarkFlagsMutuallyExclusive("wait", "watch")
 
 	certsCmd.AddCommand(retrieveCmd)
 }
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	certdomain "github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/cert/providers/mock"
+	"github.com/spf13/cobra"
+)
+
+// provisioningProvider fails retrievals with the scripted errors, then serves the mock certificate
+type provisioningProvider struct {
+	*mock.Provider
+	errs     []error
+	attempts int
+}
+
+func (p *provisioningProvider) RetrieveCertificate(ctx context.Context, domainName string) ([]byte, []byte, error) {
+	p.attempts++
+	if p.attempts <= len(p.errs) {
+		return nil, nil, p.errs[p.attempts-1]
+	}
+	return p.Provider.RetrieveCertificate(ctx, domainName)
+}
+
+func TestRetrieveWithWait(t *testing.T) {
+	notFound := fmt.Errorf("no bundle yet: %w", certdomain.ErrCertNotFound)
+	authFailed := fmt.Errorf("invalid API key: %w", certdomain.ErrAuthFailed)
+
+	notFoundTimes := func(n int) []error {
+		errs := make([]error, n)
+		for i := range errs {
+			errs[i] = notFound
+		}
+		return errs
+	}
+
+	tests := []struct {
+		name         string
+		errs         []error
+		wait         time.Duration
+		wantAttempts int
+		wantErr      error
+		wantMessage  string
+	}{
+		{"retries until the certificate is ready", []error{notFound, notFound}, time.Minute, 3, nil, ""},
+		{"gives up at the deadline", notFoundTimes(50), 50 * time.Millisecond, 0, certdomain.ErrCertNotFound, "gave up after"},
+		{"fails fast on other errors", []error{authFailed}, time.Minute, 1, certdomain.ErrAuthFailed, ""},
+		{"does not retry without --wait", []error{notFound}, 0, 1, certdomain.ErrCertNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &provisioningProvider{Provider: mock.NewProvider([]string{"example.com"}, nil, nil), errs: tt.errs}
+
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+			var stderr bytes.Buffer
+			cmd.SetErr(&stderr)
+
+			certChain, _, err := retrieveWithWait(cmd, "example.com", provider, tt.wait, 10*time.Millisecond)
+			if tt.wantErr == nil {
+				if err != nil || len(certChain) == 0 {
+					t.Fatalf("Expected the certificate, got error %v", err)
+				}
+			} else if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantMessage != "" && !strings.Contains(err.Error(), tt.wantMessage) {
+				t.Errorf("Error %q does not contain %q", err, tt.wantMessage)
+			}
+
+			if tt.wantAttempts > 0 && provider.attempts != tt.wantAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.wantAttempts, provider.attempts)
+			}
+			if tt.wantAttempts == 0 && (provider.attempts < 2 || provider.attempts > len(tt.errs)) {
+				t.Errorf("Expected a few attempts before the deadline, got %d", provider.attempts)
+			}
+			if retries := strings.Count(stderr.String(), "not found yet"); tt.wantErr == nil && retries != provider.attempts-1 {
+				t.Errorf("Expected a progress message per retry, got:\n%s", stderr.String())
+			}
+		})
+	}
+}
+
+func TestRetrieveWithWaitStopsOnCancel(t *testing.T) {
+	provider := &provisioningProvider{
+		Provider: mock.NewProvider([]string{"example.com"}, nil, nil),
+		errs:     []error{certdomain.ErrCertNotFound, certdomain.ErrCertNotFound},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	cmd.SetErr(&bytes.Buffer{})
+
+	// A canceled context ends the wait instead of sleeping until the next poll
+	if _, _, err := retrieveWithWait(cmd, "example.com", provider, time.Minute, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the wait to end with the context, got %v", err)
+	}
+	if provider.attempts != 1 {
+		t.Errorf("Expected one attempt, got %d", provider.attempts)
+	}
+}
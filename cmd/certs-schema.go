@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dh-kam/go-cert-provider/graph"
+	"github.com/spf13/cobra"
+)
+
+// schemaCmd represents the certs schema command
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the GraphQL schema (SDL) served by certs serve",
+	Long: `Print the GraphQL schema of the certificate API in SDL form.
+
+The schema is rendered from the built-in server, so no running server, provider
+or credentials are needed. Check the output into client repositories to generate
+typed clients.
+
+Examples:
+  go-cert-provider certs schema > schema.graphql`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := fmt.Fprint(cmd.OutOrStdout(), graph.SchemaSDL())
+		return err
+	},
+}
+
+func init() {
+	certsCmd.AddCommand(schemaCmd)
+}
@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -14,10 +20,17 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/dh-kam/go-cert-provider/audit"
+	"github.com/dh-kam/go-cert-provider/auth"
+	"github.com/dh-kam/go-cert-provider/cert/registry"
 	"github.com/dh-kam/go-cert-provider/config"
 	"github.com/dh-kam/go-cert-provider/graph"
 	"github.com/dh-kam/go-cert-provider/graph/generated"
+	"github.com/dh-kam/go-cert-provider/notify"
+	"github.com/dh-kam/go-cert-provider/session"
+	"github.com/dh-kam/go-cert-provider/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 )
 
@@ -31,6 +44,13 @@ The server provides:
 - GraphQL API endpoint at /graphql
 - GraphQL Playground at /
 - Health check endpoint at /health
+- Readiness endpoint at /readyz (pings the provider APIs; 503 when one fails)
+- Provider reload endpoint at /admin/reload (POST, requires a token allowed for "*")
+- Session statistics at /admin/stats (same token requirement)
+- Certificate warming at /admin/warm (POST, same token requirement)
+
+Sending SIGHUP to the server also reloads the providers, re-running domain
+auto-discovery so added domains become retrievable without a restart.
 
 Examples:
   # Start server with default settings
@@ -39,6 +59,12 @@ Examples:
   # Start server on custom port
   go-cert-provider certs serve --listen-port 8080
 
+  # Also verify in /readyz that a certificate can actually be retrieved
+  go-cert-provider certs serve --readiness-probe-domain example.com
+
+  # Listen on a unix domain socket (e.g., for a sidecar)
+  go-cert-provider certs serve --listen-socket /run/go-cert-provider/api.sock
+
   # Start with Porkbun provider
   go-cert-provider certs serve \
     --porkbun-api-key "your-key" \
@@ -53,12 +79,116 @@ Examples:
 		if err != nil {
 			return err
 		}
+		listenSocket, err := cmd.Flags().GetString("listen-socket")
+		if err != nil {
+			return err
+		}
 		jwtSecretKey, err := cmd.Flags().GetString("jwt-secret-key")
 		if err != nil {
 			return err
 		}
+		jwtPublicKeyFile, err := cmd.Flags().GetString("jwt-public-key-file")
+		if err != nil {
+			return err
+		}
+		strictSecret, err := cmd.Flags().GetBool("strict-secret")
+		if err != nil {
+			return err
+		}
+		expectedAudience, err := cmd.Flags().GetString("expected-audience")
+		if err != nil {
+			return err
+		}
+		expectedIssuer, err := cmd.Flags().GetString("expected-issuer")
+		if err != nil {
+			return err
+		}
+		clockSkew, err := cmd.Flags().GetDuration("jwt-clock-skew")
+		if err != nil {
+			return err
+		}
+		if clockSkew, err = clockSkewOption(clockSkew); err != nil {
+			return err
+		}
+		auditLogFile, err := cmd.Flags().GetString("audit-log-file")
+		if err != nil {
+			return err
+		}
+		rateLimit, err := cmd.Flags().GetInt("rate-limit")
+		if err != nil {
+			return err
+		}
+		if rateLimit < 0 {
+			return fmt.Errorf("rate-limit must not be negative")
+		}
+		maxSessionsPerUser, err := cmd.Flags().GetInt("max-sessions-per-user")
+		if err != nil {
+			return err
+		}
+		maxSessions, err := cmd.Flags().GetInt("max-sessions")
+		if err != nil {
+			return err
+		}
+		if maxSessions < 0 {
+			return fmt.Errorf("max-sessions must not be negative")
+		}
+		sessionLimitPolicy, err := cmd.Flags().GetString("session-limit-policy")
+		if err != nil {
+			return err
+		}
+		policy := session.SessionLimitPolicy(sessionLimitPolicy)
+		if policy != session.SessionLimitEvictOldest && policy != session.SessionLimitReject {
+			return fmt.Errorf("invalid --session-limit-policy %q: must be %s or %s",
+				sessionLimitPolicy, session.SessionLimitEvictOldest, session.SessionLimitReject)
+		}
+		requireToken, err := cmd.Flags().GetBool("require-token")
+		if err != nil {
+			return err
+		}
+		allowIntrospection, err := cmd.Flags().GetBool("allow-introspection")
+		if err != nil {
+			return err
+		}
+		compression, err := cmd.Flags().GetBool("compression")
+		if err != nil {
+			return err
+		}
+		healthRequireAuth, err := cmd.Flags().GetBool("health-require-auth")
+		if err != nil {
+			return err
+		}
+		expiryWarningStr, err := cmd.Flags().GetString("expiry-warning")
+		if err != nil {
+			return err
+		}
+		expiryWarning, err := utils.ParseDurationString(expiryWarningStr)
+		if err != nil {
+			return fmt.Errorf("invalid --expiry-warning: %w", err)
+		}
+		probeDomain, err := cmd.Flags().GetString("readiness-probe-domain")
+		if err != nil {
+			return err
+		}
+		probeInterval, err := cmd.Flags().GetDuration("readiness-probe-interval")
+		if err != nil {
+			return err
+		}
+		if probeInterval <= 0 {
+			return fmt.Errorf("readiness-probe-interval must be positive")
+		}
+		readTimeout, writeTimeout, idleTimeout, err := getServerTimeouts(cmd)
+		if err != nil {
+			return err
+		}
+		certCacheTTL, err := cmd.Flags().GetDuration("cert-cache-ttl")
+		if err != nil {
+			return err
+		}
+		if certCacheTTL < 0 {
+			return fmt.Errorf("--cert-cache-ttl must not be negative")
+		}
 
-		if appState == nil {
+		if !appState.initialized {
 			return fmt.Errorf("certificate system not initialized")
 		}
 
@@ -72,6 +202,29 @@ Examples:
 			printJWTSecretKeyHelp(cmd.ErrOrStderr())
 			return fmt.Errorf("jwt secret key is required for server operation")
 		}
+		if err := checkJWTSecretKeyStrength(cmd, jwtSecretKey, strictSecret); err != nil {
+			return err
+		}
+
+		if expectedAudience == "" {
+			expectedAudience = os.Getenv("JWT_EXPECTED_AUDIENCE")
+		}
+		if expectedIssuer == "" {
+			expectedIssuer = os.Getenv("JWT_EXPECTED_ISSUER")
+		}
+		if jwtPublicKeyFile == "" {
+			jwtPublicKeyFile = os.Getenv("JWT_PUBLIC_KEY_FILE")
+		}
+		jwtPublicKey, err := loadJWTPublicKey(jwtPublicKeyFile)
+		if err != nil {
+			return err
+		}
+		validationOptions := auth.ValidationOptions{
+			ExpectedAudience: expectedAudience,
+			ExpectedIssuer:   expectedIssuer,
+			ClockSkew:        clockSkew,
+			PublicKey:        jwtPublicKey,
+		}
 
 		// Validate that we have at least one domain to manage
 		domains := providerRegistry.ListDomains()
@@ -103,6 +256,73 @@ For more information, see: go-cert-provider domain list --help`)
 		fmt.Printf("Configured providers: %v\n", bootstrapManager.GetConfiguredProviders())
 		fmt.Printf("Managed domains: %v\n", domains)
 		fmt.Printf("JWT authentication: enabled\n")
+		if readOnly, _ := cmd.Flags().GetBool("read-only"); readOnly {
+			fmt.Printf("Read-only mode: certificate retrieval disabled\n")
+		}
+		if expectedAudience != "" {
+			fmt.Printf("JWT expected audience: %s\n", expectedAudience)
+		}
+		if expectedIssuer != "" {
+			fmt.Printf("JWT expected issuer: %s\n", expectedIssuer)
+		}
+		if probeDomain != "" {
+			if readOnly, _ := cmd.Flags().GetBool("read-only"); readOnly {
+				return fmt.Errorf("--readiness-probe-domain cannot be used with --read-only, which refuses every retrieval")
+			}
+			if _, err := providerRegistry.GetProviderForDomain(probeDomain); err != nil {
+				return domainNotManagedError(probeDomain, providerRegistry.ListProviders(), domains)
+			}
+			fmt.Printf("Readiness probe: retrieving %s at most every %s\n", probeDomain, utils.FormatDuration(probeInterval))
+		}
+		if requireToken {
+			fmt.Printf("GraphQL bearer token: required (introspection without token: %v)\n", allowIntrospection)
+		}
+		if healthRequireAuth {
+			fmt.Printf("Health details: bearer token required (unauthenticated /health returns only the status)\n")
+		}
+
+		// Audit events go to the log file when given, otherwise to stdout as JSON lines
+		auditLogger := audit.NewLogger(os.Stdout)
+		if auditLogFile != "" {
+			auditLogger, err = audit.OpenFile(auditLogFile)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Audit log: %s\n", auditLogFile)
+		}
+		defer auditLogger.Close()
+
+		if certCacheTTL > 0 {
+			providerRegistry.SetCertificateCacheTTL(certCacheTTL)
+			fmt.Printf("Certificate cache: retrieved certificates are reused for %s\n", certCacheTTL)
+		}
+
+		var rateLimiter *session.RateLimiter
+		if rateLimit > 0 {
+			rateLimiter = session.NewRateLimiter(rateLimit)
+			defer rateLimiter.Close()
+			fmt.Printf("Certificate rate limit: %d requests per minute per user\n", rateLimit)
+		}
+
+		if maxSessionsPerUser > 0 {
+			session.GetGlobalManager().SetSessionLimit(maxSessionsPerUser, policy)
+			fmt.Printf("Session limit: %d per user (%s)\n", maxSessionsPerUser, policy)
+		}
+		if maxSessions > 0 {
+			session.GetGlobalManager().SetMaxSessions(maxSessions)
+			fmt.Printf("Session cap: %d in total, least recently used evicted first\n", maxSessions)
+		}
+
+		// Retrieved certificates expiring within the warning window are reported once each
+		var expiryChecker *notify.ExpiryChecker
+		notifier, err := getWebhookNotifier(cmd)
+		if err != nil {
+			return err
+		}
+		if notifier != nil {
+			defer notifier.Wait()
+			expiryChecker = notify.NewExpiryChecker(notifier, expiryWarning)
+		}
 
 		serverConfig := config.NewServerConfig()
 		if listenPort != 0 {
@@ -112,7 +332,11 @@ For more information, see: go-cert-provider domain list --help`)
 			serverConfig.SetAddr(listenAddr)
 		}
 
-		router := gin.Default()
+		router := gin.New()
+		router.Use(requestIDMiddleware(), gin.LoggerWithFormatter(formatRequestLog), gin.Recovery())
+		if compression {
+			router.Use(compressionMiddleware(compressionMinSize))
+		}
 
 		// GraphQL playground
 		router.GET("/", gin.WrapH(playground.Handler("GraphQL playground", "/graphql")))
@@ -121,34 +345,127 @@ For more information, see: go-cert-provider domain list --help`)
 		gqlHandler := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: &graph.Resolver{}}))
 		gqlHandler.AddTransport(transport.POST{})
 		gqlHandler.Use(extension.Introspection{})
+		gqlHandler.SetErrorPresenter(graph.PresentError)
 
-		// Custom middleware to add gin context, JWT secret, and provider registry to GraphQL context
-		router.POST("/graphql", func(c *gin.Context) {
+		// Custom middleware to add gin context, JWT secret, and provider registry to GraphQL context.
+		// Bearer tokens are validated before the request reaches the resolvers.
+		router.POST("/graphql", graphqlAuthMiddleware(jwtSecretKey, validationOptions, requireToken, allowIntrospection), func(c *gin.Context) {
 			// Add gin context, JWT secret key, and provider registry to the request context
 			ctx := context.WithValue(c.Request.Context(), graph.ContextKeyGin, c)
 			ctx = context.WithValue(ctx, graph.ContextKeyJWTSecret, jwtSecretKey)
+			ctx = context.WithValue(ctx, graph.ContextKeyJWTValidation, validationOptions)
 			ctx = context.WithValue(ctx, graph.ContextKeyCertRegistry, providerRegistry)
+			ctx = context.WithValue(ctx, graph.ContextKeyAuditLogger, auditLogger)
+			ctx = context.WithValue(ctx, graph.ContextKeyRateLimiter, rateLimiter)
+			ctx = context.WithValue(ctx, graph.ContextKeyExpiryChecker, expiryChecker)
 			c.Request = c.Request.WithContext(ctx)
 
 			// Call the GraphQL handler
 			gin.WrapH(gqlHandler)(c)
 		})
 
-		// Health check endpoint
-		router.GET("/health", func(c *gin.Context) {
+		// Reload re-runs provider auto-discovery; reloads are serialized so a SIGHUP
+		// and an API call cannot interleave
+		var reloadMutex sync.Mutex
+		reloadProviders := func(trigger string) (*registry.DomainChanges, error) {
+			reloadMutex.Lock()
+			defer reloadMutex.Unlock()
+
+			ctx := context.Background()
+			if initTimeout, _ := cmd.Flags().GetDuration("init-timeout"); initTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, initTimeout)
+				defer cancel()
+			}
+
+			changes, err := bootstrapManager.ReloadProviders(ctx)
+			printDomainChanges(trigger, changes, err)
+			return changes, err
+		}
+
+		// Admin endpoints require a bearer token allowed for all domains
+		admin := router.Group("/admin", func(c *gin.Context) {
+			if status, err := authorizeAdminRequest(c, jwtSecretKey, validationOptions); err != nil {
+				c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			}
+		})
+
+		admin.POST("/reload", func(c *gin.Context) {
+			changes, err := reloadProviders("admin API")
+			response := gin.H{
+				"added":   changes.Added,
+				"removed": changes.Removed,
+				"domains": providerRegistry.ListDomains(),
+			}
+			if err != nil {
+				response["error"] = err.Error()
+				c.JSON(http.StatusInternalServerError, response)
+				return
+			}
+			c.JSON(http.StatusOK, response)
+		})
+
+		// Warming retrieves certificates like users do, so it shares the rate limit setting
+		var allowWarm func() (bool, time.Duration)
+		if rateLimiter != nil {
+			allowWarm = func() (bool, time.Duration) {
+				return rateLimiter.Allow(warmRateLimitKey)
+			}
+		}
+		admin.POST("/warm", warmHandler(providerRegistry, allowWarm))
+
+		admin.GET("/sessions", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{
-				"status":    "ok",
-				"version":   config.Version,
-				"providers": bootstrapManager.GetConfiguredProviders(),
-				"domains":   providerRegistry.ListDomains(),
+				"sessions": session.GetGlobalManager().ListSessions(),
 			})
 		})
 
-		srv := &http.Server{
-			Addr:              serverConfig.GetListenAddr(),
-			Handler:           router,
-			ReadHeaderTimeout: 10 * time.Second,
-		}
+		admin.GET("/stats", func(c *gin.Context) {
+			expiringWithin := defaultStatsExpiringWithin
+			if value := c.Query("expiring_within"); value != "" {
+				parsed, err := time.ParseDuration(value)
+				if err != nil || parsed < 0 {
+					c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid expiring_within %q: must be a duration such as 15m", value)})
+					return
+				}
+				expiringWithin = parsed
+			}
+
+			stats := session.GetGlobalManager().Stats(expiringWithin)
+			c.JSON(http.StatusOK, gin.H{
+				"sessions":        stats,
+				"expiring_within": expiringWithin.String(),
+			})
+		})
+
+		admin.DELETE("/sessions/:id", func(c *gin.Context) {
+			sessionID := c.Param("id")
+			if !session.GetGlobalManager().DeleteSession(sessionID) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+				return
+			}
+
+			event := audit.Event{
+				Event:     audit.EventSessionRevoked,
+				ClientIP:  c.ClientIP(),
+				Reason:    "revoked via admin API: " + sessionID,
+				RequestID: c.GetString(requestIDKey),
+			}
+			if err := auditLogger.Log(event); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+			fmt.Printf("Session revoked via admin API: %s\n", sessionID)
+			c.Status(http.StatusNoContent)
+		})
+
+		// Health check endpoint
+		router.GET("/health", healthHandler(bootstrapManager, providerRegistry, jwtSecretKey, validationOptions, healthRequireAuth))
+
+		// Readiness endpoint; unlike /health it calls the provider APIs
+		readiness := newReadinessChecker(bootstrapManager, providerRegistry, probeDomain, probeInterval, jwtSecretKey, validationOptions)
+		router.GET("/readyz", readiness.handle)
+
+		srv := newHTTPServer(serverConfig.GetListenAddr(), router, readTimeout, writeTimeout, idleTimeout)
 
 		go func() {
 			sigChan := make(chan os.Signal, 1)
@@ -162,6 +479,31 @@ For more information, see: go-cert-provider domain list --help`)
 			fmt.Println("Server exiting")
 		}()
 
+		go func() {
+			hupChan := make(chan os.Signal, 1)
+			signal.Notify(hupChan, syscall.SIGHUP)
+			for range hupChan {
+				_, _ = reloadProviders("SIGHUP")
+			}
+		}()
+
+		if listenSocket != "" {
+			listener, err := listenUnixSocket(listenSocket)
+			if err != nil {
+				return err
+			}
+			defer removeSocketFile(listenSocket)
+
+			fmt.Printf("Server starting on unix socket %s\n", listenSocket)
+			fmt.Printf("GraphQL Endpoint: curl --unix-socket %s http://localhost/graphql\n", listenSocket)
+			fmt.Printf("Health Check: curl --unix-socket %s http://localhost/health\n", listenSocket)
+
+			if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+				return fmt.Errorf("failed to serve on unix socket: %w", err)
+			}
+			return nil
+		}
+
 		fmt.Printf("Server starting on %s\n", serverConfig.GetListenAddr())
 		fmt.Printf("GraphQL Playground: http://%s/\n", serverConfig.GetListenAddr())
 		fmt.Printf("GraphQL Endpoint: http://%s/graphql\n", serverConfig.GetListenAddr())
@@ -175,15 +517,263 @@ For more information, see: go-cert-provider domain list --help`)
 	},
 }
 
+// getServerTimeouts reads the HTTP server timeouts; zero disables a timeout
+func getServerTimeouts(cmd *cobra.Command) (read, write, idle time.Duration, err error) {
+	timeouts := []struct {
+		name  string
+		value *time.Duration
+	}{{"read-timeout", &read}, {"write-timeout", &write}, {"idle-timeout", &idle}}
+	for _, timeout := range timeouts {
+		if *timeout.value, err = cmd.Flags().GetDuration(timeout.name); err != nil {
+			return 0, 0, 0, err
+		}
+		if *timeout.value < 0 {
+			return 0, 0, 0, fmt.Errorf("--%s must not be negative", timeout.name)
+		}
+	}
+	return read, write, idle, nil
+}
+
+// newHTTPServer creates the server with the timeouts read by getServerTimeouts.
+// http.Server falls back to the read timeout for a zero IdleTimeout, so a disabled
+// idle timeout is passed as negative.
+func newHTTPServer(addr string, handler http.Handler, read, write, idle time.Duration) *http.Server {
+	if idle == 0 {
+		idle = -1
+	}
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       read,
+		WriteTimeout:      write,
+		IdleTimeout:       idle,
+	}
+}
+
+// addServerTimeoutFlags adds the flags read by getServerTimeouts
+func addServerTimeoutFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.Duration("read-timeout", 30*time.Second, "Maximum time to read a request, including its body (0 disables it)")
+	flags.Duration("write-timeout", 2*time.Minute, "Maximum time to write a response; generous since certificate retrieval can be slow (0 disables it)")
+	flags.Duration("idle-timeout", 2*time.Minute, "How long an idle keep-alive connection is kept open (0 disables it)")
+}
+
 func init() {
 	flags := serveCmd.Flags()
 	flags.Int("listen-port", 0, "Port to listen on (overrides LISTEN_PORT env var)")
 	flags.String("listen-addr", "", "Address to listen on (overrides LISTEN_ADDR env var)")
+	flags.String("listen-socket", "", "Listen on this unix domain socket instead of TCP; access is governed by file permissions")
 	flags.String("jwt-secret-key", "", "JWT secret key for token verification (overrides JWT_SECRET_KEY env var)")
+	flags.String("jwt-public-key-file", "", "PEM EC P-256 public key; also accept ES256 tokens signed with its private key (overrides JWT_PUBLIC_KEY_FILE env var)")
+	flags.Bool("strict-secret", false, "Refuse to start when the JWT secret key is shorter than 32 bytes")
+	flags.String("expected-audience", "", "Reject tokens whose aud claim does not contain this value (overrides JWT_EXPECTED_AUDIENCE env var)")
+	flags.String("expected-issuer", "", "Reject tokens whose iss claim differs, e.g. \"go-cert-provider\" (overrides JWT_EXPECTED_ISSUER env var)")
+	flags.Duration("jwt-clock-skew", auth.DefaultClockSkew, "Clock skew tolerance applied to token exp/nbf/iat checks (0 for none)")
+	flags.Int("rate-limit", 0, "Maximum certificate retrievals per minute per user (0 disables rate limiting)")
+	flags.Int("max-sessions-per-user", 0, "Maximum concurrent login sessions per user ID (0 means unlimited)")
+	flags.Int("max-sessions", 0, "Maximum sessions across all users; the least recently used session is evicted at the cap (0 means unlimited)")
+	flags.String("session-limit-policy", string(session.SessionLimitEvictOldest), "What to do when a user reaches --max-sessions-per-user: evict (drop the oldest session) or reject (refuse the login)")
+	flags.String("audit-log-file", "", "Append certificate retrieval audit events as JSON lines to this file (default: stdout)")
+	flags.Bool("require-token", false, "Reject GraphQL requests without a valid Authorization: Bearer token with 401 before they reach the resolvers")
+	flags.Bool("allow-introspection", false, "With --require-token, let schema introspection queries through without a token")
+	flags.Bool("compression", true, "Compress responses of 1 KiB or more with gzip or deflate when the client's Accept-Encoding allows it")
+	flags.Bool("health-require-auth", false, "Return only the status from /health unless a valid bearer token is sent, hiding providers and domains")
+	flags.String("expiry-warning", "720h", "With --webhook-url, report retrieved certificates expiring within this duration (e.g., 720h, 30d)")
+	flags.String("readiness-probe-domain", "", "Make /readyz also retrieve this domain's certificate, catching SSL-endpoint problems a ping misses (consumes API quota)")
+	flags.Duration("readiness-probe-interval", 5*time.Minute, "How long a --readiness-probe-domain result is reused before /readyz retrieves the certificate again")
+	flags.Duration("cert-cache-ttl", 0, "Reuse a retrieved certificate for this long instead of calling the provider again; certs warm fills the cache (0 disables caching)")
+	addServerTimeoutFlags(serveCmd)
+
+	serveCmd.MarkFlagsMutuallyExclusive("listen-socket", "listen-port")
+	serveCmd.MarkFlagsMutuallyExclusive("listen-socket", "listen-addr")
 
 	certsCmd.AddCommand(serveCmd)
 }
 
+// listenUnixSocket listens on a unix domain socket, replacing a stale socket file
+// left behind by a previous run. Regular files and sockets still in use are never removed.
+func listenUnixSocket(path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("refusing to replace %s: not a unix socket", path)
+		}
+
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("unix socket %s is already in use", path)
+		}
+
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale unix socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on unix socket: %w", err)
+	}
+
+	return listener, nil
+}
+
+// removeSocketFile deletes the socket file on shutdown if the listener has not already done so
+func removeSocketFile(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Warning: failed to remove unix socket %s: %v\n", path, err)
+	}
+}
+
+// defaultStatsExpiringWithin is the /admin/stats window for sessions expiring soon
+const defaultStatsExpiringWithin = 15 * time.Minute
+
+const (
+	requestIDHeader = "X-Request-ID"
+	requestIDKey    = "request_id"
+)
+
+// requestIDMiddleware assigns each request an ID, reusing a well-formed X-Request-ID from
+// the client. The ID is echoed in the response header, logged, and added to the request
+// context so GraphQL errors and audit events carry it.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		c.Set(requestIDKey, requestID)
+		c.Header(requestIDHeader, requestID)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), graph.ContextKeyRequestID, requestID))
+
+		c.Next()
+	}
+}
+
+// isValidRequestID accepts client-supplied IDs that are safe to echo and log
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > 128 {
+		return false
+	}
+
+	for _, r := range requestID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.:", r)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// formatRequestLog is gin's access log line with the request ID appended
+func formatRequestLog(param gin.LogFormatterParams) string {
+	requestID, _ := param.Keys[requestIDKey].(string)
+
+	line := fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v | request_id=%s\n",
+		param.TimeStamp.In(utils.DisplayLocation()).Format("2006/01/02 - 15:04:05"),
+		param.StatusCode,
+		param.Latency,
+		param.ClientIP,
+		param.Method,
+		param.Path,
+		requestID,
+	)
+	if param.ErrorMessage != "" {
+		line += param.ErrorMessage
+	}
+
+	return line
+}
+
+// authorizeAdminRequest checks the bearer token of an admin API request.
+// Only tokens allowed for every domain ("*") may use the admin API; a scoped token
+// also needs a scope that retrieves every domain ("retrieve:*").
+func authorizeAdminRequest(c *gin.Context, jwtSecretKey string, validationOptions auth.ValidationOptions) (int, error) {
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return http.StatusUnauthorized, fmt.Errorf("bearer token required")
+	}
+
+	claims, err := auth.ParseJWTWithOptions(token, jwtSecretKey, validationOptions)
+	if err != nil {
+		return http.StatusUnauthorized, fmt.Errorf("invalid token: %w", err)
+	}
+
+	if !slices.Contains(claims.AllowedDomains, "*") {
+		return http.StatusForbidden, fmt.Errorf("admin API requires a token allowed for all domains (\"*\")")
+	}
+	if !auth.HasScope(claims, auth.ScopeRetrieve, "*") {
+		return http.StatusForbidden, fmt.Errorf("admin API requires a token with scope %s:* or without scopes", auth.ScopeRetrieve)
+	}
+
+	return http.StatusOK, nil
+}
+
+// graphqlAuthMiddleware validates the bearer token of a GraphQL request and stores its claims
+// in the request context, so resolvers use them instead of the session cookie. Invalid tokens
+// are always rejected; missing ones only with requireToken, except introspection queries
+// when allowIntrospection is set.
+func graphqlAuthMiddleware(jwtSecretKey string, validationOptions auth.ValidationOptions, requireToken, allowIntrospection bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			if !requireToken || (allowIntrospection && isIntrospectionRequest(c)) {
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "bearer token required"})
+			return
+		}
+
+		token, found := strings.CutPrefix(header, "Bearer ")
+		if !found || token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "bearer token required"})
+			return
+		}
+
+		claims, err := auth.ParseJWTWithOptions(token, jwtSecretKey, validationOptions)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("invalid token: %v", err)})
+			return
+		}
+
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), graph.ContextKeyJWTClaims, claims))
+	}
+}
+
+// maxIntrospectionBodySize bounds how much of an unauthenticated body is inspected
+const maxIntrospectionBodySize = 64 << 10
+
+// isIntrospectionRequest reports whether a GraphQL POST body holds only an introspection query.
+// The body is restored so the GraphQL handler can read it again.
+func isIntrospectionRequest(c *gin.Context) bool {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIntrospectionBodySize))
+	if err != nil {
+		return false
+	}
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+
+	var params struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(body, &params); err != nil {
+		return false
+	}
+
+	return graph.IsIntrospectionQuery(params.Query)
+}
+
+// printDomainChanges logs the outcome of a provider reload
+func printDomainChanges(trigger string, changes *registry.DomainChanges, err error) {
+	if changes.IsEmpty() {
+		fmt.Printf("Providers reloaded (%s): no domain changes\n", trigger)
+	} else {
+		fmt.Printf("Providers reloaded (%s): added %v, removed %v\n", trigger, changes.Added, changes.Removed)
+	}
+	if err != nil {
+		fmt.Printf("Provider reload error: %v\n", err)
+	}
+}
+
 func printJWTSecretKeyHelp(w io.Writer) {
 	fmt.Fprintln(w, "jwt secret key is required for server operation")
 	fmt.Fprintln(w)
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/auth"
+	"github.com/dh-kam/go-cert-provider/graph"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cobra"
+)
+
+const testSecretKey = "test-secret-key-32-bytes-long!!"
+
+// createTestToken signs an access token for the allowed domains, restricted to scopes if any
+func createTestToken(t *testing.T, allowedDomains, scopes []string) string {
+	t.Helper()
+
+	claims := auth.NewAccessClaims("user", "", time.Now().Add(time.Hour), allowedDomains)
+	claims.Scopes = scopes
+	token, err := auth.SignJWT(claims, testSecretKey)
+	if err != nil {
+		t.Fatalf("SignJWT failed: %v", err)
+	}
+	return token
+}
+
+func TestAuthorizeAdminRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode int
+	}{
+		{"token for all domains", createTestToken(t, []string{"*"}, nil), http.StatusOK},
+		{"retrieve scope for all domains", createTestToken(t, []string{"*"}, []string{"retrieve:*"}), http.StatusOK},
+		{"list-only scope for all domains", createTestToken(t, []string{"*"}, []string{"list:*"}), http.StatusForbidden},
+		{"retrieve scope for some domains", createTestToken(t, []string{"*"}, []string{"retrieve:*.example.com"}), http.StatusForbidden},
+		{"token for one domain", createTestToken(t, []string{"example.com"}, nil), http.StatusForbidden},
+		{"invalid token", "not-a-token", http.StatusUnauthorized},
+		{"no token", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/admin/reload", nil)
+			if tt.token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			status, err := authorizeAdminRequest(c, testSecretKey, auth.ValidationOptions{})
+			if status != tt.wantCode {
+				t.Errorf("Status = %d (%v), want %d", status, err, tt.wantCode)
+			}
+			if (err == nil) != (tt.wantCode == http.StatusOK) {
+				t.Errorf("Unexpected error result: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetServerTimeouts(t *testing.T) {
+	tests := []struct {
+		name              string
+		args              []string
+		read, write, idle time.Duration // of the http.Server; negative disables the idle timeout
+		wantErr           bool
+	}{
+		{"defaults", nil, 30 * time.Second, 2 * time.Minute, 2 * time.Minute, false},
+		{"custom values", []string{"--read-timeout", "5s", "--write-timeout", "1m", "--idle-timeout", "90s"}, 5 * time.Second, time.Minute, 90 * time.Second, false},
+		{"zero disables", []string{"--read-timeout", "0", "--write-timeout", "0", "--idle-timeout", "0"}, 0, 0, -1, false},
+		// A zero IdleTimeout would make http.Server fall back to the read timeout
+		{"zero idle with a read timeout", []string{"--idle-timeout", "0"}, 30 * time.Second, 2 * time.Minute, -1, false},
+		{"negative read timeout", []string{"--read-timeout", "-1s"}, 0, 0, 0, true},
+		{"negative idle timeout", []string{"--idle-timeout", "-5m"}, 0, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			addServerTimeoutFlags(cmd)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags failed: %v", err)
+			}
+
+			read, write, idle, err := getServerTimeouts(cmd)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected an error for a negative timeout")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getServerTimeouts failed: %v", err)
+			}
+
+			srv := newHTTPServer(":0", http.NotFoundHandler(), read, write, idle)
+			if srv.ReadTimeout != tt.read || srv.WriteTimeout != tt.write || srv.IdleTimeout != tt.idle {
+				t.Errorf("Server timeouts = %v/%v/%v, want %v/%v/%v", srv.ReadTimeout, srv.WriteTimeout, srv.IdleTimeout, tt.read, tt.write, tt.idle)
+			}
+			if srv.ReadHeaderTimeout != 10*time.Second {
+				t.Errorf("ReadHeaderTimeout = %v, want 10s", srv.ReadHeaderTimeout)
+			}
+		})
+	}
+}
+
+func TestGraphQLAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const (
+		introspectionQuery = `{"query": "{ __schema { types { name } } }"}`
+		domainsQuery       = `{"query": "{ domains { name } }"}`
+	)
+	validToken := createTestToken(t, []string{"example.com"}, nil)
+
+	tests := []struct {
+		name               string
+		requireToken       bool
+		allowIntrospection bool
+		header             string
+		body               string
+		wantCode           int
+		wantUser           string // user ID of the claims in the request context, if any
+	}{
+		{"no token without --require-token", false, false, "", domainsQuery, http.StatusOK, ""},
+		{"no token with --require-token", true, false, "", domainsQuery, http.StatusUnauthorized, ""},
+		{"introspection without --allow-introspection", true, false, "", introspectionQuery, http.StatusUnauthorized, ""},
+		{"introspection with --allow-introspection", true, true, "", introspectionQuery, http.StatusOK, ""},
+		{"other query with --allow-introspection", true, true, "", domainsQuery, http.StatusUnauthorized, ""},
+		{"invalid token", false, false, "Bearer not-a-token", domainsQuery, http.StatusUnauthorized, ""},
+		{"not a bearer token", false, false, "Basic dXNlcjpwYXNz", domainsQuery, http.StatusUnauthorized, ""},
+		{"valid token", true, false, "Bearer " + validToken, domainsQuery, http.StatusOK, "user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/graphql", graphqlAuthMiddleware(testSecretKey, auth.ValidationOptions{}, tt.requireToken, tt.allowIntrospection), func(c *gin.Context) {
+				// The GraphQL handler must still be able to read the body
+				body, _ := io.ReadAll(c.Request.Body)
+				if string(body) != tt.body {
+					t.Errorf("Body = %q, want %q", body, tt.body)
+				}
+				user := ""
+				if claims, ok := c.Request.Context().Value(graph.ContextKeyJWTClaims).(*auth.JWTClaims); ok {
+					user = claims.UserID
+				}
+				c.String(http.StatusOK, user)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(tt.body))
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("Status code = %d, want %d: %s", recorder.Code, tt.wantCode, recorder.Body.String())
+			}
+			if tt.wantCode == http.StatusOK && recorder.Body.String() != tt.wantUser {
+				t.Errorf("Claims user = %q, want %q", recorder.Body.String(), tt.wantUser)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/spf13/cobra"
+)
+
+// validateCmd represents the certs validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate --file <bundle.pem> [--key <key.pem>]",
+	Short: "Check a certificate bundle file before deploying it",
+	Long: `Check a PEM certificate bundle on disk, without contacting any provider:
+
+  - the chain is continuous: each certificate is signed by the one after it
+  - the private key matches the leaf certificate
+  - no certificate of the chain is expired or not yet valid
+
+The private key is read from --key, or from the bundle itself if it holds one (as
+written by certs retrieve --bundle). Without a key, only the chain and expiry are checked.
+
+The command prints the result of each check and exits with a non-zero status if any fails,
+naming the failures (broken chain, invalid key, key mismatch, expired, not yet valid).
+
+Examples:
+  go-cert-provider certs validate --file fullchain.pem --key privkey.pem
+  go-cert-provider certs validate --file haproxy.pem`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := cmd.Flags().GetString("file")
+		if err != nil {
+			return err
+		}
+		keyFile, err := cmd.Flags().GetString("key")
+		if err != nil {
+			return err
+		}
+		if file == "" {
+			return fmt.Errorf("--file is required")
+		}
+
+		bundle, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read bundle: %w", err)
+		}
+		var key []byte
+		if keyFile != "" {
+			if key, err = os.ReadFile(keyFile); err != nil {
+				return fmt.Errorf("failed to read private key: %w", err)
+			}
+		}
+
+		// From here on, an error is a verdict on the bundle
+		cmd.SilenceUsage = true
+
+		failures, err := validateBundle(cmd.OutOrStdout(), bundle, key, time.Now())
+		if err != nil {
+			return fmt.Errorf("invalid bundle %s: %w", file, err)
+		}
+		if len(failures) > 0 {
+			return fmt.Errorf("bundle %s failed validation: %s", file, strings.Join(failures, ", "))
+		}
+		return nil
+	},
+}
+
+// validateBundle checks the chain continuity, key and expiry of a PEM bundle and prints the
+// result of each check. It returns the names of the failed checks, or an error if the
+// bundle cannot be parsed. A nil key uses the private key of the bundle, if any.
+func validateBundle(out io.Writer, bundle, key []byte, now time.Time) ([]string, error) {
+	certs, err := utils.ParseCertificates(bundle)
+	if err != nil {
+		return nil, err
+	}
+	leaf := certs[0]
+
+	fmt.Fprintf(out, "Subject:      %s\n", leaf.Subject.CommonName)
+	fmt.Fprintf(out, "SANs:         %s\n", strings.Join(leaf.DNSNames, ", "))
+	fmt.Fprintf(out, "Chain:        %d certificate(s)\n", len(certs))
+
+	var failures []string
+
+	if err := utils.CheckChainOrder(certs); err != nil {
+		fmt.Fprintf(out, "❌ Chain: %v\n", err)
+		failures = append(failures, "broken chain")
+	} else {
+		fmt.Fprintf(out, "✅ Chain: each certificate is signed by the next\n")
+	}
+
+	if key == nil {
+		if _, err := utils.PrivateKeyDER(bundle); err == nil {
+			key = bundle
+		}
+	}
+	if key == nil {
+		fmt.Fprintf(out, "➖ Key: not checked (no private key in the bundle; pass --key)\n")
+	} else if _, err := utils.ParsePrivateKey(key); err != nil {
+		// An unreadable key says nothing about whether it matches
+		fmt.Fprintf(out, "❌ Key: cannot parse the private key: %v\n", err)
+		failures = append(failures, "invalid key")
+	} else if err := utils.PrivateKeyMatchesCertificate(key, leaf); err != nil {
+		fmt.Fprintf(out, "❌ Key: %v\n", err)
+		failures = append(failures, "key mismatch")
+	} else {
+		fmt.Fprintf(out, "✅ Key: matches the leaf certificate\n")
+	}
+
+	expired, notYetValid := false, false
+	for i, cert := range certs {
+		switch {
+		case now.After(cert.NotAfter):
+			fmt.Fprintf(out, "❌ Expiry: certificate %d (%s) expired on %s\n", i+1, cert.Subject.CommonName,
+				utils.FormatDateTime(cert.NotAfter.Local()))
+			expired = true
+		case now.Before(cert.NotBefore):
+			fmt.Fprintf(out, "❌ Expiry: certificate %d (%s) is not valid until %s\n", i+1, cert.Subject.CommonName,
+				utils.FormatDateTime(cert.NotBefore.Local()))
+			notYetValid = true
+		}
+	}
+	if expired {
+		failures = append(failures, "expired")
+	}
+	if notYetValid {
+		failures = append(failures, "not yet valid")
+	}
+	if !expired && !notYetValid {
+		fmt.Fprintf(out, "✅ Expiry: leaf valid until %s (%s left)\n", utils.FormatDateTime(leaf.NotAfter.Local()),
+			utils.FormatDuration(leaf.NotAfter.Sub(now)))
+	}
+
+	return failures, nil
+}
+
+func init() {
+	validateCmd.Flags().String("file", "", "PEM file holding the certificate chain, leaf first (required)")
+	validateCmd.Flags().String("key", "", "PEM private key file to check against the leaf (default: the key in the bundle, if any)")
+
+	certsCmd.AddCommand(validateCmd)
+}
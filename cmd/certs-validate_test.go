@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testBundleCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func issueBundleCert(t *testing.T, commonName string, notAfter time.Time, parent *testBundleCert) *testBundleCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-365 * 24 * time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+	}
+
+	signerCert, signerKey := template, key
+	if parent != nil {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	return &testBundleCert{cert: cert, key: key}
+}
+
+func encodeBundle(t *testing.T, key *ecdsa.PrivateKey, certs ...*testBundleCert) []byte {
+	t.Helper()
+
+	var out []byte
+	for _, c := range certs {
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw})...)
+	}
+	if key != nil {
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatalf("Failed to marshal key: %v", err)
+		}
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})...)
+	}
+	return out
+}
+
+func TestValidateBundle(t *testing.T) {
+	now := time.Now()
+	ca := issueBundleCert(t, "Test CA", now.Add(365*24*time.Hour), nil)
+	leaf := issueBundleCert(t, "example.com", now.Add(30*24*time.Hour), ca)
+	expiredLeaf := issueBundleCert(t, "example.com", now.Add(-24*time.Hour), ca)
+	otherCA := issueBundleCert(t, "Other CA", now.Add(365*24*time.Hour), nil)
+
+	tests := []struct {
+		name   string
+		bundle []byte
+		key    []byte
+		want   []string
+	}{
+		{name: "valid with separate key", bundle: encodeBundle(t, nil, leaf, ca), key: encodeBundle(t, leaf.key)},
+		{name: "valid with key in bundle", bundle: encodeBundle(t, leaf.key, leaf, ca)},
+		{name: "valid without key", bundle: encodeBundle(t, nil, leaf, ca)},
+		{name: "broken chain", bundle: encodeBundle(t, leaf.key, leaf, otherCA), want: []string{"broken chain"}},
+		{name: "wrong order", bundle: encodeBundle(t, leaf.key, ca, leaf), want: []string{"broken chain", "key mismatch"}},
+		{name: "key mismatch", bundle: encodeBundle(t, nil, leaf, ca), key: encodeBundle(t, ca.key), want: []string{"key mismatch"}},
+		{name: "expired", bundle: encodeBundle(t, expiredLeaf.key, expiredLeaf, ca), want: []string{"expired"}},
+		{name: "empty key file", bundle: encodeBundle(t, nil, leaf, ca), key: []byte{}, want: []string{"invalid key"}},
+		{name: "encrypted key", bundle: encodeBundle(t, nil, leaf, ca),
+			key: pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte("encrypted")}), want: []string{"invalid key"}},
+		{name: "certificate as key", bundle: encodeBundle(t, nil, leaf, ca), key: encodeBundle(t, nil, leaf), want: []string{"invalid key"}},
+		{name: "corrupt key", bundle: encodeBundle(t, nil, leaf, ca),
+			key: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("garbage")}), want: []string{"invalid key"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures, err := validateBundle(io.Discard, tt.bundle, tt.key, now)
+			if err != nil {
+				t.Fatalf("validateBundle failed: %v", err)
+			}
+			if !reflect.DeepEqual(failures, tt.want) {
+				t.Errorf("Failures = %v, want %v", failures, tt.want)
+			}
+		})
+	}
+
+	if _, err := validateBundle(io.Discard, []byte("not a certificate"), nil, now); err == nil {
+		t.Error("Expected error for a bundle without certificates")
+	}
+}
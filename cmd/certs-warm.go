@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/cert/registry"
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultWarmConcurrency is how many certificates are retrieved at a time while warming
+	defaultWarmConcurrency = 4
+	// maxWarmConcurrency bounds the concurrency a warm request may ask for
+	maxWarmConcurrency = 32
+	// defaultWarmTimeout is how long certs warm waits for a server to finish warming
+	defaultWarmTimeout = 10 * time.Minute
+	// warmRateLimitKey is the rate limiter bucket shared by all warm requests
+	warmRateLimitKey = "admin:warm"
+)
+
+// warmResult is the outcome of warming one domain, as returned by /admin/warm
+type warmResult struct {
+	Domain     string     `json:"domain"`
+	Provider   string     `json:"provider,omitempty"`
+	DurationMs int64      `json:"durationMs"`
+	NotAfter   *time.Time `json:"notAfter,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// warmResponse is the /admin/warm response body
+type warmResponse struct {
+	Results []warmResult `json:"results"`
+	Warmed  int          `json:"warmed"`
+	Failed  int          `json:"failed"`
+}
+
+// warmCmd represents the certs warm command
+var warmCmd = &cobra.Command{
+	Use:   "warm [--all | <domain>...]",
+	Short: "Retrieve certificates ahead of time and report the timing per domain",
+	Long: `Retrieve the certificates of the given domains, or of all managed domains with --all,
+and report how long each retrieval took.
+
+With --server, the running server at that URL retrieves them through its /admin/warm
+endpoint. With --cert-cache-ttl set on the server, this fills its certificate cache, so
+users are answered without a provider call; either way the server remembers the
+certificate details (such as the expiry returned by the domains query). This requires a
+token allowed for all domains. The server paces the retrievals with its --rate-limit
+setting, so warming many domains may take a while; --timeout bounds the wait.
+
+Without --server, this process retrieves the certificates itself, which checks at deploy
+time that every certificate can be retrieved. The command fails if any domain fails.
+
+Examples:
+  # Warm a running server after deploying it
+  go-cert-provider certs warm --all --server http://localhost:5000 --token "$ADMIN_TOKEN"
+
+  # Check that two certificates can be retrieved
+  go-cert-provider certs warm example.com api.example.com`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, err := cmd.Flags().GetBool("all")
+		if err != nil {
+			return err
+		}
+		serverURL, err := cmd.Flags().GetString("server")
+		if err != nil {
+			return err
+		}
+		token, err := cmd.Flags().GetString("token")
+		if err != nil {
+			return err
+		}
+		concurrency, err := cmd.Flags().GetInt("concurrency")
+		if err != nil {
+			return err
+		}
+
+		if all == (len(args) > 0) {
+			return fmt.Errorf("specify either --all or one or more domains")
+		}
+		if concurrency < 1 || concurrency > maxWarmConcurrency {
+			return fmt.Errorf("concurrency must be between 1 and %d", maxWarmConcurrency)
+		}
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			return err
+		}
+		if timeout < 0 {
+			return fmt.Errorf("--timeout must not be negative")
+		}
+		if serverURL == "" && token != "" {
+			return fmt.Errorf("--token requires --server")
+		}
+
+		var response *warmResponse
+		start := time.Now()
+		if serverURL != "" {
+			if token == "" {
+				return fmt.Errorf("--server requires --token with a token allowed for all domains")
+			}
+			cmd.SilenceUsage = true
+			client := &http.Client{Timeout: timeout}
+			response, err = requestWarm(cmd.Context(), client, serverURL, token, args, concurrency)
+			if err != nil {
+				return err
+			}
+		} else {
+			// certs warm skips the provider initialization, since --server does not need it
+			state, err := initializeProviderSystem(cmd)
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			domains := args
+			if all {
+				domains = state.providerRegistry.ListDomains()
+			}
+			response = newWarmResponse(warmCertificates(cmd.Context(), state.providerRegistry, domains, concurrency, nil))
+		}
+
+		out := cmd.OutOrStdout()
+		for _, result := range response.Results {
+			duration := time.Duration(result.DurationMs) * time.Millisecond
+			if result.Error != "" {
+				fmt.Fprintf(out, "❌ %s: %s (%s)\n", result.Domain, result.Error, duration)
+				continue
+			}
+			expiry := ""
+			if result.NotAfter != nil {
+				expiry = ", expires " + utils.FormatDateTime(result.NotAfter.Local())
+			}
+			fmt.Fprintf(out, "✅ %s (%s) in %s%s\n", result.Domain, result.Provider, duration, expiry)
+		}
+		fmt.Fprintf(out, "\nWarmed %d of %d certificate(s) in %s\n", response.Warmed, len(response.Results),
+			time.Since(start).Round(time.Millisecond))
+
+		if response.Failed > 0 {
+			return fmt.Errorf("%d certificate(s) could not be retrieved", response.Failed)
+		}
+		return nil
+	},
+}
+
+// warmCertificates retrieves the certificates of the domains, at most concurrency at a time,
+// so the registry caches them and remembers their details. allow, if set, is asked before
+// each retrieval; while it refuses, warming waits as long as it says. A domain still waiting
+// when ctx ends is reported as rate limited. Results are in the order of domains.
+func warmCertificates(ctx context.Context, providerRegistry *registry.CertificateProviderRegistry,
+	domains []string, concurrency int, allow func() (bool, time.Duration)) []warmResult {
+	results := make([]warmResult, len(domains))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, domainName := range domains {
+		results[i].Domain = domainName
+		if allow != nil {
+			if err := waitForRateLimit(ctx, allow); err != nil {
+				results[i].Error = fmt.Sprintf("rate limited: %v", err)
+				continue
+			}
+		}
+
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(result *warmResult) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if provider, err := providerRegistry.GetProviderForDomain(result.Domain); err == nil {
+				result.Provider = provider.GetProviderName()
+			}
+
+			start := time.Now()
+			certInfo, err := providerRegistry.GetCertInfo(ctx, result.Domain)
+			result.DurationMs = time.Since(start).Milliseconds()
+			if err != nil {
+				result.Error = err.Error()
+				return
+			}
+			notAfter := certInfo.NotAfter.UTC()
+			result.NotAfter = &notAfter
+		}(&results[i])
+	}
+
+	wg.Wait()
+	return results
+}
+
+// waitForRateLimit blocks until allow lets a retrieval through, or ctx ends
+func waitForRateLimit(ctx context.Context, allow func() (bool, time.Duration)) error {
+	for {
+		allowed, retryAfter := allow()
+		if allowed {
+			return nil
+		}
+
+		timer := time.NewTimer(retryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// newWarmResponse counts the warmed and failed domains
+func newWarmResponse(results []warmResult) *warmResponse {
+	response := &warmResponse{Results: results}
+	for _, result := range results {
+		if result.Error != "" {
+			response.Failed++
+		} else {
+			response.Warmed++
+		}
+	}
+	return response
+}
+
+// warmHandler serves POST /admin/warm. The domain query parameter may be repeated; without
+// it, all managed domains are warmed. The concurrency parameter bounds parallel retrievals.
+func warmHandler(providerRegistry *registry.CertificateProviderRegistry, allow func() (bool, time.Duration)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		concurrency := defaultWarmConcurrency
+		if value := c.Query("concurrency"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 || parsed > maxWarmConcurrency {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid concurrency %q: must be between 1 and %d", value, maxWarmConcurrency)})
+				return
+			}
+			concurrency = parsed
+		}
+
+		// Paced warming can outlast --write-timeout; the client's own timeout bounds it instead
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: cannot lift the write deadline, warming may be cut off by --write-timeout: %v\n", err)
+		}
+
+		domains := c.QueryArray("domain")
+		if len(domains) == 0 {
+			domains = providerRegistry.ListDomains()
+		}
+
+		c.JSON(http.StatusOK, newWarmResponse(warmCertificates(c.Request.Context(), providerRegistry, domains, concurrency, allow)))
+	}
+}
+
+// requestWarm asks a running server to warm the domains, or all of its domains if none are given
+func requestWarm(ctx context.Context, client *http.Client, serverURL, token string, domains []string, concurrency int) (*warmResponse, error) {
+	query := url.Values{"concurrency": {strconv.Itoa(concurrency)}}
+	for _, domainName := range domains {
+		query.Add("domain", domainName)
+	}
+	endpoint := strings.TrimSuffix(serverURL, "/") + "/admin/warm?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL %q: %w", serverURL, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error == "" {
+			return nil, fmt.Errorf("server returned %s", resp.Status)
+		}
+		return nil, fmt.Errorf("server returned %s: %s", resp.Status, body.Error)
+	}
+
+	var response warmResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("invalid response from server: %w", err)
+	}
+	return &response, nil
+}
+
+func init() {
+	warmCmd.Flags().Bool("all", false, "Warm all managed domains")
+	warmCmd.Flags().String("server", "", "URL of a running server to warm through its /admin/warm endpoint, e.g. http://localhost:5000")
+	warmCmd.Flags().String("token", "", "Bearer token allowed for all domains, required with --server")
+	warmCmd.Flags().Int("concurrency", defaultWarmConcurrency, fmt.Sprintf("Maximum certificates retrieved at a time (at most %d)", maxWarmConcurrency))
+	warmCmd.Flags().Duration("timeout", defaultWarmTimeout, "With --server, how long to wait for the server to finish warming (0 waits indefinitely)")
+
+	certsCmd.AddCommand(warmCmd)
+}
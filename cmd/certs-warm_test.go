@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/cert/providers/mock"
+	"github.com/dh-kam/go-cert-provider/cert/registry"
+	"github.com/gin-gonic/gin"
+)
+
+func TestWarmHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(allow func() (bool, time.Duration)) (*gin.Engine, *registry.CertificateProviderRegistry) {
+		providerRegistry := registry.NewCertificateProviderRegistry()
+		if err := providerRegistry.Register(mock.NewProvider([]string{"a.com", "b.com", "c.com"}, nil, nil)); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+		router := gin.New()
+		router.POST("/admin/warm", warmHandler(providerRegistry, allow))
+		return router, providerRegistry
+	}
+
+	warm := func(router *gin.Engine, ctx context.Context, query string) (int, warmResponse) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/admin/warm"+query, nil).WithContext(ctx)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		var response warmResponse
+		if recorder.Code == http.StatusOK {
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Invalid response: %v", err)
+			}
+		}
+		return recorder.Code, response
+	}
+
+	resultDomains := func(response warmResponse) string {
+		domains := make([]string, len(response.Results))
+		for i, result := range response.Results {
+			domains[i] = result.Domain
+		}
+		return strings.Join(domains, ",")
+	}
+
+	t.Run("all domains", func(t *testing.T) {
+		router, providerRegistry := newRouter(nil)
+		code, response := warm(router, context.Background(), "")
+		if code != http.StatusOK || response.Warmed != 3 || response.Failed != 0 {
+			t.Fatalf("Unexpected response %d: %+v", code, response)
+		}
+		if domains := resultDomains(response); domains != "a.com,b.com,c.com" {
+			t.Errorf("Results for %s, want all domains in order", domains)
+		}
+		for _, result := range response.Results {
+			if result.Provider != "mock" || result.NotAfter == nil {
+				t.Errorf("Incomplete result: %+v", result)
+			}
+		}
+		if info := providerRegistry.GetDomainInfo("b.com"); info.CertSerial == "" {
+			t.Error("Expected the registry to remember the warmed certificate")
+		}
+	})
+
+	t.Run("repeated domain parameters", func(t *testing.T) {
+		router, _ := newRouter(nil)
+		code, response := warm(router, context.Background(), "?domain=c.com&domain=a.com&domain=unknown.com&concurrency=1")
+		if code != http.StatusOK || response.Warmed != 2 || response.Failed != 1 {
+			t.Fatalf("Unexpected response %d: %+v", code, response)
+		}
+		if domains := resultDomains(response); domains != "c.com,a.com,unknown.com" {
+			t.Errorf("Results for %s, want the requested domains in order", domains)
+		}
+		if response.Results[2].Error == "" {
+			t.Error("Expected an error for the unmanaged domain")
+		}
+	})
+
+	t.Run("bad concurrency", func(t *testing.T) {
+		router, _ := newRouter(nil)
+		for _, value := range []string{"0", "-1", "many", "33"} {
+			if code, _ := warm(router, context.Background(), "?concurrency="+value); code != http.StatusBadRequest {
+				t.Errorf("concurrency=%s: status %d, want %d", value, code, http.StatusBadRequest)
+			}
+		}
+	})
+
+	t.Run("rate limit is waited for", func(t *testing.T) {
+		refused := 0
+		router, _ := newRouter(func() (bool, time.Duration) {
+			// Refuse every other request briefly, as a nearly empty bucket does
+			refused++
+			return refused%2 == 0, 10 * time.Millisecond
+		})
+		code, response := warm(router, context.Background(), "")
+		if code != http.StatusOK || response.Warmed != 3 || response.Failed != 0 {
+			t.Errorf("Expected all domains to be warmed after waiting, got %d: %+v", code, response)
+		}
+	})
+
+	t.Run("rate limited results", func(t *testing.T) {
+		allowed := 0
+		router, _ := newRouter(func() (bool, time.Duration) {
+			if allowed < 1 {
+				allowed++
+				return true, 0
+			}
+			return false, time.Hour
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		code, response := warm(router, ctx, "")
+		if code != http.StatusOK || response.Warmed != 1 || response.Failed != 2 {
+			t.Fatalf("Unexpected response %d: %+v", code, response)
+		}
+		for _, result := range response.Results[1:] {
+			if !strings.Contains(result.Error, "rate limited") {
+				t.Errorf("Expected %s to be rate limited, got %q", result.Domain, result.Error)
+			}
+		}
+	})
+}
+
+func TestWarmHandlerOutlastsWriteTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	providerRegistry := registry.NewCertificateProviderRegistry()
+	if err := providerRegistry.Register(mock.NewProvider([]string{"a.com", "b.com"}, nil, nil)); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	// Pacing holds the second retrieval past the server's write timeout
+	var mutex sync.Mutex
+	calls := 0
+	allow := func() (bool, time.Duration) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		calls++
+		return calls != 2, 300 * time.Millisecond
+	}
+
+	// Compression is on by default and Go's client always accepts gzip, so the handler
+	// must lift the deadline through the compressing writer
+	router := gin.New()
+	router.Use(compressionMiddleware(compressionMinSize))
+	router.POST("/admin/warm", warmHandler(providerRegistry, allow))
+	server := httptest.NewUnstartedServer(router)
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	response, err := requestWarm(context.Background(), server.Client(), server.URL, "token", nil, 1)
+	if err != nil {
+		t.Fatalf("Warm request was cut off: %v", err)
+	}
+	if response.Warmed != 2 || response.Failed != 0 {
+		t.Errorf("Expected both domains to be warmed, got %+v", response)
+	}
+}
@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/dh-kam/go-cert-provider/notify"
 	"github.com/spf13/cobra"
 )
 
@@ -14,6 +19,34 @@ This command provides subcommands for serving certificates via GraphQL API
 and retrieving certificates directly from the command line.`,
 }
 
+// getWebhookNotifier returns a background webhook notifier for --webhook-url or the
+// WEBHOOK_URL environment variable, or nil when neither is set
+func getWebhookNotifier(cmd *cobra.Command) (*notify.Async, error) {
+	webhookURL, err := cmd.Flags().GetString("webhook-url")
+	if err != nil {
+		return nil, err
+	}
+	if webhookURL == "" {
+		webhookURL = os.Getenv("WEBHOOK_URL")
+	}
+	if webhookURL == "" {
+		return nil, nil
+	}
+
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL: must be an http(s) URL")
+	}
+
+	// Only the host is printed; webhook paths often embed tokens
+	fmt.Fprintf(infoOut(cmd), "Webhook notifications: %s\n", parsed.Host)
+	return notify.NewAsync(notify.NewWebhook(webhookURL), func(err error) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+	}), nil
+}
+
 func init() {
+	certsCmd.PersistentFlags().String("webhook-url", "", "POST certificate renewal and expiry events as JSON to this URL (overrides WEBHOOK_URL env var)")
+
 	rootCmd.AddCommand(certsCmd)
 }
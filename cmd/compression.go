@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// compressionMinSize is the smallest response body worth compressing; smaller bodies
+// are sent as is, since the encoding overhead would outweigh the savings
+const compressionMinSize = 1024
+
+// compressor is implemented by gzip.Writer and zlib.Writer
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// compressWriter buffers the start of a response until it reaches minSize, then writes it
+// compressed with the negotiated encoding. Shorter responses are written uncompressed.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+	buffer   []byte
+	decided  bool
+	encoder  compressor // nil when writing uncompressed
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buffer = append(w.buffer, data...)
+		if len(w.buffer) < w.minSize {
+			return len(data), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Unwrap returns the wrapped writer, so http.NewResponseController reaches the connection
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// WriteHeaderNow sends the headers, so an undecided response is sent uncompressed
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush sends what was written so far; an undecided response is sent uncompressed
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.encoder != nil {
+		_ = w.encoder.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide chooses between compressed and plain output and writes the buffered data.
+// Responses that already carry a Content-Encoding are never compressed again.
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+
+	header := w.Header()
+	if compress && header.Get("Content-Encoding") == "" {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		if w.encoding == "gzip" {
+			w.encoder = gzip.NewWriter(w.ResponseWriter)
+		} else {
+			// The HTTP deflate encoding is the zlib format, not raw deflate
+			w.encoder = zlib.NewWriter(w.ResponseWriter)
+		}
+	}
+
+	buffered := w.buffer
+	w.buffer = nil
+	if len(buffered) == 0 {
+		return nil
+	}
+	if w.encoder != nil {
+		_, err := w.encoder.Write(buffered)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buffered)
+	return err
+}
+
+// finish writes a response that stayed below minSize and terminates the compressed stream
+func (w *compressWriter) finish() error {
+	if !w.decided {
+		return w.decide(false)
+	}
+	if w.encoder != nil {
+		return w.encoder.Close()
+	}
+	return nil
+}
+
+// compressionMiddleware compresses response bodies of at least minSize bytes with gzip or
+// deflate, whichever the client's Accept-Encoding prefers
+func compressionMiddleware(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{ResponseWriter: c.Writer, encoding: encoding, minSize: minSize}
+		c.Writer = writer
+		defer func() {
+			_ = writer.finish()
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding returns "gzip" or "deflate", whichever Accept-Encoding ranks higher
+// (gzip on a tie, and for "*"), or "" if the client accepts neither
+func negotiateEncoding(acceptEncoding string) string {
+	best, bestQuality := "", 0.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "*" {
+			name = "gzip"
+		}
+		if name != "gzip" && name != "deflate" {
+			continue
+		}
+
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		if quality > bestQuality || (quality == bestQuality && name == "gzip") {
+			best, bestQuality = name, quality
+		}
+	}
+
+	if bestQuality <= 0 {
+		return ""
+	}
+	return best
+}
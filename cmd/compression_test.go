@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCompressionMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// A certificate chain in a GraphQL response is several KB
+	largeBody := `{"data":{"certificate":{"certChain":"` + strings.Repeat("MIIFazCCA1OgAwIBAgIRAIIQz7DSQONZRGPgu2OCiwAw", 100) + `"}}}`
+
+	router := gin.New()
+	router.Use(compressionMiddleware(compressionMinSize))
+	router.POST("/graphql", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", []byte(largeBody))
+	})
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	tests := []struct {
+		name           string
+		method, path   string
+		acceptEncoding string
+		wantEncoding   string
+		wantBody       string
+	}{
+		{"large response is gzip-encoded", http.MethodPost, "/graphql", "gzip, deflate, br", "gzip", largeBody},
+		{"deflate when preferred", http.MethodPost, "/graphql", "gzip;q=0.5, deflate", "deflate", largeBody},
+		{"identity without Accept-Encoding", http.MethodPost, "/graphql", "", "", largeBody},
+		{"identity when gzip is refused", http.MethodPost, "/graphql", "gzip;q=0", "", largeBody},
+		{"small response is not compressed", http.MethodGet, "/health", "gzip", "", `{"status":"ok"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("Status code = %d, want %d", recorder.Code, http.StatusOK)
+			}
+			if got := recorder.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if recorder.Header().Get("Vary") != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", recorder.Header().Get("Vary"))
+			}
+
+			compressedSize := recorder.Body.Len()
+			var reader io.Reader = recorder.Body
+			switch tt.wantEncoding {
+			case "gzip":
+				gzipReader, err := gzip.NewReader(recorder.Body)
+				if err != nil {
+					t.Fatalf("Invalid gzip body: %v", err)
+				}
+				reader = gzipReader
+			case "deflate":
+				zlibReader, err := zlib.NewReader(recorder.Body)
+				if err != nil {
+					t.Fatalf("Invalid deflate body: %v", err)
+				}
+				reader = zlibReader
+			}
+			body, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("Failed to read body: %v", err)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("Body = %.60q..., want %.60q...", body, tt.wantBody)
+			}
+			if tt.wantEncoding != "" && compressedSize >= len(tt.wantBody) {
+				t.Errorf("Compressed body is %d bytes, not smaller than %d", compressedSize, len(tt.wantBody))
+			}
+		})
+	}
+}
+
+func TestCompressionMiddlewareResponseController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(compressionMiddleware(compressionMinSize))
+	router.GET("/slow", func(c *gin.Context) {
+		// Long-running handlers lift the write deadline through the compressing writer
+		controller := http.NewResponseController(c.Writer)
+		if err := controller.SetWriteDeadline(time.Time{}); err != nil {
+			c.String(http.StatusInternalServerError, "SetWriteDeadline: %v", err)
+			return
+		}
+		c.String(http.StatusOK, strings.Repeat("ok\n", compressionMinSize))
+		if err := controller.Flush(); err != nil {
+			t.Errorf("Flush through the compressing writer failed: %v", err)
+		}
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// Go's client asks for gzip and decodes it transparently
+	resp, err := http.Get(server.URL + "/slow")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status code = %d: %s", resp.StatusCode, body)
+	}
+	if !resp.Uncompressed {
+		t.Error("Expected the response to be gzip-encoded")
+	}
+	if string(body) != strings.Repeat("ok\n", compressionMinSize) {
+		t.Errorf("Unexpected body: %.60q...", body)
+	}
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/spf13/cobra"
+)
+
+// doctorSamples are parsed by doctor when no values are given: durations and dates as
+// accepted by jwt create-token --expires-at
+var doctorSamples = []string{"30m", "12h", "1d", "2w", "3months", "1y", "1d12h30m"}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [duration-or-date...]",
+	Short: "Show the effective timezone and how dates and durations are parsed",
+	Long: `Print the effective timezone, the current time and how durations and dates are parsed,
+to diagnose surprising token expiry times.
+
+Dates such as "2025-12-31" are interpreted in the effective timezone, which is UTC with
+--utc and otherwise comes from --timezone, the TZ environment variable or the system,
+in that order. Values given as arguments are parsed like jwt create-token --expires-at.
+
+Examples:
+  # Show the effective timezone and parse sample durations
+  go-cert-provider doctor
+
+  # Check what an expiry means in another timezone
+  go-cert-provider doctor --timezone America/New_York 2025-12-31 "2025-12-31 09:00:00" 90d`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		now := time.Now()
+
+		name, offset := now.In(utils.DisplayLocation()).Zone()
+		fmt.Fprintln(out, "Timezone")
+		fmt.Fprintf(out, "  Effective: %s (%s, UTC%s)\n", timezoneName(), name, formatUTCOffset(offset))
+		fmt.Fprintf(out, "  Source:    %s\n", timezoneSource(cmd))
+
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Current time")
+		fmt.Fprintf(out, "  Displayed: %s\n", utils.FormatDateTime(now))
+		fmt.Fprintf(out, "  UTC:       %s\n", now.UTC().Format(time.RFC3339))
+
+		samples := args
+		if len(samples) == 0 {
+			// A date-only value expires at the end of that day in the effective timezone
+			samples = append([]string{now.AddDate(0, 0, 30).Format("2006-01-02")}, doctorSamples...)
+		}
+
+		width := 0
+		for _, sample := range samples {
+			width = max(width, len(sample)+2)
+		}
+
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Expiry parsing (jwt create-token --expires-at)")
+		failed := 0
+		for _, sample := range samples {
+			quoted := fmt.Sprintf("%q", sample)
+			expiresAt, err := parseExpiresAt(sample)
+			if err != nil {
+				failed++
+				fmt.Fprintf(out, "  %-*s  invalid: use a duration (e.g., 2y, 3months, 5d) or date (YYYY-MM-DD HH:mm:ss, YYYY-MM-DD)\n", width, quoted)
+				continue
+			}
+
+			detail := ""
+			if duration, err := utils.ParseDurationString(sample); err == nil {
+				detail = fmt.Sprintf("duration %s, ", duration)
+			}
+			fmt.Fprintf(out, "  %-*s  %s%s %s (%s)\n", width, quoted, detail,
+				utils.FormatDateTime(expiresAt), expiresAt.In(utils.DisplayLocation()).Format("MST"), expiresAt.UTC().Format(time.RFC3339))
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d value(s) could not be parsed", failed)
+		}
+		return nil
+	},
+}
+
+// timezoneName returns the IANA name of the effective timezone. The system timezone is
+// named "Local" by Go, so its name is taken from the /etc/localtime link when possible.
+func timezoneName() string {
+	if name := utils.DisplayLocation().String(); name != "Local" {
+		return name
+	}
+
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if _, name, found := strings.Cut(filepath.ToSlash(target), "zoneinfo/"); found {
+			return name
+		}
+	}
+	return "Local"
+}
+
+// timezoneSource describes where the effective timezone comes from
+func timezoneSource(cmd *cobra.Command) string {
+	if utils.DisplayLocation() == time.UTC {
+		return "--utc flag or UTC_OUTPUT environment variable"
+	}
+	if timezone, _ := cmd.Flags().GetString("timezone"); timezone != "" {
+		return "--timezone flag"
+	}
+	if tz, ok := os.LookupEnv("TZ"); ok {
+		return fmt.Sprintf("TZ environment variable (TZ=%q)", tz)
+	}
+	return "system default (/etc/localtime)"
+}
+
+// formatUTCOffset formats an offset in seconds east of UTC as +hh:mm
+func formatUTCOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d:%02d", sign, offset/3600, offset%3600/60)
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
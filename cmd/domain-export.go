@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/spf13/cobra"
+)
+
+// exportDomain is the per-domain data available to export templates
+type exportDomain struct {
+	Domain   string
+	Provider string
+	CertDir  string
+	CertFile string
+	KeyFile  string
+}
+
+// exportData is the data passed to export templates
+type exportData struct {
+	Domains []exportDomain
+}
+
+// exportTemplates holds the built-in snippets of domain export, keyed by --format
+var exportTemplates = map[string]string{
+	"caddy": `{{range .Domains}}{{.Domain}} {
+	tls {{.CertFile}} {{.KeyFile}}
+}
+
+{{end}}`,
+	"nginx": `{{range .Domains}}server {
+    listen 443 ssl;
+    server_name {{.Domain}};
+
+    ssl_certificate     {{.CertFile}};
+    ssl_certificate_key {{.KeyFile}};
+}
+
+{{end}}`,
+}
+
+// exportCmd represents the domain export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export managed domains as a reverse proxy config snippet",
+	Long: `Generate a Caddyfile or nginx config snippet for every managed domain.
+
+Each domain points at <cert-dir>/<domain>.crt and <cert-dir>/<domain>.key, the
+file names written by "certs retrieve --separate-files". --cert-dir is itself a
+template, so per-domain directories are possible (e.g. /etc/ssl/{{.Domain}}).
+
+--template renders a custom Go text/template file instead. It receives .Domains,
+a list with Domain, Provider, CertDir, CertFile and KeyFile fields.
+
+Examples:
+  # Caddyfile site blocks for all domains
+  go-cert-provider domain export --format caddy --cert-dir /etc/caddy/certs
+
+  # nginx server blocks with one directory per domain, written to a file
+  go-cert-provider domain export --format nginx \
+    --cert-dir "/etc/nginx/ssl/{{.Domain}}" --output-file certs.conf
+
+  # Custom template
+  go-cert-provider domain export --template haproxy.tmpl`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		certDir, err := cmd.Flags().GetString("cert-dir")
+		if err != nil {
+			return err
+		}
+		templateFile, err := cmd.Flags().GetString("template")
+		if err != nil {
+			return err
+		}
+		outputFile, err := cmd.Flags().GetString("output-file")
+		if err != nil {
+			return err
+		}
+
+		tmpl, err := loadExportTemplate(format, templateFile)
+		if err != nil {
+			return err
+		}
+		certDirTmpl, err := template.New("cert-dir").Option("missingkey=error").Parse(certDir)
+		if err != nil {
+			return fmt.Errorf("invalid --cert-dir template: %w", err)
+		}
+
+		if !appState.initialized {
+			return fmt.Errorf("certificate system not initialized")
+		}
+		providerRegistry := appState.providerRegistry
+
+		domains := providerRegistry.ListDomains()
+		if len(domains) == 0 {
+			fmt.Fprintln(infoOut(cmd), "No domains found")
+			return nil
+		}
+
+		var out bytes.Buffer
+		if err := renderExport(&out, tmpl, certDirTmpl, domains, providerRegistry.GetDomainInfo); err != nil {
+			return err
+		}
+
+		if outputFile == "" {
+			_, err := cmd.OutOrStdout().Write(out.Bytes())
+			return err
+		}
+
+		if err := os.WriteFile(outputFile, out.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", outputFile, err)
+		}
+		fmt.Fprintf(infoOut(cmd), "Exported %d domain(s) to %s\n", len(domains), outputFile)
+		return nil
+	},
+}
+
+// renderExport expands the certificate paths of every domain and renders tmpl with them
+func renderExport(w io.Writer, tmpl, certDirTmpl *template.Template, domains []string, lookup func(string) *domain.Info) error {
+	data := exportData{Domains: make([]exportDomain, 0, len(domains))}
+	for _, domainName := range domains {
+		entry := exportDomain{Domain: domainName}
+		if info := lookup(domainName); info != nil {
+			entry.Provider = info.Provider
+		}
+
+		var dir bytes.Buffer
+		if err := certDirTmpl.Execute(&dir, entry); err != nil {
+			return fmt.Errorf("failed to expand --cert-dir for %s: %w", domainName, err)
+		}
+		entry.CertDir = dir.String()
+		entry.CertFile = filepath.Join(entry.CertDir, domainName+".crt")
+		entry.KeyFile = filepath.Join(entry.CertDir, domainName+".key")
+
+		data.Domains = append(data.Domains, entry)
+	}
+
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	return nil
+}
+
+// loadExportTemplate returns the custom template file if given, otherwise the built-in
+// template for format
+func loadExportTemplate(format, templateFile string) (*template.Template, error) {
+	if templateFile != "" {
+		content, err := os.ReadFile(templateFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		tmpl, err := template.New(filepath.Base(templateFile)).Option("missingkey=error").Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", templateFile, err)
+		}
+		return tmpl, nil
+	}
+
+	text, ok := exportTemplates[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported export format: %s (supported: caddy, nginx)", format)
+	}
+	return template.Must(template.New(format).Parse(text)), nil
+}
+
+func init() {
+	exportCmd.Flags().String("format", "caddy", "Snippet format: caddy or nginx (ignored with --template)")
+	exportCmd.Flags().String("cert-dir", "/etc/ssl/go-cert-provider", "Directory holding <domain>.crt and <domain>.key; a template with {{.Domain}} and {{.Provider}}")
+	exportCmd.Flags().String("template", "", "Render this Go text/template file instead of a built-in format")
+	exportCmd.Flags().String("output-file", "", "Write the snippet to this file instead of stdout")
+
+	domainCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+)
+
+func TestRenderExport(t *testing.T) {
+	infos := map[string]*domain.Info{
+		"example.com": {Name: "example.com", Provider: "porkbun"},
+		"test.com":    {Name: "test.com", Provider: "digitalocean"},
+	}
+	lookup := func(name string) *domain.Info { return infos[name] }
+	domains := []string{"example.com", "test.com"}
+
+	customTemplate := filepath.Join(t.TempDir(), "haproxy.tmpl")
+	if err := os.WriteFile(customTemplate, []byte("{{range .Domains}}crt {{.CertDir}}/{{.Domain}}.pem # {{.Provider}}\n{{end}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		format       string
+		templateFile string
+		certDir      string
+		want         string
+	}{
+		{
+			name:    "caddy",
+			format:  "caddy",
+			certDir: "/etc/caddy/certs",
+			want: `example.com {
+	tls /etc/caddy/certs/example.com.crt /etc/caddy/certs/example.com.key
+}
+
+test.com {
+	tls /etc/caddy/certs/test.com.crt /etc/caddy/certs/test.com.key
+}
+
+`,
+		},
+		{
+			name:    "nginx with per-domain directories",
+			format:  "NGINX",
+			certDir: "/etc/nginx/ssl/{{.Provider}}/{{.Domain}}",
+			want: `server {
+    listen 443 ssl;
+    server_name example.com;
+
+    ssl_certificate     /etc/nginx/ssl/porkbun/example.com/example.com.crt;
+    ssl_certificate_key /etc/nginx/ssl/porkbun/example.com/example.com.key;
+}
+
+server {
+    listen 443 ssl;
+    server_name test.com;
+
+    ssl_certificate     /etc/nginx/ssl/digitalocean/test.com/test.com.crt;
+    ssl_certificate_key /etc/nginx/ssl/digitalocean/test.com/test.com.key;
+}
+
+`,
+		},
+		{
+			name:         "custom template",
+			format:       "caddy",
+			templateFile: customTemplate,
+			certDir:      "/etc/haproxy/certs",
+			want: `crt /etc/haproxy/certs/example.com.pem # porkbun
+crt /etc/haproxy/certs/test.com.pem # digitalocean
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := loadExportTemplate(tt.format, tt.templateFile)
+			if err != nil {
+				t.Fatalf("loadExportTemplate failed: %v", err)
+			}
+			certDirTmpl := template.Must(template.New("cert-dir").Option("missingkey=error").Parse(tt.certDir))
+
+			var out bytes.Buffer
+			if err := renderExport(&out, tmpl, certDirTmpl, domains, lookup); err != nil {
+				t.Fatalf("renderExport failed: %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("Export =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderExportErrors(t *testing.T) {
+	if _, err := loadExportTemplate("apache", ""); err == nil || !strings.Contains(err.Error(), "unsupported export format") {
+		t.Errorf("Expected an unsupported format error, got %v", err)
+	}
+	if _, err := loadExportTemplate("caddy", filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Error("Expected an error for a missing template file")
+	}
+
+	// A --cert-dir referring to an unknown field fails for the domain it was expanded for
+	tmpl, err := loadExportTemplate("caddy", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	certDirTmpl := template.Must(template.New("cert-dir").Option("missingkey=error").Parse("/etc/ssl/{{.Region}}"))
+	err = renderExport(&bytes.Buffer{}, tmpl, certDirTmpl, []string{"example.com"}, func(string) *domain.Info { return nil })
+	if err == nil || !strings.Contains(err.Error(), "failed to expand --cert-dir for example.com") {
+		t.Errorf("Expected a --cert-dir expansion error, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// infoCmd represents the domain info command
+var infoCmd = &cobra.Command{
+	Use:   "info <domain>",
+	Short: "Show the details of a managed domain",
+	Long: `Show the provider, status, dates and tags of a managed domain, and the details of
+its certificate once one has been retrieved.
+
+The details are captured when the providers start. With --refresh, the domain is
+re-queried from its provider first, e.g. to see a status that changed since then.
+
+Examples:
+  # Show the details of example.com
+  go-cert-provider domain info example.com
+
+  # Re-query the provider first, and print the result as JSON
+  go-cert-provider domain info example.com --refresh --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		domainName := args[0]
+
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		if outputFormat != "text" && outputFormat != "json" {
+			return fmt.Errorf("unsupported output format: %s", outputFormat)
+		}
+		refresh, err := cmd.Flags().GetBool("refresh")
+		if err != nil {
+			return err
+		}
+
+		// Use global app state (initialized in PersistentPreRunE)
+		if !appState.initialized {
+			return fmt.Errorf("certificate system not initialized")
+		}
+
+		providerRegistry := appState.providerRegistry
+
+		if _, err := providerRegistry.GetProviderForDomain(domainName); err != nil {
+			return domainNotManagedError(domainName, providerRegistry.ListProviders(), providerRegistry.ListDomains())
+		}
+
+		if refresh {
+			if err := providerRegistry.RefreshDomainInfo(cmd.Context(), domainName); err != nil {
+				return fmt.Errorf("failed to refresh domain info: %w", err)
+			}
+		}
+
+		info := providerRegistry.GetDomainInfo(domainName)
+		if info == nil {
+			return fmt.Errorf("no information available for %s", domainName)
+		}
+
+		if outputFormat == "json" {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(newDomainInfoJSON(domainName, info))
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Domain:      %s\n", info.Name)
+		fmt.Fprintf(out, "Provider:    %s\n", info.Provider)
+		fmt.Fprintf(out, "Status:      %s\n", info.Status)
+		fmt.Fprintf(out, "Created:     %s\n", formatDate(info.CreateDate))
+		fmt.Fprintf(out, "Expires:     %s\n", formatDate(info.ExpireDate))
+		fmt.Fprintf(out, "Auto-renew:  %t\n", info.AutoRenew)
+		fmt.Fprintf(out, "Tags:        %s\n", formatTags(info.Tags))
+		if info.CertSerial != "" {
+			fmt.Fprintf(out, "Certificate: serial %s, SHA-256 %s, expires %s\n",
+				info.CertSerial, info.CertFingerprintSHA256, formatDate(info.CertNotAfter))
+		}
+		return nil
+	},
+}
+
+func init() {
+	infoCmd.Flags().String("output", "text", "Output format (text, json)")
+	infoCmd.Flags().Bool("refresh", false, "Re-query the domain from its provider before showing it")
+
+	domainCmd.AddCommand(infoCmd)
+}
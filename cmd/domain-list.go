@@ -1,13 +1,16 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -30,6 +33,34 @@ Examples:
   # Output as JSON with details
   go-cert-provider domain list --output json --detail
 
+  # Stream one JSON object per domain per line (JSON Lines), e.g. for jq
+  go-cert-provider domain list --output jsonl --detail | jq -c 'select(.status == "ACTIVE")'
+
+  # Export the inventory as CSV for spreadsheets, with the certificate expiry
+  go-cert-provider domain list --output csv --cert-expiry > domains.csv
+
+  # Only show subdomains of example.com, or domains starting with "api."
+  go-cert-provider domain list --filter "*.example.com"
+  go-cert-provider domain list --filter "api.*"
+
+  # Only show domains managed by a specific provider
+  go-cert-provider domain list --provider porkbun
+
+  # Only show domains tagged both team-a and prod in the --tags file, or list by tag
+  go-cert-provider --tags tags.yaml domain list --tag team-a --tag prod
+  go-cert-provider --tags tags.yaml domain list --group-by-tag
+
+  # Only show domains registered in the last 30 days, or since a date
+  go-cert-provider domain list --created-after 30d
+  go-cert-provider domain list --created-after 2025-01-01
+
+  # Print only the number of matching domains
+  go-cert-provider domain list --count --filter "*.example.com"
+
+  # Page through the (sorted, filtered) domains 50 at a time
+  go-cert-provider domain list --limit 50
+  go-cert-provider domain list --limit 50 --offset 50
+
   # With Porkbun provider (auto-discovery)
   go-cert-provider domain list \
     --porkbun-api-key "your-key" \
@@ -50,9 +81,59 @@ Examples:
 		if err != nil {
 			return err
 		}
+		filterPattern, err := cmd.Flags().GetString("filter")
+		if err != nil {
+			return err
+		}
+		providerName, err := cmd.Flags().GetString("provider")
+		if err != nil {
+			return err
+		}
+		countOnly, err := cmd.Flags().GetBool("count")
+		if err != nil {
+			return err
+		}
+		createdAfterStr, err := cmd.Flags().GetString("created-after")
+		if err != nil {
+			return err
+		}
+		limit, err := cmd.Flags().GetInt("limit")
+		if err != nil {
+			return err
+		}
+		offset, err := cmd.Flags().GetInt("offset")
+		if err != nil {
+			return err
+		}
+		tags, err := cmd.Flags().GetStringSlice("tag")
+		if err != nil {
+			return err
+		}
+		groupByTag, err := cmd.Flags().GetBool("group-by-tag")
+		if err != nil {
+			return err
+		}
+		if groupByTag && outputFormat != "table" {
+			return fmt.Errorf("--group-by-tag requires --output table")
+		}
+		certExpiry, err := cmd.Flags().GetBool("cert-expiry")
+		if err != nil {
+			return err
+		}
+		if certExpiry && outputFormat != "csv" {
+			return fmt.Errorf("--cert-expiry requires --output csv")
+		}
+
+		var createdAfter time.Time
+		if createdAfterStr != "" {
+			createdAfter, err = parseCreatedAfter(createdAfterStr)
+			if err != nil {
+				return fmt.Errorf("invalid --created-after %q: use a duration (e.g., '30d', '2w') or date/time (YYYY-MM-DD HH:mm:ss, YYYY-MM-DD)", createdAfterStr)
+			}
+		}
 
 		// Use global app state (initialized in PersistentPreRunE)
-		if appState == nil {
+		if !appState.initialized {
 			return fmt.Errorf("certificate system not initialized")
 		}
 
@@ -60,34 +141,179 @@ Examples:
 
 		domains := providerRegistry.ListDomains()
 
+		if filterPattern != "" {
+			domains, err = utils.FilterGlob(domains, filterPattern)
+			if err != nil {
+				return err
+			}
+		}
+
+		if providerName != "" {
+			domains = filterDomainsByProvider(domains, providerName)
+		}
+
+		if len(tags) > 0 {
+			domains = filterDomainsByTags(domains, tags)
+		}
+
+		if !createdAfter.IsZero() {
+			var undated int
+			domains, undated = filterDomainsCreatedAfter(domains, createdAfter)
+			if undated > 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Note: excluded %d domain(s) without a creation date (e.g. manually configured)\n", undated)
+			}
+		}
+
+		// --count reports every match; paging only affects the listed domains
+		if countOnly {
+			fmt.Fprintln(cmd.OutOrStdout(), len(domains))
+			return nil
+		}
+
 		if len(domains) == 0 {
-			fmt.Fprintln(cmd.OutOrStderr(), "No domains found")
+			fmt.Fprintln(infoOut(cmd), "No domains found")
 			return nil
 		}
 
-		sort.Strings(domains)
+		page := newDomainPage(len(domains), offset, limit)
+		domains = domains[page.offset:page.end()]
+		if len(domains) == 0 && outputFormat != "json" && outputFormat != "csv" {
+			printTotal(cmd, page)
+			return nil
+		}
 
 		switch outputFormat {
+		case "csv":
+			if err := writeDomainsCSV(cmd.OutOrStdout(), domains, providerRegistry.GetDomainInfo, certExpiry); err != nil {
+				return err
+			}
+			printTotal(cmd, page)
+			return nil
+		case "jsonl":
+			return outputJSONLines(cmd, domains, page, showDetail)
 		case "json":
-			return outputJSON(cmd, domains, providerRegistry, showDetail)
+			return outputJSON(cmd, domains, page, showDetail)
 		case "table", "":
-			return outputTable(cmd, domains, providerRegistry, showDetail)
+			if groupByTag {
+				return outputGroupedByTag(cmd, domains, page)
+			}
+			return outputTable(cmd, domains, page, showDetail)
 		case "simple":
-			return outputSimple(cmd, domains)
+			return outputSimple(cmd, domains, page)
 		default:
 			return fmt.Errorf("unsupported output format: %s", outputFormat)
 		}
 	},
 }
 
-func outputSimple(cmd *cobra.Command, domains []string) error {
+// filterDomainsByProvider keeps only the domains managed by the named provider
+func filterDomainsByProvider(domains []string, providerName string) []string {
+	filtered := make([]string, 0, len(domains))
+	for _, domainName := range domains {
+		info := appState.providerRegistry.GetDomainInfo(domainName)
+		if info != nil && strings.EqualFold(info.Provider, providerName) {
+			filtered = append(filtered, domainName)
+		}
+	}
+	return filtered
+}
+
+// filterDomainsByTags keeps only the domains carrying every tag
+func filterDomainsByTags(domains []string, tags []string) []string {
+	tagged := make(map[string]bool)
+	for _, domainName := range appState.providerRegistry.ListDomainsWithTags(tags...) {
+		tagged[domainName] = true
+	}
+
+	filtered := make([]string, 0, len(domains))
+	for _, domainName := range domains {
+		if tagged[domainName] {
+			filtered = append(filtered, domainName)
+		}
+	}
+	return filtered
+}
+
+// filterDomainsCreatedAfter keeps only the domains created after since. Domains without
+// a creation date are dropped and counted separately.
+func filterDomainsCreatedAfter(domains []string, since time.Time) (filtered []string, undated int) {
+	filtered = make([]string, 0, len(domains))
+	for _, domainName := range domains {
+		info := appState.providerRegistry.GetDomainInfo(domainName)
+		if info == nil || info.CreateDate.IsZero() {
+			undated++
+			continue
+		}
+		if info.CreateDate.After(since) {
+			filtered = append(filtered, domainName)
+		}
+	}
+	return filtered, undated
+}
+
+// parseCreatedAfter parses a duration relative to now (e.g. "30d" means 30 days ago)
+// or an absolute date/time
+func parseCreatedAfter(value string) (time.Time, error) {
+	if duration, err := utils.ParseDurationString(value); err == nil {
+		return time.Now().Add(-duration), nil
+	}
+
+	if t, err := utils.ParseDateTime(value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, utils.DisplayLocation())
+}
+
+// domainPage is the window of the sorted, filtered domains selected by --offset and --limit
+type domainPage struct {
+	offset int
+	limit  int // 0 means no limit
+	total  int
+}
+
+// newDomainPage clamps offset and limit to the total: negative values become 0 and an
+// offset past the end selects an empty page
+func newDomainPage(total, offset, limit int) domainPage {
+	return domainPage{
+		offset: min(max(offset, 0), total),
+		limit:  max(limit, 0),
+		total:  total,
+	}
+}
+
+// end returns the index just past the last domain of the page
+func (p domainPage) end() int {
+	if p.limit == 0 {
+		return p.total
+	}
+	return min(p.offset+p.limit, p.total)
+}
+
+// printTotal writes the human-readable domain count to stderr, keeping stdout parseable
+func printTotal(cmd *cobra.Command, page domainPage) {
+	switch {
+	case page.offset == 0 && page.end() == page.total:
+		fmt.Fprintf(infoOut(cmd), "\nTotal: %d domain(s)\n", page.total)
+		return
+	case page.offset == page.end():
+		fmt.Fprintf(infoOut(cmd), "\nNo domains at offset %d of %d domain(s)\n", page.offset, page.total)
+		return
+	}
+	fmt.Fprintf(infoOut(cmd), "\nShowing %d–%d of %d domain(s)\n", page.offset+1, page.end(), page.total)
+}
+
+func outputSimple(cmd *cobra.Command, domains []string, page domainPage) error {
 	for _, domain := range domains {
 		fmt.Fprintln(cmd.OutOrStdout(), domain)
 	}
+	printTotal(cmd, page)
 	return nil
 }
 
-func outputTable(cmd *cobra.Command, domains []string, registry interface{}, showDetail bool) error {
+func outputTable(cmd *cobra.Command, domains []string, page domainPage, showDetail bool) error {
 	providerRegistry := appState.providerRegistry
 
 	allDomainInfo := providerRegistry.ListAllDomainInfo()
@@ -104,26 +330,33 @@ func outputTable(cmd *cobra.Command, domains []string, registry interface{}, sho
 				maxDomainLen = len(d)
 			}
 		}
+		maxProviderLen := 8 // "PROVIDER"
+		for _, d := range domains {
+			if info := infoMap[d]; info != nil && len(info.Provider) > maxProviderLen {
+				maxProviderLen = len(info.Provider)
+			}
+		}
 
-		fmt.Fprintf(cmd.OutOrStdout(), "%-*s  %-8s  %-10s  %-19s  %-19s\n",
-			maxDomainLen, "DOMAIN", "PROVIDER", "STATUS", "CREATED", "EXPIRES")
-		fmt.Fprintf(cmd.OutOrStdout(), "%s  %s  %s  %s  %s\n",
+		fmt.Fprintf(cmd.OutOrStdout(), "%-*s  %-*s  %-10s  %-19s  %-19s  %s\n",
+			maxDomainLen, "DOMAIN", maxProviderLen, "PROVIDER", "STATUS", "CREATED", "EXPIRES", "TAGS")
+		fmt.Fprintf(cmd.OutOrStdout(), "%s  %s  %s  %s  %s  %s\n",
 			strings.Repeat("-", maxDomainLen),
-			strings.Repeat("-", 8),
+			strings.Repeat("-", maxProviderLen),
 			strings.Repeat("-", 10),
 			strings.Repeat("-", 19),
-			strings.Repeat("-", 19))
+			strings.Repeat("-", 19),
+			strings.Repeat("-", 4))
 
 		for _, domainName := range domains {
 			info := infoMap[domainName]
 			if info != nil {
 				created := formatDate(info.CreateDate)
 				expires := formatDate(info.ExpireDate)
-				fmt.Fprintf(cmd.OutOrStdout(), "%-*s  %-8s  %-10s  %-19s  %-19s\n",
-					maxDomainLen, domainName, info.Provider, info.Status, created, expires)
+				fmt.Fprintf(cmd.OutOrStdout(), "%-*s  %-*s  %-10s  %-19s  %-19s  %s\n",
+					maxDomainLen, domainName, maxProviderLen, info.Provider, info.Status, created, expires, formatTags(info.Tags))
 			} else {
-				fmt.Fprintf(cmd.OutOrStdout(), "%-*s  %-8s  %-10s  %-19s  %-19s\n",
-					maxDomainLen, domainName, "unknown", "UNKNOWN", "-", "-")
+				fmt.Fprintf(cmd.OutOrStdout(), "%-*s  %-*s  %-10s  %-19s  %-19s  %s\n",
+					maxDomainLen, domainName, maxProviderLen, "unknown", "UNKNOWN", "-", "-", "-")
 			}
 		}
 	} else {
@@ -134,19 +367,186 @@ func outputTable(cmd *cobra.Command, domains []string, registry interface{}, sho
 		}
 	}
 
-	fmt.Fprintf(cmd.OutOrStderr(), "\nTotal: %d domain(s)\n", len(domains))
+	printTotal(cmd, page)
 	return nil
 }
 
+// outputGroupedByTag lists the domains under each of their tags; a domain with several
+// tags is listed once per tag, and domains without tags are listed last
+func outputGroupedByTag(cmd *cobra.Command, domains []string, page domainPage) error {
+	groups := make(map[string][]string)
+	var untagged []string
+	for _, domainName := range domains {
+		info := appState.providerRegistry.GetDomainInfo(domainName)
+		if info == nil || len(info.Tags) == 0 {
+			untagged = append(untagged, domainName)
+			continue
+		}
+		for _, tag := range info.Tags {
+			groups[tag] = append(groups[tag], domainName)
+		}
+	}
+
+	tags := make([]string, 0, len(groups))
+	for tag := range groups {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	printGroup := func(title string, groupDomains []string) {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s (%d)\n", title, len(groupDomains))
+		fmt.Fprintln(cmd.OutOrStdout(), strings.Repeat("-", 40))
+		for _, domainName := range groupDomains {
+			fmt.Fprintln(cmd.OutOrStdout(), domainName)
+		}
+	}
+
+	for i, tag := range tags {
+		if i > 0 {
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+		printGroup(tag, groups[tag])
+	}
+	if len(untagged) > 0 {
+		if len(tags) > 0 {
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+		printGroup("(untagged)", untagged)
+	}
+
+	printTotal(cmd, page)
+	return nil
+}
+
+// formatTags formats tags for display
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return "-"
+	}
+	return strings.Join(tags, ",")
+}
+
 // formatDate formats a time.Time for display
 func formatDate(t time.Time) string {
 	if t.IsZero() {
 		return "-"
 	}
-	return t.Format("2006-01-02 15:04")
+	return t.In(utils.DisplayLocation()).Format("2006-01-02 15:04")
+}
+
+// nonNilTags returns tags, or an empty slice so JSON output has [] rather than null
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// domainInfoJSON is the detailed JSON form of a domain, shared by the json and jsonl outputs
+type domainInfoJSON struct {
+	Domain     string   `json:"domain"`
+	Provider   string   `json:"provider"`
+	Status     string   `json:"status"`
+	CreateDate string   `json:"createDate,omitempty"`
+	ExpireDate string   `json:"expireDate,omitempty"`
+	Tags       []string `json:"tags"`
+}
+
+// newDomainInfoJSON builds the detailed JSON form of a domain; info may be nil
+func newDomainInfoJSON(domainName string, info *domain.Info) domainInfoJSON {
+	if info == nil {
+		return domainInfoJSON{
+			Domain:   domainName,
+			Provider: "unknown",
+			Status:   "UNKNOWN",
+			Tags:     []string{},
+		}
+	}
+
+	created := ""
+	expires := ""
+	if !info.CreateDate.IsZero() {
+		created = info.CreateDate.In(utils.DisplayLocation()).Format(time.RFC3339)
+	}
+	if !info.ExpireDate.IsZero() {
+		expires = info.ExpireDate.In(utils.DisplayLocation()).Format(time.RFC3339)
+	}
+
+	return domainInfoJSON{
+		Domain:     domainName,
+		Provider:   info.Provider,
+		Status:     info.Status,
+		CreateDate: created,
+		ExpireDate: expires,
+		Tags:       nonNilTags(info.Tags),
+	}
+}
+
+// writeDomainsCSV writes the domains as RFC 4180 CSV with a header row. Dates are RFC 3339
+// and empty when unknown; certExpiry adds the certificate's expiry as a last column.
+func writeDomainsCSV(w io.Writer, domains []string, lookup func(string) *domain.Info, certExpiry bool) error {
+	formatCSVDate := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.In(utils.DisplayLocation()).Format(time.RFC3339)
+	}
+
+	writer := csv.NewWriter(w)
+	header := []string{"domain", "provider", "status", "createDate", "expireDate"}
+	if certExpiry {
+		header = append(header, "certExpireDate")
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, domainName := range domains {
+		record := []string{domainName, "unknown", "UNKNOWN", "", ""}
+		var certNotAfter time.Time
+		if info := lookup(domainName); info != nil {
+			record = []string{domainName, info.Provider, info.Status, formatCSVDate(info.CreateDate), formatCSVDate(info.ExpireDate)}
+			certNotAfter = info.CertNotAfter
+		}
+		if certExpiry {
+			record = append(record, formatCSVDate(certNotAfter))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
 }
 
-func outputJSON(cmd *cobra.Command, domains []string, registry interface{}, showDetail bool) error {
+// outputJSONLines writes one JSON object per domain per line, without a wrapping array.
+// Each line is written as soon as it is encoded, so consumers can stream the list.
+func outputJSONLines(cmd *cobra.Command, domains []string, page domainPage, showDetail bool) error {
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+
+	if showDetail {
+		for _, domainName := range domains {
+			if err := encoder.Encode(newDomainInfoJSON(domainName, appState.providerRegistry.GetDomainInfo(domainName))); err != nil {
+				return err
+			}
+		}
+	} else {
+		type domainJSON struct {
+			Domain string `json:"domain"`
+		}
+		for _, domainName := range domains {
+			if err := encoder.Encode(domainJSON{Domain: domainName}); err != nil {
+				return err
+			}
+		}
+	}
+
+	printTotal(cmd, page)
+	return nil
+}
+
+func outputJSON(cmd *cobra.Command, domains []string, page domainPage, showDetail bool) error {
 	providerRegistry := appState.providerRegistry
 
 	// Get all domain info
@@ -159,73 +559,74 @@ func outputJSON(cmd *cobra.Command, domains []string, registry interface{}, show
 	}
 
 	if showDetail {
-		// Build domain-provider map with full info
-		type domainInfoJSON struct {
-			Domain     string `json:"domain"`
-			Provider   string `json:"provider"`
-			Status     string `json:"status"`
-			CreateDate string `json:"createDate,omitempty"`
-			ExpireDate string `json:"expireDate,omitempty"`
-		}
-
-		var domainInfos []domainInfoJSON
+		domainInfos := make([]domainInfoJSON, 0, len(domains))
 		for _, domainName := range domains {
-			info := infoMap[domainName]
-			if info != nil {
-				created := ""
-				expires := ""
-				if !info.CreateDate.IsZero() {
-					created = info.CreateDate.Format(time.RFC3339)
-				}
-				if !info.ExpireDate.IsZero() {
-					expires = info.ExpireDate.Format(time.RFC3339)
-				}
-
-				domainInfos = append(domainInfos, domainInfoJSON{
-					Domain:     domainName,
-					Provider:   info.Provider,
-					Status:     info.Status,
-					CreateDate: created,
-					ExpireDate: expires,
-				})
-			} else {
-				domainInfos = append(domainInfos, domainInfoJSON{
-					Domain:   domainName,
-					Provider: "unknown",
-					Status:   "UNKNOWN",
-				})
-			}
+			domainInfos = append(domainInfos, newDomainInfoJSON(domainName, infoMap[domainName]))
 		}
 
 		payload := struct {
 			Total   int              `json:"total"`
+			Offset  int              `json:"offset"`
+			Limit   int              `json:"limit,omitempty"`
 			Domains []domainInfoJSON `json:"domains"`
 		}{
-			Total:   len(domains),
+			Total:   page.total,
+			Offset:  page.offset,
+			Limit:   page.limit,
 			Domains: domainInfos,
 		}
 
 		encoder := json.NewEncoder(cmd.OutOrStdout())
 		encoder.SetIndent("", "  ")
-		return encoder.Encode(payload)
+		if err := encoder.Encode(payload); err != nil {
+			return err
+		}
+		printTotal(cmd, page)
+		return nil
 	}
 
 	payload := struct {
 		Total   int      `json:"total"`
+		Offset  int      `json:"offset"`
+		Limit   int      `json:"limit,omitempty"`
 		Domains []string `json:"domains"`
 	}{
-		Total:   len(domains),
+		Total:   page.total,
+		Offset:  page.offset,
+		Limit:   page.limit,
 		Domains: domains,
 	}
 
 	encoder := json.NewEncoder(cmd.OutOrStdout())
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(payload)
+	if err := encoder.Encode(payload); err != nil {
+		return err
+	}
+	printTotal(cmd, page)
+	return nil
+}
+
+// addListFlags adds the flags read by domain list
+func addListFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.String("output", "table", "Output format (table, simple, json, jsonl, csv)")
+	flags.Bool("detail", false, "Show detailed information (provider, status, dates)")
+	flags.String("filter", "", "Only show domains matching a glob pattern (e.g. \"*.example.com\", \"api.*\")")
+	flags.String("provider", "", "Only show domains managed by this provider (e.g. porkbun)")
+	flags.String("created-after", "", "Only show domains created after this date/time or within this duration (e.g. 30d, 2025-01-01); domains without a creation date are excluded")
+	flags.Bool("count", false, "Print only the number of matching domains")
+	flags.Int("limit", 0, "Show at most this many domains, after sorting and filtering (0 for no limit)")
+	flags.Int("offset", 0, "Skip this many domains before listing, for paging with --limit")
+	flags.StringSlice("tag", nil, "Only show domains carrying this tag from the --tags file; repeat or comma-separate to require several tags")
+	flags.Bool("cert-expiry", false, "With --output csv, add a certExpireDate column with the certificate expiry, when known")
+	flags.Bool("group-by-tag", false, "List the domains under each of their tags (table output)")
+
+	cmd.MarkFlagsMutuallyExclusive("count", "detail")
+	cmd.MarkFlagsMutuallyExclusive("group-by-tag", "detail")
 }
 
 func init() {
-	listCmd.Flags().String("output", "table", "Output format (table, simple, json)")
-	listCmd.Flags().Bool("detail", false, "Show detailed information (provider, status, dates)")
+	addListFlags(listCmd)
 
 	domainCmd.AddCommand(listCmd)
 }
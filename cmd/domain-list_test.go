@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/cert/providers/mock"
+	"github.com/dh-kam/go-cert-provider/cert/registry"
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/spf13/cobra"
+)
+
+// useTestProviders makes the providers the initialized certificate system of the CLI
+// for the duration of the test
+func useTestProviders(t *testing.T, providers ...domain.CertificateProvider) {
+	t.Helper()
+
+	providerRegistry := registry.NewCertificateProviderRegistry()
+	for _, provider := range providers {
+		if err := providerRegistry.Register(provider); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+	}
+
+	saved := appState
+	appState = &globalState{providerRegistry: providerRegistry, initialized: true}
+	t.Cleanup(func() { appState = saved })
+}
+
+// runDomainList runs domain list with the arguments, returning stdout, stderr and the error
+func runDomainList(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+
+	cmd := &cobra.Command{}
+	addListFlags(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags failed: %v", err)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	err := listCmd.RunE(cmd, nil)
+	return stdout.String(), stderr.String(), err
+}
+
+func TestWriteDomainsCSV(t *testing.T) {
+	infos := map[string]*domain.Info{
+		"example.com": {
+			Name:         "example.com",
+			Provider:     "porkbun",
+			Status:       "ACTIVE",
+			CreateDate:   time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			ExpireDate:   time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+			CertNotAfter: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"test.com": {Name: "test.com", Provider: "porkbun", Status: "CONFIGURED, MANUAL"},
+	}
+	lookup := func(name string) *domain.Info { return infos[name] }
+	domains := []string{"example.com", "test.com", "unknown.com"}
+
+	tests := []struct {
+		name       string
+		certExpiry bool
+		header     []string
+	}{
+		{"default columns", false, []string{"domain", "provider", "status", "createDate", "expireDate"}},
+		{"with cert expiry", true, []string{"domain", "provider", "status", "createDate", "expireDate", "certExpireDate"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeDomainsCSV(&buf, domains, lookup, tt.certExpiry); err != nil {
+				t.Fatalf("writeDomainsCSV() error = %v", err)
+			}
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("Output is not valid CSV: %v", err)
+			}
+			if len(records) != len(domains)+1 {
+				t.Fatalf("Got %d records, want a header and %d rows", len(records), len(domains))
+			}
+			if !reflect.DeepEqual(records[0], tt.header) {
+				t.Errorf("Header = %v, want %v", records[0], tt.header)
+			}
+
+			// A status containing a comma must survive as a single field
+			if records[2][2] != "CONFIGURED, MANUAL" {
+				t.Errorf("Status of test.com = %q, want %q", records[2][2], "CONFIGURED, MANUAL")
+			}
+			if records[3][1] != "unknown" || records[3][2] != "UNKNOWN" {
+				t.Errorf("Unknown domain row = %v", records[3])
+			}
+			if tt.certExpiry && records[1][5] == "" {
+				t.Error("certExpireDate of example.com should be set")
+			}
+		})
+	}
+}
+
+func TestNewDomainPage(t *testing.T) {
+	tests := []struct {
+		name                  string
+		total, offset, limit  int
+		wantOffset, wantLimit int
+		wantEnd               int
+	}{
+		{"everything", 5, 0, 0, 0, 0, 5},
+		{"first page", 5, 0, 2, 0, 2, 2},
+		{"middle page", 5, 2, 2, 2, 2, 4},
+		{"last partial page", 5, 4, 2, 4, 2, 5},
+		{"offset without limit", 5, 3, 0, 3, 0, 5},
+		{"offset at the end", 5, 5, 2, 5, 2, 5},
+		{"offset past the end", 5, 9, 2, 5, 2, 5},
+		{"negative offset", 5, -3, 2, 0, 2, 2},
+		{"negative limit means no limit", 5, 1, -1, 1, 0, 5},
+		{"no domains", 0, 2, 2, 0, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := newDomainPage(tt.total, tt.offset, tt.limit)
+			if page.offset != tt.wantOffset || page.limit != tt.wantLimit || page.total != tt.total {
+				t.Errorf("Page = %+v, want offset %d, limit %d, total %d", page, tt.wantOffset, tt.wantLimit, tt.total)
+			}
+			if page.end() != tt.wantEnd {
+				t.Errorf("end() = %d, want %d", page.end(), tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestDomainListPaging(t *testing.T) {
+	useTestProviders(t, mock.NewProvider([]string{"a.com", "b.com", "api.c.com", "www.c.com", "d.com"}, nil, nil))
+
+	type listJSON struct {
+		Total   int      `json:"total"`
+		Offset  int      `json:"offset"`
+		Limit   *int     `json:"limit"`
+		Domains []string `json:"domains"`
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantTotal   int
+		wantOffset  int
+		wantLimit   int // 0 means the limit field is omitted
+		wantDomains []string
+		wantStderr  string
+	}{
+		{"all domains", nil, 5, 0, 0, []string{"a.com", "api.c.com", "b.com", "d.com", "www.c.com"}, "Total: 5 domain(s)"},
+		{"page", []string{"--limit", "2", "--offset", "1"}, 5, 1, 2, []string{"api.c.com", "b.com"}, "Showing 2–3 of 5 domain(s)"},
+		{"offset past the end", []string{"--limit", "2", "--offset", "9"}, 5, 5, 2, []string{}, "No domains at offset 5 of 5 domain(s)"},
+		{"negative values", []string{"--limit", "-1", "--offset", "-1"}, 5, 0, 0, []string{"a.com", "api.c.com", "b.com", "d.com", "www.c.com"}, "Total: 5 domain(s)"},
+		{"paging after the filter", []string{"--filter", "*.c.com", "--limit", "1", "--offset", "1"}, 2, 1, 1, []string{"www.c.com"}, "Showing 2–2 of 2 domain(s)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, err := runDomainList(t, append([]string{"--output", "json"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("domain list failed: %v", err)
+			}
+
+			var got listJSON
+			if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+				t.Fatalf("Invalid JSON output: %v\n%s", err, stdout)
+			}
+			if got.Total != tt.wantTotal || got.Offset != tt.wantOffset {
+				t.Errorf("total/offset = %d/%d, want %d/%d", got.Total, got.Offset, tt.wantTotal, tt.wantOffset)
+			}
+			if (tt.wantLimit == 0 && got.Limit != nil) || (tt.wantLimit != 0 && (got.Limit == nil || *got.Limit != tt.wantLimit)) {
+				t.Errorf("limit = %v, want %d (0 omits it)", got.Limit, tt.wantLimit)
+			}
+			if !reflect.DeepEqual(got.Domains, tt.wantDomains) {
+				t.Errorf("domains = %v, want %v", got.Domains, tt.wantDomains)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("Stderr does not contain %q:\n%s", tt.wantStderr, stderr)
+			}
+		})
+	}
+}
+
+func TestDomainListCount(t *testing.T) {
+	useTestProviders(t, mock.NewProvider([]string{"a.com", "api.c.com", "www.c.com"}, nil, nil))
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"all domains", nil, "3"},
+		{"filtered", []string{"--filter", "*.c.com"}, "2"},
+		// --count reports every match; paging only affects the listed domains
+		{"paging is ignored", []string{"--filter", "*.c.com", "--limit", "1", "--offset", "1"}, "2"},
+		{"no match", []string{"--filter", "*.org"}, "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, _, err := runDomainList(t, append([]string{"--count"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("domain list failed: %v", err)
+			}
+			if strings.TrimSpace(stdout) != tt.want {
+				t.Errorf("Count = %q, want %s", stdout, tt.want)
+			}
+		})
+	}
+}
+
+// datedProvider is a mock provider reporting creation dates; domains missing from created are undated
+type datedProvider struct {
+	*mock.Provider
+	created map[string]time.Time
+}
+
+func (p *datedProvider) GetDomainInfo(domainName string) *domain.Info {
+	info := p.Provider.GetDomainInfo(domainName)
+	if info != nil {
+		info.CreateDate = p.created[domainName]
+	}
+	return info
+}
+
+func TestFilterDomainsCreatedAfter(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	useTestProviders(t, &datedProvider{
+		Provider: mock.NewProvider([]string{"old.com", "new.com", "exact.com", "manual.com"}, nil, nil),
+		created: map[string]time.Time{
+			"old.com":   since.AddDate(0, 0, -1),
+			"new.com":   since.AddDate(0, 0, 1),
+			"exact.com": since,
+		},
+	})
+
+	tests := []struct {
+		name        string
+		domains     []string
+		want        []string
+		wantUndated int
+	}{
+		// A domain created exactly at the cutoff is not after it
+		{"dated domains", []string{"old.com", "new.com", "exact.com"}, []string{"new.com"}, 0},
+		{"undated domains are counted", []string{"new.com", "manual.com"}, []string{"new.com"}, 1},
+		{"unknown domains are counted as undated", []string{"unknown.com"}, []string{}, 1},
+		{"no domains", nil, []string{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, undated := filterDomainsCreatedAfter(tt.domains, since)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Domains = %v, want %v", got, tt.want)
+			}
+			if undated != tt.wantUndated {
+				t.Errorf("Undated = %d, want %d", undated, tt.wantUndated)
+			}
+		})
+	}
+
+	utils.SetUTC(true)
+	defer utils.SetUTC(false)
+	stdout, stderr, err := runDomainList(t, "--created-after", "2025-01-01", "--output", "simple")
+	if err != nil {
+		t.Fatalf("domain list failed: %v", err)
+	}
+	if strings.TrimSpace(stdout) != "new.com" || !strings.Contains(stderr, "excluded 1 domain(s) without a creation date") {
+		t.Errorf("Unexpected output:\n%s\nstderr:\n%s", stdout, stderr)
+	}
+}
+
+func TestParseCreatedAfter(t *testing.T) {
+	utils.SetUTC(true)
+	defer utils.SetUTC(false)
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		// A bare date means the start of the day in the display timezone
+		{"2025-01-01", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2025-01-01 08:30:00", time.Date(2025, 1, 1, 8, 30, 0, 0, time.UTC)},
+		{"2025-01-01T08:30:00+09:00", time.Date(2024, 12, 31, 23, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseCreatedAfter(tt.value)
+		if err != nil {
+			t.Errorf("parseCreatedAfter(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseCreatedAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	// A duration counts back from now
+	before := time.Now()
+	got, err := parseCreatedAfter("30d")
+	if err != nil || got.Before(before.Add(-30*24*time.Hour)) || got.After(time.Now().Add(-30*24*time.Hour)) {
+		t.Errorf("parseCreatedAfter(30d) = %v (error %v), want 30 days ago", got, err)
+	}
+
+	for _, value := range []string{"", "last month", "2025-13-01"} {
+		if _, err := parseCreatedAfter(value); err == nil {
+			t.Errorf("parseCreatedAfter(%q): expected an error", value)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/dh-kam/go-cert-provider/auth"
+	"github.com/dh-kam/go-cert-provider/cert/registry"
+	"github.com/dh-kam/go-cert-provider/config"
+	"github.com/gin-gonic/gin"
+)
+
+// healthHandler serves /health. With requireAuth, callers without a bearer token only get
+// the status, so load balancers can probe it cheaply without the providers and domains being
+// disclosed. Authenticated callers see the domains their token may list; an invalid token
+// is rejected with 401.
+func healthHandler(bootstrapManager *registry.BootstrapManager, providerRegistry *registry.CertificateProviderRegistry,
+	jwtSecretKey string, validationOptions auth.ValidationOptions, requireAuth bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Providers skipped by --continue-on-provider-error or failing to reload
+		// leave the server running with reduced coverage
+		failed := bootstrapManager.FailedProviders()
+		status := "ok"
+		if len(failed) > 0 {
+			status = "degraded"
+		}
+
+		domains := providerRegistry.ListDomains()
+		if requireAuth {
+			claims, ok := optionalBearerClaims(c, jwtSecretKey, validationOptions)
+			if !ok {
+				return
+			}
+			if claims == nil {
+				c.JSON(http.StatusOK, gin.H{"status": status})
+				return
+			}
+
+			visible := make([]string, 0, len(domains))
+			for _, domainName := range domains {
+				if auth.HasScope(claims, auth.ScopeList, domainName) {
+					visible = append(visible, domainName)
+				}
+			}
+			domains = visible
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":    status,
+			"version":   config.Version,
+			"providers": bootstrapManager.GetConfiguredProviders(),
+			"degraded":  failed,
+			"domains":   domains,
+		})
+	}
+}
+
+// optionalBearerClaims returns the claims of the request's bearer token, or nil claims when
+// no Authorization header is sent. A malformed or invalid token is answered with 401 and
+// ok is false.
+func optionalBearerClaims(c *gin.Context, jwtSecretKey string, validationOptions auth.ValidationOptions) (claims *auth.JWTClaims, ok bool) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		return nil, true
+	}
+
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found || token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "bearer token required"})
+		return nil, false
+	}
+	claims, err := auth.ParseJWTWithOptions(token, jwtSecretKey, validationOptions)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token: " + err.Error()})
+		return nil, false
+	}
+	return claims, true
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/auth"
+	"github.com/dh-kam/go-cert-provider/cert/providers/mock"
+	"github.com/dh-kam/go-cert-provider/cert/registry"
+	"github.com/gin-gonic/gin"
+)
+
+func TestHealthRequireAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const secretKey = "test-secret-key-32-bytes-long!!"
+
+	providerRegistry := registry.NewCertificateProviderRegistry()
+	if err := providerRegistry.Register(mock.NewProvider([]string{"example.com", "test.com"}, nil, nil)); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	bootstrapManager := registry.NewBootstrapManager(providerRegistry)
+
+	router := gin.New()
+	router.GET("/health", healthHandler(bootstrapManager, providerRegistry, secretKey, auth.ValidationOptions{}, true))
+
+	createToken := func(allowedDomains ...string) string {
+		token, err := auth.CreateJWT("user", "", time.Now().Add(time.Hour), allowedDomains, secretKey)
+		if err != nil {
+			t.Fatalf("CreateJWT failed: %v", err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name        string
+		token       string
+		wantCode    int
+		wantDomains []string // nil means the domains key must be absent
+	}{
+		{"no token gets only the status", "", http.StatusOK, nil},
+		{"token for all domains", createToken("*"), http.StatusOK, []string{"example.com", "test.com"}},
+		{"token sees only its domains", createToken("test.com"), http.StatusOK, []string{"test.com"}},
+		{"invalid token is rejected", "not-a-token", http.StatusUnauthorized, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/health", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("Status code = %d, want %d", recorder.Code, tt.wantCode)
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Invalid JSON response: %v", err)
+			}
+
+			domains, present := body["domains"]
+			if tt.wantDomains == nil {
+				if present {
+					t.Errorf("Domains should be omitted, got %v", domains)
+				}
+				if tt.wantCode == http.StatusOK && (body["status"] != "ok" || len(body) != 1) {
+					t.Errorf("Expected only the status, got %v", body)
+				}
+				return
+			}
+
+			var got []string
+			for _, d := range domains.([]interface{}) {
+				got = append(got, d.(string))
+			}
+			if !reflect.DeepEqual(got, tt.wantDomains) {
+				t.Errorf("Domains = %v, want %v", got, tt.wantDomains)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/auth"
+	"github.com/spf13/cobra"
+)
+
+type authzCheckOptions struct {
+	token            string
+	domain           string
+	action           string
+	jwtSecretKey     string
+	publicKeyFile    string
+	expectedAudience string
+	expectedIssuer   string
+	clockSkew        time.Duration
+}
+
+var authzCheckCmd = &cobra.Command{
+	Use:   "authz-check",
+	Short: "Check whether a token may list or retrieve a domain",
+	Long: `Check whether a JWT token would be allowed to list or retrieve a domain, using the
+same token validation and domain authorization as the server.
+
+The token is verified when a secret is given with --jwt-secret-key or JWT_SECRET_KEY,
+or a public key for ES256 tokens with --public-key-file. Without either, the signature is not checked, but expiry and the other claims are.
+Pass the server's --expected-audience, --expected-issuer and --jwt-clock-skew to check
+the claims the same way. The domain is matched exactly as given, like the server does.
+No provider is contacted. The command exits with a non-zero status when access would
+be denied.
+
+Examples:
+  # Check whether a token may retrieve the certificate of example.com
+  go-cert-provider jwt authz-check --token "$TOKEN" --domain example.com
+
+  # Check listing only
+  go-cert-provider jwt authz-check --token "$TOKEN" --domain example.com --action list`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		options, ok := cmd.Context().Value(KeyForOptions).(*authzCheckOptions)
+		if !ok {
+			return fmt.Errorf("failed to get command options from context")
+		}
+
+		if options.token == "" {
+			return fmt.Errorf("--token is required")
+		}
+		if options.domain == "" {
+			return fmt.Errorf("--domain is required")
+		}
+		action := strings.ToLower(options.action)
+		if action != auth.ScopeList && action != auth.ScopeRetrieve {
+			return fmt.Errorf("invalid action %q: must be %s or %s", options.action, auth.ScopeList, auth.ScopeRetrieve)
+		}
+		domainName := options.domain
+
+		jwtSecretKey := options.jwtSecretKey
+		if jwtSecretKey == "" {
+			jwtSecretKey = os.Getenv("JWT_SECRET_KEY")
+		}
+
+		publicKey, err := loadJWTPublicKey(options.publicKeyFile)
+		if err != nil {
+			return err
+		}
+		clockSkew, err := clockSkewOption(options.clockSkew)
+		if err != nil {
+			return err
+		}
+
+		expectedAudience := options.expectedAudience
+		if expectedAudience == "" {
+			expectedAudience = os.Getenv("JWT_EXPECTED_AUDIENCE")
+		}
+		expectedIssuer := options.expectedIssuer
+		if expectedIssuer == "" {
+			expectedIssuer = os.Getenv("JWT_EXPECTED_ISSUER")
+		}
+		validationOptions := auth.ValidationOptions{
+			ExpectedAudience: expectedAudience,
+			ExpectedIssuer:   expectedIssuer,
+			ClockSkew:        clockSkew,
+			PublicKey:        publicKey,
+		}
+		out := cmd.OutOrStdout()
+
+		// From here on, an error is the verdict rather than a usage mistake
+		cmd.SilenceUsage = true
+
+		var claims *auth.JWTClaims
+		if jwtSecretKey != "" || publicKey != nil {
+			claims, err = auth.ParseJWTWithOptions(options.token, jwtSecretKey, validationOptions)
+		} else {
+			fmt.Fprintf(out, "⚠️  No JWT secret key or public key given: the signature is NOT verified\n\n")
+			claims, err = auth.ParseJWTUnverifiedWithOptions(options.token, validationOptions)
+		}
+		if err != nil {
+			fmt.Fprintf(out, "❌ DENIED: %s %s\n", action, domainName)
+			fmt.Fprintf(out, "  Reason: invalid token: %v\n", err)
+			return fmt.Errorf("authorization denied")
+		}
+
+		allowed, reason := auth.ExplainScope(claims.AllowedDomains, claims.Scopes, action, domainName)
+		if !allowed {
+			fmt.Fprintf(out, "❌ DENIED: user %s may not %s %s\n", claims.UserID, action, domainName)
+			fmt.Fprintf(out, "  Reason: %s\n", reason)
+			return fmt.Errorf("authorization denied")
+		}
+
+		fmt.Fprintf(out, "✅ ALLOWED: user %s may %s %s\n", claims.UserID, action, domainName)
+		fmt.Fprintf(out, "  Reason: %s\n", reason)
+		return nil
+	},
+}
+
+func init() {
+	opts := &authzCheckOptions{}
+
+	authzCheckCmd.Flags().StringVar(&opts.token, "token", "", "JWT token to check (required)")
+	authzCheckCmd.Flags().StringVar(&opts.domain, "domain", "", "Domain to check access to (required)")
+	authzCheckCmd.Flags().StringVar(&opts.action, "action", auth.ScopeRetrieve, "Action to check (list, retrieve)")
+	authzCheckCmd.Flags().StringVar(&opts.jwtSecretKey, "jwt-secret-key", "", "JWT secret key (overrides JWT_SECRET_KEY env var)")
+	authzCheckCmd.Flags().StringVar(&opts.publicKeyFile, "public-key-file", "", "PEM EC P-256 public key to verify ES256 tokens with")
+	authzCheckCmd.Flags().StringVar(&opts.expectedAudience, "expected-audience", "", "Require the aud claim to contain this value, as the server's --expected-audience does (overrides JWT_EXPECTED_AUDIENCE env var)")
+	authzCheckCmd.Flags().StringVar(&opts.expectedIssuer, "expected-issuer", "", "Require the iss claim to equal this value, as the server's --expected-issuer does (overrides JWT_EXPECTED_ISSUER env var)")
+	authzCheckCmd.Flags().DurationVar(&opts.clockSkew, "jwt-clock-skew", auth.DefaultClockSkew, "Clock skew tolerance applied to token exp/nbf/iat checks, as on the server (0 for none)")
+
+	ctx := context.WithValue(context.Background(), KeyForOptions, opts)
+	authzCheckCmd.SetContext(ctx)
+
+	jwtCmd.AddCommand(authzCheckCmd)
+}
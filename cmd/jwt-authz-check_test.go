@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/cobra"
+)
+
+// runAuthzCheck runs jwt authz-check with the options, returning its output and error
+func runAuthzCheck(t *testing.T, options *authzCheckOptions) (string, error) {
+	t.Helper()
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.WithValue(context.Background(), KeyForOptions, options))
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	err := authzCheckCmd.RunE(cmd, nil)
+	return out.String(), err
+}
+
+func TestAuthzCheck(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "")
+	t.Setenv("JWT_EXPECTED_AUDIENCE", "")
+	t.Setenv("JWT_EXPECTED_ISSUER", "")
+
+	token := createTestToken(t, []string{"example.com"}, nil)
+	listOnly := createTestToken(t, []string{"*"}, []string{"list:*.example.com"})
+
+	otherIssuerClaims := auth.NewAccessClaims("user", "", time.Now().Add(time.Hour), []string{"example.com"})
+	otherIssuerClaims.Issuer = "go-cert-provider-staging"
+	otherIssuer, err := auth.SignJWT(otherIssuerClaims, testSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Expired 10 seconds ago: within the default clock skew, not within a 1s skew
+	justExpiredClaims := auth.NewAccessClaims("user", "", time.Now().Add(-10*time.Second), []string{"example.com"})
+	justExpiredClaims.IssuedAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	justExpiredClaims.NotBefore = justExpiredClaims.IssuedAt
+	justExpired, err := auth.SignJWT(justExpiredClaims, testSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		options    authzCheckOptions
+		wantAllow  bool
+		wantOutput string
+	}{
+		{"allowed domain", authzCheckOptions{token: token, domain: "example.com"}, true, "ALLOWED: user user may retrieve example.com"},
+		{"other domain", authzCheckOptions{token: token, domain: "test.com"}, false, "DENIED: user user may not retrieve test.com"},
+		// The server matches domains case-sensitively, so the check must too
+		{"domain in another case", authzCheckOptions{token: token, domain: "Example.COM"}, false, "DENIED: user user may not retrieve Example.COM"},
+		{"list scope allows listing", authzCheckOptions{token: listOnly, domain: "api.example.com", action: "list"}, true, "ALLOWED"},
+		{"list scope denies retrieval", authzCheckOptions{token: listOnly, domain: "api.example.com"}, false, "DENIED"},
+		{"wrong secret", authzCheckOptions{token: token, domain: "example.com", jwtSecretKey: "another-secret-key-32-bytes-long"}, false, "invalid token"},
+		{"expected issuer", authzCheckOptions{token: otherIssuer, domain: "example.com", expectedIssuer: auth.DefaultIssuer}, false, "invalid token"},
+		{"expected audience", authzCheckOptions{token: token, domain: "example.com", expectedAudience: "prod"}, false, "invalid token"},
+		{"within default clock skew", authzCheckOptions{token: justExpired, domain: "example.com", clockSkew: auth.DefaultClockSkew}, true, "ALLOWED"},
+		{"beyond clock skew", authzCheckOptions{token: justExpired, domain: "example.com", clockSkew: time.Second}, false, "invalid token"},
+		// --jwt-clock-skew 0 means no leeway rather than the default one
+		{"clock skew disabled", authzCheckOptions{token: justExpired, domain: "example.com"}, false, "invalid token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := tt.options
+			if options.jwtSecretKey == "" {
+				options.jwtSecretKey = testSecretKey
+			}
+			if options.action == "" {
+				options.action = auth.ScopeRetrieve
+			}
+
+			out, err := runAuthzCheck(t, &options)
+			if tt.wantAllow && err != nil {
+				t.Fatalf("Expected access to be allowed, got %v:\n%s", err, out)
+			}
+			if !tt.wantAllow && (err == nil || err.Error() != "authorization denied") {
+				t.Fatalf("Expected access to be denied, got %v:\n%s", err, out)
+			}
+			if !strings.Contains(out, tt.wantOutput) {
+				t.Errorf("Output does not contain %q:\n%s", tt.wantOutput, out)
+			}
+		})
+	}
+
+	t.Run("unverified token keeps the claim checks", func(t *testing.T) {
+		out, err := runAuthzCheck(t, &authzCheckOptions{token: otherIssuer, domain: "example.com", action: auth.ScopeRetrieve, expectedIssuer: auth.DefaultIssuer})
+		if err == nil || !strings.Contains(out, "signature is NOT verified") || !strings.Contains(out, "invalid token") {
+			t.Errorf("Expected the issuer to be checked without a secret, got %v:\n%s", err, out)
+		}
+	})
+
+	t.Run("negative clock skew", func(t *testing.T) {
+		if _, err := runAuthzCheck(t, &authzCheckOptions{token: token, domain: "example.com", action: auth.ScopeRetrieve, clockSkew: -time.Second}); err == nil || !strings.Contains(err.Error(), "must not be negative") {
+			t.Errorf("Expected a negative clock skew to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("invalid action", func(t *testing.T) {
+		if _, err := runAuthzCheck(t, &authzCheckOptions{token: token, domain: "example.com", action: "delete"}); err == nil || !strings.Contains(err.Error(), "invalid action") {
+			t.Errorf("Expected an invalid action error, got %v", err)
+		}
+	})
+}
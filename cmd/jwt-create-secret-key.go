@@ -36,26 +36,26 @@ var createSecretKeyCmd = &cobra.Command{
 		secretKey := base64.StdEncoding.EncodeToString(secretBytes)
 
 		// Define styles
-		titleStyle := lipgloss.NewStyle().
+		renderTitle := styleRenderer(cmd, lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("12"))
+			Foreground(lipgloss.Color("12")))
 
-		greenStyle := lipgloss.NewStyle().
+		renderGreen := styleRenderer(cmd, lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("10"))
+			Foreground(lipgloss.Color("10")))
 
-		usageStyle := lipgloss.NewStyle().
+		renderUsage := styleRenderer(cmd, lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("14"))
+			Foreground(lipgloss.Color("14")))
 
-		fmt.Println(titleStyle.Render("Generated JWT Secret Key (base64 encoded):"))
-		fmt.Println("   ", greenStyle.Render(secretKey))
+		fmt.Println(renderTitle("Generated JWT Secret Key (base64 encoded):"))
+		fmt.Println("   ", renderGreen(secretKey))
 		fmt.Println()
-		fmt.Println(usageStyle.Render("Usage:"))
+		fmt.Println(renderUsage("Usage:"))
 		fmt.Println("    Environment variable:")
-		fmt.Println("        ", greenStyle.Render(fmt.Sprintf("export JWT_SECRET_KEY=\"%s\"", secretKey)))
+		fmt.Println("        ", renderGreen(fmt.Sprintf("export JWT_SECRET_KEY=\"%s\"", secretKey)))
 		fmt.Println("    Command line option:")
-		fmt.Println("        ", greenStyle.Render(fmt.Sprintf("--jwt-secret-key \"%s\"", secretKey)))
+		fmt.Println("        ", renderGreen(fmt.Sprintf("--jwt-secret-key \"%s\"", secretKey)))
 
 		return nil
 	},
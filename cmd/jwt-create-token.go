@@ -2,29 +2,86 @@ package cmd
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/dh-kam/go-cert-provider/auth"
 	"github.com/dh-kam/go-cert-provider/utils"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/spf13/cobra"
 )
 
 type createJwtTokenOptions struct {
-	userID         string
-	description    string
-	allowedDomains string
-	expiresAt      string
-	jwtSecretKey   string
+	userID             string
+	description        string
+	allowedDomains     string
+	allowedDomainsFile string
+	scopes             []string
+	expiresAt          string
+	notBefore          string
+	jwtSecretKey       string
+	withRefresh        bool
+	refreshExpiresAt   string
+	audience           string
+	issuer             string
+	algorithm          string
+	privateKeyFile     string
+	strictSecret       bool
+	outputFile         string
+	quiet              bool
+	output             string
+}
+
+// createTokenJSON is the --output json payload of create-token
+type createTokenJSON struct {
+	Token            string     `json:"token,omitempty"`
+	TokenFile        string     `json:"tokenFile,omitempty"`
+	Algorithm        string     `json:"algorithm"`
+	RefreshToken     string     `json:"refreshToken,omitempty"`
+	RefreshExpiresAt *time.Time `json:"refreshExpiresAt,omitempty"`
+	*auth.TokenReport
 }
 
 var createTokenCmd = &cobra.Command{
 	Use:   "create-token",
 	Short: "Create a new JWT token",
-	Long:  "Create a new JWT token with specified user ID, description, and allowed domains",
+	Long: `Create a new JWT token with specified user ID, description, and allowed domains.
+
+The token is highlighted only when stdout is a terminal. Use --quiet to print
+only the raw token (and the refresh token on a second line with --with-refresh),
+or --output-file to write the raw token to a file with 0600 permissions.
+Use --output json to print the token and its claims as a JSON object.
+
+Examples:
+  # Mint a short-lived token in CI
+  TOKEN=$(go-cert-provider jwt create-token --user-id ci --allowed-domains example.com --expires-at 1h --quiet)
+
+  # Write the token to a file
+  go-cert-provider jwt create-token --user-id ci --allowed-domains example.com --output-file token.jwt
+
+  # Print the token and claims as JSON
+  go-cert-provider jwt create-token --user-id ci --allowed-domains example.com --output json
+
+  # Issue a token that is valid only during December 2025
+  go-cert-provider jwt create-token --user-id contractor --allowed-domains example.com \
+    --not-before 2025-12-01 --expires-at "2025-12-31 23:59:59"
+
+  # Sign with an EC P-256 key; the server verifies with --jwt-public-key-file
+  go-cert-provider jwt create-token --user-id ci --allowed-domains example.com \
+    --jwt-algorithm ES256 --private-key-file jwt-signing.key
+
+  # Mint a staging token that a production server started with
+  # --expected-issuer go-cert-provider-prod rejects
+  go-cert-provider jwt create-token --user-id ci --allowed-domains example.com --issuer go-cert-provider-staging
+
+  # Let a dashboard list example.com and its subdomains, but only retrieve api.example.com
+  go-cert-provider jwt create-token --user-id dashboard \
+    --scopes "list:*.example.com,retrieve:api.example.com"`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		options, ok := cmd.Context().Value(KeyForOptions).(*createJwtTokenOptions)
 		if !ok {
@@ -35,121 +92,391 @@ var createTokenCmd = &cobra.Command{
 			return fmt.Errorf("user-id is required")
 		}
 
-		if options.allowedDomains == "" {
-			return fmt.Errorf("allowed-domains is required")
+		if options.output != "text" && options.output != "json" {
+			return fmt.Errorf("unsupported output format: %s", options.output)
 		}
 
-		allowedDomainsList := strings.Split(options.allowedDomains, ",")
-		for i, domain := range allowedDomainsList {
-			allowedDomainsList[i] = strings.TrimSpace(domain)
+		if options.allowedDomains == "" && options.allowedDomainsFile == "" && len(options.scopes) == 0 {
+			return fmt.Errorf("allowed-domains, allowed-domains-file or scopes is required")
 		}
 
-		jwtSecretKey := options.jwtSecretKey
-		if jwtSecretKey == "" {
-			jwtSecretKey = os.Getenv("JWT_SECRET_KEY")
+		var allowedDomainsList []string
+		if options.allowedDomains != "" || options.allowedDomainsFile != "" {
+			var err error
+			allowedDomainsList, err = loadAllowedDomains(options.allowedDomains, options.allowedDomainsFile)
+			if err != nil {
+				return err
+			}
 		}
-		if jwtSecretKey == "" {
-			return fmt.Errorf("jwt secret key is required; use --jwt-secret-key flag or set JWT_SECRET_KEY environment variable")
+
+		scopes, err := normalizeScopes(options.scopes)
+		if err != nil {
+			return err
 		}
 
-		var expiresAt time.Time
+		// A private key file selects ES256; the algorithm then only needs to agree with it
+		var signingKey *ecdsa.PrivateKey
+		var jwtSecretKey string
+		algorithm := strings.ToUpper(options.algorithm)
+		if options.privateKeyFile != "" || algorithm == auth.AlgorithmES256 {
+			if cmd.Flags().Changed("jwt-algorithm") && algorithm != auth.AlgorithmES256 {
+				return fmt.Errorf("--private-key-file requires --jwt-algorithm %s, got %s", auth.AlgorithmES256, options.algorithm)
+			}
+			if options.privateKeyFile == "" {
+				return fmt.Errorf("--jwt-algorithm %s requires --private-key-file", auth.AlgorithmES256)
+			}
+			if options.withRefresh {
+				return fmt.Errorf("--with-refresh is not supported with %s: refresh tokens are signed with the JWT secret key", auth.AlgorithmES256)
+			}
+			signingKey, err = loadJWTPrivateKey(options.privateKeyFile)
+			if err != nil {
+				return err
+			}
+			algorithm = auth.AlgorithmES256
+		} else {
+			jwtSecretKey = options.jwtSecretKey
+			if jwtSecretKey == "" {
+				jwtSecretKey = os.Getenv("JWT_SECRET_KEY")
+			}
+			if jwtSecretKey == "" {
+				return fmt.Errorf("jwt secret key is required; use --jwt-secret-key flag or set JWT_SECRET_KEY environment variable")
+			}
+			if err := checkJWTSecretKeyStrength(cmd, jwtSecretKey, options.strictSecret); err != nil {
+				return err
+			}
+		}
+
+		var signingMethod *jwt.SigningMethodHMAC
+		if signingKey == nil {
+			signingMethod, err = auth.ParseSigningMethod(options.algorithm)
+			if err != nil {
+				return err
+			}
+			algorithm = signingMethod.Alg()
+		}
+
+		expiresAt := time.Now().Add(365 * 24 * time.Hour)
+		if options.withRefresh {
+			// Access tokens are short-lived when a refresh token can renew them
+			expiresAt = time.Now().Add(auth.DefaultAccessTokenTTL)
+		}
 		if options.expiresAt != "" {
-			var err error
+			expiresAt, err = parseExpiresAt(options.expiresAt)
+			if err != nil {
+				return fmt.Errorf("invalid expires-at format, use duration (e.g., '2y', '3months', '5d') or date/time format (YYYY-MM-DD HH:mm:ss, YYYY-MM-DD)")
+			}
+		}
 
-			// Try parsing as duration first (e.g., "2y", "3months", "5d")
-			if duration, durationErr := utils.ParseDurationString(options.expiresAt); durationErr == nil {
-				expiresAt = time.Now().Add(duration)
-			} else {
-				// Try parsing as date/time formats
-				formats := []string{
-					utils.DateTimeFormat,
-					time.RFC3339,
-					"2006-01-02T15:04:05",
-					"2006-01-02",
-				}
+		var notBefore time.Time
+		if options.notBefore != "" {
+			notBefore, err = parseNotBefore(options.notBefore)
+			if err != nil {
+				return fmt.Errorf("invalid not-before format, use duration (e.g., '1d', '2w') or date/time format (YYYY-MM-DD HH:mm:ss, YYYY-MM-DD)")
+			}
+		}
 
-				for _, format := range formats {
-					switch format {
-					case utils.DateTimeFormat:
-						expiresAt, err = utils.ParseDateTime(options.expiresAt)
-					case "2006-01-02":
-						// For date-only format, set time to 23:59:59
-						dateOnly, parseErr := time.ParseInLocation(format, options.expiresAt, time.Local)
-						if parseErr == nil {
-							expiresAt = time.Date(dateOnly.Year(), dateOnly.Month(), dateOnly.Day(), 23, 59, 59, 0, time.Local)
-							err = nil
-						} else {
-							err = parseErr
-						}
-					default:
-						expiresAt, err = time.ParseInLocation(format, options.expiresAt, time.Local)
-					}
-					if err == nil {
-						break
-					}
-				}
+		claims := auth.NewAccessClaims(options.userID, options.description, expiresAt, allowedDomainsList)
+		claims.Scopes = scopes
+		if err := claims.SetNotBefore(notBefore); err != nil {
+			return err
+		}
+		if options.audience != "" {
+			claims.Audience = jwt.ClaimStrings{options.audience}
+		}
+		claims.Issuer = resolveIssuer(options.issuer)
+		issuedAt := claims.IssuedAt.Time
+
+		var tokenString string
+		if signingKey != nil {
+			tokenString, err = auth.SignJWTWithECDSA(claims, signingKey)
+		} else {
+			tokenString, err = auth.SignJWTWithMethod(claims, jwtSecretKey, signingMethod)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create JWT token: %w", err)
+		}
 
+		var refreshToken string
+		var refreshExpiresAt time.Time
+		if options.withRefresh {
+			refreshExpiresAt = time.Now().Add(auth.DefaultRefreshTokenTTL)
+			if options.refreshExpiresAt != "" {
+				refreshExpiresAt, err = parseExpiresAt(options.refreshExpiresAt)
 				if err != nil {
-					return fmt.Errorf("invalid expires-at format, use duration (e.g., '2y', '3months', '5d') or date/time format (YYYY-MM-DD HH:mm:ss, YYYY-MM-DD)")
+					return fmt.Errorf("invalid refresh-expires-at format, use duration (e.g., '30d', '3months') or date/time format (YYYY-MM-DD HH:mm:ss, YYYY-MM-DD)")
 				}
 			}
-		} else {
-			expiresAt = time.Now().Add(365 * 24 * time.Hour)
+
+			refreshToken, err = auth.SignJWTWithMethod(auth.NewRefreshClaims(claims, refreshExpiresAt), jwtSecretKey, signingMethod)
+			if err != nil {
+				return fmt.Errorf("failed to create refresh token: %w", err)
+			}
 		}
 
-		issuedAt := time.Now()
+		if options.outputFile != "" {
+			if err := writeTokenFile(options.outputFile, tokenString); err != nil {
+				return err
+			}
+		}
 
-		claims := jwt.MapClaims{
-			"user_id":         options.userID,
-			"description":     options.description,
-			"allowed_domains": allowedDomainsList,
-			"exp":             expiresAt.Unix(),
-			"iat":             issuedAt.Unix(),
-			"nbf":             issuedAt.Unix(),
-			"iss":             "go-cert-provider",
-			"sub":             options.userID,
+		out := cmd.OutOrStdout()
+		if options.quiet {
+			if options.outputFile == "" {
+				fmt.Fprintln(out, tokenString)
+			}
+			if refreshToken != "" {
+				fmt.Fprintln(out, refreshToken)
+			}
+			return nil
 		}
 
-		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-		tokenString, err := token.SignedString([]byte(jwtSecretKey))
-		if err != nil {
-			return fmt.Errorf("failed to create JWT token: %w", err)
+		if options.output == "json" {
+			payload := createTokenJSON{
+				Algorithm:    algorithm,
+				RefreshToken: refreshToken,
+				TokenReport:  auth.NewTokenReport(claims, time.Now()),
+			}
+			if options.outputFile != "" {
+				payload.TokenFile = options.outputFile
+			} else {
+				payload.Token = tokenString
+			}
+			if refreshToken != "" {
+				refreshExpiresAt = refreshExpiresAt.UTC().Truncate(time.Second)
+				payload.RefreshExpiresAt = &refreshExpiresAt
+			}
+
+			encoder := json.NewEncoder(out)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(payload)
 		}
 
-		greenStyle := lipgloss.NewStyle().
+		render := styleRenderer(cmd, lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("10"))
-
-		fmt.Printf("JWT Token created successfully:\n\n")
-		fmt.Printf("Token:\n")
-		fmt.Println(greenStyle.Render(tokenString))
-		fmt.Printf("\nClaims:\n")
-		fmt.Printf("  User ID: %s\n", options.userID)
-		fmt.Printf("  Description: %s\n", options.description)
-		fmt.Printf("  Allowed Domains: %s\n", strings.Join(allowedDomainsList, ", "))
-		fmt.Printf("  Expires At: %s\n", utils.FormatDateTime(expiresAt))
-		fmt.Printf("  Issued At: %s\n", utils.FormatDateTime(issuedAt))
+			Foreground(lipgloss.Color("10")))
+
+		fmt.Fprintf(out, "JWT Token created successfully:\n\n")
+		if options.outputFile != "" {
+			fmt.Fprintf(out, "Token written to %s\n", options.outputFile)
+		} else {
+			fmt.Fprintf(out, "Token:\n")
+			fmt.Fprintln(out, render(tokenString))
+		}
+		fmt.Fprintf(out, "\nClaims:\n")
+		fmt.Fprintf(out, "  User ID: %s\n", options.userID)
+		fmt.Fprintf(out, "  Description: %s\n", options.description)
+		fmt.Fprintf(out, "  Allowed Domains: %s\n", strings.Join(allowedDomainsList, ", "))
+		if len(scopes) > 0 {
+			fmt.Fprintf(out, "  Scopes: %s\n", strings.Join(scopes, ", "))
+		}
+		if options.audience != "" {
+			fmt.Fprintf(out, "  Audience: %s\n", options.audience)
+		}
+		fmt.Fprintf(out, "  Issuer: %s\n", claims.Issuer)
+		fmt.Fprintf(out, "  Expires At: %s\n", utils.FormatDateTime(expiresAt))
+		fmt.Fprintf(out, "  Issued At: %s\n", utils.FormatDateTime(issuedAt))
+		if !notBefore.IsZero() {
+			fmt.Fprintf(out, "  Not Before: %s\n", utils.FormatDateTime(notBefore))
+		}
+		fmt.Fprintf(out, "  Algorithm: %s\n", algorithm)
+
+		if refreshToken == "" {
+			return nil
+		}
+
+		fmt.Fprintf(out, "\nRefresh Token:\n")
+		fmt.Fprintln(out, render(refreshToken))
+		fmt.Fprintf(out, "  Refresh Expires At: %s\n", utils.FormatDateTime(refreshExpiresAt))
+		fmt.Fprintf(out, "\nExchange the refresh token for a new access token with the refreshToken GraphQL mutation.\n")
 
 		return nil
 	},
 }
 
+// loadAllowedDomains merges the --allowed-domains value with the entries of the
+// --allowed-domains-file, lowercasing and validating each entry and removing duplicates
+func loadAllowedDomains(allowedDomains, allowedDomainsFile string) ([]string, error) {
+	entries := splitDomainList(allowedDomains)
+
+	if allowedDomainsFile != "" {
+		data, err := os.ReadFile(allowedDomainsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read allowed-domains-file: %w", err)
+		}
+
+		fileEntries := splitDomainList(string(data))
+		if len(fileEntries) == 0 {
+			return nil, fmt.Errorf("allowed-domains-file %s contains no domains", allowedDomainsFile)
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	seen := make(map[string]bool, len(entries))
+	domains := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if err := validateAllowedDomain(entry); err != nil {
+			return nil, err
+		}
+		if seen[entry] {
+			continue
+		}
+		seen[entry] = true
+		domains = append(domains, entry)
+	}
+
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("no valid allowed domains specified")
+	}
+
+	return domains, nil
+}
+
+// normalizeScopes validates scopes such as "list:*.example.com", lowercasing their domains
+// and removing duplicates
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		action, pattern, err := auth.ParseScope(strings.TrimSpace(scope))
+		if err != nil {
+			return nil, err
+		}
+		pattern = strings.ToLower(pattern)
+		if err := validateAllowedDomain(pattern); err != nil {
+			return nil, fmt.Errorf("invalid scope %q: %w", scope, err)
+		}
+
+		scope = action + ":" + pattern
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
+
+// splitDomainList splits a comma- or newline-separated list, dropping blank entries
+// and lines starting with '#'
+func splitDomainList(value string) []string {
+	var entries []string
+
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		for _, part := range strings.Split(line, ",") {
+			if entry := strings.ToLower(strings.TrimSpace(part)); entry != "" {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	return entries
+}
+
+// validateAllowedDomain checks that an entry is "*", a domain name, or a "*." wildcard
+func validateAllowedDomain(entry string) error {
+	if entry == "*" {
+		return nil
+	}
+
+	name := strings.TrimPrefix(entry, "*.")
+	if name == "" || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") || strings.Contains(name, "..") {
+		return fmt.Errorf("invalid allowed domain: %q", entry)
+	}
+
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return fmt.Errorf("invalid allowed domain: %q", entry)
+		}
+	}
+
+	return nil
+}
+
+// parseNotBefore parses a --not-before value like parseExpiresAt, except that a bare
+// date means the start of that day
+func parseNotBefore(value string) (time.Time, error) {
+	if date, err := time.ParseInLocation("2006-01-02", value, utils.DisplayLocation()); err == nil {
+		return date, nil
+	}
+	return parseExpiresAt(value)
+}
+
+// parseExpiresAt parses an expiry given as a duration (e.g., "2y", "3months", "5d")
+// or as a date/time (YYYY-MM-DD HH:mm:ss, RFC3339, YYYY-MM-DD); a bare date means the
+// end of that day
+func parseExpiresAt(value string) (time.Time, error) {
+	// Try parsing as duration first (e.g., "2y", "3months", "5d")
+	if duration, err := utils.ParseDurationString(value); err == nil {
+		return time.Now().Add(duration), nil
+	}
+
+	// Try parsing as date/time formats
+	formats := []string{
+		utils.DateTimeFormat,
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02",
+	}
+
+	// Dates are read in the display timezone, so --utc makes them UTC
+	location := utils.DisplayLocation()
+
+	var err error
+	for _, format := range formats {
+		var expiresAt time.Time
+		switch format {
+		case utils.DateTimeFormat:
+			expiresAt, err = utils.ParseDateTime(value)
+		case "2006-01-02":
+			// For date-only format, set time to 23:59:59
+			var dateOnly time.Time
+			dateOnly, err = time.ParseInLocation(format, value, location)
+			if err == nil {
+				expiresAt = time.Date(dateOnly.Year(), dateOnly.Month(), dateOnly.Day(), 23, 59, 59, 0, location)
+			}
+		default:
+			expiresAt, err = time.ParseInLocation(format, value, location)
+		}
+		if err == nil {
+			return expiresAt, nil
+		}
+	}
+
+	return time.Time{}, err
+}
+
 func init() {
 	opts := &createJwtTokenOptions{}
 
 	flags := createTokenCmd.Flags()
 	flags.StringVar(&opts.userID, "user-id", "", "User ID (required)")
 	flags.StringVar(&opts.description, "description", "", "Token description")
-	flags.StringVar(&opts.allowedDomains, "allowed-domains", "", "Comma-separated list of allowed domains (required)")
+	flags.StringVar(&opts.allowedDomains, "allowed-domains", "", "Comma-separated list of allowed domains, lowercased (required unless --allowed-domains-file is set)")
+	flags.StringVar(&opts.allowedDomainsFile, "allowed-domains-file", "", "File with newline- or comma-separated allowed domains (# starts a comment line), lowercased and merged with --allowed-domains")
+	flags.StringSliceVar(&opts.scopes, "scopes", nil, "Comma-separated scopes (list:<domain>, retrieve:<domain>); when set, they decide access instead of --allowed-domains")
 	flags.StringVar(&opts.expiresAt, "expires-at", "", "Token expiration time: duration (2y, 3months, 5d) or date (YYYY-MM-DD HH:mm:ss, YYYY-MM-DD) (default: 1 year)")
+	flags.StringVar(&opts.notBefore, "not-before", "", "Time the token becomes valid: duration from now (1d, 2w) or date (YYYY-MM-DD HH:mm:ss, YYYY-MM-DD; a bare date means its start) (default: now)")
 	flags.StringVar(&opts.jwtSecretKey, "jwt-secret-key", "", "JWT secret key (overrides JWT_SECRET_KEY env var)")
+	flags.StringVar(&opts.algorithm, "jwt-algorithm", "HS256", "Signing algorithm (HS256, HS384, HS512 with the secret key; ES256 with --private-key-file)")
+	flags.StringVar(&opts.privateKeyFile, "private-key-file", "", "PEM EC P-256 private key to sign with ES256 instead of the secret key")
+	flags.StringVar(&opts.audience, "audience", "", "Audience (aud claim) the token is intended for (optional)")
+	flags.StringVar(&opts.issuer, "issuer", "", "Issuer (iss claim), e.g. per environment (overrides JWT_ISSUER or ISSUER env var; default \"go-cert-provider\")")
+	flags.BoolVar(&opts.strictSecret, "strict-secret", false, "Refuse to sign when the JWT secret key is shorter than 32 bytes")
+	flags.BoolVar(&opts.withRefresh, "with-refresh", false, "Also issue a refresh token; the access token then defaults to 1 hour")
+	flags.StringVar(&opts.refreshExpiresAt, "refresh-expires-at", "", "Refresh token expiration time: duration or date, same formats as --expires-at (default: 30 days)")
+	flags.StringVar(&opts.outputFile, "output-file", "", "Write the raw access token to this file with 0600 permissions")
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Print only the raw token(s), without claims or styling")
+	flags.StringVar(&opts.output, "output", "text", "Output format (text, json)")
 
 	if err := createTokenCmd.MarkFlagRequired("user-id"); err != nil {
 		panic(err)
 	}
-	if err := createTokenCmd.MarkFlagRequired("allowed-domains"); err != nil {
-		panic(err)
-	}
+	createTokenCmd.MarkFlagsOneRequired("allowed-domains", "allowed-domains-file", "scopes")
+	createTokenCmd.MarkFlagsMutuallyExclusive("quiet", "output")
 
 	ctx := context.WithValue(context.Background(), KeyForOptions, opts)
 	createTokenCmd.SetContext(ctx)
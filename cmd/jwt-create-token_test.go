@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/auth"
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/spf13/cobra"
+)
+
+// runCreateToken runs jwt create-token with the options, returning stdout and the error
+func runCreateToken(t *testing.T, options *createJwtTokenOptions) (string, error) {
+	t.Helper()
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.WithValue(context.Background(), KeyForOptions, options))
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&bytes.Buffer{})
+
+	err := createTokenCmd.RunE(cmd, nil)
+	return stdout.String(), err
+}
+
+func TestLoadAllowedDomains(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	newlineFile := writeFile("newline.txt", "example.com\n*.Example.com\n\n  test.com  \n")
+	commaFile := writeFile("comma.txt", "example.com, test.com,,other.com\n")
+	commentedFile := writeFile("commented.txt", "# production\nexample.com\n\n   # staging\nstaging.example.com\n")
+	trailingCommentFile := writeFile("trailing.txt", "example.com # production\n")
+	commentsOnlyFile := writeFile("empty.txt", "# nothing here\n\n")
+	invalidFile := writeFile("invalid.txt", "example.com\nexample..com\n")
+
+	tests := []struct {
+		name    string
+		flag    string
+		file    string
+		want    []string
+		wantErr string
+	}{
+		{"flag only", "example.com,Test.COM", "", []string{"example.com", "test.com"}, ""},
+		{"newline-separated file", "", newlineFile, []string{"example.com", "*.example.com", "test.com"}, ""},
+		{"comma-separated file", "", commaFile, []string{"example.com", "test.com", "other.com"}, ""},
+		{"comments and blank lines", "", commentedFile, []string{"example.com", "staging.example.com"}, ""},
+		{"only whole lines are comments", "", trailingCommentFile, nil, `invalid allowed domain: "example.com # production"`},
+		{"flag and file merged without duplicates", "api.example.com,EXAMPLE.com", newlineFile,
+			[]string{"api.example.com", "example.com", "*.example.com", "test.com"}, ""},
+		{"file without domains", "example.com", commentsOnlyFile, nil, "contains no domains"},
+		{"missing file", "", filepath.Join(dir, "missing.txt"), nil, "failed to read allowed-domains-file"},
+		{"invalid entry in file", "", invalidFile, nil, `invalid allowed domain: "example..com"`},
+		{"nothing given", " , ", "", nil, "no valid allowed domains specified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadAllowedDomains(tt.flag, tt.file)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadAllowedDomains failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Allowed domains = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNotBefore(t *testing.T) {
+	utils.SetUTC(true)
+	defer utils.SetUTC(false)
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		// A bare date means the start of the day, where --expires-at means its end
+		{"2026-12-01", time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{"2026-12-01 08:30:00", time.Date(2026, 12, 1, 8, 30, 0, 0, time.UTC)},
+		{"2026-12-01T08:30:00Z", time.Date(2026, 12, 1, 8, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseNotBefore(tt.value)
+		if err != nil {
+			t.Errorf("parseNotBefore(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseNotBefore(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	expiresAt, err := parseExpiresAt("2026-12-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 12, 1, 23, 59, 59, 0, time.UTC); !expiresAt.Equal(want) {
+		t.Errorf("parseExpiresAt(2026-12-01) = %v, want the end of the day", expiresAt)
+	}
+
+	before := time.Now()
+	got, err := parseNotBefore("1d")
+	if err != nil || got.Before(before.Add(24*time.Hour)) || got.After(time.Now().Add(24*time.Hour)) {
+		t.Errorf("parseNotBefore(1d) = %v (error %v), want a day from now", got, err)
+	}
+
+	if _, err := parseNotBefore("next week"); err == nil {
+		t.Error("Expected an error for an invalid value")
+	}
+}
+
+func TestNotBeforeAfterExpiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		notBefore string
+		expiresAt string
+		wantErr   bool
+	}{
+		{"same day", "2026-12-01", "2026-12-01", false},
+		{"before expiry", "2026-12-01", "2026-12-31", false},
+		{"after expiry", "2026-12-31", "2026-12-01", true},
+		{"at expiry", "2026-12-01 23:59:59", "2026-12-01", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notBefore, err := parseNotBefore(tt.notBefore)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expiresAt, err := parseExpiresAt(tt.expiresAt)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			claims := auth.NewAccessClaims("user", "", expiresAt, []string{"example.com"})
+			err = claims.SetNotBefore(notBefore)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "must be earlier than expires-at") {
+					t.Errorf("Expected not-before to be rejected, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetNotBefore failed: %v", err)
+			}
+			if !claims.NotBefore.Time.Equal(notBefore) {
+				t.Errorf("nbf = %v, want %v", claims.NotBefore.Time, notBefore)
+			}
+		})
+	}
+}
+
+func TestWriteTokenFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("new file", func(t *testing.T) {
+		path := filepath.Join(dir, "new.jwt")
+		if err := writeTokenFile(path, "token"); err != nil {
+			t.Fatalf("writeTokenFile failed: %v", err)
+		}
+		assertTokenFile(t, path, "token\n")
+	})
+
+	t.Run("existing file is tightened and truncated", func(t *testing.T) {
+		path := filepath.Join(dir, "existing.jwt")
+		if err := os.WriteFile(path, []byte("a much longer previous token\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeTokenFile(path, "token"); err != nil {
+			t.Fatalf("writeTokenFile failed: %v", err)
+		}
+		assertTokenFile(t, path, "token\n")
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		if err := writeTokenFile(filepath.Join(dir, "missing", "token.jwt"), "token"); err == nil || !strings.Contains(err.Error(), "failed to open output file") {
+			t.Errorf("Expected an open error, got %v", err)
+		}
+	})
+}
+
+// assertTokenFile checks that the file at path has the content and is readable only by its owner
+func assertTokenFile(t *testing.T, path, want string) {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Token file not written: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Token file mode = %v, want 0600", info.Mode().Perm())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != want {
+		t.Errorf("Token file = %q, want %q", data, want)
+	}
+}
+
+func TestCreateTokenOutputFile(t *testing.T) {
+	t.Setenv("JWT_ISSUER", "")
+
+	newOptions := func(outputFile string, quiet bool) *createJwtTokenOptions {
+		return &createJwtTokenOptions{
+			userID:         "ci",
+			allowedDomains: "example.com",
+			jwtSecretKey:   testSecretKey,
+			algorithm:      "HS256",
+			output:         "text",
+			outputFile:     outputFile,
+			quiet:          quiet,
+		}
+	}
+
+	t.Run("quiet prints the token", func(t *testing.T) {
+		stdout, err := runCreateToken(t, newOptions("", true))
+		if err != nil {
+			t.Fatalf("create-token failed: %v", err)
+		}
+		if _, err := auth.ParseJWT(strings.TrimSpace(stdout), testSecretKey); err != nil {
+			t.Errorf("Expected only the raw token on stdout, got %q: %v", stdout, err)
+		}
+	})
+
+	t.Run("quiet with an output file prints nothing", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token.jwt")
+		stdout, err := runCreateToken(t, newOptions(path, true))
+		if err != nil {
+			t.Fatalf("create-token failed: %v", err)
+		}
+		if stdout != "" {
+			t.Errorf("Expected no output with --quiet and --output-file, got %q", stdout)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Token file not written: %v", err)
+		}
+		if _, err := auth.ParseJWT(strings.TrimSpace(string(data)), testSecretKey); err != nil {
+			t.Errorf("Token file does not hold a valid token: %v", err)
+		}
+	})
+
+	t.Run("output file keeps the token off stdout", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token.jwt")
+		stdout, err := runCreateToken(t, newOptions(path, false))
+		if err != nil {
+			t.Fatalf("create-token failed: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Token file not written: %v", err)
+		}
+		if !strings.Contains(stdout, "Token written to "+path) || strings.Contains(stdout, strings.TrimSpace(string(data))) {
+			t.Errorf("Expected the file name but not the token on stdout:\n%s", stdout)
+		}
+	})
+}
+
+func TestResolveIssuer(t *testing.T) {
+	tests := []struct {
+		name      string
+		flag      string
+		jwtIssuer string
+		issuer    string
+		want      string
+	}{
+		{"default", "", "", "", auth.DefaultIssuer},
+		{"ISSUER", "", "", "from-issuer", "from-issuer"},
+		{"JWT_ISSUER wins over ISSUER", "", "from-jwt-issuer", "from-issuer", "from-jwt-issuer"},
+		{"flag wins over the environment", "from-flag", "from-jwt-issuer", "from-issuer", "from-flag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JWT_ISSUER", tt.jwtIssuer)
+			t.Setenv("ISSUER", tt.issuer)
+			if got := resolveIssuer(tt.flag); got != tt.want {
+				t.Errorf("resolveIssuer(%q) = %q, want %q", tt.flag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateTokenIssuer(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "")
+	t.Setenv("JWT_ISSUER", "")
+	t.Setenv("ISSUER", "")
+	t.Setenv("JWT_EXPECTED_AUDIENCE", "")
+	t.Setenv("JWT_EXPECTED_ISSUER", "")
+
+	mint := func(t *testing.T, issuer string) string {
+		t.Helper()
+		stdout, err := runCreateToken(t, &createJwtTokenOptions{
+			userID:         "ci",
+			allowedDomains: "example.com",
+			jwtSecretKey:   testSecretKey,
+			algorithm:      "HS256",
+			output:         "text",
+			issuer:         issuer,
+			quiet:          true,
+		})
+		if err != nil {
+			t.Fatalf("create-token failed: %v", err)
+		}
+		return strings.TrimSpace(stdout)
+	}
+
+	staging := mint(t, "go-cert-provider-staging")
+	claims, err := auth.ParseJWT(staging, testSecretKey)
+	if err != nil {
+		t.Fatalf("Minted token does not verify: %v", err)
+	}
+	if claims.Issuer != "go-cert-provider-staging" {
+		t.Errorf("iss = %q, want the --issuer value", claims.Issuer)
+	}
+
+	t.Setenv("JWT_ISSUER", "go-cert-provider-prod")
+	if claims, err := auth.ParseJWT(mint(t, ""), testSecretKey); err != nil || claims.Issuer != "go-cert-provider-prod" {
+		t.Errorf("Expected iss from JWT_ISSUER, got %+v (error %v)", claims, err)
+	}
+
+	// A server expecting the production issuer rejects the staging token, even with the same secret
+	check := func(expectedIssuer string) error {
+		_, err := runAuthzCheck(t, &authzCheckOptions{
+			token:          staging,
+			domain:         "example.com",
+			action:         auth.ScopeRetrieve,
+			jwtSecretKey:   testSecretKey,
+			expectedIssuer: expectedIssuer,
+		})
+		return err
+	}
+	if err := check("go-cert-provider-prod"); err == nil {
+		t.Error("Expected the staging token to be rejected by --expected-issuer go-cert-provider-prod")
+	}
+	if err := check("go-cert-provider-staging"); err != nil {
+		t.Errorf("Expected the staging token to be accepted by its own issuer, got %v", err)
+	}
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/auth"
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/cobra"
+)
+
+type createJwtTokensOptions struct {
+	manifest     string
+	outputFile   string
+	jwtSecretKey string
+	algorithm    string
+	audience     string
+	issuer       string
+	strictSecret bool
+}
+
+// tokenManifestEntry is one row of a create-tokens manifest
+type tokenManifestEntry struct {
+	UserID         string   `json:"userID"`
+	Description    string   `json:"description"`
+	AllowedDomains []string `json:"allowedDomains"`
+	ExpiresAt      string   `json:"expiresAt"`
+}
+
+// tokenManifestResult is the outcome of minting the token for one manifest row
+type tokenManifestResult struct {
+	Row       int        `json:"row"`
+	UserID    string     `json:"userID"`
+	Token     string     `json:"token,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+var createTokensCmd = &cobra.Command{
+	Use:   "create-tokens",
+	Short: "Create JWT tokens for every user in a manifest",
+	Long: `Create one JWT token per entry of a JSON or CSV manifest.
+
+A JSON manifest is an array of objects:
+
+  [
+    {"userID": "alice", "description": "Alice", "allowedDomains": ["example.com"], "expiresAt": "90d"},
+    {"userID": "ci", "allowedDomains": ["*.example.com"], "expiresAt": "2026-12-31"}
+  ]
+
+A CSV manifest (.csv extension) has the header userID,description,allowedDomains,expiresAt,
+with allowed domains separated by semicolons.
+
+expiresAt accepts the same durations and dates as create-token and defaults to 1 year.
+Invalid rows are reported and skipped; the other tokens are still created, and the
+command exits with an error if any row failed.
+
+Examples:
+  # Print a table of users and tokens
+  go-cert-provider jwt create-tokens --manifest users.json
+
+  # Write the tokens as JSON to a 0600 file instead
+  go-cert-provider jwt create-tokens --manifest users.csv --output-file tokens.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		options, ok := cmd.Context().Value(KeyForOptions).(*createJwtTokensOptions)
+		if !ok {
+			return fmt.Errorf("failed to get command options from context")
+		}
+
+		jwtSecretKey := options.jwtSecretKey
+		if jwtSecretKey == "" {
+			jwtSecretKey = os.Getenv("JWT_SECRET_KEY")
+		}
+		if jwtSecretKey == "" {
+			return fmt.Errorf("jwt secret key is required; use --jwt-secret-key flag or set JWT_SECRET_KEY environment variable")
+		}
+		if err := checkJWTSecretKeyStrength(cmd, jwtSecretKey, options.strictSecret); err != nil {
+			return err
+		}
+
+		signingMethod, err := auth.ParseSigningMethod(options.algorithm)
+		if err != nil {
+			return err
+		}
+
+		entries, err := loadTokenManifest(options.manifest)
+		if err != nil {
+			return err
+		}
+
+		issuer := resolveIssuer(options.issuer)
+		results := make([]tokenManifestResult, 0, len(entries))
+		failed := 0
+		for i, entry := range entries {
+			result := tokenManifestResult{Row: i + 1, UserID: entry.UserID}
+
+			claims, err := manifestEntryClaims(entry)
+			if err == nil {
+				if options.audience != "" {
+					claims.Audience = jwt.ClaimStrings{options.audience}
+				}
+				claims.Issuer = issuer
+				result.Token, err = auth.SignJWTWithMethod(claims, jwtSecretKey, signingMethod)
+			}
+			if err != nil {
+				failed++
+				result.Error = err.Error()
+				fmt.Fprintf(cmd.ErrOrStderr(), "Row %d (%s): %v\n", result.Row, entry.UserID, err)
+			} else {
+				expiresAt := claims.ExpiresAt.Time.UTC()
+				result.ExpiresAt = &expiresAt
+			}
+
+			results = append(results, result)
+		}
+
+		if options.outputFile != "" {
+			data, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode results: %w", err)
+			}
+			if err := writeTokenFile(options.outputFile, string(data)); err != nil {
+				return err
+			}
+			fmt.Fprintf(infoOut(cmd), "Tokens written to %s\n", options.outputFile)
+		}
+
+		printTokenManifestResults(cmd.OutOrStdout(), results, options.outputFile == "")
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d manifest rows failed", failed, len(entries))
+		}
+		return nil
+	},
+}
+
+// loadTokenManifest reads manifest entries from a JSON file, or a CSV file by extension
+func loadTokenManifest(path string) ([]tokenManifestEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var entries []tokenManifestEntry
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		entries, err = parseTokenManifestCSV(string(data))
+	} else if err = json.Unmarshal(data, &entries); err != nil {
+		err = fmt.Errorf("invalid JSON manifest: %w", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("manifest %s contains no entries", path)
+	}
+
+	return entries, nil
+}
+
+// parseTokenManifestCSV parses a CSV manifest with a userID,description,allowedDomains,expiresAt header
+func parseTokenManifestCSV(data string) ([]tokenManifestEntry, error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV manifest: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"userid", "alloweddomains"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("invalid CSV manifest: missing %q column", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	entries := make([]tokenManifestEntry, 0, len(records)-1)
+	for _, record := range records[1:] {
+		entries = append(entries, tokenManifestEntry{
+			UserID:         field(record, "userid"),
+			Description:    field(record, "description"),
+			AllowedDomains: strings.Split(field(record, "alloweddomains"), ";"),
+			ExpiresAt:      field(record, "expiresat"),
+		})
+	}
+
+	return entries, nil
+}
+
+// manifestEntryClaims validates a manifest row and builds its access token claims
+func manifestEntryClaims(entry tokenManifestEntry) (*auth.JWTClaims, error) {
+	if strings.TrimSpace(entry.UserID) == "" {
+		return nil, fmt.Errorf("userID is required")
+	}
+
+	allowedDomains, err := loadAllowedDomains(strings.Join(entry.AllowedDomains, ","), "")
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(365 * 24 * time.Hour)
+	if entry.ExpiresAt != "" {
+		expiresAt, err = parseExpiresAt(entry.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiresAt %q: use a duration (e.g., '2y', '3months', '5d') or date/time (YYYY-MM-DD HH:mm:ss, YYYY-MM-DD)", entry.ExpiresAt)
+		}
+	}
+	if !expiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expiresAt %q is in the past", entry.ExpiresAt)
+	}
+
+	return auth.NewAccessClaims(entry.UserID, entry.Description, expiresAt, allowedDomains), nil
+}
+
+// printTokenManifestResults prints a user → token table; tokens are omitted when written to a file
+func printTokenManifestResults(w io.Writer, results []tokenManifestResult, showTokens bool) {
+	maxUserLen := 7 // "USER ID"
+	for _, result := range results {
+		if len(result.UserID) > maxUserLen {
+			maxUserLen = len(result.UserID)
+		}
+	}
+
+	last := "STATUS"
+	if showTokens {
+		last = "TOKEN"
+	}
+
+	fmt.Fprintf(w, "%-4s  %-*s  %-19s  %s\n", "ROW", maxUserLen, "USER ID", "EXPIRES", last)
+	for _, result := range results {
+		expires := "-"
+		if result.ExpiresAt != nil {
+			expires = utils.FormatDateTime(result.ExpiresAt.Local())
+		}
+
+		value := "ok"
+		switch {
+		case result.Error != "":
+			value = "FAILED: " + result.Error
+		case showTokens:
+			value = result.Token
+		}
+
+		fmt.Fprintf(w, "%-4d  %-*s  %-19s  %s\n", result.Row, maxUserLen, result.UserID, expires, value)
+	}
+}
+
+func init() {
+	opts := &createJwtTokensOptions{}
+
+	flags := createTokensCmd.Flags()
+	flags.StringVar(&opts.manifest, "manifest", "", "JSON or CSV manifest listing userID, description, allowedDomains and expiresAt per token (required)")
+	flags.StringVar(&opts.outputFile, "output-file", "", "Write the results, including tokens, as JSON to this file with 0600 permissions")
+	flags.StringVar(&opts.jwtSecretKey, "jwt-secret-key", "", "JWT secret key (overrides JWT_SECRET_KEY env var)")
+	flags.StringVar(&opts.algorithm, "jwt-algorithm", "HS256", "HMAC signing algorithm (HS256, HS384, HS512)")
+	flags.StringVar(&opts.audience, "audience", "", "Audience (aud claim) set on every token (optional)")
+	flags.StringVar(&opts.issuer, "issuer", "", "Issuer (iss claim) set on every token (overrides JWT_ISSUER or ISSUER env var; default \"go-cert-provider\")")
+	flags.BoolVar(&opts.strictSecret, "strict-secret", false, "Refuse to sign when the JWT secret key is shorter than 32 bytes")
+
+	if err := createTokensCmd.MarkFlagRequired("manifest"); err != nil {
+		panic(err)
+	}
+
+	ctx := context.WithValue(context.Background(), KeyForOptions, opts)
+	createTokensCmd.SetContext(ctx)
+
+	jwtCmd.AddCommand(createTokensCmd)
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dh-kam/go-cert-provider/auth"
+	"github.com/spf13/cobra"
+)
+
+// runCreateTokens runs jwt create-tokens with the options, returning stdout, stderr and the error
+func runCreateTokens(t *testing.T, options *createJwtTokensOptions) (string, string, error) {
+	t.Helper()
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.WithValue(context.Background(), KeyForOptions, options))
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	err := createTokensCmd.RunE(cmd, nil)
+	return stdout.String(), stderr.String(), err
+}
+
+func TestCreateTokensFromManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	jsonManifest := writeManifest("users.json", `[
+  {"userID": "alice", "description": "Alice", "allowedDomains": ["Example.com"], "expiresAt": "90d"},
+  {"userID": "", "allowedDomains": ["example.com"]},
+  {"userID": "ci", "allowedDomains": ["*.example.com", "test.com"], "expiresAt": "2001-01-01"}
+]`)
+	csvManifest := writeManifest("users.csv", `userID,description,allowedDomains,expiresAt
+alice,Alice,example.com;test.com,30d
+bob,,,
+`)
+
+	t.Run("JSON manifest with invalid rows", func(t *testing.T) {
+		outputFile := filepath.Join(dir, "tokens.json")
+		stdout, stderr, err := runCreateTokens(t, &createJwtTokensOptions{
+			manifest:     jsonManifest,
+			outputFile:   outputFile,
+			jwtSecretKey: testSecretKey,
+			algorithm:    "HS256",
+		})
+		if err == nil || err.Error() != "2 of 3 manifest rows failed" {
+			t.Fatalf("Error = %v, want 2 of 3 rows failed", err)
+		}
+		for _, want := range []string{"Row 2 (): userID is required", "Row 3 (ci): expiresAt \"2001-01-01\" is in the past"} {
+			if !strings.Contains(stderr, want) {
+				t.Errorf("Stderr does not report %q:\n%s", want, stderr)
+			}
+		}
+
+		// With --output-file the table shows the status instead of the tokens
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if len(lines) != 4 || !strings.HasSuffix(lines[0], "STATUS") || !strings.HasSuffix(lines[1], "  ok") ||
+			!strings.Contains(lines[2], "FAILED: userID is required") {
+			t.Errorf("Unexpected table:\n%s", stdout)
+		}
+
+		info, err := os.Stat(outputFile)
+		if err != nil {
+			t.Fatalf("Output file not written: %v", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("Output file mode = %v, want 0600", info.Mode().Perm())
+		}
+		data, err := os.ReadFile(outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var results []tokenManifestResult
+		if err := json.Unmarshal(data, &results); err != nil {
+			t.Fatalf("Invalid output file: %v", err)
+		}
+		if len(results) != 3 || results[0].Token == "" || results[0].ExpiresAt == nil ||
+			results[1].Error == "" || results[1].Token != "" || results[2].Error == "" {
+			t.Fatalf("Unexpected results: %+v", results)
+		}
+
+		claims, err := auth.ParseJWT(results[0].Token, testSecretKey)
+		if err != nil {
+			t.Fatalf("Token of alice does not verify: %v", err)
+		}
+		if claims.UserID != "alice" || claims.Description != "Alice" || !reflect.DeepEqual(claims.AllowedDomains, []string{"example.com"}) {
+			t.Errorf("Unexpected claims for alice: %+v", claims)
+		}
+	})
+
+	t.Run("CSV manifest printed as a table", func(t *testing.T) {
+		stdout, _, err := runCreateTokens(t, &createJwtTokensOptions{
+			manifest:     csvManifest,
+			jwtSecretKey: testSecretKey,
+			algorithm:    "HS256",
+		})
+		if err == nil || err.Error() != "1 of 2 manifest rows failed" {
+			t.Fatalf("Error = %v, want 1 of 2 rows failed", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if len(lines) != 3 || !strings.HasSuffix(lines[0], "TOKEN") {
+			t.Fatalf("Unexpected table:\n%s", stdout)
+		}
+		fields := strings.Fields(lines[1])
+		claims, err := auth.ParseJWT(fields[len(fields)-1], testSecretKey)
+		if err != nil {
+			t.Fatalf("Printed token does not verify: %v", err)
+		}
+		if !reflect.DeepEqual(claims.AllowedDomains, []string{"example.com", "test.com"}) {
+			t.Errorf("Allowed domains = %v, want the semicolon-separated list", claims.AllowedDomains)
+		}
+		if !strings.Contains(lines[2], "bob") || !strings.Contains(lines[2], "FAILED:") {
+			t.Errorf("Expected bob's row to fail without allowed domains:\n%s", stdout)
+		}
+	})
+
+	t.Run("unusable manifests", func(t *testing.T) {
+		for name, manifest := range map[string]string{
+			"missing file":   filepath.Join(dir, "missing.json"),
+			"invalid JSON":   writeManifest("broken.json", `[{"userID": `),
+			"empty manifest": writeManifest("empty.json", `[]`),
+			"missing column": writeManifest("columns.csv", "userID,description\nalice,Alice\n"),
+		} {
+			stdout, _, err := runCreateTokens(t, &createJwtTokensOptions{manifest: manifest, jwtSecretKey: testSecretKey, algorithm: "HS256"})
+			if err == nil || stdout != "" {
+				t.Errorf("%s: expected an error and no output, got %v and %q", name, err, stdout)
+			}
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/auth"
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/spf13/cobra"
+)
+
+var inspectTokenCmd = &cobra.Command{
+	Use:   "inspect [token]",
+	Short: "Decode a JWT token without verifying it",
+	Long: `Decode a JWT token and display its header and claims without verifying the signature.
+
+No secret key is required. The output is UNVERIFIED: anyone can craft a token
+with arbitrary claims, so use verify-token to check whether a token is genuine.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		decoded, err := auth.DecodeJWT(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to decode token: %w", err)
+		}
+
+		claims := decoded.Claims
+
+		fmt.Printf("⚠️  UNVERIFIED: the signature of this token has NOT been checked\n\n")
+		fmt.Printf("Header:\n")
+		fmt.Printf("  Algorithm: %v\n", decoded.Header["alg"])
+		fmt.Printf("  Type: %v\n", decoded.Header["typ"])
+
+		fmt.Printf("\nClaims (unverified):\n")
+		fmt.Printf("  User ID: %s\n", claims.UserID)
+		fmt.Printf("  Description: %s\n", claims.Description)
+		fmt.Printf("  Allowed Domains: %s\n", strings.Join(claims.AllowedDomains, ", "))
+		if len(claims.Scopes) > 0 {
+			fmt.Printf("  Scopes: %s\n", strings.Join(claims.Scopes, ", "))
+		}
+		if len(claims.Audience) > 0 {
+			fmt.Printf("  Audience: %s\n", strings.Join(claims.Audience, ", "))
+		}
+
+		if claims.ExpiresAt != nil {
+			fmt.Printf("  Expires At: %s\n", utils.FormatDateTime(claims.ExpiresAt.Time))
+
+			if time.Now().After(claims.ExpiresAt.Time) {
+				fmt.Printf("  Status: ⚠️  EXPIRED\n")
+			} else {
+				timeLeft := time.Until(claims.ExpiresAt.Time)
+				fmt.Printf("  Status: not expired (expires in %s)\n", utils.FormatDuration(timeLeft))
+			}
+		} else {
+			fmt.Printf("  Expires At: -\n")
+		}
+
+		if claims.IssuedAt != nil {
+			fmt.Printf("  Issued At: %s\n", utils.FormatDateTime(claims.IssuedAt.Time))
+		}
+
+		if claims.NotBefore != nil {
+			fmt.Printf("  Not Before: %s\n", utils.FormatDateTime(claims.NotBefore.Time))
+		}
+
+		if claims.Issuer != "" {
+			fmt.Printf("  Issuer: %s\n", claims.Issuer)
+		}
+
+		if claims.Subject != "" {
+			fmt.Printf("  Subject: %s\n", claims.Subject)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	jwtCmd.AddCommand(inspectTokenCmd)
+}
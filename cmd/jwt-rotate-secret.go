@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dh-kam/go-cert-provider/auth"
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/spf13/cobra"
+)
+
+type rotateSecretOptions struct {
+	oldKey string
+	newKey string
+}
+
+var rotateSecretCmd = &cobra.Command{
+	Use:   "rotate-secret [token]",
+	Short: "Re-sign an existing JWT token with a new secret key",
+	Long: `Verify a JWT token under the old secret key and re-sign identical claims under the new key.
+
+The user ID, description, allowed domains, expiry and issue time are preserved,
+so outstanding tokens can be migrated during a key rollover window.
+Expired tokens are refused.
+
+Examples:
+  go-cert-provider jwt rotate-secret --old-key "old-key" --new-key "new-key" "your-jwt-token"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		options, ok := cmd.Context().Value(KeyForOptions).(*rotateSecretOptions)
+		if !ok {
+			return fmt.Errorf("failed to get command options from context")
+		}
+
+		oldKey := options.oldKey
+		if oldKey == "" {
+			oldKey = os.Getenv("JWT_SECRET_KEY")
+		}
+		if oldKey == "" {
+			return fmt.Errorf("old secret key is required; use --old-key flag or set JWT_SECRET_KEY environment variable")
+		}
+		if options.newKey == "" {
+			return fmt.Errorf("new-key is required")
+		}
+		if err := checkJWTSecretKeyStrength(cmd, options.newKey, false); err != nil {
+			return err
+		}
+
+		tokenString, claims, err := auth.ResignJWT(args[0], oldKey, options.newKey)
+		if err != nil {
+			return fmt.Errorf("failed to rotate token: %w", err)
+		}
+
+		render := styleRenderer(cmd, lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("10")))
+
+		fmt.Printf("JWT Token re-signed successfully:\n\n")
+		fmt.Printf("Token:\n")
+		fmt.Println(render(tokenString))
+		fmt.Printf("\nClaims:\n")
+		fmt.Printf("  User ID: %s\n", claims.UserID)
+		fmt.Printf("  Description: %s\n", claims.Description)
+		fmt.Printf("  Allowed Domains: %s\n", strings.Join(claims.AllowedDomains, ", "))
+		if claims.ExpiresAt != nil {
+			fmt.Printf("  Expires At: %s\n", utils.FormatDateTime(claims.ExpiresAt.Time))
+		}
+		if claims.IssuedAt != nil {
+			fmt.Printf("  Issued At: %s\n", utils.FormatDateTime(claims.IssuedAt.Time))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	opts := &rotateSecretOptions{}
+
+	flags := rotateSecretCmd.Flags()
+	flags.StringVar(&opts.oldKey, "old-key", "", "Secret key the token is currently signed with (default: JWT_SECRET_KEY env var)")
+	flags.StringVar(&opts.newKey, "new-key", "", "Secret key to re-sign the token with (required)")
+
+	if err := rotateSecretCmd.MarkFlagRequired("new-key"); err != nil {
+		panic(err)
+	}
+
+	ctx := context.WithValue(context.Background(), KeyForOptions, opts)
+	rotateSecretCmd.SetContext(ctx)
+
+	jwtCmd.AddCommand(rotateSecretCmd)
+}
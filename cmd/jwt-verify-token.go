@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -13,14 +14,28 @@ import (
 )
 
 type verifyJwtTokenOptions struct {
-	jwtSecretKey string
+	jwtSecretKey     string
+	publicKeyFile    string
+	expectedAudience string
+	checkDomains     bool
+	output           string
+}
+
+// verifyTokenJSON is the --output json payload of verify-token
+type verifyTokenJSON struct {
+	*auth.TokenReport
+	StaleDomains []string `json:"staleDomains,omitempty"`
 }
 
 var verifyTokenCmd = &cobra.Command{
 	Use:   "verify-token [token]",
 	Short: "Verify a JWT token",
-	Long:  "Verify a JWT token and display its claims",
-	Args:  cobra.ExactArgs(1),
+	Long: `Verify a JWT token and display its claims.
+
+Verification is offline and does not contact any provider. With --check-domains,
+the configured providers are initialized and each allowed domain in the token is
+checked against the managed domains; entries that match nothing are reported as stale.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		token := args[0]
 
@@ -34,7 +49,22 @@ var verifyTokenCmd = &cobra.Command{
 			jwtSecretKey = os.Getenv("JWT_SECRET_KEY")
 		}
 
-		claims, err := auth.ParseJWT(token, jwtSecretKey)
+		if options.output != "text" && options.output != "json" {
+			return fmt.Errorf("unsupported output format: %s", options.output)
+		}
+
+		publicKey, err := loadJWTPublicKey(options.publicKeyFile)
+		if err != nil {
+			return err
+		}
+
+		claims, err := auth.ParseJWTWithOptions(token, jwtSecretKey, auth.ValidationOptions{
+			ExpectedAudience: options.expectedAudience,
+			PublicKey:        publicKey,
+		})
+		if options.output == "json" {
+			return outputVerifyTokenJSON(cmd, options, claims, err)
+		}
 		if err != nil {
 			fmt.Printf("❌ Token verification failed: %v\n", err)
 			return nil
@@ -45,6 +75,12 @@ var verifyTokenCmd = &cobra.Command{
 		fmt.Printf("  User ID: %s\n", claims.UserID)
 		fmt.Printf("  Description: %s\n", claims.Description)
 		fmt.Printf("  Allowed Domains: %s\n", strings.Join(claims.AllowedDomains, ", "))
+		if len(claims.Scopes) > 0 {
+			fmt.Printf("  Scopes: %s\n", strings.Join(claims.Scopes, ", "))
+		}
+		if len(claims.Audience) > 0 {
+			fmt.Printf("  Audience: %s\n", strings.Join(claims.Audience, ", "))
+		}
 
 		if claims.ExpiresAt != nil {
 			fmt.Printf("  Expires At: %s\n", utils.FormatDateTime(claims.ExpiresAt.Time))
@@ -73,14 +109,92 @@ var verifyTokenCmd = &cobra.Command{
 			fmt.Printf("  Subject: %s\n", claims.Subject)
 		}
 
+		if options.checkDomains {
+			state, err := initializeProviderSystem(cmd)
+			if err != nil {
+				return fmt.Errorf("--check-domains requires configured providers: %w", err)
+			}
+
+			printAllowedDomainCheck(claims.AllowedDomains, state.providerRegistry.ListDomains())
+		}
+
 		return nil
 	},
 }
 
+// outputVerifyTokenJSON writes the verification result as a JSON object
+func outputVerifyTokenJSON(cmd *cobra.Command, options *verifyJwtTokenOptions, claims *auth.JWTClaims, verifyErr error) error {
+	payload := verifyTokenJSON{}
+	if verifyErr != nil {
+		payload.TokenReport = auth.NewInvalidTokenReport(verifyErr)
+	} else {
+		payload.TokenReport = auth.NewTokenReport(claims, time.Now())
+
+		if options.checkDomains {
+			state, err := initializeProviderSystem(cmd)
+			if err != nil {
+				return fmt.Errorf("--check-domains requires configured providers: %w", err)
+			}
+
+			managedDomains := state.providerRegistry.ListDomains()
+			payload.StaleDomains = []string{}
+			for _, entry := range claims.AllowedDomains {
+				if len(matchAllowedDomainEntry(entry, managedDomains)) == 0 {
+					payload.StaleDomains = append(payload.StaleDomains, entry)
+				}
+			}
+		}
+	}
+
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(payload)
+}
+
+// printAllowedDomainCheck reports, for each allowed-domain entry, which managed domains it covers.
+// Entries that cover no managed domain are marked stale.
+func printAllowedDomainCheck(allowedDomains, managedDomains []string) {
+	fmt.Printf("\nDomain Check (%d managed domain(s)):\n", len(managedDomains))
+
+	stale := 0
+	for _, entry := range allowedDomains {
+		matches := matchAllowedDomainEntry(entry, managedDomains)
+		switch {
+		case len(matches) == 0:
+			stale++
+			fmt.Printf("  ⚠️  %s: STALE (not managed by any configured provider)\n", entry)
+		case entry == "*" || strings.HasPrefix(entry, "*."):
+			fmt.Printf("  ✅ %s: matches %d domain(s)\n", entry, len(matches))
+		default:
+			fmt.Printf("  ✅ %s\n", entry)
+		}
+	}
+
+	if stale > 0 {
+		fmt.Printf("\n%d of %d allowed domain entries are stale\n", stale, len(allowedDomains))
+	}
+}
+
+// matchAllowedDomainEntry returns the managed domains an allowed-domain entry grants access to,
+// using auth.MatchDomain as certificate authorization does
+func matchAllowedDomainEntry(entry string, managedDomains []string) []string {
+	var matches []string
+	for _, managed := range managedDomains {
+		if auth.MatchDomain(entry, managed) {
+			matches = append(matches, managed)
+		}
+	}
+	return matches
+}
+
 func init() {
 	opts := &verifyJwtTokenOptions{}
 
 	verifyTokenCmd.Flags().StringVar(&opts.jwtSecretKey, "jwt-secret-key", "", "JWT secret key (overrides JWT_SECRET_KEY env var)")
+	verifyTokenCmd.Flags().StringVar(&opts.publicKeyFile, "public-key-file", "", "PEM EC P-256 public key to verify ES256 tokens with")
+	verifyTokenCmd.Flags().StringVar(&opts.expectedAudience, "expected-audience", "", "Require the aud claim to contain this value (optional)")
+	verifyTokenCmd.Flags().StringVar(&opts.output, "output", "text", "Output format (text, json)")
+	verifyTokenCmd.Flags().BoolVar(&opts.checkDomains, "check-domains", false, "Initialize the configured providers and report allowed domains that no longer exist")
 
 	ctx := context.WithValue(context.Background(), KeyForOptions, opts)
 	verifyTokenCmd.SetContext(ctx)
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchAllowedDomainEntry(t *testing.T) {
+	managed := []string{"example.com", "api.example.com", "test.com"}
+
+	tests := []struct {
+		entry string
+		want  []string
+	}{
+		{"*", managed},
+		{"example.com", []string{"example.com"}},
+		{"*.example.com", []string{"example.com", "api.example.com"}},
+		{"other.com", nil},
+		// The server matches case-sensitively, so these entries cover nothing
+		{"Example.COM", nil},
+		{"*.EXAMPLE.com", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			if got := matchAllowedDomainEntry(tt.entry, managed); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matchAllowedDomainEntry(%q) = %v, want %v", tt.entry, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,14 @@
 package cmd
 
 import (
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dh-kam/go-cert-provider/auth"
+	"github.com/dh-kam/go-cert-provider/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -17,3 +25,103 @@ and verifying token validity.`,
 func init() {
 	rootCmd.AddCommand(jwtCmd)
 }
+
+// checkJWTSecretKeyStrength warns about short secrets, or refuses them in strict mode
+func checkJWTSecretKeyStrength(cmd *cobra.Command, secret string, strict bool) error {
+	err := auth.ValidateSecretKeyStrength(secret)
+	if err == nil {
+		return nil
+	}
+
+	if strict {
+		return fmt.Errorf("%w; generate a strong key with: go-cert-provider jwt create-secret-key", err)
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+	fmt.Fprintln(cmd.ErrOrStderr(), "         generate a strong key with: go-cert-provider jwt create-secret-key")
+	return nil
+}
+
+// resolveIssuer returns the iss claim for new tokens: the --issuer value, else the
+// JWT_ISSUER env var, else the ISSUER env var, else auth.DefaultIssuer
+func resolveIssuer(issuer string) string {
+	if issuer == "" {
+		issuer = os.Getenv("JWT_ISSUER")
+	}
+	if issuer == "" {
+		issuer = os.Getenv("ISSUER")
+	}
+	if issuer == "" {
+		issuer = auth.DefaultIssuer
+	}
+	return issuer
+}
+
+// clockSkewOption converts a --jwt-clock-skew value to auth.ValidationOptions.ClockSkew,
+// where zero would mean the default leeway instead of none
+func clockSkewOption(clockSkew time.Duration) (time.Duration, error) {
+	switch {
+	case clockSkew < 0:
+		return 0, fmt.Errorf("--jwt-clock-skew must not be negative")
+	case clockSkew == 0:
+		return -1, nil
+	}
+	return clockSkew, nil
+}
+
+// loadJWTPrivateKey reads the EC P-256 key used to sign ES256 tokens
+func loadJWTPrivateKey(path string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key file: %w", err)
+	}
+	key, err := auth.ParseECPrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key file %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// loadJWTPublicKey reads the EC P-256 key used to verify ES256 tokens; it returns nil
+// when path is empty
+func loadJWTPublicKey(path string) (*ecdsa.PublicKey, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key file: %w", err)
+	}
+	key, err := auth.ParseECPublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key file %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// styleRenderer returns style.Render, or a plain renderer when --no-color or NO_COLOR is
+// set or stdout is not a terminal, so styled output is only emitted when a human reads it
+func styleRenderer(cmd *cobra.Command, style lipgloss.Style) func(...string) string {
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	return utils.Renderer(style, utils.ColorEnabled(noColor, os.Stdout))
+}
+
+// writeTokenFile writes a raw token to path, readable only by the current user
+func writeTokenFile(path, token string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	defer file.Close()
+
+	// OpenFile keeps the mode of an existing file, so tighten it explicitly
+	if err := file.Chmod(0600); err != nil {
+		return fmt.Errorf("failed to set output file permissions: %w", err)
+	}
+
+	if _, err := file.WriteString(token + "\n"); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	return file.Close()
+}
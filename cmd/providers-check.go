@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dh-kam/go-cert-provider/cert/registry"
+	"github.com/spf13/cobra"
+)
+
+// providersCheckCmd represents the providers check command
+var providersCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Validate provider configuration and credentials",
+	Long: `Create every configured provider, test its connectivity against the provider API
+(e.g. Porkbun ping) and report the number of discovered domains, then exit.
+
+Nothing is registered or served. The command exits with a non-zero status if any
+configured provider fails, so bad credentials are caught before "certs serve" starts.
+
+Examples:
+  # Check all configured providers
+  go-cert-provider providers check
+
+  # Output as JSON
+  go-cert-provider providers check --output json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		// Provider initialization is skipped in PersistentPreRunE for this command
+		// so that every provider can be checked and reported individually
+		_, bootstrapManager, err := initializeCertificateSystem(cmd)
+		if err != nil {
+			return err
+		}
+
+		checks := bootstrapManager.CheckProviders(cmd.Context())
+		if len(checks) == 0 {
+			return fmt.Errorf("no certificate providers configured")
+		}
+
+		switch outputFormat {
+		case "json":
+			err = outputProviderChecksJSON(cmd, checks)
+		case "table", "":
+			err = outputProviderChecksTable(cmd, checks)
+		default:
+			return fmt.Errorf("unsupported output format: %s", outputFormat)
+		}
+		if err != nil {
+			return err
+		}
+
+		failed := 0
+		for _, check := range checks {
+			if !check.OK() {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d configured provider(s) failed", failed, len(checks))
+		}
+
+		return nil
+	},
+}
+
+func outputProviderChecksTable(cmd *cobra.Command, checks []registry.ProviderCheck) error {
+	maxNameLen := 8 // "PROVIDER"
+	for _, check := range checks {
+		if len(check.Name) > maxNameLen {
+			maxNameLen = len(check.Name)
+		}
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%-*s  %-6s  %-7s  %s\n",
+		maxNameLen, "PROVIDER", "STATUS", "DOMAINS", "ERROR")
+	fmt.Fprintf(cmd.OutOrStdout(), "%s  %s  %s  %s\n",
+		strings.Repeat("-", maxNameLen),
+		strings.Repeat("-", 6),
+		strings.Repeat("-", 7),
+		strings.Repeat("-", 5))
+
+	for _, check := range checks {
+		status := "ok"
+		if !check.OK() {
+			status = "FAILED"
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "%-*s  %-6s  %-7d  %s\n",
+			maxNameLen, check.Name, status, check.DomainCount, check.Error)
+	}
+
+	return nil
+}
+
+func outputProviderChecksJSON(cmd *cobra.Command, checks []registry.ProviderCheck) error {
+	payload := struct {
+		Providers []registry.ProviderCheck `json:"providers"`
+	}{
+		Providers: checks,
+	}
+
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(payload)
+}
+
+func init() {
+	providersCheckCmd.Flags().String("output", "table", "Output format (table, json)")
+
+	providersCmd.AddCommand(providersCheckCmd)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dh-kam/go-cert-provider/cert/registry"
+	"github.com/spf13/cobra"
+)
+
+// providersListCmd represents the providers list command
+var providersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available providers and their configuration status",
+	Long: `List every available certificate provider, whether it is configured,
+and how many domains it manages.
+
+Unlike other commands, a provider initialization failure does not abort this
+command; the error is reported alongside the provider list instead. This helps
+debugging why "certs serve" reports that no domains are available.
+
+Examples:
+  # List providers
+  go-cert-provider providers list
+
+  # Output as JSON
+  go-cert-provider providers list --output json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		// Provider initialization is skipped in PersistentPreRunE for this command
+		// so that failures can be reported instead of aborting
+		_, bootstrapManager, err := initializeCertificateSystem(cmd)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel, err := initContext(cmd)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		initErr := bootstrapManager.InitializeProviders(ctx)
+		statuses := bootstrapManager.ListBootstraps()
+
+		switch outputFormat {
+		case "json":
+			return outputProvidersJSON(cmd, statuses, initErr)
+		case "table", "":
+			return outputProvidersTable(cmd, statuses, initErr)
+		default:
+			return fmt.Errorf("unsupported output format: %s", outputFormat)
+		}
+	},
+}
+
+func outputProvidersTable(cmd *cobra.Command, statuses []registry.BootstrapStatus, initErr error) error {
+	maxNameLen := 8 // "PROVIDER"
+	for _, status := range statuses {
+		if len(status.Name) > maxNameLen {
+			maxNameLen = len(status.Name)
+		}
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%-*s  %-10s  %-10s  %s\n",
+		maxNameLen, "PROVIDER", "CONFIGURED", "REGISTERED", "DOMAINS")
+	fmt.Fprintf(cmd.OutOrStdout(), "%s  %s  %s  %s\n",
+		strings.Repeat("-", maxNameLen),
+		strings.Repeat("-", 10),
+		strings.Repeat("-", 10),
+		strings.Repeat("-", 7))
+
+	for _, status := range statuses {
+		fmt.Fprintf(cmd.OutOrStdout(), "%-*s  %-10s  %-10s  %d\n",
+			maxNameLen, status.Name, yesNo(status.Configured), yesNo(status.Registered), status.DomainCount)
+	}
+
+	if initErr != nil {
+		fmt.Fprintf(cmd.OutOrStderr(), "\nInitialization error: %v\n", initErr)
+	}
+
+	return nil
+}
+
+func outputProvidersJSON(cmd *cobra.Command, statuses []registry.BootstrapStatus, initErr error) error {
+	payload := struct {
+		Providers []registry.BootstrapStatus `json:"providers"`
+		Error     string                     `json:"error,omitempty"`
+	}{
+		Providers: statuses,
+	}
+	if initErr != nil {
+		payload.Error = initErr.Error()
+	}
+
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(payload)
+}
+
+// yesNo formats a boolean for table output
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+func init() {
+	providersListCmd.Flags().String("output", "table", "Output format (table, json)")
+
+	providersCmd.AddCommand(providersListCmd)
+}
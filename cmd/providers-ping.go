@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// providersPingCmd represents the providers ping command
+var providersPingCmd = &cobra.Command{
+	Use:   "ping",
+	Short: "Ping provider APIs and show the detected egress IP",
+	Long: `Ping the API of every configured provider and print the egress IP address the
+provider saw together with the request latency.
+
+Providers such as Porkbun allowlist API access by IP, so this is the first thing
+to check when certificate retrievals start failing. The command exits with a
+non-zero status if any ping fails.
+
+Examples:
+  # Ping all configured providers
+  go-cert-provider providers ping
+
+  # Ping a single provider
+  go-cert-provider providers ping --provider porkbun`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		providerName, err := cmd.Flags().GetString("provider")
+		if err != nil {
+			return err
+		}
+
+		// Provider initialization is skipped in PersistentPreRunE for this command
+		// so that the ping works even when domain discovery would fail
+		_, bootstrapManager, err := initializeCertificateSystem(cmd)
+		if err != nil {
+			return err
+		}
+
+		pings, err := bootstrapManager.PingProviders(cmd.Context(), providerName)
+		if err != nil {
+			return err
+		}
+		if len(pings) == 0 {
+			return fmt.Errorf("no certificate providers configured")
+		}
+
+		maxNameLen := 8 // "PROVIDER"
+		for _, ping := range pings {
+			if len(ping.Name) > maxNameLen {
+				maxNameLen = len(ping.Name)
+			}
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "%-*s  %-6s  %-39s  %-8s  %s\n",
+			maxNameLen, "PROVIDER", "STATUS", "EGRESS IP", "LATENCY", "ERROR")
+		fmt.Fprintf(cmd.OutOrStdout(), "%s  %s  %s  %s  %s\n",
+			strings.Repeat("-", maxNameLen),
+			strings.Repeat("-", 6),
+			strings.Repeat("-", 39),
+			strings.Repeat("-", 8),
+			strings.Repeat("-", 5))
+
+		failed := 0
+		for _, ping := range pings {
+			status := "ok"
+			if !ping.OK() {
+				status = "FAILED"
+				failed++
+			}
+
+			clientIP := ping.ClientIP
+			if clientIP == "" {
+				clientIP = "-"
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%-*s  %-6s  %-39s  %-8s  %s\n",
+				maxNameLen, ping.Name, status, clientIP, ping.Latency.Round(time.Millisecond), ping.Error)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d provider ping(s) failed", failed, len(pings))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	providersPingCmd.Flags().String("provider", "", "Only ping this provider (e.g. porkbun)")
+
+	providersCmd.AddCommand(providersPingCmd)
+}
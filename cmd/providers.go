@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// providersCmd represents the providers command
+var providersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "Certificate provider commands",
+	Long: `Inspect the certificate providers known to this tool.
+
+This command provides subcommands for listing available providers,
+checking which of them are configured, and testing their credentials.`,
+}
+
+func init() {
+	rootCmd.AddCommand(providersCmd)
+}
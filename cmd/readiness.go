@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/auth"
+	"github.com/dh-kam/go-cert-provider/cert/registry"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// readinessPingTTL is how long provider ping results are reused between probes
+	readinessPingTTL = 30 * time.Second
+	// readinessCheckTimeout bounds the pings and the probe retrieval of one check
+	readinessCheckTimeout = 30 * time.Second
+)
+
+// readinessProbeResult is the cached outcome of retrieving the probe domain's certificate
+type readinessProbeResult struct {
+	Domain    string    `json:"domain,omitempty"`
+	OK        bool      `json:"ok"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// readinessProvider is the ping outcome of one provider in a /readyz response
+type readinessProvider struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// readinessChecker answers /readyz. Provider pings are cached for readinessPingTTL and
+// the optional certificate retrieval of probeDomain for probeTTL, since both consume
+// provider API quota. Checks are serialized so concurrent probes share one refresh.
+// Error details and the probe domain are only shown to callers with a valid bearer token.
+type readinessChecker struct {
+	bootstrapManager  *registry.BootstrapManager
+	providerRegistry  *registry.CertificateProviderRegistry
+	probeDomain       string
+	probeTTL          time.Duration
+	jwtSecretKey      string
+	validationOptions auth.ValidationOptions
+
+	mu       sync.Mutex
+	pings    []readinessProvider
+	pingedAt time.Time
+	probe    *readinessProbeResult
+}
+
+// newReadinessChecker returns a checker pinging the configured providers and, when
+// probeDomain is not empty, retrieving its certificate at most once per probeTTL. Tokens
+// revealing the details are validated with jwtSecretKey and validationOptions.
+func newReadinessChecker(bootstrapManager *registry.BootstrapManager, providerRegistry *registry.CertificateProviderRegistry,
+	probeDomain string, probeTTL time.Duration, jwtSecretKey string, validationOptions auth.ValidationOptions) *readinessChecker {
+	return &readinessChecker{
+		bootstrapManager:  bootstrapManager,
+		providerRegistry:  providerRegistry,
+		probeDomain:       probeDomain,
+		probeTTL:          probeTTL,
+		jwtSecretKey:      jwtSecretKey,
+		validationOptions: validationOptions,
+	}
+}
+
+// check returns the provider pings and probe result, refreshing whichever has expired
+func (r *readinessChecker) check(ctx context.Context) ([]readinessProvider, *readinessProbeResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// A probe client hanging up must not cache a failure for everyone else
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), readinessCheckTimeout)
+	defer cancel()
+
+	now := time.Now()
+	if r.pings == nil || now.Sub(r.pingedAt) >= readinessPingTTL {
+		r.pings = r.pingProviders(ctx)
+		r.pingedAt = now
+	}
+
+	if r.probeDomain != "" && (r.probe == nil || now.Sub(r.probe.CheckedAt) >= r.probeTTL) {
+		r.probe = r.retrieveProbe(ctx)
+	}
+
+	return r.pings, r.probe
+}
+
+func (r *readinessChecker) pingProviders(ctx context.Context) []readinessProvider {
+	pings, err := r.bootstrapManager.PingProviders(ctx, "")
+	if err != nil {
+		return []readinessProvider{{Error: err.Error()}}
+	}
+
+	providers := make([]readinessProvider, 0, len(pings))
+	for _, ping := range pings {
+		providers = append(providers, readinessProvider{Name: ping.Name, OK: ping.OK(), Error: ping.Error})
+	}
+	return providers
+}
+
+func (r *readinessChecker) retrieveProbe(ctx context.Context) *readinessProbeResult {
+	result := &readinessProbeResult{Domain: r.probeDomain, CheckedAt: time.Now()}
+
+	provider, err := r.providerRegistry.GetProviderForDomain(r.probeDomain)
+	if err == nil {
+		_, _, err = provider.RetrieveCertificate(ctx, r.probeDomain)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.OK = true
+	return result
+}
+
+// handle serves /readyz: 200 when every provider answers its ping and the probe
+// retrieval (if configured) succeeded, 503 otherwise. Callers without a bearer token
+// get only the status and the provider names; an invalid token is rejected with 401.
+func (r *readinessChecker) handle(c *gin.Context) {
+	claims, ok := optionalBearerClaims(c, r.jwtSecretKey, r.validationOptions)
+	if !ok {
+		return
+	}
+
+	providers, probe := r.check(c.Request.Context())
+	if claims == nil {
+		providers, probe = redactReadiness(providers, probe)
+	}
+
+	ready := probe == nil || probe.OK
+	for _, provider := range providers {
+		ready = ready && provider.OK
+	}
+
+	status, code := "ready", http.StatusOK
+	if !ready {
+		status, code = "not ready", http.StatusServiceUnavailable
+	}
+
+	response := gin.H{
+		"status":    status,
+		"providers": providers,
+	}
+	if probe != nil {
+		response["probe"] = probe
+	}
+	c.JSON(code, response)
+}
+
+// redactReadiness drops the raw error strings and the probe domain, which may reveal
+// provider internals to unauthenticated callers
+func redactReadiness(providers []readinessProvider, probe *readinessProbeResult) ([]readinessProvider, *readinessProbeResult) {
+	redacted := make([]readinessProvider, 0, len(providers))
+	for _, provider := range providers {
+		redacted = append(redacted, readinessProvider{Name: provider.Name, OK: provider.OK})
+	}
+	if probe != nil {
+		probe = &readinessProbeResult{OK: probe.OK, CheckedAt: probe.CheckedAt}
+	}
+	return redacted, probe
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/auth"
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/cert/providers/mock"
+	"github.com/dh-kam/go-cert-provider/cert/registry"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cobra"
+)
+
+// probedProvider wraps the mock provider, counting and optionally failing retrievals
+type probedProvider struct {
+	*mock.Provider
+	retrievals  int
+	retrieveErr error
+}
+
+func (p *probedProvider) RetrieveCertificate(ctx context.Context, domainName string) ([]byte, []byte, error) {
+	p.retrievals++
+	if p.retrieveErr != nil {
+		return nil, nil, p.retrieveErr
+	}
+	return p.Provider.RetrieveCertificate(ctx, domainName)
+}
+
+// pingedBootstrap is a configured bootstrap whose connectivity check is counted and may fail
+type pingedBootstrap struct {
+	pings   int
+	pingErr error
+}
+
+func (b *pingedBootstrap) GetProviderName() string          { return "mock" }
+func (b *pingedBootstrap) RegisterFlags(cmd *cobra.Command) {}
+func (b *pingedBootstrap) IsConfigured() bool               { return true }
+
+func (b *pingedBootstrap) CreateProvider(ctx context.Context) (domain.CertificateProvider, error) {
+	return nil, errors.New("not used by /readyz")
+}
+
+func (b *pingedBootstrap) CheckConnectivity(ctx context.Context) (*domain.ConnectivityResult, error) {
+	b.pings++
+	if b.pingErr != nil {
+		return nil, b.pingErr
+	}
+	return &domain.ConnectivityResult{ClientIP: "127.0.0.1"}, nil
+}
+
+func TestReadinessChecker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	setup := func(probeDomain string) (*readinessChecker, *pingedBootstrap, *probedProvider) {
+		providerRegistry := registry.NewCertificateProviderRegistry()
+		provider := &probedProvider{Provider: mock.NewProvider([]string{"example.com"}, nil, nil)}
+		if err := providerRegistry.Register(provider); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+		bootstrap := &pingedBootstrap{}
+		bootstrapManager := registry.NewBootstrapManager(providerRegistry)
+		bootstrapManager.RegisterBootstrap(bootstrap)
+		return newReadinessChecker(bootstrapManager, providerRegistry, probeDomain, time.Hour, testSecretKey, auth.ValidationOptions{}), bootstrap, provider
+	}
+
+	token := createTestToken(t, []string{"*"}, nil)
+	readyzWithToken := func(checker *readinessChecker, token string) (int, map[string]interface{}) {
+		t.Helper()
+		router := gin.New()
+		router.GET("/readyz", checker.handle)
+		req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Invalid response: %v", err)
+		}
+		return recorder.Code, body
+	}
+	readyz := func(checker *readinessChecker) (int, map[string]interface{}) {
+		t.Helper()
+		return readyzWithToken(checker, token)
+	}
+
+	t.Run("ready without a probe domain", func(t *testing.T) {
+		checker, _, provider := setup("")
+		code, body := readyz(checker)
+		if code != http.StatusOK || body["status"] != "ready" || body["probe"] != nil {
+			t.Errorf("Unexpected response %d: %v", code, body)
+		}
+		if provider.retrievals != 0 {
+			t.Errorf("Expected no retrieval without a probe domain, got %d", provider.retrievals)
+		}
+	})
+
+	t.Run("results are cached", func(t *testing.T) {
+		checker, bootstrap, provider := setup("example.com")
+		for i := 0; i < 3; i++ {
+			if code, body := readyz(checker); code != http.StatusOK {
+				t.Fatalf("Unexpected response %d: %v", code, body)
+			}
+		}
+		if bootstrap.pings != 1 || provider.retrievals != 1 {
+			t.Errorf("Expected one ping and one retrieval, got %d and %d", bootstrap.pings, provider.retrievals)
+		}
+
+		// Expired results are checked again
+		checker.pingedAt = checker.pingedAt.Add(-readinessPingTTL)
+		checker.probe.CheckedAt = checker.probe.CheckedAt.Add(-time.Hour)
+		readyz(checker)
+		if bootstrap.pings != 2 || provider.retrievals != 2 {
+			t.Errorf("Expected the expired results to be refreshed, got %d pings and %d retrievals", bootstrap.pings, provider.retrievals)
+		}
+	})
+
+	t.Run("failed ping", func(t *testing.T) {
+		checker, bootstrap, _ := setup("")
+		bootstrap.pingErr = errors.New("connection refused")
+		code, body := readyz(checker)
+		if code != http.StatusServiceUnavailable || body["status"] != "not ready" {
+			t.Errorf("Unexpected response %d: %v", code, body)
+		}
+	})
+
+	t.Run("failed probe", func(t *testing.T) {
+		checker, _, provider := setup("example.com")
+		provider.retrieveErr = errors.New("SSL endpoint not allowed from this IP")
+		code, body := readyz(checker)
+		probe, _ := body["probe"].(map[string]interface{})
+		if code != http.StatusServiceUnavailable || probe["ok"] != false || probe["error"] != "SSL endpoint not allowed from this IP" {
+			t.Errorf("Unexpected response %d: %v", code, body)
+		}
+	})
+
+	t.Run("details are hidden without a token", func(t *testing.T) {
+		checker, bootstrap, provider := setup("example.com")
+		bootstrap.pingErr = errors.New("401 Unauthorized: invalid API key pk1_abc")
+		provider.retrieveErr = errors.New("SSL endpoint not allowed from this IP")
+
+		code, body := readyzWithToken(checker, "")
+		want := map[string]interface{}{
+			"status":    "not ready",
+			"providers": []interface{}{map[string]interface{}{"name": "mock", "ok": false}},
+			"probe":     map[string]interface{}{"ok": false, "checkedAt": body["probe"].(map[string]interface{})["checkedAt"]},
+		}
+		if code != http.StatusServiceUnavailable || !reflect.DeepEqual(body, want) {
+			t.Errorf("Unexpected response %d: %v", code, body)
+		}
+
+		// The cached results keep their details for authenticated callers
+		_, body = readyz(checker)
+		probe, _ := body["probe"].(map[string]interface{})
+		if probe["error"] != "SSL endpoint not allowed from this IP" || probe["domain"] != "example.com" {
+			t.Errorf("Expected the details with a valid token, got %v", body)
+		}
+		if bootstrap.pings != 1 {
+			t.Errorf("Expected the redacted and full responses to share one ping, got %d", bootstrap.pings)
+		}
+
+		if code, _ := readyzWithToken(checker, "not-a-token"); code != http.StatusUnauthorized {
+			t.Errorf("Expected an invalid token to be rejected, got %d", code)
+		}
+	})
+
+	t.Run("probe domain not managed", func(t *testing.T) {
+		checker, _, provider := setup("other.com")
+		code, body := readyz(checker)
+		probe, _ := body["probe"].(map[string]interface{})
+		if code != http.StatusServiceUnavailable || probe["domain"] != "other.com" || probe["ok"] != false {
+			t.Errorf("Unexpected response %d: %v", code, body)
+		}
+		if provider.retrievals != 0 {
+			t.Errorf("Expected no retrieval for an unmanaged domain, got %d", provider.retrievals)
+		}
+	})
+}
@@ -1,10 +1,20 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/dh-kam/go-cert-provider/cert"
 	"github.com/dh-kam/go-cert-provider/cert/registry"
+	"github.com/dh-kam/go-cert-provider/certprovider"
+	"github.com/dh-kam/go-cert-provider/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -12,20 +22,43 @@ import (
 type globalState struct {
 	providerRegistry *registry.CertificateProviderRegistry
 	bootstrapManager *registry.BootstrapManager
+
+	providersConfigApplied bool // the providers config file has configured the bootstraps
+	initialized            bool // the providers have been initialized
 }
 
 var (
+	// appState holds the certificate system of the CLI; the provider flags are bound to
+	// its bootstraps in init, and its providers are initialized in PersistentPreRunE
 	appState *globalState
 
 	rootCmd = &cobra.Command{
 		Use:   "go-cert-provider",
 		Short: "Certificate provider service with JWT authentication",
-		Long: `A service that provides TLS certificates from domain providers (Porkbun) 
+		Long: `A service that provides TLS certificates from domain providers (Porkbun, DigitalOcean, Namecheap)
 to authorized users via JWT authentication.
 
 This tool allows users to retrieve certificates without exposing provider API keys,
 using JWT tokens for authentication and authorization.`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Load the env file first so that every command, including those that
+			// skip provider initialization, sees its variables
+			envFile, err := cmd.Flags().GetString("env-file")
+			if err != nil {
+				return err
+			}
+			if envFile != "" {
+				if err := utils.LoadEnvFile(envFile); err != nil {
+					return err
+				}
+			}
+
+			if err := applyTimezone(cmd); err != nil {
+				return err
+			}
+
+			withVerboseHTTPTiming(cmd)
+
 			// Skip provider initialization for commands that don't need it
 			cmdPath := cmd.CommandPath()
 			skipProviderInit := false
@@ -34,8 +67,13 @@ using JWT tokens for authentication and authorization.`,
 			skipCommands := []string{
 				"go-cert-provider jwt",
 				"go-cert-provider version",
+				"go-cert-provider doctor",
 				"go-cert-provider help",
 				"go-cert-provider completion",
+				"go-cert-provider providers",
+				"go-cert-provider certs schema",
+				"go-cert-provider certs warm",
+				"go-cert-provider certs validate",
 			}
 
 			for _, skipCmd := range skipCommands {
@@ -49,21 +87,9 @@ using JWT tokens for authentication and authorization.`,
 				return nil
 			}
 
-			// Initialize certificate provider system
-			providerRegistry, bootstrapManager, err := cert.InitializeCertificateSystem(cmd)
-			if err != nil {
-				return fmt.Errorf("failed to initialize certificate system: %w", err)
-			}
-
-			// Initialize all configured providers
-			if err := bootstrapManager.InitializeProviders(); err != nil {
-				return fmt.Errorf("failed to initialize providers: %w", err)
-			}
-
-			// Store in global state for subcommands to use
-			appState = &globalState{
-				providerRegistry: providerRegistry,
-				bootstrapManager: bootstrapManager,
+			// Initialize the providers of the global state for subcommands to use
+			if _, err := initializeProviderSystem(cmd); err != nil {
+				return err
 			}
 
 			return nil
@@ -71,20 +97,198 @@ using JWT tokens for authentication and authorization.`,
 	}
 )
 
-// Execute adds all child commands to the root command and sets flags appropriately.
-// This is called by main.main(). It only needs to happen once to the rootCmd.
+// initializeProviderSystem bootstraps the configured providers and returns the shared state.
+// Commands in the skip list may call it themselves when they need a registry optionally.
+func initializeProviderSystem(cmd *cobra.Command) (*globalState, error) {
+	providerRegistry, bootstrapManager, err := initializeCertificateSystem(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := certprovider.Options{}
+
+	tagsConfig, err := cmd.Flags().GetString("tags")
+	if err != nil {
+		return nil, err
+	}
+	if tagsConfig == "" {
+		tagsConfig = os.Getenv("TAGS_CONFIG")
+	}
+	if tagsConfig != "" {
+		cfg, err := registry.LoadTagsConfig(tagsConfig)
+		if err != nil {
+			return nil, err
+		}
+		opts.DomainTags = cfg.Tags
+	}
+
+	if opts.ContinueOnProviderError, err = cmd.Flags().GetBool("continue-on-provider-error"); err != nil {
+		return nil, err
+	}
+	if opts.ProviderPriority, err = cmd.Flags().GetStringSlice("provider-priority"); err != nil {
+		return nil, err
+	}
+	if opts.ReadOnly, err = cmd.Flags().GetBool("read-only"); err != nil {
+		return nil, err
+	}
+	if opts.RegisterWildcards, err = cmd.Flags().GetBool("register-wildcards"); err != nil {
+		return nil, err
+	}
+
+	// The providers config file was applied to the flag-configured bootstraps already
+	if err := certprovider.Configure(bootstrapManager, opts); err != nil {
+		return nil, err
+	}
+
+	// Initialize all configured providers; discovery is bounded by --init-timeout and
+	// can be interrupted, since the server is not listening yet
+	ctx, cancel, err := initContext(cmd)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	start := time.Now()
+	if err := bootstrapManager.InitializeProviders(ctx); err != nil {
+		return nil, initError(cmd, err)
+	}
+	fmt.Fprintf(verboseOut(cmd), "Initialized %d provider(s) managing %d domain(s) in %s\n",
+		len(providerRegistry.ListProviders()), len(providerRegistry.ListDomains()), time.Since(start).Round(time.Millisecond))
+	for _, failure := range bootstrapManager.FailedProviders() {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: skipping provider %s: %s\n", failure.Name, failure.Error)
+	}
+
+	appState.initialized = true
+	return appState, nil
+}
+
+// applyTimezone sets the timezone dates are parsed and displayed in: UTC with --utc (or
+// UTC_OUTPUT), else --timezone, else the TZ variable, which may come from --env-file after Go
+// has read the environment. Like Go itself, an invalid TZ is ignored.
+func applyTimezone(cmd *cobra.Command) error {
+	timezone, err := cmd.Flags().GetString("timezone")
+	if err != nil {
+		return err
+	}
+	utc, err := cmd.Flags().GetBool("utc")
+	if err != nil {
+		return err
+	}
+	if !utc {
+		utc, _ = strconv.ParseBool(os.Getenv("UTC_OUTPUT"))
+	}
+	utils.SetUTC(utc)
+
+	if timezone != "" {
+		if _, err := utils.SetTimezone(timezone); err != nil {
+			return fmt.Errorf("invalid --timezone: %w", err)
+		}
+		return nil
+	}
+
+	if tz := strings.TrimPrefix(os.Getenv("TZ"), ":"); tz != "" {
+		utils.SetTimezone(tz) //nolint:errcheck // keep the timezone Go chose
+	}
+	return nil
+}
+
+// initContext returns the context bounding provider initialization: it ends after
+// --init-timeout (unless 0) or when SIGINT or SIGTERM is received
+func initContext(cmd *cobra.Command) (context.Context, context.CancelFunc, error) {
+	initTimeout, err := cmd.Flags().GetDuration("init-timeout")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parent := cmd.Context()
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	if initTimeout <= 0 {
+		return ctx, stop, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, initTimeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}, nil
+}
+
+// initError explains provider initialization failures caused by --init-timeout or an interrupt
+func initError(cmd *cobra.Command, err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		initTimeout, _ := cmd.Flags().GetDuration("init-timeout")
+		return fmt.Errorf("provider initialization did not finish within --init-timeout %s (is domain discovery hanging?): %w", initTimeout, err)
+	case errors.Is(err, context.Canceled):
+		return fmt.Errorf("provider initialization interrupted: %w", err)
+	default:
+		return fmt.Errorf("failed to initialize providers: %w", err)
+	}
+}
+
+// initializeCertificateSystem returns the provider registry and bootstrap manager of the
+// global state, with the providers config file (--providers-config or PROVIDERS_CONFIG) applied
+func initializeCertificateSystem(cmd *cobra.Command) (*registry.CertificateProviderRegistry, *registry.BootstrapManager, error) {
+	providerRegistry, bootstrapManager := appState.providerRegistry, appState.bootstrapManager
+	if appState.providersConfigApplied {
+		return providerRegistry, bootstrapManager, nil
+	}
+
+	providersConfig, err := cmd.Flags().GetString("providers-config")
+	if err != nil {
+		return nil, nil, err
+	}
+	if providersConfig == "" {
+		providersConfig = os.Getenv("PROVIDERS_CONFIG")
+	}
+	if err := cert.ApplyProvidersConfig(bootstrapManager, providersConfig); err != nil {
+		return nil, nil, err
+	}
+	appState.providersConfigApplied = true
+
+	return providerRegistry, bootstrapManager, nil
+}
+
+// Execute runs the root command. SIGINT and SIGTERM cancel the command's context, so
+// provider requests in flight are abandoned; a second signal exits immediately.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	return rootCmd.ExecuteContext(ctx)
 }
 
 func init() {
-	// Initialize certificate system to register provider flags
-	_, bootstrapManager, err := cert.InitializeCertificateSystem(rootCmd)
-	if err != nil {
-		fmt.Fprintf(rootCmd.OutOrStderr(), "Warning: failed to initialize certificate system: %v\n", err)
-	} else {
-		// Register all provider flags as persistent flags at root level
-		// These will be available to all subcommands
-		bootstrapManager.RegisterFlags(rootCmd)
+	rootCmd.PersistentFlags().Bool("continue-on-provider-error", false, "Skip providers that fail to initialize instead of exiting; fails only if no provider comes up")
+	rootCmd.PersistentFlags().StringSlice("provider-priority", nil, "Provider names, highest priority first; a domain listed by several of them is served by the highest-ranked one instead of failing as a duplicate")
+	rootCmd.PersistentFlags().Bool("read-only", false, "Allow listing domains but refuse certificate retrieval, so no private key can be exposed")
+	rootCmd.PersistentFlags().Bool("register-wildcards", false, "Also serve *.<domain> for every managed domain, retrieved as the domain's certificate")
+	rootCmd.PersistentFlags().Duration("init-timeout", 2*time.Minute, "Maximum time for provider initialization, including domain auto-discovery (0 disables the limit)")
+	rootCmd.PersistentFlags().String("tags", "", "YAML file mapping domain names or glob patterns to tags, shown and filtered by domain list (overrides TAGS_CONFIG env var)")
+	rootCmd.PersistentFlags().String("providers-config", "", "YAML file declaring provider instances and their credentials; flags and env vars override it (overrides PROVIDERS_CONFIG env var)")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable styled output (also disabled by the NO_COLOR env var or when stdout is not a terminal)")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Suppress informational messages on stderr; warnings and errors are still printed")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Print extra detail on stderr, including the timing of each provider API request")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
+	rootCmd.PersistentFlags().String("timezone", "", "IANA timezone for parsing and displaying dates, e.g. Asia/Seoul or UTC (default: TZ env var or the system timezone)")
+	rootCmd.PersistentFlags().Bool("utc", false, "Display timestamps and read bare dates (e.g. --expires-at 2025-12-31) in UTC instead of the local timezone (overrides UTC_OUTPUT env var)")
+	rootCmd.MarkFlagsMutuallyExclusive("utc", "timezone")
+	rootCmd.PersistentFlags().String("env-file", "", "Load KEY=VALUE pairs from this .env file; variables already set in the environment take precedence")
+
+	// Create the certificate system and register all provider flags as persistent flags
+	// at root level, so they are available to all subcommands
+	providerRegistry, bootstrapManager := cert.NewCertificateSystem()
+	bootstrapManager.RegisterFlags(rootCmd)
+	appState = &globalState{
+		providerRegistry: providerRegistry,
+		bootstrapManager: bootstrapManager,
 	}
 }
@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+	"github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/cert/registry"
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+)
+
+// tuiCmd represents the tui command
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse managed domains and retrieve certificates interactively",
+	Long: `Browse the managed domains in an interactive terminal UI.
+
+Select a domain with the arrow keys (or j/k), type / to filter, and press Enter to
+show its details. Press s to retrieve the domain's certificate and save it as a bundle
+in --output-dir. Press q to quit.
+
+The TUI requires an interactive terminal. For scripts, use "domain list" and
+"certs retrieve" instead.
+
+Examples:
+  # Browse domains and save bundles to the current directory
+  go-cert-provider tui
+
+  # Save bundles to ./certs
+  go-cert-provider tui --output-dir ./certs`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputDir, err := cmd.Flags().GetString("output-dir")
+		if err != nil {
+			return err
+		}
+
+		if !term.IsTerminal(os.Stdin.Fd()) || !term.IsTerminal(os.Stdout.Fd()) {
+			return fmt.Errorf("the tui command requires an interactive terminal; use \"domain list\" and \"certs retrieve\" in scripts")
+		}
+
+		if !appState.initialized {
+			return fmt.Errorf("certificate system not initialized")
+		}
+
+		infos := appState.providerRegistry.ListAllDomainInfo()
+		if len(infos) == 0 {
+			return fmt.Errorf("no domains found; check your provider configuration")
+		}
+		sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+		if noColor, _ := cmd.Flags().GetBool("no-color"); !utils.ColorEnabled(noColor, os.Stdout) {
+			lipgloss.SetColorProfile(termenv.Ascii)
+		}
+
+		model := newTUIModel(cmd, appState.providerRegistry, infos, outputDir)
+		if _, err := tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(cmd.Context())).Run(); err != nil {
+			return fmt.Errorf("tui failed: %w", err)
+		}
+		return nil
+	},
+}
+
+// tuiKeyMap holds the TUI's key bindings beyond the list navigation
+type tuiKeyMap struct {
+	details key.Binding
+	back    key.Binding
+	save    key.Binding
+	quit    key.Binding
+}
+
+// newTUIKeyMap returns the default key bindings
+func newTUIKeyMap() tuiKeyMap {
+	return tuiKeyMap{
+		details: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "details")),
+		back:    key.NewBinding(key.WithKeys("esc", "backspace", "h"), key.WithHelp("esc", "back")),
+		save:    key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "save certificate")),
+		quit:    key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+	}
+}
+
+// domainItem is a managed domain in the TUI list
+type domainItem struct {
+	info domain.Info
+}
+
+// Title returns the domain name
+func (i domainItem) Title() string { return i.info.Name }
+
+// Description returns the provider, status and expiry of the domain
+func (i domainItem) Description() string {
+	return fmt.Sprintf("%s • %s • expires %s", i.info.Provider, i.info.Status, formatDate(i.info.ExpireDate))
+}
+
+// FilterValue returns the domain name, which / filters on
+func (i domainItem) FilterValue() string { return i.info.Name }
+
+// certificateSavedMsg reports the outcome of saving a domain's certificate
+type certificateSavedMsg struct {
+	domain string
+	info   *domain.Info // updated domain details, nil if unknown
+	status string
+	failed bool
+}
+
+// tuiModel is the bubbletea model of the TUI
+type tuiModel struct {
+	ctx              context.Context
+	providerRegistry *registry.CertificateProviderRegistry
+	outputDir        string
+
+	list   list.Model
+	keys   tuiKeyMap
+	detail bool
+	saving bool
+	status string
+
+	title   func(...string) string
+	faint   func(...string) string
+	failure func(...string) string
+}
+
+// newTUIModel creates the TUI state for the given domains
+func newTUIModel(cmd *cobra.Command, providerRegistry *registry.CertificateProviderRegistry, infos []domain.Info, outputDir string) *tuiModel {
+	items := make([]list.Item, len(infos))
+	for i, info := range infos {
+		items[i] = domainItem{info: info}
+	}
+
+	keys := newTUIKeyMap()
+	domains := list.New(items, list.NewDefaultDelegate(), 80, 24)
+	domains.Title = fmt.Sprintf("Managed domains (%d)", len(infos))
+	domains.SetStatusBarItemName("domain", "domains")
+	// q and ctrl+c are handled by the model, and esc must not quit the list
+	domains.DisableQuitKeybindings()
+	domains.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.details, keys.save, keys.quit}
+	}
+
+	return &tuiModel{
+		ctx:              cmd.Context(),
+		providerRegistry: providerRegistry,
+		outputDir:        outputDir,
+		list:             domains,
+		keys:             keys,
+		title:            styleRenderer(cmd, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))),
+		faint:            styleRenderer(cmd, lipgloss.NewStyle().Faint(true)),
+		failure:          styleRenderer(cmd, lipgloss.NewStyle().Foreground(lipgloss.Color("9"))),
+	}
+}
+
+// Init starts the TUI without an initial command
+func (m *tuiModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles key presses, resizes and finished certificate retrievals
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Leave a line for the status
+		m.list.SetSize(msg.Width, msg.Height-1)
+
+	case certificateSavedMsg:
+		m.saving = false
+		m.status = msg.status
+		if msg.failed {
+			m.status = m.failure(msg.status)
+		}
+		if msg.info != nil {
+			for i, item := range m.list.Items() {
+				if item.(domainItem).info.Name == msg.domain {
+					return m, m.list.SetItem(i, domainItem{info: *msg.info})
+				}
+			}
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		// While filtering, keys are typed into the filter, except ctrl+c
+		if m.list.FilterState() == list.Filtering {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			break
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.save):
+			return m, m.save()
+		case m.detail:
+			if key.Matches(msg, m.keys.back) {
+				m.detail = false
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.details):
+			if m.list.SelectedItem() != nil {
+				m.detail = true
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// save starts retrieving the certificate of the selected domain in the background
+func (m *tuiModel) save() tea.Cmd {
+	item, ok := m.list.SelectedItem().(domainItem)
+	if !ok || m.saving {
+		return nil
+	}
+
+	m.saving = true
+	m.status = fmt.Sprintf("Retrieving certificate for %s...", item.info.Name)
+	ctx, providerRegistry, outputDir := m.ctx, m.providerRegistry, m.outputDir
+	return func() tea.Msg {
+		return saveCertificateBundle(ctx, providerRegistry, item.info.Name, outputDir)
+	}
+}
+
+// saveCertificateBundle retrieves the certificate of a domain and writes it as a bundle
+func saveCertificateBundle(ctx context.Context, providerRegistry *registry.CertificateProviderRegistry, domainName, outputDir string) certificateSavedMsg {
+	failed := func(message string) certificateSavedMsg {
+		return certificateSavedMsg{domain: domainName, status: message, failed: true}
+	}
+
+	certChain, privateKey, err := providerRegistry.RetrieveCertificate(ctx, domainName)
+	if err != nil {
+		message := fmt.Sprintf("Failed to retrieve certificate: %v", err)
+		if provider, providerErr := providerRegistry.GetProviderForDomain(domainName); providerErr == nil {
+			if hint := retrievalErrorHint(err, provider.GetProviderName()); hint != "" {
+				message += " (" + hint + ")"
+			}
+		}
+		return failed(message)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return failed(fmt.Sprintf("Failed to create output directory: %v", err))
+	}
+	bundle, err := utils.BuildPEMBundle(certChain, privateKey, false)
+	if err != nil {
+		return failed(err.Error())
+	}
+	bundlePath := certificateFilePath(domainName, &fileOptions{outputDir: outputDir})
+	if err := os.WriteFile(bundlePath, bundle, 0600); err != nil {
+		return failed(fmt.Sprintf("Failed to write bundle file: %v", err))
+	}
+
+	// The registry records the certificate details on retrieval
+	return certificateSavedMsg{
+		domain: domainName,
+		info:   providerRegistry.GetDomainInfo(domainName),
+		status: fmt.Sprintf("Certificate bundle with %s saved to: %s", keyLabel(privateKey), bundlePath),
+	}
+}
+
+// View renders the domain list or the details of the selected domain, and the status line
+func (m *tuiModel) View() string {
+	var view string
+	if m.detail {
+		view = m.detailView()
+	} else {
+		view = m.list.View()
+	}
+	return view + "\n" + m.status
+}
+
+// detailView renders the details of the selected domain
+func (m *tuiModel) detailView() string {
+	item, ok := m.list.SelectedItem().(domainItem)
+	if !ok {
+		return ""
+	}
+	info := item.info
+
+	lines := []string{
+		m.title(info.Name),
+		"",
+		fmt.Sprintf("  Provider:     %s", info.Provider),
+		fmt.Sprintf("  Status:       %s", info.Status),
+		fmt.Sprintf("  Created:      %s", formatDate(info.CreateDate)),
+		fmt.Sprintf("  Expires:      %s", formatDate(info.ExpireDate)),
+		fmt.Sprintf("  Auto-renew:   %t", info.AutoRenew),
+		fmt.Sprintf("  Tags:         %s", formatTags(info.Tags)),
+	}
+
+	if info.CertSerial != "" {
+		lines = append(lines,
+			"",
+			fmt.Sprintf("  Certificate serial:       %s", info.CertSerial),
+			fmt.Sprintf("  Certificate SHA-256:      %s", info.CertFingerprintSHA256),
+			fmt.Sprintf("  Certificate expires:      %s", formatDate(info.CertNotAfter)))
+	} else {
+		lines = append(lines, "", m.faint("  No certificate retrieved yet; press s to retrieve it"))
+	}
+
+	lines = append(lines, "", m.faint("esc back • s save certificate • q quit"))
+	return strings.Join(lines, "\n")
+}
+
+func init() {
+	tuiCmd.Flags().String("output-dir", ".", "Directory to save certificate bundles to")
+
+	rootCmd.AddCommand(tuiCmd)
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dh-kam/go-cert-provider/cert/providers/mock"
+	"github.com/dh-kam/go-cert-provider/cert/registry"
+	"github.com/spf13/cobra"
+)
+
+// pressKey sends a key press to the model, as typed on the keyboard
+func pressKey(m *tuiModel, keyName string) tea.Cmd {
+	var msg tea.KeyMsg
+	switch keyName {
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	case "down":
+		msg = tea.KeyMsg{Type: tea.KeyDown}
+	case "ctrl+c":
+		msg = tea.KeyMsg{Type: tea.KeyCtrlC}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(keyName)}
+	}
+	_, cmd := m.Update(msg)
+	return cmd
+}
+
+// isQuit reports whether cmd quits the program
+func isQuit(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
+
+func TestTUIModel(t *testing.T) {
+	providerRegistry := registry.NewCertificateProviderRegistry()
+	if err := providerRegistry.Register(mock.NewProvider([]string{"a.example.com", "b.example.com"}, nil, nil)); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	cmd.Flags().Bool("no-color", true, "")
+	outputDir := t.TempDir()
+	model := newTUIModel(cmd, providerRegistry, providerRegistry.ListAllDomainInfo(), outputDir)
+	model.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	if view := model.View(); !strings.Contains(view, "a.example.com") || !strings.Contains(view, "b.example.com") {
+		t.Fatalf("Expected both domains in the list, got:\n%s", view)
+	}
+
+	// Enter shows the selected domain; esc goes back to the list
+	pressKey(model, "down")
+	pressKey(model, "enter")
+	if view := model.View(); !model.detail || !strings.Contains(view, "b.example.com") || !strings.Contains(view, "No certificate retrieved yet") {
+		t.Fatalf("Expected the details of b.example.com, got:\n%s", view)
+	}
+	pressKey(model, "esc")
+	if model.detail {
+		t.Fatal("Expected esc to return to the list")
+	}
+
+	// s retrieves the certificate in the background and saves the bundle
+	saveCmd := pressKey(model, "s")
+	if saveCmd == nil || !model.saving || !strings.Contains(model.status, "Retrieving certificate for b.example.com") {
+		t.Fatalf("Expected a retrieval to start, status %q", model.status)
+	}
+	if pressKey(model, "s") != nil {
+		t.Error("Expected no second retrieval while one is running")
+	}
+	model.Update(saveCmd())
+
+	bundlePath := filepath.Join(outputDir, "b.example.com-bundle.pem")
+	if _, err := os.Stat(bundlePath); err != nil {
+		t.Fatalf("Expected the bundle to be written: %v", err)
+	}
+	if model.saving || !strings.Contains(model.status, "saved to: "+bundlePath) {
+		t.Errorf("Unexpected status after saving: %q", model.status)
+	}
+	pressKey(model, "enter")
+	if view := model.View(); !strings.Contains(view, "Certificate serial:") {
+		t.Errorf("Expected the certificate details after saving, got:\n%s", view)
+	}
+	pressKey(model, "esc")
+
+	// While filtering, q is typed into the filter instead of quitting
+	pressKey(model, "/")
+	if isQuit(pressKey(model, "q")) {
+		t.Error("Expected q to be typed into the filter")
+	}
+	pressKey(model, "esc")
+	if !isQuit(pressKey(model, "q")) {
+		t.Error("Expected q to quit")
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dh-kam/go-cert-provider/utils"
+	"github.com/spf13/cobra"
+)
+
+// verbosity is the amount of informational output selected by --quiet and --verbose
+type verbosity int
+
+const (
+	verbosityQuiet verbosity = iota
+	verbosityNormal
+	verbosityVerbose
+)
+
+// getVerbosity returns the level selected by the global --quiet and --verbose flags
+func getVerbosity(cmd *cobra.Command) verbosity {
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		return verbosityQuiet
+	}
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+		return verbosityVerbose
+	}
+	return verbosityNormal
+}
+
+// infoOut returns the writer for informational messages such as progress and saved
+// file paths: stderr, or a discarding writer with --quiet. Warnings and errors are
+// written to stderr regardless.
+func infoOut(cmd *cobra.Command) io.Writer {
+	if getVerbosity(cmd) == verbosityQuiet {
+		return io.Discard
+	}
+	return cmd.ErrOrStderr()
+}
+
+// verboseOut returns stderr with --verbose, otherwise a discarding writer
+func verboseOut(cmd *cobra.Command) io.Writer {
+	if getVerbosity(cmd) < verbosityVerbose {
+		return io.Discard
+	}
+	return cmd.ErrOrStderr()
+}
+
+// withVerboseHTTPTiming makes provider API requests made with the command's context
+// print their status and duration when --verbose is set
+func withVerboseHTTPTiming(cmd *cobra.Command) {
+	if getVerbosity(cmd) < verbosityVerbose {
+		return
+	}
+
+	w := cmd.ErrOrStderr()
+	cmd.SetContext(utils.WithHTTPTiming(cmd.Context(), func(method, url string, status int, elapsed time.Duration) {
+		result := fmt.Sprintf("HTTP %d", status)
+		if status == 0 {
+			result = "failed"
+		}
+		fmt.Fprintf(w, "[%s] %s %s: %s in %s\n", utils.FormatCurrentTime(), method, url, result, elapsed.Round(time.Millisecond))
+	}))
+}
@@ -38,6 +38,22 @@ type Mutation struct {
 type Query struct {
 }
 
+type RefreshTokenResponse struct {
+	Success      bool    `json:"success"`
+	Message      string  `json:"message"`
+	AccessToken  *string `json:"accessToken,omitempty"`
+	RefreshToken *string `json:"refreshToken,omitempty"`
+	ExpiresAt    *string `json:"expiresAt,omitempty"`
+}
+
+type ServerInfo struct {
+	Version     string   `json:"version"`
+	BuildTime   string   `json:"buildTime"`
+	GitCommit   string   `json:"gitCommit"`
+	Providers   []string `json:"providers"`
+	DomainCount int32    `json:"domainCount"`
+}
+
 type User struct {
 	ID          string `json:"id"`
 	Description string `json:"description"`
@@ -2,15 +2,26 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/dh-kam/go-cert-provider/audit"
+	"github.com/dh-kam/go-cert-provider/auth"
 	"github.com/dh-kam/go-cert-provider/cert/domain"
 	"github.com/dh-kam/go-cert-provider/cert/registry"
 	"github.com/dh-kam/go-cert-provider/graph/model"
+	"github.com/dh-kam/go-cert-provider/notify"
 	"github.com/dh-kam/go-cert-provider/session"
+	"github.com/dh-kam/go-cert-provider/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
 )
 
 // This file will not be regenerated automatically.
@@ -22,12 +33,83 @@ type Resolver struct{}
 type contextKey string
 
 const (
-	ContextKeyGin          contextKey = "gin"
-	ContextKeyJWTSecret    contextKey = "jwt_secret_key" //nolint:gosec // context key, not a credential
-	ContextKeyCertRegistry contextKey = "cert_registry"
+	ContextKeyGin           contextKey = "gin"
+	ContextKeyJWTSecret     contextKey = "jwt_secret_key" //nolint:gosec // context key, not a credential
+	ContextKeyCertRegistry  contextKey = "cert_registry"
+	ContextKeyJWTValidation contextKey = "jwt_validation"
+	ContextKeyAuditLogger   contextKey = "audit_logger"
+	ContextKeyRateLimiter   contextKey = "rate_limiter"
+	ContextKeyExpiryChecker contextKey = "expiry_checker"
+	ContextKeyRequestID     contextKey = "request_id"
+	ContextKeyJWTClaims     contextKey = "jwt_claims"
 )
 
+// RequestIDFromContext returns the request ID assigned by the server, if any
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(ContextKeyRequestID).(string)
+	return requestID
+}
+
+// PresentError is the GraphQL error presenter. It tags every error with the request ID
+// so clients can quote it when reporting a failed call.
+func PresentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		if gqlErr.Extensions == nil {
+			gqlErr.Extensions = map[string]interface{}{}
+		}
+		gqlErr.Extensions["requestId"] = requestID
+	}
+
+	return gqlErr
+}
+
+// IsIntrospectionQuery reports whether every operation of a GraphQL document only selects
+// introspection fields (__schema, __type, __typename). Unparseable documents are not.
+func IsIntrospectionQuery(query string) bool {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil || len(doc.Operations) == 0 {
+		return false
+	}
+
+	for _, operation := range doc.Operations {
+		if operation.Operation != ast.Query || len(operation.SelectionSet) == 0 {
+			return false
+		}
+		for _, selection := range operation.SelectionSet {
+			field, ok := selection.(*ast.Field)
+			if !ok || !strings.HasPrefix(field.Name, "__") {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// claimsFromContext returns the claims of a bearer token validated by the server middleware, if any
+func claimsFromContext(ctx context.Context) *auth.JWTClaims {
+	claims, _ := ctx.Value(ContextKeyJWTClaims).(*auth.JWTClaims)
+	return claims
+}
+
+// getSessionFromContext returns the caller of the current request. A bearer token already
+// validated by the server middleware takes precedence over the session cookie.
 func getSessionFromContext(ctx context.Context) (*session.UserSession, error) {
+	if claims := claimsFromContext(ctx); claims != nil {
+		userSession := &session.UserSession{
+			UserID:         claims.UserID,
+			Description:    claims.Description,
+			AllowedDomains: claims.AllowedDomains,
+			Scopes:         claims.Scopes,
+		}
+		if claims.ExpiresAt != nil {
+			userSession.ExpireDate = claims.ExpiresAt.Time
+		}
+		return userSession, nil
+	}
+
 	ginCtx, ok := ctx.Value(ContextKeyGin).(*gin.Context)
 	if !ok {
 		return nil, fmt.Errorf("request context is unavailable")
@@ -55,23 +137,108 @@ func getRegistryFromContext(ctx context.Context) (*registry.CertificateProviderR
 	return providerRegistry, nil
 }
 
-func isDomainAllowed(allowedDomains []string, candidate string) bool {
-	for _, allowed := range allowedDomains {
-		if allowed == "*" || allowed == candidate {
-			return true
-		}
+// logAudit records an audit event with the client IP and request ID of the current request.
+// It is a no-op when no audit logger is configured.
+func logAudit(ctx context.Context, event audit.Event) {
+	auditLogger, ok := ctx.Value(ContextKeyAuditLogger).(*audit.Logger)
+	if !ok || auditLogger == nil {
+		return
+	}
 
-		if !strings.HasPrefix(allowed, "*.") {
-			continue
-		}
+	if ginCtx, ok := ctx.Value(ContextKeyGin).(*gin.Context); ok {
+		event.ClientIP = ginCtx.ClientIP()
+	}
+	event.RequestID = RequestIDFromContext(ctx)
 
-		suffix := strings.TrimPrefix(allowed, "*.")
-		if candidate == suffix || strings.HasSuffix(candidate, "."+suffix) {
-			return true
-		}
+	if err := auditLogger.Log(event); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// checkRateLimit enforces the per-user certificate retrieval limit, if one is configured.
+// Exceeding it yields a GraphQL error carrying a retry hint instead of a server error.
+func checkRateLimit(ctx context.Context, userID string) error {
+	rateLimiter, ok := ctx.Value(ContextKeyRateLimiter).(*session.RateLimiter)
+	if !ok || rateLimiter == nil {
+		return nil
+	}
+
+	allowed, retryAfter := rateLimiter.Allow(userID)
+	if allowed {
+		return nil
+	}
+
+	retryAfterSeconds := int(math.Ceil(retryAfter.Seconds()))
+	return &gqlerror.Error{
+		Message: fmt.Sprintf("rate limit exceeded, retry in %d seconds", retryAfterSeconds),
+		Extensions: map[string]interface{}{
+			"code":              "RATE_LIMITED",
+			"retryAfterSeconds": retryAfterSeconds,
+		},
+	}
+}
+
+// domainNotManagedError is the NOT_FOUND GraphQL error for a domain no provider manages,
+// so clients can tell it apart from upstream provider failures
+func domainNotManagedError(domainName string) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message: fmt.Sprintf("domain not managed: %s", domainName),
+		Extensions: map[string]interface{}{
+			"code": "NOT_FOUND",
+		},
+	}
+}
+
+// retrievalError classifies a failed certificate retrieval by its provider-neutral error
+// kind, so clients can tell retryable upstream failures (RATE_LIMITED, UPSTREAM_ERROR) from
+// permanent ones (NOT_FOUND, UNAUTHORIZED, UNSUPPORTED). The original error stays unwrappable.
+func retrievalError(domainName string, err error) *gqlerror.Error {
+	if errors.Is(err, domain.ErrDomainNotManaged) {
+		gqlErr := domainNotManagedError(domainName)
+		gqlErr.Err = err
+		return gqlErr
+	}
+
+	code := "UPSTREAM_ERROR"
+	switch {
+	case errors.Is(err, domain.ErrRateLimited):
+		code = "RATE_LIMITED"
+	case errors.Is(err, domain.ErrCertNotFound):
+		code = "NOT_FOUND"
+	case errors.Is(err, domain.ErrAuthFailed):
+		code = "UNAUTHORIZED"
+	case errors.Is(err, domain.ErrRetrievalUnsupported):
+		code = "UNSUPPORTED"
+	}
+
+	return &gqlerror.Error{
+		Message: fmt.Sprintf("failed to retrieve certificate for %s: %v", domainName, err),
+		Err:     err,
+		Extensions: map[string]interface{}{
+			"code": code,
+		},
+	}
+}
+
+// checkExpiry reports a retrieved certificate nearing expiry to the configured notifier.
+// It is a no-op when no expiry checker is configured or the chain cannot be parsed.
+func checkExpiry(ctx context.Context, providerRegistry *registry.CertificateProviderRegistry, domainName string, certChain []byte) {
+	expiryChecker, ok := ctx.Value(ContextKeyExpiryChecker).(*notify.ExpiryChecker)
+	if !ok || expiryChecker == nil {
+		return
+	}
+
+	leaf, err := utils.ParseLeafCertificate(certChain)
+	if err != nil {
+		return
+	}
+
+	providerName := ""
+	if provider, err := providerRegistry.GetProviderForDomain(domainName); err == nil {
+		providerName = provider.GetProviderName()
 	}
 
-	return false
+	expiryChecker.Check(domainName, providerName, leaf.NotAfter)
 }
 
 func formatOptionalTime(t time.Time) *string {
@@ -1,16 +1,28 @@
 package graph
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/dh-kam/go-cert-provider/audit"
+	"github.com/dh-kam/go-cert-provider/auth"
 	certdomain "github.com/dh-kam/go-cert-provider/cert/domain"
+	"github.com/dh-kam/go-cert-provider/cert/providers/mock"
 	"github.com/dh-kam/go-cert-provider/cert/registry"
+	"github.com/dh-kam/go-cert-provider/notify"
 	"github.com/dh-kam/go-cert-provider/session"
 	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
 type fakeProvider struct {
@@ -19,6 +31,7 @@ type fakeProvider struct {
 	domainInfos map[string]*certdomain.Info
 	certChain   []byte
 	privateKey  []byte
+	retrieveErr error
 }
 
 func (p *fakeProvider) GetProviderName() string {
@@ -42,7 +55,10 @@ func (p *fakeProvider) ListDomainInfo() []certdomain.Info {
 	return result
 }
 
-func (p *fakeProvider) RetrieveCertificate(domain string) ([]byte, []byte, error) {
+func (p *fakeProvider) RetrieveCertificate(ctx context.Context, domain string) ([]byte, []byte, error) {
+	if p.retrieveErr != nil {
+		return nil, nil, p.retrieveErr
+	}
 	return p.certChain, p.privateKey, nil
 }
 
@@ -58,12 +74,15 @@ func makeResolverContext(t *testing.T, allowedDomains []string, provider *fakePr
 	ginCtx, _ := gin.CreateTestContext(recorder)
 	req := httptest.NewRequest("POST", "/graphql", nil)
 
-	sessionID := session.GetGlobalManager().CreateSession(
+	sessionID, err := session.GetGlobalManager().CreateSession(
 		"user-1",
 		"test user",
 		time.Now().Add(time.Hour),
 		allowedDomains,
 	)
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
 	t.Cleanup(func() {
 		session.GetGlobalManager().DeleteSession(sessionID)
 	})
@@ -82,32 +101,6 @@ func makeResolverContext(t *testing.T, allowedDomains []string, provider *fakePr
 	return ctx
 }
 
-func TestIsDomainAllowed(t *testing.T) {
-	t.Parallel()
-
-	tests := []struct {
-		name           string
-		allowedDomains []string
-		candidate      string
-		want           bool
-	}{
-		{name: "exact match", allowedDomains: []string{"example.com"}, candidate: "example.com", want: true},
-		{name: "wildcard suffix", allowedDomains: []string{"*.example.com"}, candidate: "api.example.com", want: true},
-		{name: "wildcard apex", allowedDomains: []string{"*.example.com"}, candidate: "example.com", want: true},
-		{name: "global wildcard", allowedDomains: []string{"*"}, candidate: "anything.com", want: true},
-		{name: "not allowed", allowedDomains: []string{"test.com"}, candidate: "example.com", want: false},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			if got := isDomainAllowed(tt.allowedDomains, tt.candidate); got != tt.want {
-				t.Fatalf("expected %v, got %v", tt.want, got)
-			}
-		})
-	}
-}
-
 func TestDomainsFiltersBySessionAllowedDomains(t *testing.T) {
 	provider := &fakeProvider{
 		name:    "fake",
@@ -174,3 +167,412 @@ func TestCertificateReturnsMaterialForAllowedDomain(t *testing.T) {
 		t.Fatalf("unexpected certificate payload: %+v", result)
 	}
 }
+
+func TestCertificateWritesAuditLog(t *testing.T) {
+	provider := &fakeProvider{
+		name:        "fake",
+		domains:     []string{"example.com", "test.com"},
+		domainInfos: map[string]*certdomain.Info{},
+		certChain:   []byte("cert"),
+		privateKey:  []byte("key"),
+	}
+
+	var buf bytes.Buffer
+	ctx := makeResolverContext(t, []string{"example.com"}, provider)
+	ctx = context.WithValue(ctx, ContextKeyAuditLogger, audit.NewLogger(&buf))
+	ctx = context.WithValue(ctx, ContextKeyRequestID, "req-42")
+
+	resolver := &queryResolver{&Resolver{}}
+	if _, err := resolver.Certificate(ctx, "example.com"); err != nil {
+		t.Fatalf("certificate query failed: %v", err)
+	}
+	if _, err := resolver.Certificate(ctx, "test.com"); err == nil {
+		t.Fatal("expected access denied error")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 audit lines, got %d: %q", len(lines), buf.String())
+	}
+
+	var retrieved, denied audit.Event
+	if err := json.Unmarshal([]byte(lines[0]), &retrieved); err != nil {
+		t.Fatalf("invalid audit line: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &denied); err != nil {
+		t.Fatalf("invalid audit line: %v", err)
+	}
+
+	if retrieved.Event != audit.EventCertificateRetrieved || retrieved.UserID != "user-1" || retrieved.Domain != "example.com" || retrieved.ClientIP == "" || retrieved.RequestID != "req-42" {
+		t.Fatalf("unexpected retrieval event: %+v", retrieved)
+	}
+
+	if denied.Event != audit.EventAuthorizationFailed || denied.Domain != "test.com" || denied.Reason == "" {
+		t.Fatalf("unexpected authorization failure event: %+v", denied)
+	}
+}
+
+func TestScopedTokenCanListButNotRetrieve(t *testing.T) {
+	provider := &fakeProvider{
+		name:    "fake",
+		domains: []string{"example.com", "api.example.com", "test.com"},
+		domainInfos: map[string]*certdomain.Info{
+			"example.com":     {Name: "example.com", Provider: "fake", Status: "ACTIVE"},
+			"api.example.com": {Name: "api.example.com", Provider: "fake", Status: "ACTIVE"},
+			"test.com":        {Name: "test.com", Provider: "fake", Status: "ACTIVE"},
+		},
+		certChain:  []byte("cert"),
+		privateKey: []byte("key"),
+	}
+
+	providerRegistry := registry.NewCertificateProviderRegistry()
+	if err := providerRegistry.Register(provider); err != nil {
+		t.Fatalf("failed to register fake provider: %v", err)
+	}
+
+	// allowed_domains is ignored once scopes are present
+	claims := auth.NewAccessClaims("user-1", "scoped", time.Now().Add(time.Hour), []string{"*"})
+	claims.Scopes = []string{"list:example.com", "retrieve:api.example.com"}
+	ctx := context.WithValue(context.Background(), ContextKeyJWTClaims, claims)
+	ctx = context.WithValue(ctx, ContextKeyCertRegistry, providerRegistry)
+
+	resolver := &queryResolver{&Resolver{}}
+	domains, err := resolver.Domains(ctx)
+	if err != nil {
+		t.Fatalf("domains query failed: %v", err)
+	}
+	if len(domains) != 2 || domains[0].Name != "api.example.com" || domains[1].Name != "example.com" {
+		t.Fatalf("expected the listed and retrievable domains, got %+v", domains)
+	}
+
+	if _, err := resolver.Certificate(ctx, "example.com"); err == nil {
+		t.Fatal("expected a list-only scope to deny retrieval")
+	}
+	if _, err := resolver.Certificate(ctx, "api.example.com"); err != nil {
+		t.Fatalf("expected the retrieve scope to allow retrieval, got %v", err)
+	}
+}
+
+func TestPresentErrorAddsRequestID(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ContextKeyRequestID, "req-42")
+
+	gqlErr := PresentError(ctx, errors.New("authentication required"))
+	if gqlErr.Message != "authentication required" {
+		t.Fatalf("unexpected message: %q", gqlErr.Message)
+	}
+	if gqlErr.Extensions["requestId"] != "req-42" {
+		t.Fatalf("expected requestId extension, got %v", gqlErr.Extensions)
+	}
+
+	// Existing extensions such as the rate limit code are kept
+	coded := &gqlerror.Error{Message: "rate limited", Extensions: map[string]interface{}{"code": "RATE_LIMITED"}}
+	gqlErr = PresentError(ctx, coded)
+	if gqlErr.Extensions["code"] != "RATE_LIMITED" || gqlErr.Extensions["requestId"] != "req-42" {
+		t.Fatalf("unexpected extensions: %v", gqlErr.Extensions)
+	}
+
+	if gqlErr := PresentError(context.Background(), errors.New("boom")); gqlErr.Extensions["requestId"] != nil {
+		t.Fatalf("expected no requestId without one in context, got %v", gqlErr.Extensions)
+	}
+}
+
+func TestCertificateRateLimited(t *testing.T) {
+	provider := &fakeProvider{
+		name:        "fake",
+		domains:     []string{"example.com"},
+		domainInfos: map[string]*certdomain.Info{},
+		certChain:   []byte("cert"),
+		privateKey:  []byte("key"),
+	}
+
+	ctx := makeResolverContext(t, []string{"example.com"}, provider)
+	rateLimiter := session.NewRateLimiter(1)
+	defer rateLimiter.Close()
+	ctx = context.WithValue(ctx, ContextKeyRateLimiter, rateLimiter)
+
+	resolver := &queryResolver{&Resolver{}}
+	if _, err := resolver.Certificate(ctx, "example.com"); err != nil {
+		t.Fatalf("first certificate query failed: %v", err)
+	}
+
+	_, err := resolver.Certificate(ctx, "example.com")
+	if err == nil {
+		t.Fatal("expected rate limit error")
+	}
+
+	var gqlErr *gqlerror.Error
+	if !errors.As(err, &gqlErr) {
+		t.Fatalf("expected GraphQL error, got %T: %v", err, err)
+	}
+
+	if gqlErr.Extensions["code"] != "RATE_LIMITED" {
+		t.Fatalf("expected RATE_LIMITED code, got %v", gqlErr.Extensions["code"])
+	}
+
+	if retryAfter, ok := gqlErr.Extensions["retryAfterSeconds"].(int); !ok || retryAfter <= 0 {
+		t.Fatalf("expected positive retry hint, got %v", gqlErr.Extensions["retryAfterSeconds"])
+	}
+}
+
+func TestCertificateDomainNotManaged(t *testing.T) {
+	provider := &fakeProvider{
+		name:        "fake",
+		domains:     []string{"example.com"},
+		domainInfos: map[string]*certdomain.Info{},
+		certChain:   []byte("cert"),
+		privateKey:  []byte("key"),
+	}
+
+	ctx := makeResolverContext(t, []string{"*"}, provider)
+	resolver := &queryResolver{&Resolver{}}
+
+	_, err := resolver.Certificate(ctx, "unknown.com")
+	var gqlErr *gqlerror.Error
+	if !errors.As(err, &gqlErr) {
+		t.Fatalf("expected GraphQL error, got %T: %v", err, err)
+	}
+	if gqlErr.Extensions["code"] != "NOT_FOUND" {
+		t.Fatalf("expected NOT_FOUND code, got %v", gqlErr.Extensions["code"])
+	}
+}
+
+func TestCertificateClassifiesUpstreamErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"rate limited", fmt.Errorf("SSL retrieval failed: %w", certdomain.ErrRateLimited), "RATE_LIMITED"},
+		{"certificate not found", fmt.Errorf("SSL retrieval failed: %w", certdomain.ErrCertNotFound), "NOT_FOUND"},
+		{"authentication failed", fmt.Errorf("SSL retrieval failed: %w", certdomain.ErrAuthFailed), "UNAUTHORIZED"},
+		{"retrieval unsupported", fmt.Errorf("certificate is managed by Let's Encrypt: %w", certdomain.ErrRetrievalUnsupported), "UNSUPPORTED"},
+		{"unclassified", errors.New("connection reset by peer"), "UPSTREAM_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &fakeProvider{
+				name:        "fake",
+				domains:     []string{"example.com"},
+				domainInfos: map[string]*certdomain.Info{},
+				retrieveErr: tt.err,
+			}
+
+			ctx := makeResolverContext(t, []string{"example.com"}, provider)
+			resolver := &queryResolver{&Resolver{}}
+
+			_, err := resolver.Certificate(ctx, "example.com")
+			var gqlErr *gqlerror.Error
+			if !errors.As(err, &gqlErr) {
+				t.Fatalf("expected GraphQL error, got %T: %v", err, err)
+			}
+			if gqlErr.Extensions["code"] != tt.code {
+				t.Errorf("expected %s code, got %v", tt.code, gqlErr.Extensions["code"])
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("expected the provider error to stay unwrappable, got %v", err)
+			}
+		})
+	}
+}
+
+type recordingNotifier struct {
+	events chan notify.Event
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, event notify.Event) error {
+	n.events <- event
+	return nil
+}
+
+func TestCertificateNotifiesExpiringCertificate(t *testing.T) {
+	// The mock provider issues 90-day certificates, inside a one-year warning window
+	certChain, privateKey, err := mock.NewProvider([]string{"example.com"}, nil, nil).RetrieveCertificate(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("failed to generate certificate: %v", err)
+	}
+
+	provider := &fakeProvider{
+		name:        "fake",
+		domains:     []string{"example.com"},
+		domainInfos: map[string]*certdomain.Info{},
+		certChain:   certChain,
+		privateKey:  privateKey,
+	}
+
+	notifier := &recordingNotifier{events: make(chan notify.Event, 2)}
+	sender := notify.NewAsync(notifier, nil)
+
+	ctx := makeResolverContext(t, []string{"example.com"}, provider)
+	ctx = context.WithValue(ctx, ContextKeyExpiryChecker, notify.NewExpiryChecker(sender, 365*24*time.Hour))
+
+	resolver := &queryResolver{&Resolver{}}
+	for i := 0; i < 2; i++ {
+		if _, err := resolver.Certificate(ctx, "example.com"); err != nil {
+			t.Fatalf("certificate query failed: %v", err)
+		}
+	}
+	sender.Wait()
+
+	if len(notifier.events) != 1 {
+		t.Fatalf("expected 1 expiry notification, got %d", len(notifier.events))
+	}
+
+	event := <-notifier.events
+	if event.Type != notify.EventCertificateExpiring || event.Domain != "example.com" || event.Provider != "fake" || event.NewNotAfter == nil {
+		t.Fatalf("unexpected notification: %+v", event)
+	}
+}
+
+func TestServerInfoReportsProvidersAndDomainCount(t *testing.T) {
+	providerRegistry := registry.NewCertificateProviderRegistry()
+	for _, provider := range []*fakeProvider{
+		{name: "zeta", domains: []string{"a.com", "b.com"}},
+		{name: "alpha", domains: []string{"c.com"}},
+	} {
+		if err := providerRegistry.Register(provider); err != nil {
+			t.Fatalf("failed to register fake provider: %v", err)
+		}
+	}
+
+	// No session is needed: server metadata is public
+	ctx := context.WithValue(context.Background(), ContextKeyCertRegistry, providerRegistry)
+
+	resolver := &queryResolver{&Resolver{}}
+	info, err := resolver.ServerInfo(ctx)
+	if err != nil {
+		t.Fatalf("serverInfo query failed: %v", err)
+	}
+
+	if len(info.Providers) != 2 || info.Providers[0] != "alpha" || info.Providers[1] != "zeta" {
+		t.Fatalf("expected sorted providers [alpha zeta], got %v", info.Providers)
+	}
+
+	if info.DomainCount != 3 {
+		t.Fatalf("expected 3 domains, got %d", info.DomainCount)
+	}
+
+	if info.Version == "" || info.BuildTime == "" || info.GitCommit == "" {
+		t.Fatalf("expected build metadata, got %+v", info)
+	}
+}
+
+func TestRefreshTokenIssuesAccessToken(t *testing.T) {
+	secretKey := "test-secret-key-32-bytes-long!!"
+	refreshToken, err := auth.CreateRefreshJWT("user-1", "test user", time.Now().Add(time.Hour), []string{"example.com"}, secretKey)
+	if err != nil {
+		t.Fatalf("failed to create refresh token: %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), ContextKeyJWTSecret, secretKey)
+	resolver := &mutationResolver{&Resolver{}}
+
+	result, err := resolver.RefreshToken(ctx, refreshToken)
+	if err != nil {
+		t.Fatalf("refreshToken mutation failed: %v", err)
+	}
+
+	if !result.Success || result.AccessToken == nil || result.RefreshToken == nil {
+		t.Fatalf("unexpected refresh payload: %+v", result)
+	}
+
+	claims, err := auth.ParseJWT(*result.AccessToken, secretKey)
+	if err != nil {
+		t.Fatalf("issued access token should verify: %v", err)
+	}
+
+	if len(claims.AllowedDomains) != 1 || claims.AllowedDomains[0] != "example.com" {
+		t.Fatalf("expected allowed domains to carry over, got %v", claims.AllowedDomains)
+	}
+
+	reused, err := resolver.RefreshToken(ctx, refreshToken)
+	if err != nil {
+		t.Fatalf("refreshToken mutation failed: %v", err)
+	}
+
+	if reused.Success {
+		t.Fatal("expected reused refresh token to be rejected")
+	}
+}
+
+func TestIsIntrospectionQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{name: "schema", query: `query IntrospectionQuery { __schema { queryType { name } } }`, want: true},
+		{name: "type and typename", query: `{ __type(name: "Domain") { name } __typename }`, want: true},
+		{name: "data field", query: `{ domains { name } }`, want: false},
+		{name: "mixed", query: `{ __schema { types { name } } certificate(domain: "example.com") { privateKey } }`, want: false},
+		{name: "mutation", query: `mutation { __typename }`, want: false},
+		{name: "fragment spread", query: `{ ...F } fragment F on Query { domains { name } }`, want: false},
+		{name: "invalid", query: `{ __schema `, want: false},
+		{name: "empty", query: ``, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsIntrospectionQuery(tt.query); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDomainsUsesBearerClaims(t *testing.T) {
+	provider := &fakeProvider{
+		name:    "fake",
+		domains: []string{"example.com", "test.com"},
+		domainInfos: map[string]*certdomain.Info{
+			"example.com": {Name: "example.com", Provider: "fake", Status: "ACTIVE"},
+			"test.com":    {Name: "test.com", Provider: "fake", Status: "ACTIVE"},
+		},
+	}
+
+	providerRegistry := registry.NewCertificateProviderRegistry()
+	if err := providerRegistry.Register(provider); err != nil {
+		t.Fatalf("failed to register fake provider: %v", err)
+	}
+
+	// No session cookie: the claims validated by the server middleware identify the caller
+	claims := auth.NewAccessClaims("ci", "CI runner", time.Now().Add(time.Hour), []string{"test.com"})
+	ctx := context.WithValue(context.Background(), ContextKeyJWTClaims, claims)
+	ctx = context.WithValue(ctx, ContextKeyCertRegistry, providerRegistry)
+
+	resolver := &queryResolver{&Resolver{}}
+	domains, err := resolver.Domains(ctx)
+	if err != nil {
+		t.Fatalf("domains query failed: %v", err)
+	}
+	if len(domains) != 1 || domains[0].Name != "test.com" {
+		t.Fatalf("expected only test.com, got %v", domains)
+	}
+
+	user, err := resolver.Me(ctx)
+	if err != nil || user == nil || user.ID != "ci" {
+		t.Fatalf("expected me to return ci, got %v (err: %v)", user, err)
+	}
+}
+
+func TestSchemaSDL(t *testing.T) {
+	sdl := SchemaSDL()
+
+	for _, expected := range []string{
+		"certificate(domain: String!): CertificateBundle!",
+		"login(input: LoginInput!): LoginResponse!",
+		"type Domain {",
+	} {
+		if !strings.Contains(sdl, expected) {
+			t.Errorf("expected SDL to contain %q", expected)
+		}
+	}
+
+	// The output must be valid SDL that client codegen can load
+	if _, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphqls", Input: sdl}); err != nil {
+		t.Fatalf("SDL does not parse: %v", err)
+	}
+}
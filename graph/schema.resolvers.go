@@ -7,12 +7,15 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
 	"time"
 
+	"github.com/dh-kam/go-cert-provider/audit"
 	"github.com/dh-kam/go-cert-provider/auth"
+	certdomain "github.com/dh-kam/go-cert-provider/cert/domain"
 	"github.com/dh-kam/go-cert-provider/config"
 	"github.com/dh-kam/go-cert-provider/graph/generated"
 	"github.com/dh-kam/go-cert-provider/graph/model"
@@ -22,12 +25,14 @@ import (
 
 // Login is the resolver for the login field.
 func (r *mutationResolver) Login(ctx context.Context, input model.LoginInput) (*model.LoginResponse, error) {
-	// Get JWT secret key from context
+	// Get JWT secret key and validation options from context
 	jwtSecretKey, _ := ctx.Value(ContextKeyJWTSecret).(string)
+	validationOptions, _ := ctx.Value(ContextKeyJWTValidation).(auth.ValidationOptions)
 
 	// Parse JWT token
-	claims, err := auth.ParseJWT(input.APIKey, jwtSecretKey)
+	claims, err := auth.ParseJWTWithOptions(input.APIKey, jwtSecretKey, validationOptions)
 	if err != nil {
+		logAudit(ctx, audit.Event{Event: audit.EventLoginFailed, Reason: err.Error()})
 		return &model.LoginResponse{
 			Success: false,
 			Message: fmt.Sprintf("Invalid API key: %v", err),
@@ -37,12 +42,21 @@ func (r *mutationResolver) Login(ctx context.Context, input model.LoginInput) (*
 
 	// Create session
 	sessionManager := session.GetGlobalManager()
-	sessionID := sessionManager.CreateSession(
+	sessionID, err := sessionManager.CreateSessionWithScopes(
 		claims.UserID,
 		claims.Description,
 		claims.ExpiresAt.Time,
 		claims.AllowedDomains,
+		claims.Scopes,
 	)
+	if err != nil {
+		logAudit(ctx, audit.Event{Event: audit.EventLoginFailed, UserID: claims.UserID, Reason: err.Error()})
+		return &model.LoginResponse{
+			Success: false,
+			Message: fmt.Sprintf("Login refused: %v", err),
+			User:    nil,
+		}, nil
+	}
 
 	// Set cookie if we can access the gin context
 	if ginCtx, ok := ctx.Value(ContextKeyGin).(*gin.Context); ok {
@@ -95,6 +109,32 @@ func (r *mutationResolver) Logout(ctx context.Context) (bool, error) {
 	return true, nil
 }
 
+// RefreshToken is the resolver for the refreshToken field.
+func (r *mutationResolver) RefreshToken(ctx context.Context, refreshToken string) (*model.RefreshTokenResponse, error) {
+	// Get JWT secret key and validation options from context
+	jwtSecretKey, _ := ctx.Value(ContextKeyJWTSecret).(string)
+	validationOptions, _ := ctx.Value(ContextKeyJWTValidation).(auth.ValidationOptions)
+
+	// Exchange the refresh token, rotating it in the global store
+	pair, err := auth.GetGlobalRefreshTokenStore().Exchange(refreshToken, jwtSecretKey, auth.DefaultAccessTokenTTL, validationOptions)
+	if err != nil {
+		return &model.RefreshTokenResponse{
+			Success: false,
+			Message: fmt.Sprintf("Invalid refresh token: %v", err),
+		}, nil
+	}
+
+	expiresAt := pair.AccessExpiresAt.Format(time.RFC3339)
+
+	return &model.RefreshTokenResponse{
+		Success:      true,
+		Message:      "Token refreshed",
+		AccessToken:  &pair.AccessToken,
+		RefreshToken: &pair.RefreshToken,
+		ExpiresAt:    &expiresAt,
+	}, nil
+}
+
 // Health is the resolver for the health field.
 func (r *queryResolver) Health(ctx context.Context) (*model.Health, error) {
 	return &model.Health{
@@ -112,8 +152,34 @@ func (r *queryResolver) Version(ctx context.Context) (*model.Version, error) {
 	}, nil
 }
 
+// ServerInfo is the resolver for the serverInfo field.
+func (r *queryResolver) ServerInfo(ctx context.Context) (*model.ServerInfo, error) {
+	providerRegistry, err := getRegistryFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	providers := providerRegistry.ListProviders()
+	sort.Strings(providers)
+
+	return &model.ServerInfo{
+		Version:     config.Version,
+		BuildTime:   config.BuildTime,
+		GitCommit:   config.GitCommit,
+		Providers:   providers,
+		DomainCount: int32(len(providerRegistry.ListDomains())),
+	}, nil
+}
+
 // Me is the resolver for the me field.
 func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
+	if claims := claimsFromContext(ctx); claims != nil {
+		return &model.User{
+			ID:          claims.UserID,
+			Description: claims.Description,
+		}, nil
+	}
+
 	// Get session ID from cookie if available
 	if ginCtx, ok := ctx.Value(ContextKeyGin).(*gin.Context); ok {
 		sessionID, err := ginCtx.Cookie("session_id")
@@ -153,7 +219,7 @@ func (r *queryResolver) Domains(ctx context.Context) ([]*model.Domain, error) {
 	result := make([]*model.Domain, 0, len(allDomainInfo))
 
 	for _, info := range allDomainInfo {
-		if isDomainAllowed(userSession.AllowedDomains, info.Name) {
+		if auth.ScopesAllow(userSession.AllowedDomains, userSession.Scopes, auth.ScopeList, info.Name) {
 			result = append(result, toDomainModel(info))
 		}
 	}
@@ -169,23 +235,36 @@ func (r *queryResolver) Domains(ctx context.Context) ([]*model.Domain, error) {
 func (r *queryResolver) Certificate(ctx context.Context, domain string) (*model.CertificateBundle, error) {
 	userSession, err := getSessionFromContext(ctx)
 	if err != nil {
+		logAudit(ctx, audit.Event{Event: audit.EventAuthorizationFailed, Domain: domain, Reason: err.Error()})
 		return nil, err
 	}
 
-	if !isDomainAllowed(userSession.AllowedDomains, domain) {
+	if allowed, reason := auth.ExplainScope(userSession.AllowedDomains, userSession.Scopes, auth.ScopeRetrieve, domain); !allowed {
+		logAudit(ctx, audit.Event{Event: audit.EventAuthorizationFailed, UserID: userSession.UserID, Domain: domain, Reason: reason})
 		return nil, fmt.Errorf("access denied for domain: %s", domain)
 	}
 
+	if err := checkRateLimit(ctx, userSession.UserID); err != nil {
+		return nil, err
+	}
+
 	providerRegistry, err := getRegistryFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	certChain, privateKey, err := providerRegistry.RetrieveCertificate(domain)
+	certChain, privateKey, err := providerRegistry.RetrieveCertificate(ctx, domain)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, certdomain.ErrReadOnly) {
+			logAudit(ctx, audit.Event{Event: audit.EventAuthorizationFailed, UserID: userSession.UserID, Domain: domain, Reason: "read-only mode"})
+			return nil, err
+		}
+		return nil, retrievalError(domain, err)
 	}
 
+	logAudit(ctx, audit.Event{Event: audit.EventCertificateRetrieved, UserID: userSession.UserID, Domain: domain})
+	checkExpiry(ctx, providerRegistry, domain, certChain)
+
 	return &model.CertificateBundle{
 		Domain:           domain,
 		CertificateChain: string(certChain),
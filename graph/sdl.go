@@ -0,0 +1,18 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/dh-kam/go-cert-provider/graph/generated"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// SchemaSDL returns the GraphQL schema served by the API in SDL form, rendered from the
+// in-process executable schema (built-in scalars and directives are omitted)
+func SchemaSDL() string {
+	schema := generated.NewExecutableSchema(generated.Config{Resolvers: &Resolver{}}).Schema()
+
+	var sdl strings.Builder
+	formatter.NewFormatter(&sdl, formatter.WithIndent("  ")).FormatSchema(schema)
+	return sdl.String()
+}
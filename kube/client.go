@@ -0,0 +1,149 @@
+// Package kube stores certificates in Kubernetes Secrets through client-go
+package kube
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const defaultRequestTimeout = 30 * time.Second
+
+// NewTLSSecret builds a TLS Secret holding certChain as tls.crt and privateKey as tls.key.
+// caChain, if not empty, is stored as ca.crt.
+func NewTLSSecret(namespace, name string, certChain, privateKey, caChain []byte) *corev1.Secret {
+	data := map[string][]byte{
+		corev1.TLSCertKey:       certChain,
+		corev1.TLSPrivateKeyKey: privateKey,
+	}
+	if len(caChain) > 0 {
+		data["ca.crt"] = caChain
+	}
+
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"app.kubernetes.io/managed-by": "go-cert-provider"},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: data,
+	}
+}
+
+// ApplyResult describes what ApplySecret did
+type ApplyResult string
+
+const (
+	// SecretCreated means the Secret did not exist and was created
+	SecretCreated ApplyResult = "created"
+	// SecretUpdated means the Secret existed with different data and was patched
+	SecretUpdated ApplyResult = "updated"
+	// SecretUnchanged means the Secret already held the same data and was left alone
+	SecretUnchanged ApplyResult = "unchanged"
+)
+
+// Client writes TLS Secrets through the Kubernetes API
+type Client struct {
+	clientset kubernetes.Interface
+}
+
+// NewClient creates a client for the API server described by cfg
+func NewClient(cfg *rest.Config) (*Client, error) {
+	cfg = rest.CopyConfig(cfg)
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultRequestTimeout
+	}
+
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	return NewClientForClientset(clientset), nil
+}
+
+// NewClientForClientset creates a client using an existing clientset, such as a fake in tests
+func NewClientForClientset(clientset kubernetes.Interface) *Client {
+	return &Client{clientset: clientset}
+}
+
+// ApplySecret creates the Secret or replaces the data of an existing one. An existing
+// Secret that already holds the same data is not written. Only the data of an existing
+// Secret is changed, so its annotations, owner references and finalizers are kept.
+func (c *Client) ApplySecret(ctx context.Context, secret *corev1.Secret) (ApplyResult, error) {
+	namespace, name := secret.Namespace, secret.Name
+	secrets := c.clientset.CoreV1().Secrets(namespace)
+
+	existing, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to create secret %s/%s: %w", namespace, name, err)
+		}
+		return SecretCreated, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s/%s: %w", namespace, name, err)
+	}
+
+	if existing.Type != secret.Type {
+		return "", fmt.Errorf("secret %s/%s has type %q, not %q; the type of a secret cannot be changed", namespace, name, existing.Type, secret.Type)
+	}
+	if sameData(existing.Data, secret.Data) {
+		return SecretUnchanged, nil
+	}
+
+	patch, err := dataMergePatch(existing, secret.Data)
+	if err != nil {
+		return "", err
+	}
+	if _, err := secrets.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return "", fmt.Errorf("failed to update secret %s/%s: %w", namespace, name, err)
+	}
+	return SecretUpdated, nil
+}
+
+// dataMergePatch builds a JSON merge patch replacing the data of existing with data.
+// Keys missing from data are removed. The resource version makes the patch fail
+// instead of overwriting a concurrent change.
+func dataMergePatch(existing *corev1.Secret, data map[string][]byte) ([]byte, error) {
+	patchData := make(map[string]interface{}, len(data))
+	for key := range existing.Data {
+		patchData[key] = nil
+	}
+	for key, value := range data {
+		patchData[key] = value
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"resourceVersion": existing.ResourceVersion},
+		"data":     patchData,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode patch: %w", err)
+	}
+	return patch, nil
+}
+
+// sameData reports whether two Secret data maps hold the same keys and values
+func sameData(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		other, ok := b[key]
+		if !ok || !bytes.Equal(value, other) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,186 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// countWrites returns the number of create, update and patch actions the fake clientset saw
+func countWrites(clientset *fake.Clientset) int {
+	writes := 0
+	for _, action := range clientset.Actions() {
+		switch action.GetVerb() {
+		case "create", "update", "patch":
+			writes++
+		}
+	}
+	return writes
+}
+
+func TestApplySecret(t *testing.T) {
+	clientset := fake.NewClientset()
+	client := NewClientForClientset(clientset)
+	ctx := context.Background()
+
+	steps := []struct {
+		name   string
+		cert   string
+		ca     string
+		want   ApplyResult
+		writes int
+	}{
+		{"missing secret is created", "cert-1", "ca-1", SecretCreated, 1},
+		{"same content is not written", "cert-1", "ca-1", SecretUnchanged, 1},
+		{"new content is updated", "cert-2", "", SecretUpdated, 2},
+	}
+
+	for _, step := range steps {
+		secret := NewTLSSecret("web", "tls", []byte(step.cert), []byte("key"), []byte(step.ca))
+		result, err := client.ApplySecret(ctx, secret)
+		if err != nil {
+			t.Fatalf("%s: ApplySecret failed: %v", step.name, err)
+		}
+		if result != step.want {
+			t.Errorf("%s: result = %s, want %s", step.name, result, step.want)
+		}
+		if writes := countWrites(clientset); writes != step.writes {
+			t.Errorf("%s: %d writes, want %d", step.name, writes, step.writes)
+		}
+	}
+
+	stored, err := clientset.CoreV1().Secrets("web").Get(ctx, "tls", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	want := map[string][]byte{"tls.crt": []byte("cert-2"), "tls.key": []byte("key")}
+	if stored.Type != corev1.SecretTypeTLS || !reflect.DeepEqual(stored.Data, want) {
+		t.Errorf("Unexpected stored secret: type %s, data %v", stored.Type, stored.Data)
+	}
+}
+
+func TestApplySecretKeepsMetadata(t *testing.T) {
+	existing := NewTLSSecret("web", "tls", []byte("old-cert"), []byte("old-key"), nil)
+	existing.Annotations = map[string]string{"cert-manager.io/issuer-name": "letsencrypt"}
+	existing.Finalizers = []string{"example.com/protect"}
+	existing.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "1234"}}
+	existing.Labels["app.kubernetes.io/instance"] = "web"
+
+	clientset := fake.NewClientset(existing)
+	client := NewClientForClientset(clientset)
+	ctx := context.Background()
+
+	result, err := client.ApplySecret(ctx, NewTLSSecret("web", "tls", []byte("new-cert"), []byte("new-key"), nil))
+	if err != nil {
+		t.Fatalf("ApplySecret failed: %v", err)
+	}
+	if result != SecretUpdated {
+		t.Errorf("Result = %s, want %s", result, SecretUpdated)
+	}
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "update" {
+			t.Error("Expected a patch, not a full update of the secret")
+		}
+		if patch, ok := action.(k8stesting.PatchAction); ok && patch.GetPatchType() != "application/merge-patch+json" {
+			t.Errorf("Expected a JSON merge patch, got %s", patch.GetPatchType())
+		}
+	}
+
+	stored, err := clientset.CoreV1().Secrets("web").Get(ctx, "tls", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if string(stored.Data["tls.crt"]) != "new-cert" || string(stored.Data["tls.key"]) != "new-key" {
+		t.Errorf("Data not updated: %v", stored.Data)
+	}
+	if !reflect.DeepEqual(stored.Annotations, existing.Annotations) ||
+		!reflect.DeepEqual(stored.Finalizers, existing.Finalizers) ||
+		!reflect.DeepEqual(stored.OwnerReferences, existing.OwnerReferences) ||
+		!reflect.DeepEqual(stored.Labels, existing.Labels) {
+		t.Errorf("Metadata not preserved: %+v", stored.ObjectMeta)
+	}
+}
+
+func TestApplySecretRejectsOtherType(t *testing.T) {
+	clientset := fake.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "web"},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{"password": []byte("x")},
+	})
+	client := NewClientForClientset(clientset)
+
+	if _, err := client.ApplySecret(context.Background(), NewTLSSecret("web", "tls", []byte("cert"), []byte("key"), nil)); err == nil {
+		t.Error("Expected an error when replacing an Opaque secret")
+	}
+	if writes := countWrites(clientset); writes != 0 {
+		t.Errorf("Expected no writes, got %d", writes)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte("file-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	kubeconfig := `apiVersion: v1
+kind: Config
+current-context: %s
+contexts:
+- name: prod
+  context: {cluster: prod, user: eks}
+- name: dev
+  context: {cluster: dev, user: ci}
+clusters:
+- name: prod
+  cluster: {server: "https://prod.example.com"}
+- name: dev
+  cluster:
+    server: https://dev.example.com:6443
+    insecure-skip-tls-verify: true
+users:
+- name: eks
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: aws
+      args: [eks, get-token, --cluster-name, prod]
+- name: ci
+  user:
+    tokenFile: token
+`
+	writeKubeconfig := func(currentContext string) string {
+		path := filepath.Join(dir, currentContext)
+		if err := os.WriteFile(path, []byte(fmt.Sprintf(kubeconfig, currentContext)), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	cfg, err := LoadConfig(writeKubeconfig("dev"))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Host != "https://dev.example.com:6443" || !cfg.Insecure || cfg.BearerTokenFile != filepath.Join(dir, "token") {
+		t.Errorf("Unexpected config: host %s, insecure %v, token file %s", cfg.Host, cfg.Insecure, cfg.BearerTokenFile)
+	}
+
+	// Exec plugins, as used by EKS and GKE, are accepted
+	cfg, err = LoadConfig(writeKubeconfig("prod"))
+	if err != nil {
+		t.Fatalf("LoadConfig with an exec plugin failed: %v", err)
+	}
+	if cfg.ExecProvider == nil || cfg.ExecProvider.Command != "aws" {
+		t.Errorf("Expected the exec plugin to be configured, got %+v", cfg.ExecProvider)
+	}
+}
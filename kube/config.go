@@ -0,0 +1,32 @@
+package kube
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// LoadConfig returns the in-cluster configuration when running in a pod and no kubeconfig
+// path is given, and otherwise reads the kubeconfig from path, or else from KUBECONFIG or
+// ~/.kube/config as kubectl does. All kubeconfig credentials are supported, including the
+// exec and auth-provider plugins used by EKS and GKE.
+func LoadConfig(kubeconfigPath string) (*rest.Config, error) {
+	if kubeconfigPath == "" && os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		cfg, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load in-cluster configuration: %w", err)
+		}
+		return cfg, nil
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfigPath
+
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	return cfg, nil
+}
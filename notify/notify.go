@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// EventCertificateRenewed is sent after a certificate was replaced with a newer one
+	EventCertificateRenewed = "certificate_renewed"
+	// EventCertificateExpiring is sent when a certificate expires within the warning threshold
+	EventCertificateExpiring = "certificate_expiring"
+)
+
+// Event describes a certificate lifecycle event
+type Event struct {
+	Type        string     `json:"event"`
+	Time        time.Time  `json:"time"`
+	Domain      string     `json:"domain"`
+	Provider    string     `json:"provider,omitempty"`
+	OldNotAfter *time.Time `json:"old_not_after,omitempty"`
+	NewNotAfter *time.Time `json:"new_not_after,omitempty"`
+}
+
+// Notifier delivers certificate events to an external system.
+// Implementations may block while delivering; use Async to decouple them from the caller.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// Async delivers events in the background so slow or failing notifiers never stall the caller
+type Async struct {
+	notifier Notifier
+	onError  func(error)
+	timeout  time.Duration
+	wg       sync.WaitGroup
+}
+
+// NewAsync wraps notifier; onError, if not nil, receives delivery failures
+func NewAsync(notifier Notifier, onError func(error)) *Async {
+	return &Async{
+		notifier: notifier,
+		onError:  onError,
+		timeout:  time.Minute,
+	}
+}
+
+// Send queues an event for delivery and returns immediately
+func (a *Async) Send(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+		defer cancel()
+
+		if err := a.notifier.Notify(ctx, event); err != nil && a.onError != nil {
+			a.onError(fmt.Errorf("failed to deliver %s event for %s: %w", event.Type, event.Domain, err))
+		}
+	}()
+}
+
+// Wait blocks until all queued events have been delivered or given up on
+func (a *Async) Wait() {
+	a.wg.Wait()
+}
+
+// ExpiryChecker sends one expiring event per certificate that is within the threshold
+type ExpiryChecker struct {
+	sender    *Async
+	threshold time.Duration
+	notified  map[string]time.Time // key: domain, value: NotAfter already reported
+	mutex     sync.Mutex
+}
+
+// NewExpiryChecker creates a checker that warns about certificates expiring within threshold
+func NewExpiryChecker(sender *Async, threshold time.Duration) *ExpiryChecker {
+	return &ExpiryChecker{
+		sender:    sender,
+		threshold: threshold,
+		notified:  make(map[string]time.Time),
+	}
+}
+
+// Check sends an expiring event if notAfter is within the threshold and this certificate
+// has not been reported yet. It reports whether an event was sent.
+func (c *ExpiryChecker) Check(domain, provider string, notAfter time.Time) bool {
+	if time.Until(notAfter) > c.threshold {
+		return false
+	}
+
+	c.mutex.Lock()
+	if reported, exists := c.notified[domain]; exists && reported.Equal(notAfter) {
+		c.mutex.Unlock()
+		return false
+	}
+	c.notified[domain] = notAfter
+	c.mutex.Unlock()
+
+	c.sender.Send(Event{
+		Type:        EventCertificateExpiring,
+		Domain:      domain,
+		Provider:    provider,
+		NewNotAfter: &notAfter,
+	})
+	return true
+}
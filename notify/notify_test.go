@@ -0,0 +1,201 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type recordingNotifier struct {
+	mutex  sync.Mutex
+	events []Event
+	err    error
+	block  chan struct{}
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, event Event) error {
+	if n.block != nil {
+		<-n.block
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.events = append(n.events, event)
+	return n.err
+}
+
+func (n *recordingNotifier) Events() []Event {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return append([]Event(nil), n.events...)
+}
+
+func newTestWebhook(url string) *Webhook {
+	webhook := NewWebhook(url)
+	webhook.backoff = time.Millisecond
+	return webhook
+}
+
+func TestWebhookPostsJSON(t *testing.T) {
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected JSON content type, got %q", r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	oldNotAfter := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newNotAfter := oldNotAfter.Add(90 * 24 * time.Hour)
+
+	err := newTestWebhook(server.URL).Notify(context.Background(), Event{
+		Type:        EventCertificateRenewed,
+		Domain:      "example.com",
+		Provider:    "porkbun",
+		OldNotAfter: &oldNotAfter,
+		NewNotAfter: &newNotAfter,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if received.Type != EventCertificateRenewed || received.Domain != "example.com" || received.Provider != "porkbun" {
+		t.Errorf("Unexpected payload: %+v", received)
+	}
+	if received.OldNotAfter == nil || !received.OldNotAfter.Equal(oldNotAfter) {
+		t.Errorf("Expected old NotAfter %v, got %v", oldNotAfter, received.OldNotAfter)
+	}
+	if received.NewNotAfter == nil || !received.NewNotAfter.Equal(newNotAfter) {
+		t.Errorf("Expected new NotAfter %v, got %v", newNotAfter, received.NewNotAfter)
+	}
+}
+
+func TestWebhookRetriesTransientFailures(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if err := newTestWebhook(server.URL).Notify(context.Background(), Event{Type: EventCertificateExpiring}); err != nil {
+		t.Fatalf("Expected delivery on third attempt, got: %v", err)
+	}
+
+	if attempts.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts.Load())
+	}
+}
+
+func TestWebhookRetriesAreBounded(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if err := newTestWebhook(server.URL).Notify(context.Background(), Event{Type: EventCertificateExpiring}); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if attempts.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts.Load())
+	}
+}
+
+func TestWebhookDoesNotRetryClientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	if err := newTestWebhook(server.URL).Notify(context.Background(), Event{Type: EventCertificateExpiring}); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if attempts.Load() != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts.Load())
+	}
+}
+
+func TestAsyncDoesNotBlockCaller(t *testing.T) {
+	notifier := &recordingNotifier{block: make(chan struct{}), err: errors.New("boom")}
+
+	var reported atomic.Int32
+	sender := NewAsync(notifier, func(err error) {
+		reported.Add(1)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		sender.Send(Event{Type: EventCertificateRenewed, Domain: "example.com"})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Send blocked on a slow notifier")
+	}
+
+	close(notifier.block)
+	sender.Wait()
+
+	events := notifier.Events()
+	if len(events) != 1 || events[0].Time.IsZero() {
+		t.Fatalf("Expected one time-stamped event, got %+v", events)
+	}
+	if reported.Load() != 1 {
+		t.Errorf("Expected the delivery error to be reported once, got %d", reported.Load())
+	}
+}
+
+func TestExpiryCheckerNotifiesOncePerCertificate(t *testing.T) {
+	notifier := &recordingNotifier{}
+	sender := NewAsync(notifier, nil)
+	checker := NewExpiryChecker(sender, 30*24*time.Hour)
+
+	expiringSoon := time.Now().Add(10 * 24 * time.Hour)
+	farAway := time.Now().Add(60 * 24 * time.Hour)
+
+	if checker.Check("valid.com", "mock", farAway) {
+		t.Error("Certificate outside the threshold should not be reported")
+	}
+	if !checker.Check("example.com", "mock", expiringSoon) {
+		t.Error("Certificate within the threshold should be reported")
+	}
+	if checker.Check("example.com", "mock", expiringSoon) {
+		t.Error("The same certificate should only be reported once")
+	}
+	if !checker.Check("example.com", "mock", expiringSoon.Add(time.Hour)) {
+		t.Error("A different certificate for the domain should be reported again")
+	}
+
+	sender.Wait()
+
+	events := notifier.Events()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	for _, event := range events {
+		if event.Type != EventCertificateExpiring || event.Domain != "example.com" || event.NewNotAfter == nil {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Webhook posts events as JSON to a URL, retrying transient failures a bounded number of times
+type Webhook struct {
+	url         string
+	httpClient  *http.Client
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// NewWebhook creates a webhook notifier posting to url
+func NewWebhook(url string) *Webhook {
+	return &Webhook{
+		url: url,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		maxAttempts: 3,
+		backoff:     2 * time.Second,
+	}
+}
+
+// Notify posts the event, retrying network errors, 429 and 5xx responses with exponential backoff
+func (w *Webhook) Notify(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	backoff := w.backoff
+	var lastErr error
+	for attempt := 1; attempt <= w.maxAttempts; attempt++ {
+		retryable, err := w.post(ctx, payload)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retryable || attempt == w.maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("webhook delivery cancelled: %w", lastErr)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	return lastErr
+}
+
+func (w *Webhook) post(ctx context.Context, payload []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
@@ -0,0 +1,109 @@
+package session
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits requests per user with a token bucket.
+// Each user may burst up to the per-minute limit, refilled continuously.
+type RateLimiter struct {
+	ratePerSecond float64
+	burst         float64
+	buckets       map[string]*tokenBucket
+	done          chan struct{}
+	closeOnce     sync.Once
+	mutex         sync.Mutex
+}
+
+// tokenBucket holds the remaining tokens for one user
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewRateLimiter creates a rate limiter allowing requestsPerMinute requests per user and
+// starts its background cleanup, which runs until Close is called
+func NewRateLimiter(requestsPerMinute int) *RateLimiter {
+	rl := &RateLimiter{
+		ratePerSecond: float64(requestsPerMinute) / 60,
+		burst:         float64(requestsPerMinute),
+		buckets:       make(map[string]*tokenBucket),
+		done:          make(chan struct{}),
+	}
+
+	// Start cleanup routine for inactive users
+	go rl.cleanupInactiveBuckets()
+
+	return rl
+}
+
+// Close stops the background cleanup. Allow keeps working; calling Close again is a no-op.
+func (rl *RateLimiter) Close() {
+	rl.closeOnce.Do(func() {
+		close(rl.done)
+	})
+}
+
+// Allow consumes a token for the user. When the limit is exceeded it returns false
+// and the time until the next request would be allowed.
+func (rl *RateLimiter) Allow(userID string) (bool, time.Duration) {
+	return rl.allowAt(userID, time.Now())
+}
+
+func (rl *RateLimiter) allowAt(userID string, now time.Time) (bool, time.Duration) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	bucket, exists := rl.buckets[userID]
+	if !exists {
+		bucket = &tokenBucket{tokens: rl.burst, lastRefill: now}
+		rl.buckets[userID] = bucket
+	}
+
+	elapsed := now.Sub(bucket.lastRefill).Seconds()
+	if elapsed > 0 {
+		bucket.tokens = math.Min(rl.burst, bucket.tokens+elapsed*rl.ratePerSecond)
+		bucket.lastRefill = now
+	}
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+
+	retryAfter := time.Duration((1 - bucket.tokens) / rl.ratePerSecond * float64(time.Second))
+	return false, retryAfter
+}
+
+// CleanupInactiveBuckets removes users whose bucket has refilled completely (for testing)
+func (rl *RateLimiter) CleanupInactiveBuckets() {
+	rl.cleanupAt(time.Now())
+}
+
+// cleanupAt drops buckets that would be full at now; they are indistinguishable from new ones
+func (rl *RateLimiter) cleanupAt(now time.Time) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	for userID, bucket := range rl.buckets {
+		if bucket.tokens+now.Sub(bucket.lastRefill).Seconds()*rl.ratePerSecond >= rl.burst {
+			delete(rl.buckets, userID)
+		}
+	}
+}
+
+func (rl *RateLimiter) cleanupInactiveBuckets() {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rl.done:
+			return
+		case <-ticker.C:
+			rl.CleanupInactiveBuckets()
+		}
+	}
+}
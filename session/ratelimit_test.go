@@ -0,0 +1,103 @@
+package session
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_AllowsBurstThenLimits(t *testing.T) {
+	rl := NewRateLimiter(3)
+	defer rl.Close()
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if allowed, _ := rl.allowAt("user", now); !allowed {
+			t.Fatalf("Request %d should be allowed", i+1)
+		}
+	}
+
+	allowed, retryAfter := rl.allowAt("user", now)
+	if allowed {
+		t.Fatal("Request beyond the limit should be refused")
+	}
+
+	// 3 requests per minute refill one token every 20 seconds
+	if retryAfter <= 0 || retryAfter > 20*time.Second {
+		t.Errorf("Expected retry hint within 20s, got %v", retryAfter)
+	}
+
+	if allowed, _ := rl.allowAt("user", now.Add(retryAfter)); !allowed {
+		t.Error("Request should be allowed after the retry hint has elapsed")
+	}
+}
+
+func TestRateLimiter_UsersAreIndependent(t *testing.T) {
+	rl := NewRateLimiter(1)
+	defer rl.Close()
+	now := time.Now()
+
+	if allowed, _ := rl.allowAt("alice", now); !allowed {
+		t.Fatal("First request for alice should be allowed")
+	}
+
+	if allowed, _ := rl.allowAt("alice", now); allowed {
+		t.Error("Second request for alice should be refused")
+	}
+
+	if allowed, _ := rl.allowAt("bob", now); !allowed {
+		t.Error("Bob should not be limited by alice's requests")
+	}
+}
+
+func TestRateLimiter_CleanupInactiveBuckets(t *testing.T) {
+	rl := NewRateLimiter(60)
+	defer rl.Close()
+	now := time.Now()
+
+	rl.allowAt("idle", now.Add(-2*time.Minute))
+	rl.allowAt("active", now)
+
+	rl.cleanupAt(now)
+
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	if _, exists := rl.buckets["idle"]; exists {
+		t.Error("Idle user bucket should be cleaned up")
+	}
+
+	if _, exists := rl.buckets["active"]; !exists {
+		t.Error("Active user bucket should be kept")
+	}
+}
+
+func TestRateLimiter_CloseStopsCleanup(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	limiters := make([]*RateLimiter, 10)
+	for i := range limiters {
+		limiters[i] = NewRateLimiter(60)
+	}
+	if running := runtime.NumGoroutine(); running < before+len(limiters) {
+		t.Fatalf("Expected %d cleanup goroutines to start, goroutines went from %d to %d", len(limiters), before, running)
+	}
+
+	for _, rl := range limiters {
+		rl.Close()
+		rl.Close() // Closing twice is a no-op
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected cleanup goroutines to exit after Close, goroutines went from %d to %d", before, runtime.NumGoroutine())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// A closed limiter still limits requests
+	if allowed, _ := limiters[0].Allow("user"); !allowed {
+		t.Error("First request after Close should be allowed")
+	}
+}
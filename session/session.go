@@ -1,6 +1,9 @@
 package session
 
 import (
+	"errors"
+	"runtime"
+	"sort"
 	"sync"
 	"time"
 
@@ -14,20 +17,74 @@ type UserSession struct {
 	Description    string    `json:"description"`
 	ExpireDate     time.Time `json:"expire_date"`
 	AllowedDomains []string  `json:"allowed_domains"`
+	Scopes         []string  `json:"scopes,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
 	LastAccessedAt time.Time `json:"last_accessed_at"`
 }
 
+// SessionLimitPolicy decides what happens when a user already has the maximum number of sessions
+type SessionLimitPolicy string
+
+const (
+	// SessionLimitEvictOldest deletes the user's oldest session to make room for the new one
+	SessionLimitEvictOldest SessionLimitPolicy = "evict"
+	// SessionLimitReject refuses to create the new session
+	SessionLimitReject SessionLimitPolicy = "reject"
+)
+
+// ErrTooManySessions is returned by CreateSession when the user is at the session limit
+// and the reject policy is in effect
+var ErrTooManySessions = errors.New("too many active sessions for user")
+
+// defaultCleanupInterval is how often the background cleanup removes expired sessions
+const defaultCleanupInterval = 5 * time.Minute
+
 // Manager manages user sessions in memory
 type Manager struct {
-	sessions map[string]*UserSession
-	mutex    sync.RWMutex
+	sessions           map[string]*UserSession
+	userSessions       map[string][]string // key: user ID, value: session IDs, oldest first
+	maxSessionsPerUser int
+	maxSessions        int
+	limitPolicy        SessionLimitPolicy
+	cleanupInterval    time.Duration
+	done               chan struct{}
+	closeOnce          sync.Once
+	mutex              sync.RWMutex
+}
+
+// ManagerOption configures optional Manager settings
+type ManagerOption func(*Manager)
+
+// WithCleanupInterval overrides how often expired sessions are removed in the background
+func WithCleanupInterval(interval time.Duration) ManagerOption {
+	return func(sm *Manager) {
+		sm.cleanupInterval = interval
+	}
 }
 
-// NewManager creates a new session manager
-func NewManager() *Manager {
+// WithMaxSessions caps the total number of sessions; see SetMaxSessions
+func WithMaxSessions(maxSessions int) ManagerOption {
+	return func(sm *Manager) {
+		sm.maxSessions = maxSessions
+	}
+}
+
+// NewManager creates a new session manager and starts its background cleanup,
+// which runs until Close is called
+func NewManager(opts ...ManagerOption) *Manager {
 	sm := &Manager{
-		sessions: make(map[string]*UserSession),
+		sessions:        make(map[string]*UserSession),
+		userSessions:    make(map[string][]string),
+		limitPolicy:     SessionLimitEvictOldest,
+		cleanupInterval: defaultCleanupInterval,
+		done:            make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(sm)
+	}
+	if sm.cleanupInterval <= 0 {
+		sm.cleanupInterval = defaultCleanupInterval
 	}
 
 	// Start cleanup routine for expired sessions
@@ -36,14 +93,70 @@ func NewManager() *Manager {
 	return sm
 }
 
-// CreateSession creates a new session and returns session ID
-func (sm *Manager) CreateSession(userID, description string, expireDate time.Time, allowedDomains []string) string {
+// Close stops the background cleanup. Sessions stay readable; calling Close again is a no-op.
+func (sm *Manager) Close() {
+	sm.closeOnce.Do(func() {
+		close(sm.done)
+	})
+}
+
+// SetSessionLimit caps the number of concurrent sessions per user ID.
+// A maxSessions of zero or less removes the limit.
+func (sm *Manager) SetSessionLimit(maxSessions int, policy SessionLimitPolicy) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.maxSessionsPerUser = maxSessions
+	sm.limitPolicy = policy
+}
+
+// SetMaxSessions caps the total number of sessions across all users, so memory stays
+// bounded even if cleanup falls behind. At the cap, CreateSession evicts an expired
+// session, or else the least recently accessed one. Zero or less removes the cap.
+func (sm *Manager) SetMaxSessions(maxSessions int) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	sm.maxSessions = maxSessions
+}
+
+// CreateSession creates a new session and returns session ID.
+// When the user is at the session limit, the oldest session is evicted or
+// ErrTooManySessions is returned, depending on the limit policy.
+func (sm *Manager) CreateSession(userID, description string, expireDate time.Time, allowedDomains []string) (string, error) {
+	return sm.CreateSessionWithScopes(userID, description, expireDate, allowedDomains, nil)
+}
+
+// CreateSessionWithScopes creates a session that also carries the scopes of the token
+// it was created from; see CreateSession
+func (sm *Manager) CreateSessionWithScopes(userID, description string, expireDate time.Time, allowedDomains, scopes []string) (string, error) {
 	sm.mutex.Lock()
 	defer sm.mutex.Unlock()
 
-	sessionID := uuid.New().String()
 	now := time.Now()
 
+	if sm.maxSessionsPerUser > 0 {
+		// Expired sessions that have not been cleaned up yet do not count
+		for _, sessionID := range sm.userSessions[userID] {
+			if now.After(sm.sessions[sessionID].ExpireDate) {
+				sm.removeSessionLocked(sessionID)
+			}
+		}
+
+		for len(sm.userSessions[userID]) >= sm.maxSessionsPerUser {
+			if sm.limitPolicy == SessionLimitReject {
+				return "", ErrTooManySessions
+			}
+			sm.removeSessionLocked(sm.userSessions[userID][0])
+		}
+	}
+
+	// The global cap is applied after the per-user limit, so a rejected login evicts nothing
+	for sm.maxSessions > 0 && len(sm.sessions) >= sm.maxSessions {
+		sm.evictLeastRecentlyUsedLocked(now)
+	}
+
+	sessionID := uuid.New().String()
+
 	// Session expires in 30 minutes or at JWT expiry, whichever comes first
 	sessionExpiry := now.Add(30 * time.Minute)
 	if expireDate.Before(sessionExpiry) {
@@ -56,15 +169,59 @@ func (sm *Manager) CreateSession(userID, description string, expireDate time.Tim
 		Description:    description,
 		ExpireDate:     sessionExpiry,
 		AllowedDomains: allowedDomains,
+		Scopes:         scopes,
 		CreatedAt:      now,
 		LastAccessedAt: now,
 	}
 
 	sm.sessions[sessionID] = session
-	return sessionID
+	sm.userSessions[userID] = append(sm.userSessions[userID], sessionID)
+	return sessionID, nil
+}
+
+// removeSessionLocked removes a session and its user index entry; the caller holds the lock
+func (sm *Manager) removeSessionLocked(sessionID string) {
+	session, exists := sm.sessions[sessionID]
+	if !exists {
+		return
+	}
+	delete(sm.sessions, sessionID)
+
+	ids := sm.userSessions[session.UserID]
+	for i, id := range ids {
+		if id == sessionID {
+			ids = append(ids[:i:i], ids[i+1:]...)
+			break
+		}
+	}
+	if len(ids) == 0 {
+		delete(sm.userSessions, session.UserID)
+	} else {
+		sm.userSessions[session.UserID] = ids
+	}
+}
+
+// evictLeastRecentlyUsedLocked removes an expired session if there is one, or else the
+// session accessed least recently; the caller holds the lock
+func (sm *Manager) evictLeastRecentlyUsedLocked(now time.Time) {
+	var victim *UserSession
+	for _, session := range sm.sessions {
+		if now.After(session.ExpireDate) {
+			victim = session
+			break
+		}
+		if victim == nil || session.LastAccessedAt.Before(victim.LastAccessedAt) {
+			victim = session
+		}
+	}
+	if victim != nil {
+		sm.removeSessionLocked(victim.SessionID)
+	}
 }
 
-// GetSession retrieves a session by ID
+// GetSession retrieves a session by ID and records the access time.
+// The returned session is a copy, so callers can read it without holding the lock
+// while other requests keep updating the stored session.
 func (sm *Manager) GetSession(sessionID string) (*UserSession, bool) {
 	sm.mutex.Lock()
 	defer sm.mutex.Unlock()
@@ -75,23 +232,100 @@ func (sm *Manager) GetSession(sessionID string) (*UserSession, bool) {
 	}
 
 	if time.Now().After(session.ExpireDate) {
-		delete(sm.sessions, sessionID)
+		sm.removeSessionLocked(sessionID)
 		return nil, false
 	}
 
 	session.LastAccessedAt = time.Now()
-	return session, true
+
+	snapshot := *session
+	snapshot.AllowedDomains = append([]string(nil), session.AllowedDomains...)
+	snapshot.Scopes = append([]string(nil), session.Scopes...)
+	return &snapshot, true
 }
 
-// DeleteSession removes a session
-func (sm *Manager) DeleteSession(sessionID string) {
+// DeleteSession removes a session and reports whether it existed
+func (sm *Manager) DeleteSession(sessionID string) bool {
 	sm.mutex.Lock()
 	defer sm.mutex.Unlock()
 
-	delete(sm.sessions, sessionID)
+	_, exists := sm.sessions[sessionID]
+	sm.removeSessionLocked(sessionID)
+	return exists
+}
+
+// ListSessions returns a snapshot of all unexpired sessions, oldest first.
+// The returned sessions are copies and can be used without holding the lock.
+func (sm *Manager) ListSessions() []UserSession {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	now := time.Now()
+	sessions := make([]UserSession, 0, len(sm.sessions))
+	for _, session := range sm.sessions {
+		if now.After(session.ExpireDate) {
+			continue
+		}
+
+		snapshot := *session
+		snapshot.AllowedDomains = append([]string(nil), session.AllowedDomains...)
+		snapshot.Scopes = append([]string(nil), session.Scopes...)
+		sessions = append(sessions, snapshot)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].CreatedAt.Equal(sessions[j].CreatedAt) {
+			return sessions[i].SessionID < sessions[j].SessionID
+		}
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+
+	return sessions
 }
 
-// CleanupExpiredSessions manually triggers cleanup of expired sessions (for testing)
+// Stats is a consistent snapshot of session counts, for dashboards
+type Stats struct {
+	Active         int            `json:"active"`        // unexpired sessions
+	Expired        int            `json:"expired"`       // expired sessions not yet cleaned up
+	ExpiringSoon   int            `json:"expiring_soon"` // active sessions expiring within ExpiringWithin
+	ExpiringWithin time.Duration  `json:"-"`             // window used for ExpiringSoon
+	PerUser        map[string]int `json:"per_user"`      // active sessions by user ID
+	TakenAt        time.Time      `json:"taken_at"`
+}
+
+// Stats counts the sessions under a single read lock, so the numbers are consistent with
+// each other. Sessions expiring within the given window are reported as ExpiringSoon.
+// It only counts and does not copy sessions, so it is cheap to call frequently.
+func (sm *Manager) Stats(expiringWithin time.Duration) Stats {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	now := time.Now()
+	stats := Stats{
+		ExpiringWithin: expiringWithin,
+		PerUser:        make(map[string]int, len(sm.userSessions)),
+		TakenAt:        now,
+	}
+
+	soon := now.Add(expiringWithin)
+	for _, session := range sm.sessions {
+		if now.After(session.ExpireDate) {
+			stats.Expired++
+			continue
+		}
+
+		stats.Active++
+		stats.PerUser[session.UserID]++
+		if !session.ExpireDate.After(soon) {
+			stats.ExpiringSoon++
+		}
+	}
+
+	return stats
+}
+
+// CleanupExpiredSessions manually triggers cleanup of expired sessions (for testing).
+// It removes every expired session under a single write lock.
 func (sm *Manager) CleanupExpiredSessions() {
 	sm.mutex.Lock()
 	defer sm.mutex.Unlock()
@@ -99,27 +333,76 @@ func (sm *Manager) CleanupExpiredSessions() {
 	now := time.Now()
 	for sessionID, session := range sm.sessions {
 		if now.After(session.ExpireDate) {
-			delete(sm.sessions, sessionID)
+			sm.removeSessionLocked(sessionID)
+		}
+	}
+}
+
+// cleanupBatchSize bounds how many sessions the background cleanup removes per write lock
+const cleanupBatchSize = 500
+
+// cleanupExpiredSessionsInBatches removes expired sessions without holding the write lock
+// for the whole sweep: expired IDs are collected under the read lock, then deleted in
+// batches, yielding between batches so GetSession calls are not starved.
+func (sm *Manager) cleanupExpiredSessionsInBatches() {
+	expired := sm.collectExpiredSessionIDs(time.Now())
+
+	for len(expired) > 0 {
+		batch := expired
+		if len(batch) > cleanupBatchSize {
+			batch = batch[:cleanupBatchSize]
 		}
+		expired = expired[len(batch):]
+
+		sm.removeExpiredSessions(batch)
+		runtime.Gosched()
 	}
 }
+
+// collectExpiredSessionIDs returns the IDs of sessions expired at now
+func (sm *Manager) collectExpiredSessionIDs(now time.Time) []string {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	var expired []string
+	for sessionID, session := range sm.sessions {
+		if now.After(session.ExpireDate) {
+			expired = append(expired, sessionID)
+		}
+	}
+	return expired
+}
+
+// removeExpiredSessions deletes the given sessions under the write lock.
+// Sessions are re-checked, since they may have been deleted since they were collected.
+func (sm *Manager) removeExpiredSessions(sessionIDs []string) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	now := time.Now()
+	for _, sessionID := range sessionIDs {
+		if session, exists := sm.sessions[sessionID]; exists && now.After(session.ExpireDate) {
+			sm.removeSessionLocked(sessionID)
+		}
+	}
+}
+
 func (sm *Manager) cleanupExpiredSessions() {
-	ticker := time.NewTicker(5 * time.Minute)
+	ticker := time.NewTicker(sm.cleanupInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		sm.mutex.Lock()
-		now := time.Now()
-		for sessionID, session := range sm.sessions {
-			if now.After(session.ExpireDate) {
-				delete(sm.sessions, sessionID)
-			}
+	for {
+		select {
+		case <-sm.done:
+			return
+		case <-ticker.C:
+			sm.cleanupExpiredSessionsInBatches()
 		}
-		sm.mutex.Unlock()
 	}
 }
 
-// Global session manager instance
+// Global session manager instance; it is never closed and cleans up for the
+// lifetime of the process
 var globalManager *Manager
 var globalManagerOnce sync.Once
 
@@ -1,19 +1,40 @@
 package session
 
 import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 )
 
-func TestManager_CreateAndGet(t *testing.T) {
+// newTestManager creates a manager whose background cleanup stops when the test ends
+func newTestManager(tb testing.TB) *Manager {
 	manager := NewManager()
+	tb.Cleanup(manager.Close)
+	return manager
+}
+
+func mustCreateSession(t *testing.T, manager *Manager, userID, description string, expireDate time.Time, allowedDomains []string) string {
+	t.Helper()
+
+	sessionID, err := manager.CreateSession(userID, description, expireDate, allowedDomains)
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	return sessionID
+}
+
+func TestManager_CreateAndGet(t *testing.T) {
+	manager := newTestManager(t)
 
 	userID := "test-user"
 	description := "Test User"
 	expiresAt := time.Now().Add(1 * time.Hour)
 	allowedDomains := []string{"example.com", "test.com"}
 
-	sessionID := manager.CreateSession(userID, description, expiresAt, allowedDomains)
+	sessionID := mustCreateSession(t, manager, userID, description, expiresAt, allowedDomains)
 
 	if sessionID == "" {
 		t.Fatal("Session ID should not be empty")
@@ -54,16 +75,18 @@ func TestManager_CreateAndGet(t *testing.T) {
 }
 
 func TestManager_DeleteSession(t *testing.T) {
-	manager := NewManager()
+	manager := newTestManager(t)
 
-	sessionID := manager.CreateSession("user1", "User One", time.Now().Add(1*time.Hour), []string{"example.com"})
+	sessionID := mustCreateSession(t, manager, "user1", "User One", time.Now().Add(1*time.Hour), []string{"example.com"})
 	_, exists := manager.GetSession(sessionID)
 	if !exists {
 		t.Fatal("Session should exist before deletion")
 	}
 
 	// Delete session
-	manager.DeleteSession(sessionID)
+	if !manager.DeleteSession(sessionID) {
+		t.Error("DeleteSession should report an existing session as deleted")
+	}
 
 	_, exists = manager.GetSession(sessionID)
 	if exists {
@@ -72,9 +95,9 @@ func TestManager_DeleteSession(t *testing.T) {
 }
 
 func TestManager_ExpiredSession(t *testing.T) {
-	manager := NewManager()
+	manager := newTestManager(t)
 
-	sessionID := manager.CreateSession(
+	sessionID := mustCreateSession(t, manager,
 		"expired-user",
 		"Expired User",
 		time.Now().Add(-1*time.Hour),
@@ -87,10 +110,10 @@ func TestManager_ExpiredSession(t *testing.T) {
 }
 
 func TestManager_CleanupExpiredSessions(t *testing.T) {
-	manager := NewManager()
+	manager := newTestManager(t)
 
-	validID := manager.CreateSession("valid-user", "Valid", time.Now().Add(1*time.Hour), []string{"example.com"})
-	expiredID := manager.CreateSession("expired-user", "Expired", time.Now().Add(-1*time.Hour), []string{"test.com"})
+	validID := mustCreateSession(t, manager, "valid-user", "Valid", time.Now().Add(1*time.Hour), []string{"example.com"})
+	expiredID := mustCreateSession(t, manager, "expired-user", "Expired", time.Now().Add(-1*time.Hour), []string{"test.com"})
 
 	manager.CleanupExpiredSessions()
 	_, validExists := manager.GetSession(validID)
@@ -108,14 +131,14 @@ func TestManager_CleanupExpiredSessions(t *testing.T) {
 }
 
 func TestManager_MultipleSessions(t *testing.T) {
-	manager := NewManager()
+	manager := newTestManager(t)
 
 	sessions := make(map[string]string)
 	expiresAt := time.Now().Add(1 * time.Hour)
 
 	for i := 0; i < 10; i++ {
 		userID := string(rune('a' + i))
-		sessionID := manager.CreateSession(userID, "User "+userID, expiresAt, []string{"example.com"})
+		sessionID := mustCreateSession(t, manager, userID, "User "+userID, expiresAt, []string{"example.com"})
 		sessions[sessionID] = userID
 	}
 
@@ -133,7 +156,7 @@ func TestManager_MultipleSessions(t *testing.T) {
 }
 
 func TestManager_GetNonExistentSession(t *testing.T) {
-	manager := NewManager()
+	manager := newTestManager(t)
 
 	_, exists := manager.GetSession("non-existent-session-id")
 	if exists {
@@ -142,10 +165,40 @@ func TestManager_GetNonExistentSession(t *testing.T) {
 }
 
 func TestManager_DeleteNonExistentSession(t *testing.T) {
-	manager := NewManager()
+	manager := newTestManager(t)
 
 	// Should not panic when deleting non-existent session
-	manager.DeleteSession("non-existent-session-id")
+	if manager.DeleteSession("non-existent-session-id") {
+		t.Error("DeleteSession should report a non-existent session as not deleted")
+	}
+}
+
+func TestManager_ListSessions(t *testing.T) {
+	manager := newTestManager(t)
+
+	first := mustCreateSession(t, manager, "user1", "User One", time.Now().Add(1*time.Hour), []string{"example.com"})
+	second := mustCreateSession(t, manager, "user2", "User Two", time.Now().Add(1*time.Hour), []string{"*.test.com"})
+	mustCreateSession(t, manager, "user3", "Expired", time.Now().Add(-1*time.Second), []string{"expired.com"})
+
+	sessions := manager.ListSessions()
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 unexpired sessions, got %d", len(sessions))
+	}
+
+	ids := map[string]bool{sessions[0].SessionID: true, sessions[1].SessionID: true}
+	if !ids[first] || !ids[second] {
+		t.Errorf("Expected sessions %s and %s, got %+v", first, second, sessions)
+	}
+
+	// The snapshot must not alias the manager's state
+	sessions[0].AllowedDomains[0] = "modified.com"
+	sessions[0].UserID = "modified"
+
+	for _, session := range manager.ListSessions() {
+		if session.UserID == "modified" || session.AllowedDomains[0] == "modified.com" {
+			t.Errorf("Modifying the snapshot changed the stored session: %+v", session)
+		}
+	}
 }
 
 func TestGlobalManager(t *testing.T) {
@@ -159,7 +212,7 @@ func TestGlobalManager(t *testing.T) {
 		t.Error("Global session manager should return the same instance")
 	}
 
-	sessionID := manager1.CreateSession("test", "Test", time.Now().Add(1*time.Hour), []string{"example.com"})
+	sessionID := mustCreateSession(t, manager1, "test", "Test", time.Now().Add(1*time.Hour), []string{"example.com"})
 	_, exists := manager2.GetSession(sessionID)
 	if !exists {
 		t.Error("Session should exist in global manager")
@@ -168,14 +221,72 @@ func TestGlobalManager(t *testing.T) {
 	manager1.DeleteSession(sessionID)
 }
 
+func TestGlobalManagerConcurrentFirstUse(t *testing.T) {
+	// Start from an uninitialized global manager so the goroutines race on creating it
+	savedManager := globalManager
+	globalManager, globalManagerOnce = nil, sync.Once{}
+	t.Cleanup(func() {
+		if globalManager != nil {
+			globalManager.Close()
+		}
+		globalManager, globalManagerOnce = savedManager, sync.Once{}
+		if savedManager != nil {
+			globalManagerOnce.Do(func() {})
+		}
+	})
+
+	const callers = 50
+	managers := make([]*Manager, callers)
+
+	var start, wg sync.WaitGroup
+	start.Add(1)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			start.Wait()
+			managers[i] = GetGlobalManager()
+		}(i)
+	}
+	start.Done()
+	wg.Wait()
+
+	for i, manager := range managers {
+		if manager == nil || manager != managers[0] {
+			t.Fatalf("Caller %d got a different global manager instance", i)
+		}
+	}
+}
+
+func TestManager_Stats(t *testing.T) {
+	manager := newTestManager(t)
+	now := time.Now()
+
+	mustCreateSession(t, manager, "alice", "laptop", now.Add(time.Hour), []string{"*"})
+	mustCreateSession(t, manager, "alice", "ci", now.Add(5*time.Minute), []string{"*"})
+	mustCreateSession(t, manager, "bob", "laptop", now.Add(2*time.Hour), []string{"example.com"})
+	mustCreateSession(t, manager, "carol", "old", now.Add(-time.Minute), []string{"example.com"})
+
+	stats := manager.Stats(15 * time.Minute)
+	if stats.Active != 3 || stats.Expired != 1 || stats.ExpiringSoon != 1 {
+		t.Errorf("Expected 3 active, 1 expired and 1 expiring soon, got %+v", stats)
+	}
+	if stats.PerUser["alice"] != 2 || stats.PerUser["bob"] != 1 {
+		t.Errorf("Expected alice=2 and bob=1, got %v", stats.PerUser)
+	}
+	if _, exists := stats.PerUser["carol"]; exists {
+		t.Errorf("Expected users with only expired sessions to be omitted, got %v", stats.PerUser)
+	}
+}
+
 func TestManager_UniqueSessionIDs(t *testing.T) {
-	manager := NewManager()
+	manager := newTestManager(t)
 
 	expiresAt := time.Now().Add(1 * time.Hour)
 	sessionIDs := make(map[string]bool)
 
 	for i := 0; i < 100; i++ {
-		sessionID := manager.CreateSession("same-user", "Same User", expiresAt, []string{"example.com"})
+		sessionID := mustCreateSession(t, manager, "same-user", "Same User", expiresAt, []string{"example.com"})
 
 		if sessionIDs[sessionID] {
 			t.Fatalf("Duplicate session ID detected: %s", sessionID)
@@ -185,21 +296,21 @@ func TestManager_UniqueSessionIDs(t *testing.T) {
 }
 
 func TestManager_ConcurrentAccess(t *testing.T) {
-	manager := NewManager()
+	manager := newTestManager(t)
 	expiresAt := time.Now().Add(1 * time.Hour)
 
 	done := make(chan bool)
 
 	go func() {
 		for i := 0; i < 50; i++ {
-			manager.CreateSession("user-goroutine1", "User 1", expiresAt, []string{"example.com"})
+			_, _ = manager.CreateSession("user-goroutine1", "User 1", expiresAt, []string{"example.com"})
 		}
 		done <- true
 	}()
 
 	go func() {
 		for i := 0; i < 50; i++ {
-			manager.CreateSession("user-goroutine2", "User 2", expiresAt, []string{"test.com"})
+			_, _ = manager.CreateSession("user-goroutine2", "User 2", expiresAt, []string{"test.com"})
 		}
 		done <- true
 	}()
@@ -211,7 +322,7 @@ func TestManager_ConcurrentAccess(t *testing.T) {
 }
 
 func TestManager_EmptyFields(t *testing.T) {
-	manager := NewManager()
+	manager := newTestManager(t)
 	expiresAt := time.Now().Add(1 * time.Hour)
 
 	tests := []struct {
@@ -228,7 +339,7 @@ func TestManager_EmptyFields(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sessionID := manager.CreateSession(tt.userID, tt.description, expiresAt, tt.allowedDomains)
+			sessionID := mustCreateSession(t, manager, tt.userID, tt.description, expiresAt, tt.allowedDomains)
 
 			if sessionID == "" {
 				t.Error("Session ID should not be empty even with empty fields")
@@ -247,3 +358,268 @@ func TestManager_EmptyFields(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_SessionLimitEvictsOldest(t *testing.T) {
+	manager := newTestManager(t)
+	manager.SetSessionLimit(2, SessionLimitEvictOldest)
+	expiresAt := time.Now().Add(1 * time.Hour)
+
+	first := mustCreateSession(t, manager, "user", "First", expiresAt, []string{"example.com"})
+	second := mustCreateSession(t, manager, "user", "Second", expiresAt, []string{"example.com"})
+	other := mustCreateSession(t, manager, "other", "Other user", expiresAt, []string{"example.com"})
+	third := mustCreateSession(t, manager, "user", "Third", expiresAt, []string{"example.com"})
+
+	if _, exists := manager.GetSession(first); exists {
+		t.Error("Oldest session should have been evicted")
+	}
+
+	for _, sessionID := range []string{second, third, other} {
+		if _, exists := manager.GetSession(sessionID); !exists {
+			t.Errorf("Session %s should still exist", sessionID)
+		}
+	}
+
+	fourth := mustCreateSession(t, manager, "user", "Fourth", expiresAt, []string{"example.com"})
+	if _, exists := manager.GetSession(second); exists {
+		t.Error("Second session should be evicted next")
+	}
+	if _, exists := manager.GetSession(fourth); !exists {
+		t.Error("Newest session should exist")
+	}
+}
+
+func TestManager_SessionLimitRejects(t *testing.T) {
+	manager := newTestManager(t)
+	manager.SetSessionLimit(1, SessionLimitReject)
+	expiresAt := time.Now().Add(1 * time.Hour)
+
+	first := mustCreateSession(t, manager, "user", "First", expiresAt, []string{"example.com"})
+
+	if _, err := manager.CreateSession("user", "Second", expiresAt, []string{"example.com"}); !errors.Is(err, ErrTooManySessions) {
+		t.Fatalf("Expected ErrTooManySessions, got: %v", err)
+	}
+
+	if _, exists := manager.GetSession(first); !exists {
+		t.Error("Existing session should be kept when a new one is rejected")
+	}
+
+	// Deleting a session frees a slot
+	manager.DeleteSession(first)
+	mustCreateSession(t, manager, "user", "Third", expiresAt, []string{"example.com"})
+}
+
+func TestManager_SessionLimitIgnoresExpiredSessions(t *testing.T) {
+	manager := newTestManager(t)
+	manager.SetSessionLimit(1, SessionLimitReject)
+
+	mustCreateSession(t, manager, "user", "Expired", time.Now().Add(-1*time.Second), []string{"example.com"})
+	mustCreateSession(t, manager, "user", "Fresh", time.Now().Add(1*time.Hour), []string{"example.com"})
+}
+
+// addExpiredSessions inserts n already-expired sessions directly, bypassing CreateSession
+func addExpiredSessions(manager *Manager, n int) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	expiredAt := time.Now().Add(-time.Minute)
+	for i := 0; i < n; i++ {
+		sessionID := fmt.Sprintf("expired-%d", i)
+		userID := fmt.Sprintf("user-%d", i)
+		manager.sessions[sessionID] = &UserSession{SessionID: sessionID, UserID: userID, ExpireDate: expiredAt}
+		manager.userSessions[userID] = append(manager.userSessions[userID], sessionID)
+	}
+}
+
+func TestManager_CleanupExpiredSessionsInBatches(t *testing.T) {
+	manager := newTestManager(t)
+
+	validID := mustCreateSession(t, manager, "user-1", "Valid", time.Now().Add(1*time.Hour), []string{"example.com"})
+	addExpiredSessions(manager, cleanupBatchSize*2+7)
+
+	manager.cleanupExpiredSessionsInBatches()
+
+	if _, exists := manager.GetSession(validID); !exists {
+		t.Error("Valid session should survive cleanup")
+	}
+
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+	if len(manager.sessions) != 1 {
+		t.Errorf("Expected only the valid session to remain, got %d sessions", len(manager.sessions))
+	}
+	if len(manager.userSessions) != 1 || len(manager.userSessions["user-1"]) != 1 {
+		t.Errorf("Expected the user index to hold only the valid session, got %v", manager.userSessions)
+	}
+}
+
+func BenchmarkCleanupExpiredSessions(b *testing.B) {
+	const sessionCount = 100000
+	manager := newTestManager(b)
+
+	b.Run("single-lock", func(b *testing.B) {
+		var maxHold time.Duration
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			addExpiredSessions(manager, sessionCount)
+			b.StartTimer()
+
+			start := time.Now()
+			manager.CleanupExpiredSessions()
+			if hold := time.Since(start); hold > maxHold {
+				maxHold = hold
+			}
+		}
+		b.ReportMetric(float64(maxHold.Microseconds()), "max-lock-µs")
+	})
+
+	b.Run("batched", func(b *testing.B) {
+		var maxHold time.Duration
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			addExpiredSessions(manager, sessionCount)
+			b.StartTimer()
+
+			// Mirrors cleanupExpiredSessionsInBatches, timing each write-locked batch
+			expired := manager.collectExpiredSessionIDs(time.Now())
+			for len(expired) > 0 {
+				batch := expired[:min(cleanupBatchSize, len(expired))]
+				expired = expired[len(batch):]
+
+				start := time.Now()
+				manager.removeExpiredSessions(batch)
+				if hold := time.Since(start); hold > maxHold {
+					maxHold = hold
+				}
+			}
+		}
+		b.ReportMetric(float64(maxHold.Microseconds()), "max-lock-µs")
+	})
+}
+
+func TestManager_ConcurrentGetSameSession(t *testing.T) {
+	manager := newTestManager(t)
+	sessionID := mustCreateSession(t, manager, "user", "Shared", time.Now().Add(1*time.Hour), []string{"example.com"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				session, exists := manager.GetSession(sessionID)
+				if !exists {
+					t.Error("Session should exist")
+					return
+				}
+				// Reading the returned session must not race with other GetSession calls
+				if session.LastAccessedAt.IsZero() || session.AllowedDomains[0] != "example.com" {
+					t.Errorf("Unexpected session: %+v", session)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestManager_CloseStopsCleanup(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	managers := make([]*Manager, 10)
+	for i := range managers {
+		managers[i] = NewManager(WithCleanupInterval(time.Millisecond))
+	}
+	if running := runtime.NumGoroutine(); running < before+len(managers) {
+		t.Fatalf("Expected %d cleanup goroutines to start, goroutines went from %d to %d", len(managers), before, running)
+	}
+
+	for _, manager := range managers {
+		manager.Close()
+		manager.Close() // Closing twice is a no-op
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected cleanup goroutines to exit after Close, goroutines went from %d to %d", before, runtime.NumGoroutine())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestManager_CleanupInterval(t *testing.T) {
+	manager := NewManager(WithCleanupInterval(10 * time.Millisecond))
+	defer manager.Close()
+
+	addExpiredSessions(manager, 3)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		manager.mutex.RLock()
+		remaining := len(manager.sessions)
+		manager.mutex.RUnlock()
+		if remaining == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected background cleanup to remove expired sessions, %d remain", remaining)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestManager_MaxSessionsEvictsLeastRecentlyUsed(t *testing.T) {
+	manager := NewManager(WithMaxSessions(3))
+	t.Cleanup(manager.Close)
+	expiresAt := time.Now().Add(1 * time.Hour)
+
+	var sessionIDs []string
+	for i := 0; i < 5; i++ {
+		sessionID := mustCreateSession(t, manager, fmt.Sprintf("user-%d", i), "", expiresAt, []string{"example.com"})
+		sessionIDs = append(sessionIDs, sessionID)
+
+		// Space out access times so the LRU order does not depend on the clock resolution
+		manager.mutex.Lock()
+		manager.sessions[sessionID].LastAccessedAt = time.Now().Add(time.Duration(i-10) * time.Minute)
+		manager.mutex.Unlock()
+
+		if stats := manager.Stats(0); stats.Active > 3 {
+			t.Fatalf("Expected at most 3 sessions, got %d", stats.Active)
+		}
+	}
+
+	manager.mutex.RLock()
+	for i, sessionID := range sessionIDs {
+		_, exists := manager.sessions[sessionID]
+		if want := i >= 2; exists != want {
+			t.Errorf("Session %d exists = %v, want %v", i, exists, want)
+		}
+	}
+	manager.mutex.RUnlock()
+
+	// Accessing the least recently used session makes the next one the eviction candidate
+	manager.GetSession(sessionIDs[2])
+	mustCreateSession(t, manager, "user-5", "", expiresAt, []string{"example.com"})
+	if _, exists := manager.GetSession(sessionIDs[3]); exists {
+		t.Error("Least recently used session should have been evicted")
+	}
+	if _, exists := manager.GetSession(sessionIDs[2]); !exists {
+		t.Error("Recently accessed session should survive")
+	}
+}
+
+func TestManager_MaxSessionsEvictsExpiredFirst(t *testing.T) {
+	manager := newTestManager(t)
+	manager.SetMaxSessions(2)
+	addExpiredSessions(manager, 1)
+
+	active := mustCreateSession(t, manager, "user", "", time.Now().Add(1*time.Hour), []string{"example.com"})
+	manager.mutex.Lock()
+	manager.sessions[active].LastAccessedAt = time.Now().Add(-time.Hour)
+	manager.mutex.Unlock()
+
+	mustCreateSession(t, manager, "other", "", time.Now().Add(1*time.Hour), []string{"example.com"})
+	if _, exists := manager.GetSession(active); !exists {
+		t.Error("The expired session should be evicted before an active one")
+	}
+}
@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// ParseLeafCertificate parses the first CERTIFICATE block of a PEM chain or bundle.
+// Other block types (such as a private key in a bundle) are skipped.
+func ParseLeafCertificate(pemData []byte) (*x509.Certificate, error) {
+	rest := pemData
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM certificate found")
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		return cert, nil
+	}
+}
+
+// CertificateFingerprintSHA256 returns the SHA-256 fingerprint of a certificate's DER encoding
+// as colon-separated uppercase hex, the format printed by openssl x509 -fingerprint
+func CertificateFingerprintSHA256(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// CertificateSerial returns a certificate's serial number as uppercase hex
+func CertificateSerial(cert *x509.Certificate) string {
+	return fmt.Sprintf("%X", cert.SerialNumber)
+}
+
+// SplitLeaf separates the first certificate of a PEM chain from the certificates after it.
+// Both parts are re-encoded as PEM; blocks other than certificates are dropped.
+// The CA part is empty when the chain holds a single certificate.
+func SplitLeaf(pemData []byte) (leaf []byte, caChain []byte, err error) {
+	rest := pemData
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		encoded := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: block.Bytes})
+		if leaf == nil {
+			leaf = encoded
+		} else {
+			caChain = append(caChain, encoded...)
+		}
+	}
+
+	if leaf == nil {
+		return nil, nil, fmt.Errorf("no PEM certificate found")
+	}
+
+	return leaf, caChain, nil
+}
+
+// CertificatesDER returns the DER encoding of each certificate of a PEM chain, in order.
+// Blocks other than certificates are skipped.
+func CertificatesDER(pemData []byte) ([][]byte, error) {
+	var certs [][]byte
+	rest := pemData
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			certs = append(certs, block.Bytes)
+		}
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+	return certs, nil
+}
+
+// ParseCertificates parses every CERTIFICATE block of PEM data, in order.
+// Other block types are skipped.
+func ParseCertificates(pemData []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := pemData
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate %d: %w", len(certs)+1, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+	return certs, nil
+}
+
+// CheckChainOrder checks that each certificate of a chain was signed by the one after it,
+// as servers must present them. Unlike NormalizeChain, it does not reorder the chain.
+func CheckChainOrder(certs []*x509.Certificate) error {
+	for i := 0; i+1 < len(certs); i++ {
+		if !issuedBy(certs[i], certs[i+1]) {
+			return fmt.Errorf("certificate %d (%s) is not signed by certificate %d (%s); expected a certificate for %s",
+				i+1, certs[i].Subject.String(), i+2, certs[i+1].Subject.String(), certs[i].Issuer.String())
+		}
+	}
+	return nil
+}
+
+// NormalizeChain reorders a PEM certificate chain from leaf to root, matching each
+// certificate's issuer to the next one's subject. With stripRoot, a trailing
+// self-signed root is removed. It fails if the certificates do not form a single chain,
+// for example when an intermediate is missing.
+func NormalizeChain(pemData []byte, stripRoot bool) ([]byte, error) {
+	certs, err := ParseCertificates(pemData)
+	if err != nil {
+		return nil, err
+	}
+
+	// The leaf is the only certificate that issued none of the others
+	var leaves []*x509.Certificate
+	for _, candidate := range certs {
+		issuesOther := false
+		for _, other := range certs {
+			if other != candidate && issuedBy(other, candidate) {
+				issuesOther = true
+				break
+			}
+		}
+		if !issuesOther {
+			leaves = append(leaves, candidate)
+		}
+	}
+	if len(leaves) != 1 {
+		return nil, fmt.Errorf("certificates do not form a single chain: found %d leaf candidates (missing intermediate?)", len(leaves))
+	}
+
+	chain := []*x509.Certificate{leaves[0]}
+	used := map[*x509.Certificate]bool{leaves[0]: true}
+	for current := leaves[0]; !isSelfSigned(current); {
+		var issuer *x509.Certificate
+		for _, candidate := range certs {
+			if !used[candidate] && issuedBy(current, candidate) {
+				issuer = candidate
+				break
+			}
+		}
+		if issuer == nil {
+			break
+		}
+
+		chain = append(chain, issuer)
+		used[issuer] = true
+		current = issuer
+	}
+
+	if len(chain) != len(certs) {
+		for _, cert := range certs {
+			if !used[cert] {
+				return nil, fmt.Errorf("cannot assemble chain: no path from %q to %q (missing intermediate?)",
+					chain[len(chain)-1].Issuer.String(), cert.Subject.String())
+			}
+		}
+	}
+
+	if stripRoot && len(chain) > 1 && isSelfSigned(chain[len(chain)-1]) {
+		chain = chain[:len(chain)-1]
+	}
+
+	var out []byte
+	for _, cert := range chain {
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	return out, nil
+}
+
+// issuedBy reports whether cert was signed by issuer
+func issuedBy(cert, issuer *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, issuer.RawSubject) && cert.CheckSignatureFrom(issuer) == nil
+}
+
+// isSelfSigned reports whether cert is a self-signed (root) certificate
+func isSelfSigned(cert *x509.Certificate) bool {
+	return issuedBy(cert, cert)
+}
+
+// BuildPEMBundle concatenates a certificate chain and a private key into a single PEM file,
+// chain first or, with keyFirst, key first (as HAProxy expects). The parts are separated by
+// a newline even if the first does not end with one, and the result is checked to consist
+// only of well-formed PEM blocks with at least one certificate and one private key.
+func BuildPEMBundle(certChain, privateKey []byte, keyFirst bool) ([]byte, error) {
+	first, second := certChain, privateKey
+	if keyFirst {
+		first, second = privateKey, certChain
+	}
+
+	bundle := make([]byte, 0, len(first)+len(second)+1)
+	bundle = append(bundle, first...)
+	if len(bundle) > 0 && bundle[len(bundle)-1] != '\n' {
+		bundle = append(bundle, '\n')
+	}
+	bundle = append(bundle, second...)
+
+	if err := validatePEMBundle(bundle); err != nil {
+		return nil, fmt.Errorf("invalid certificate bundle: %w", err)
+	}
+
+	return bundle, nil
+}
+
+// validatePEMBundle checks that data holds nothing but PEM blocks, including at least
+// one certificate and one private key
+func validatePEMBundle(data []byte) error {
+	var certs, keys int
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		switch {
+		case block.Type == "CERTIFICATE":
+			certs++
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			keys++
+		}
+	}
+
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return fmt.Errorf("unparseable data after %d PEM block(s)", certs+keys)
+	}
+	if certs == 0 {
+		return fmt.Errorf("no PEM certificate found")
+	}
+	if keys == 0 {
+		return fmt.Errorf("no PEM private key found")
+	}
+
+	return nil
+}
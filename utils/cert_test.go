@@ -0,0 +1,366 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func makeTestCertPEM(t *testing.T, commonName string, notAfter time.Time) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestParseLeafCertificate(t *testing.T) {
+	notAfter := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	leaf := makeTestCertPEM(t, "leaf.example.com", notAfter)
+	intermediate := makeTestCertPEM(t, "intermediate", notAfter.Add(time.Hour))
+	key := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})
+
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{"single certificate", leaf},
+		{"chain", append(append([]byte{}, leaf...), intermediate...)},
+		{"key before certificate", append(append([]byte{}, key...), leaf...)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cert, err := ParseLeafCertificate(tc.data)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if cert.Subject.CommonName != "leaf.example.com" {
+				t.Errorf("Expected leaf.example.com, got %s", cert.Subject.CommonName)
+			}
+
+			if !cert.NotAfter.Equal(notAfter) {
+				t.Errorf("Expected NotAfter %v, got %v", notAfter, cert.NotAfter)
+			}
+		})
+	}
+}
+
+func TestParseLeafCertificateInvalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"not PEM", []byte("hello")},
+		{"key only", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})},
+		{"garbage certificate", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("junk")})},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseLeafCertificate(tc.data); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func TestCertificateFingerprintAndSerial(t *testing.T) {
+	cert, err := ParseLeafCertificate(makeTestCertPEM(t, "example.com", time.Now().Add(time.Hour)))
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	sum := sha256.Sum256(cert.Raw)
+	expected := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	fingerprint := CertificateFingerprintSHA256(cert)
+	if strings.ReplaceAll(fingerprint, ":", "") != expected {
+		t.Errorf("Expected fingerprint %s, got %s", expected, fingerprint)
+	}
+	if len(fingerprint) != 32*3-1 {
+		t.Errorf("Expected 32 colon-separated bytes, got %q", fingerprint)
+	}
+
+	cert.SerialNumber = big.NewInt(0xABCDEF)
+	if serial := CertificateSerial(cert); serial != "ABCDEF" {
+		t.Errorf("Expected serial ABCDEF, got %s", serial)
+	}
+}
+
+func issueTestCert(t *testing.T, commonName string, isCA bool, parent *testCA) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+	if isCA {
+		template.KeyUsage = x509.KeyUsageCertSign
+	}
+
+	signerCert, signerKey := template, key
+	if parent != nil {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	return &testCA{cert: cert, key: key}
+}
+
+func encodeTestChain(certs ...*testCA) []byte {
+	var out []byte
+	for _, c := range certs {
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw})...)
+	}
+	return out
+}
+
+func chainSubjects(t *testing.T, pemData []byte) []string {
+	t.Helper()
+
+	var subjects []string
+	for rest := pemData; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return subjects
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatalf("Failed to parse output certificate: %v", err)
+		}
+		subjects = append(subjects, cert.Subject.CommonName)
+	}
+}
+
+func TestNormalizeChain(t *testing.T) {
+	root := issueTestCert(t, "root", true, nil)
+	intermediate := issueTestCert(t, "intermediate", true, root)
+	leaf := issueTestCert(t, "leaf", false, intermediate)
+
+	testCases := []struct {
+		name      string
+		input     []byte
+		stripRoot bool
+		expected  []string
+	}{
+		{"already ordered", encodeTestChain(leaf, intermediate, root), false, []string{"leaf", "intermediate", "root"}},
+		{"reversed", encodeTestChain(root, intermediate, leaf), false, []string{"leaf", "intermediate", "root"}},
+		{"shuffled", encodeTestChain(intermediate, root, leaf), false, []string{"leaf", "intermediate", "root"}},
+		{"strip root", encodeTestChain(root, leaf, intermediate), true, []string{"leaf", "intermediate"}},
+		{"no root to strip", encodeTestChain(intermediate, leaf), true, []string{"leaf", "intermediate"}},
+		{"self-signed leaf is kept", encodeTestChain(root), true, []string{"root"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := NormalizeChain(tc.input, tc.stripRoot)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			subjects := chainSubjects(t, output)
+			if len(subjects) != len(tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, subjects)
+			}
+			for i := range subjects {
+				if subjects[i] != tc.expected[i] {
+					t.Errorf("Expected %s at index %d, got %s", tc.expected[i], i, subjects[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSplitLeaf(t *testing.T) {
+	root := issueTestCert(t, "Root CA", true, nil)
+	intermediate := issueTestCert(t, "Intermediate CA", true, root)
+	leaf := issueTestCert(t, "example.com", false, intermediate)
+
+	leafPEM, caChain, err := SplitLeaf(encodeTestChain(leaf, intermediate, root))
+	if err != nil {
+		t.Fatalf("SplitLeaf failed: %v", err)
+	}
+
+	if got := chainSubjects(t, leafPEM); len(got) != 1 || got[0] != "example.com" {
+		t.Errorf("Expected only the leaf, got %v", got)
+	}
+	if got := chainSubjects(t, caChain); len(got) != 2 || got[0] != "Intermediate CA" || got[1] != "Root CA" {
+		t.Errorf("Expected intermediate and root, got %v", got)
+	}
+
+	// A single certificate has no CA part
+	leafPEM, caChain, err = SplitLeaf(encodeTestChain(leaf))
+	if err != nil {
+		t.Fatalf("SplitLeaf failed: %v", err)
+	}
+	if len(leafPEM) == 0 || len(caChain) != 0 {
+		t.Errorf("Expected leaf and no CA chain, got %d and %d bytes", len(leafPEM), len(caChain))
+	}
+
+	if _, _, err := SplitLeaf([]byte("not pem")); err == nil {
+		t.Error("Expected error for input without certificates")
+	}
+}
+
+func TestNormalizeChainErrors(t *testing.T) {
+	root := issueTestCert(t, "root", true, nil)
+	intermediate := issueTestCert(t, "intermediate", true, root)
+	leaf := issueTestCert(t, "leaf", false, intermediate)
+	otherLeaf := issueTestCert(t, "other leaf", false, intermediate)
+
+	testCases := []struct {
+		name  string
+		input []byte
+	}{
+		{"empty", nil},
+		{"missing intermediate", encodeTestChain(leaf, root)},
+		{"two leaves", encodeTestChain(leaf, otherLeaf, intermediate)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NormalizeChain(tc.input, false); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestCertificatesDER(t *testing.T) {
+	leafPEM := makeTestCertPEM(t, "example.com", time.Now().Add(time.Hour))
+	caPEM := makeTestCertPEM(t, "Test CA", time.Now().Add(time.Hour))
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})
+
+	// Non-certificate blocks, such as the key of a bundle, are skipped
+	chain := append(append(append([]byte{}, leafPEM...), keyPEM...), caPEM...)
+
+	certs, err := CertificatesDER(chain)
+	if err != nil {
+		t.Fatalf("CertificatesDER failed: %v", err)
+	}
+	if len(certs) != 2 {
+		t.Fatalf("Expected 2 certificates, got %d", len(certs))
+	}
+
+	for i, want := range []string{"example.com", "Test CA"} {
+		cert, err := x509.ParseCertificate(certs[i])
+		if err != nil {
+			t.Fatalf("DER certificate %d does not parse: %v", i, err)
+		}
+		if cert.Subject.CommonName != want {
+			t.Errorf("Certificate %d: expected CN %q, got %q", i, want, cert.Subject.CommonName)
+		}
+	}
+
+	if _, err := CertificatesDER(keyPEM); err == nil {
+		t.Error("Expected an error for PEM data without a certificate")
+	}
+}
+
+func TestBuildPEMBundle(t *testing.T) {
+	certPEM := makeTestCertPEM(t, "example.com", time.Now().Add(time.Hour))
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})
+
+	// A chain without a trailing newline must not run into the key
+	chain := []byte(strings.TrimRight(string(certPEM), "\n"))
+
+	for _, keyFirst := range []bool{false, true} {
+		bundle, err := BuildPEMBundle(chain, keyPEM, keyFirst)
+		if err != nil {
+			t.Fatalf("BuildPEMBundle(keyFirst=%v) failed: %v", keyFirst, err)
+		}
+
+		var types []string
+		rest := bundle
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			types = append(types, block.Type)
+		}
+		if len(strings.TrimSpace(string(rest))) != 0 {
+			t.Errorf("Unparsed data left in bundle: %q", rest)
+		}
+
+		want := []string{"CERTIFICATE", "PRIVATE KEY"}
+		if keyFirst {
+			want = []string{"PRIVATE KEY", "CERTIFICATE"}
+		}
+		if strings.Join(types, ",") != strings.Join(want, ",") {
+			t.Errorf("keyFirst=%v: expected blocks %v, got %v", keyFirst, want, types)
+		}
+	}
+}
+
+func TestBuildPEMBundleInvalid(t *testing.T) {
+	certPEM := makeTestCertPEM(t, "example.com", time.Now().Add(time.Hour))
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("key")})
+
+	tests := []struct {
+		name       string
+		certChain  []byte
+		privateKey []byte
+	}{
+		{name: "missing key", certChain: certPEM},
+		{name: "missing certificate", privateKey: keyPEM},
+		{name: "garbage key", certChain: certPEM, privateKey: []byte("not a key")},
+	}
+
+	for _, tt := range tests {
+		if _, err := BuildPEMBundle(tt.certChain, tt.privateKey, false); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
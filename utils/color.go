@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// isTerminal reports whether out is a terminal; tests replace it to simulate one
+var isTerminal = func(out io.Writer) bool {
+	file, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// ColorEnabled reports whether styled output may be written to out. Color is off when
+// noColor is set, when the NO_COLOR environment variable is non-empty (https://no-color.org),
+// or when out is not a terminal.
+func ColorEnabled(noColor bool, out io.Writer) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(out)
+}
+
+// Renderer returns style.Render when enabled, otherwise a function that joins its
+// arguments with spaces like Render does, but without escape sequences
+func Renderer(style lipgloss.Style, enabled bool) func(...string) string {
+	if enabled {
+		return style.Render
+	}
+	return func(strs ...string) string {
+		return strings.Join(strs, " ")
+	}
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// simulateTerminal makes every writer count as a terminal for the duration of the test
+func simulateTerminal(t *testing.T) {
+	t.Helper()
+	saved := isTerminal
+	isTerminal = func(io.Writer) bool { return true }
+	t.Cleanup(func() { isTerminal = saved })
+}
+
+func TestColorEnabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	if ColorEnabled(false, &bytes.Buffer{}) {
+		t.Error("Expected color to be disabled for a non-terminal writer")
+	}
+
+	simulateTerminal(t)
+	if !ColorEnabled(false, &bytes.Buffer{}) {
+		t.Fatal("Expected color to be enabled on a terminal")
+	}
+	if ColorEnabled(true, &bytes.Buffer{}) {
+		t.Error("Expected --no-color to disable color on a terminal")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if ColorEnabled(false, &bytes.Buffer{}) {
+		t.Error("Expected NO_COLOR to disable color on a terminal")
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	if isTerminal(&bytes.Buffer{}) {
+		t.Error("A buffer is not a terminal")
+	}
+
+	file, err := os.CreateTemp(t.TempDir(), "output")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if isTerminal(file) {
+		t.Error("A regular file is not a terminal")
+	}
+}
+
+func TestRenderer(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	simulateTerminal(t)
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10"))
+
+	render := Renderer(style, ColorEnabled(false, &bytes.Buffer{}))
+	if got, want := render("export", "JWT_SECRET_KEY=abc"), style.Render("export", "JWT_SECRET_KEY=abc"); got != want {
+		t.Errorf("Expected the styled rendering %q on a terminal, got %q", want, got)
+	}
+
+	// --no-color renders plain text even on a terminal
+	render = Renderer(style, ColorEnabled(true, &bytes.Buffer{}))
+	output := render("export", "JWT_SECRET_KEY=abc")
+	if strings.Contains(output, "\x1b") {
+		t.Errorf("Expected no escape sequences, got %q", output)
+	}
+	if output != "export JWT_SECRET_KEY=abc" {
+		t.Errorf("Expected plain joined text, got %q", output)
+	}
+}
@@ -4,24 +4,59 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
 // DateTimeFormat defines the standard date/time format used throughout the project
 const DateTimeFormat = "2006-01-02 15:04:05"
 
-// FormatDateTime formats the given time to standard format in local timezone
+// displayUTC makes dates display and parse in UTC instead of the local timezone
+var displayUTC atomic.Bool
+
+// SetUTC makes FormatDateTime and ParseDateTime use UTC (true) or the local timezone (false)
+func SetUTC(utc bool) {
+	displayUTC.Store(utc)
+}
+
+// DisplayLocation returns the timezone dates are formatted and parsed in:
+// UTC after SetUTC(true), time.Local otherwise
+func DisplayLocation() *time.Location {
+	if displayUTC.Load() {
+		return time.UTC
+	}
+	return time.Local
+}
+
+// FormatDateTimeIn formats the given time to standard format in the given timezone
+func FormatDateTimeIn(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(DateTimeFormat)
+}
+
+// FormatDateTime formats the given time to standard format in the display timezone
 func FormatDateTime(t time.Time) string {
-	return t.Local().Format(DateTimeFormat)
+	return FormatDateTimeIn(t, DisplayLocation())
 }
 
 func FormatCurrentTime() string {
 	return FormatDateTime(time.Now())
 }
 
-// ParseDateTime parses YYYY-MM-DD HH:mm:ss format string to time in local timezone
+// ParseDateTime parses YYYY-MM-DD HH:mm:ss format string to time in the display timezone,
+// so a date is read the way FormatDateTime writes it
 func ParseDateTime(timeStr string) (time.Time, error) {
-	return time.ParseInLocation(DateTimeFormat, timeStr, time.Local)
+	return time.ParseInLocation(DateTimeFormat, timeStr, DisplayLocation())
+}
+
+// SetTimezone loads the named IANA timezone (e.g., "Asia/Seoul", "UTC") and makes it the
+// local timezone used to format and parse dates
+func SetTimezone(name string) (*time.Location, error) {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q: %w", name, err)
+	}
+	time.Local = location
+	return location, nil
 }
 
 // FormatDuration formats duration to human-readable format
@@ -48,8 +83,12 @@ func FormatDuration(d time.Duration) string {
 }
 
 // ParseDurationString parses duration strings with extended support for years, months, weeks
-// Supports: y/year/years, M/month/months, w/week/weeks, d/day/days, h/hour/hours, m/minute/minutes, s/second/seconds
-// Examples: "2y", "3months", "5d", "10w", "2h30m"
+// Supports: y/year/years, m/M/month/months, w/week/weeks, d/day/days, h/hour/hours, min/minute/minutes, s/second/seconds
+// Examples: "2y", "3months", "5d", "10w", "2h30m", "1d12h30m", "1d12h30min"
+//
+// The unit "m" is ambiguous. It means minutes on its own ("30m") or after a day, hour,
+// minute or second ("1d12h30m"), and months after a year or week or at the start of a
+// longer string ("1y2m", "2m1d"). "M" always means months and "min" always means minutes.
 func ParseDurationString(s string) (time.Duration, error) {
 	if s == "" {
 		return 0, fmt.Errorf("empty duration string")
@@ -68,23 +107,34 @@ func ParseDurationString(s string) (time.Duration, error) {
 	m := 30 * 24 * time.Hour
 	w := 7 * 24 * time.Hour
 	d := 24 * time.Hour
+	h := time.Hour
 
 	units := map[string]time.Duration{
-		"y":      y,
-		"year":   y,
-		"years":  y,
-		"m":      m,
-		"month":  m,
-		"months": m,
-		"w":      w,
-		"week":   w,
-		"weeks":  w,
-		"d":      d,
-		"day":    d,
-		"days":   d,
+		"y":       y,
+		"year":    y,
+		"years":   y,
+		"m":       m,
+		"month":   m,
+		"months":  m,
+		"w":       w,
+		"week":    w,
+		"weeks":   w,
+		"d":       d,
+		"day":     d,
+		"days":    d,
+		"h":       h,
+		"hour":    h,
+		"hours":   h,
+		"min":     time.Minute,
+		"minute":  time.Minute,
+		"minutes": time.Minute,
+		"s":       time.Second,
+		"second":  time.Second,
+		"seconds": time.Second,
 	}
 
 	// Parse number + unit combinations
+	var previousUnit time.Duration
 	i := 0
 	for i < len(remaining) {
 		// Find the start of a number
@@ -125,6 +175,10 @@ func ParseDurationString(s string) (time.Duration, error) {
 		if !exists {
 			return 0, fmt.Errorf("unknown duration unit: %s", unitStr)
 		}
+		if unitStr == "m" && previousUnit != 0 && previousUnit <= d {
+			unitDuration = time.Minute
+		}
+		previousUnit = unitDuration
 
 		total += time.Duration(float64(unitDuration) * num)
 	}
@@ -160,6 +160,19 @@ func TestParseDurationString_ComplexCombinations(t *testing.T) {
 	}{
 		{"year and month", "1y3months", 365*24*time.Hour + 3*30*24*time.Hour},
 		{"week and days", "1w2d", 7*24*time.Hour + 2*24*time.Hour},
+		{"day and hours", "1d12h", 24*time.Hour + 12*time.Hour},
+		{"weeks days and hours", "2w3d4h", 2*7*24*time.Hour + 3*24*time.Hour + 4*time.Hour},
+		{"year months and days", "1y6months15d", 365*24*time.Hour + 6*30*24*time.Hour + 15*24*time.Hour},
+		{"day hours and minutes", "1d12h30min", 36*time.Hour + 30*time.Minute},
+		{"day and long units", "1day2hours3minutes4seconds", 26*time.Hour + 3*time.Minute + 4*time.Second},
+		{"day hours and m as minutes", "1d12h30m", 36*time.Hour + 30*time.Minute},
+		{"day and m as minutes", "1d2m", 24*time.Hour + 2*time.Minute},
+		{"hours minutes and seconds", "1d1h1m1s", 25*time.Hour + time.Minute + time.Second},
+		// Without a smaller unit before it, m means months
+		{"m alone as month", "2m1d", 2*30*24*time.Hour + 24*time.Hour},
+		{"year and m as month", "1y2m", 365*24*time.Hour + 2*30*24*time.Hour},
+		{"week and m as month", "1w2m", 7*24*time.Hour + 2*30*24*time.Hour},
+		{"day and M as month", "1d2M", 24*time.Hour + 2*30*24*time.Hour},
 	}
 
 	for _, tc := range testCases {
@@ -223,3 +236,73 @@ func TestParseDurationString_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestSetTimezone(t *testing.T) {
+	original := time.Local
+	t.Cleanup(func() { time.Local = original })
+
+	location, err := SetTimezone("Asia/Seoul")
+	if err != nil {
+		t.Fatalf("SetTimezone failed: %v", err)
+	}
+	if time.Local != location || location.String() != "Asia/Seoul" {
+		t.Fatalf("Expected time.Local to be Asia/Seoul, got %s", time.Local)
+	}
+
+	// Parsing and formatting now round-trip through KST (UTC+9, no DST)
+	parsed, err := ParseDateTime("2025-08-22 09:00:00")
+	if err != nil {
+		t.Fatalf("ParseDateTime failed: %v", err)
+	}
+	if utc := parsed.UTC().Format(time.RFC3339); utc != "2025-08-22T00:00:00Z" {
+		t.Errorf("Expected 2025-08-22T00:00:00Z, got %s", utc)
+	}
+	if formatted := FormatDateTime(parsed.UTC()); formatted != "2025-08-22 09:00:00" {
+		t.Errorf("Expected 2025-08-22 09:00:00, got %s", formatted)
+	}
+
+	if _, err := SetTimezone("Not/AZone"); err == nil {
+		t.Error("Expected an error for an unknown timezone")
+	}
+	if time.Local != location {
+		t.Error("Expected an unknown timezone to leave time.Local unchanged")
+	}
+}
+
+func TestSetUTC(t *testing.T) {
+	original := time.Local
+	t.Cleanup(func() {
+		time.Local = original
+		SetUTC(false)
+	})
+
+	if _, err := SetTimezone("Asia/Seoul"); err != nil {
+		t.Fatalf("SetTimezone failed: %v", err)
+	}
+	instant := time.Date(2025, 8, 22, 0, 0, 0, 0, time.UTC)
+
+	if formatted := FormatDateTime(instant); formatted != "2025-08-22 09:00:00" {
+		t.Errorf("Expected local 2025-08-22 09:00:00, got %s", formatted)
+	}
+
+	SetUTC(true)
+	if DisplayLocation() != time.UTC {
+		t.Errorf("Expected UTC display location, got %s", DisplayLocation())
+	}
+	if formatted := FormatDateTime(instant); formatted != "2025-08-22 00:00:00" {
+		t.Errorf("Expected UTC 2025-08-22 00:00:00, got %s", formatted)
+	}
+
+	// Bare dates are parsed in UTC too, so they round-trip with the output
+	parsed, err := ParseDateTime("2025-08-22 00:00:00")
+	if err != nil {
+		t.Fatalf("ParseDateTime failed: %v", err)
+	}
+	if !parsed.Equal(instant) {
+		t.Errorf("Expected %s, got %s", instant, parsed)
+	}
+
+	if formatted := FormatDateTimeIn(instant, time.Local); formatted != "2025-08-22 09:00:00" {
+		t.Errorf("Expected FormatDateTimeIn to ignore the UTC setting, got %s", formatted)
+	}
+}
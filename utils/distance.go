@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"strings"
+)
+
+// LevenshteinDistance returns the number of single-character insertions, deletions
+// and substitutions needed to turn a into b (case-insensitive)
+func LevenshteinDistance(a, b string) int {
+	ra := []rune(strings.ToLower(a))
+	rb := []rune(strings.ToLower(b))
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// ClosestMatch returns the candidate with the smallest edit distance to value, if it is
+// at most maxDistance. Ties go to the earlier candidate.
+func ClosestMatch(value string, candidates []string, maxDistance int) (string, bool) {
+	best := ""
+	bestDistance := maxDistance + 1
+	for _, candidate := range candidates {
+		if d := LevenshteinDistance(value, candidate); d < bestDistance {
+			best = candidate
+			bestDistance = d
+		}
+	}
+	return best, best != "" && bestDistance <= maxDistance
+}
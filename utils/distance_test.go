@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestLevenshteinDistance(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"example.com", "example.com", 0},
+		{"example.com", "EXAMPLE.com", 0},
+		{"exmaple.com", "example.com", 2},
+		{"example.co", "example.com", 1},
+		{"example.org", "example.com", 3},
+		{"", "abc", 3},
+		{"www.example.com", "example.com", 4},
+	}
+
+	for _, tc := range testCases {
+		if d := LevenshteinDistance(tc.a, tc.b); d != tc.expected {
+			t.Errorf("LevenshteinDistance(%q, %q) = %d, expected %d", tc.a, tc.b, d, tc.expected)
+		}
+	}
+}
+
+func TestClosestMatch(t *testing.T) {
+	domains := []string{"example.com", "example.org", "test.com"}
+
+	if match, ok := ClosestMatch("exmaple.com", domains, 3); !ok || match != "example.com" {
+		t.Errorf("Expected example.com, got %q (ok=%v)", match, ok)
+	}
+	if match, ok := ClosestMatch("tset.com", domains, 3); !ok || match != "test.com" {
+		t.Errorf("Expected test.com, got %q (ok=%v)", match, ok)
+	}
+	if match, ok := ClosestMatch("unrelated.net", domains, 3); ok {
+		t.Errorf("Expected no match, got %q", match)
+	}
+	if _, ok := ClosestMatch("example.com", nil, 3); ok {
+		t.Error("Expected no match without candidates")
+	}
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ParseEnvFile parses KEY=VALUE lines in .env format.
+// Blank lines and lines starting with '#' are ignored, an optional "export " prefix is
+// accepted, double-quoted values support \n, \t, \" and \\ escapes, single-quoted values
+// are taken literally, and unquoted values end at an inline " #" comment.
+func ParseEnvFile(r io.Reader) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		key, rawValue, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("invalid line %d: expected KEY=VALUE", lineNumber)
+		}
+
+		value, err := parseEnvValue(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s on line %d: %w", key, lineNumber, err)
+		}
+
+		values[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	return values, nil
+}
+
+// parseEnvValue unquotes a single .env value
+func parseEnvValue(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	switch raw[0] {
+	case '\'':
+		end := strings.IndexByte(raw[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated single quote")
+		}
+		return raw[1 : end+1], nil
+	case '"':
+		var value strings.Builder
+		for i := 1; i < len(raw); i++ {
+			switch c := raw[i]; c {
+			case '"':
+				return value.String(), nil
+			case '\\':
+				if i+1 >= len(raw) {
+					return "", fmt.Errorf("unterminated double quote")
+				}
+				i++
+				switch raw[i] {
+				case 'n':
+					value.WriteByte('\n')
+				case 't':
+					value.WriteByte('\t')
+				default:
+					value.WriteByte(raw[i])
+				}
+			default:
+				value.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("unterminated double quote")
+	default:
+		if idx := strings.Index(raw, " #"); idx >= 0 {
+			raw = raw[:idx]
+		}
+		return strings.TrimSpace(raw), nil
+	}
+}
+
+// LoadEnvFile loads the variables of a .env file into the process environment.
+// Variables that are already set are not overwritten.
+func LoadEnvFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open env file: %w", err)
+	}
+	defer file.Close()
+
+	values, err := ParseEnvFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to parse env file %s: %w", path, err)
+	}
+
+	for key, value := range values {
+		if _, exists := os.LookupEnv(key); exists {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	content := `# Porkbun credentials
+PORKBUN_API_KEY=pk1_abc
+export PORKBUN_SECRET_KEY="sk1 with spaces"
+
+JWT_SECRET_KEY='literal $value \n'
+ESCAPED="line1\nline2 \"quoted\""
+INLINE=value # trailing comment
+HASH="keep # inside quotes"
+EMPTY=
+`
+
+	values, err := ParseEnvFile(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Failed to parse env file: %v", err)
+	}
+
+	expected := map[string]string{
+		"PORKBUN_API_KEY":    "pk1_abc",
+		"PORKBUN_SECRET_KEY": "sk1 with spaces",
+		"JWT_SECRET_KEY":     `literal $value \n`,
+		"ESCAPED":            "line1\nline2 \"quoted\"",
+		"INLINE":             "value",
+		"HASH":               "keep # inside quotes",
+		"EMPTY":              "",
+	}
+
+	if len(values) != len(expected) {
+		t.Errorf("Expected %d values, got %d: %v", len(expected), len(values), values)
+	}
+
+	for key, want := range expected {
+		if got, exists := values[key]; !exists || got != want {
+			t.Errorf("Expected %s=%q, got %q (exists: %v)", key, want, got, exists)
+		}
+	}
+}
+
+func TestParseEnvFile_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"missing equals", "JUST_A_KEY"},
+		{"empty key", "=value"},
+		{"unterminated double quote", `KEY="value`},
+		{"unterminated single quote", `KEY='value`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseEnvFile(strings.NewReader(tc.content)); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoadEnvFile_DoesNotOverrideExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "ENVFILE_TEST_EXISTING=from-file\nENVFILE_TEST_NEW=from-file\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write env file: %v", err)
+	}
+
+	t.Setenv("ENVFILE_TEST_EXISTING", "from-env")
+	t.Setenv("ENVFILE_TEST_NEW", "")
+	os.Unsetenv("ENVFILE_TEST_NEW")
+
+	if err := LoadEnvFile(path); err != nil {
+		t.Fatalf("Failed to load env file: %v", err)
+	}
+
+	if got := os.Getenv("ENVFILE_TEST_EXISTING"); got != "from-env" {
+		t.Errorf("Existing variable should win, got %q", got)
+	}
+
+	if got := os.Getenv("ENVFILE_TEST_NEW"); got != "from-file" {
+		t.Errorf("Unset variable should be loaded from file, got %q", got)
+	}
+}
+
+func TestLoadEnvFile_MissingFile(t *testing.T) {
+	if err := LoadEnvFile(filepath.Join(t.TempDir(), "missing.env")); err == nil {
+		t.Error("Expected error for missing env file, got nil")
+	}
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// MatchGlob reports whether value matches the shell-style glob pattern (case-insensitive).
+// Supports '*', '?' and character classes, e.g. "*.example.com" or "api.*".
+func MatchGlob(pattern, value string) (bool, error) {
+	matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(value))
+	if err != nil {
+		return false, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+	}
+	return matched, nil
+}
+
+// FilterGlob returns the values matching the glob pattern, preserving order
+func FilterGlob(values []string, pattern string) ([]string, error) {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		matched, err := MatchGlob(pattern, value)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			result = append(result, value)
+		}
+	}
+	return result, nil
+}
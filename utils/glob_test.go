@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestFilterGlob(t *testing.T) {
+	domains := []string{"api.example.com", "example.com", "test.com", "www.example.com", "api.test.com"}
+
+	testCases := []struct {
+		name     string
+		pattern  string
+		expected []string
+	}{
+		{"literal", "example.com", []string{"example.com"}},
+		{"literal case-insensitive", "EXAMPLE.com", []string{"example.com"}},
+		{"prefix wildcard", "*.example.com", []string{"api.example.com", "www.example.com"}},
+		{"suffix wildcard", "api.*", []string{"api.example.com", "api.test.com"}},
+		{"match all", "*", domains},
+		{"no match", "*.org", []string{}},
+		{"single character", "test.co?", []string{"test.com"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := FilterGlob(domains, tc.pattern)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if len(result) != len(tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, result)
+			}
+
+			for i := range result {
+				if result[i] != tc.expected[i] {
+					t.Errorf("Expected %s at index %d, got %s", tc.expected[i], i, result[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFilterGlob_InvalidPattern(t *testing.T) {
+	if _, err := FilterGlob([]string{"example.com"}, "[example.com"); err == nil {
+		t.Error("Expected error for invalid pattern, got nil")
+	}
+}
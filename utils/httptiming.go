@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"context"
+	"time"
+)
+
+// HTTPTimingFunc receives the outcome and duration of an outgoing HTTP request.
+// status is 0 when the request failed before a response arrived.
+type HTTPTimingFunc func(method, url string, status int, elapsed time.Duration)
+
+type httpTimingKey struct{}
+
+// WithHTTPTiming returns a context whose outgoing provider API requests are reported to fn
+func WithHTTPTiming(ctx context.Context, fn HTTPTimingFunc) context.Context {
+	return context.WithValue(ctx, httpTimingKey{}, fn)
+}
+
+// ReportHTTPTiming passes a finished request to the HTTPTimingFunc of ctx, if any
+func ReportHTTPTiming(ctx context.Context, method, url string, status int, elapsed time.Duration) {
+	if fn, ok := ctx.Value(httpTimingKey{}).(HTTPTimingFunc); ok && fn != nil {
+		fn(method, url, status, elapsed)
+	}
+}
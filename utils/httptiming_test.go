@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReportHTTPTiming(t *testing.T) {
+	// Without a hook, reporting is a no-op
+	ReportHTTPTiming(context.Background(), "POST", "https://example.com", 200, time.Second)
+
+	var gotMethod, gotURL string
+	var gotStatus int
+	ctx := WithHTTPTiming(context.Background(), func(method, url string, status int, elapsed time.Duration) {
+		gotMethod, gotURL, gotStatus = method, url, status
+	})
+
+	ReportHTTPTiming(ctx, "POST", "https://example.com/ping", 200, 10*time.Millisecond)
+	if gotMethod != "POST" || gotURL != "https://example.com/ping" || gotStatus != 200 {
+		t.Errorf("Expected POST https://example.com/ping 200, got %s %s %d", gotMethod, gotURL, gotStatus)
+	}
+}
@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+const (
+	// KeyFormatPKCS1 is the traditional per-algorithm encoding:
+	// "RSA PRIVATE KEY" (PKCS#1) for RSA and "EC PRIVATE KEY" (SEC 1) for ECDSA
+	KeyFormatPKCS1 = "pkcs1"
+	// KeyFormatPKCS8 is the algorithm-neutral "PRIVATE KEY" encoding
+	KeyFormatPKCS8 = "pkcs8"
+)
+
+const (
+	// KeyTypeRSA labels RSA private keys
+	KeyTypeRSA = "rsa"
+	// KeyTypeEC labels ECDSA private keys
+	KeyTypeEC = "ec"
+	// KeyTypeEd25519 labels Ed25519 private keys
+	KeyTypeEd25519 = "ed25519"
+)
+
+// KeyInfo describes the algorithm and strength of a private key
+type KeyInfo struct {
+	Type  string // KeyTypeRSA, KeyTypeEC or KeyTypeEd25519
+	Bits  int    // Modulus size for RSA, curve size for EC
+	Curve string // Curve name for EC, e.g. "P-256"
+}
+
+// String returns a label such as "RSA 2048-bit" or "EC P-256"
+func (k KeyInfo) String() string {
+	switch k.Type {
+	case KeyTypeRSA:
+		return fmt.Sprintf("RSA %d-bit", k.Bits)
+	case KeyTypeEC:
+		return "EC " + k.Curve
+	case KeyTypeEd25519:
+		return "Ed25519"
+	default:
+		return k.Type
+	}
+}
+
+// DescribePrivateKey parses a PEM private key and reports its type and size
+func DescribePrivateKey(pemData []byte) (*KeyInfo, error) {
+	key, err := ParsePrivateKey(pemData)
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &KeyInfo{Type: KeyTypeRSA, Bits: k.N.BitLen()}, nil
+	case *ecdsa.PrivateKey:
+		params := k.Curve.Params()
+		return &KeyInfo{Type: KeyTypeEC, Bits: params.BitSize, Curve: params.Name}, nil
+	case ed25519.PrivateKey:
+		return &KeyInfo{Type: KeyTypeEd25519, Bits: 256}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// ParsePrivateKey parses the first private key block of PEM data, accepting
+// PKCS#1, SEC 1 (EC) and PKCS#8 encodings
+func ParsePrivateKey(pemData []byte) (interface{}, error) {
+	rest := pemData
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM private key found")
+		}
+
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse PKCS#1 private key: %w", err)
+			}
+			return key, nil
+		case "EC PRIVATE KEY":
+			key, err := x509.ParseECPrivateKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse EC private key: %w", err)
+			}
+			return key, nil
+		case "PRIVATE KEY":
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse PKCS#8 private key: %w", err)
+			}
+			return key, nil
+		}
+	}
+}
+
+// PrivateKeyMatchesCertificate checks that the first private key of PEM data belongs to the
+// certificate, i.e. that its public key is the certificate's
+func PrivateKeyMatchesCertificate(pemData []byte, cert *x509.Certificate) error {
+	key, err := ParsePrivateKey(pemData)
+	if err != nil {
+		return err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("unsupported private key type %T", key)
+	}
+	public, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !public.Equal(cert.PublicKey) {
+		return fmt.Errorf("private key does not match the certificate for %s", cert.Subject.CommonName)
+	}
+	return nil
+}
+
+// PrivateKeyDER returns the DER encoding of the first private key block of PEM data,
+// in the encoding of that block (PKCS#1, SEC 1 or PKCS#8)
+func PrivateKeyDER(pemData []byte) ([]byte, error) {
+	rest := pemData
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM private key found")
+		}
+		if strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			return block.Bytes, nil
+		}
+	}
+}
+
+// ConvertPrivateKey re-encodes a PEM private key into the given format.
+// An empty format returns the key unchanged.
+func ConvertPrivateKey(pemData []byte, format string) ([]byte, error) {
+	if format == "" {
+		return pemData, nil
+	}
+	if format != KeyFormatPKCS1 && format != KeyFormatPKCS8 {
+		return nil, fmt.Errorf("unsupported key format %q (supported: %s, %s)", format, KeyFormatPKCS1, KeyFormatPKCS8)
+	}
+
+	key, err := ParsePrivateKey(pemData)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert private key to %s: %w", format, err)
+	}
+
+	var block *pem.Block
+	if format == KeyFormatPKCS8 {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode PKCS#8 private key: %w", err)
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	} else {
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}
+		case *ecdsa.PrivateKey:
+			der, err := x509.MarshalECPrivateKey(k)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode EC private key: %w", err)
+			}
+			block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+		default:
+			return nil, fmt.Errorf("%T keys have no %s encoding; use %s", key, KeyFormatPKCS1, KeyFormatPKCS8)
+		}
+	}
+
+	return pem.EncodeToMemory(block), nil
+}
@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+func TestConvertPrivateKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate EC key: %v", err)
+	}
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal EC key: %v", err)
+	}
+	rsaPKCS8, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal RSA key: %v", err)
+	}
+
+	rsaPKCS1PEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+	rsaPKCS8PEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: rsaPKCS8})
+	ecSEC1PEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER})
+
+	testCases := []struct {
+		name     string
+		input    []byte
+		format   string
+		expected string
+	}{
+		{"RSA PKCS#1 to PKCS#8", rsaPKCS1PEM, KeyFormatPKCS8, "PRIVATE KEY"},
+		{"RSA PKCS#8 to PKCS#1", rsaPKCS8PEM, KeyFormatPKCS1, "RSA PRIVATE KEY"},
+		{"EC SEC 1 to PKCS#8", ecSEC1PEM, KeyFormatPKCS8, "PRIVATE KEY"},
+		{"EC SEC 1 to traditional", ecSEC1PEM, KeyFormatPKCS1, "EC PRIVATE KEY"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := ConvertPrivateKey(tc.input, tc.format)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			block, _ := pem.Decode(output)
+			if block == nil || block.Type != tc.expected {
+				t.Fatalf("Expected %s block, got %v", tc.expected, block)
+			}
+
+			if _, err := ParsePrivateKey(output); err != nil {
+				t.Errorf("Converted key should parse: %v", err)
+			}
+		})
+	}
+}
+
+func TestPrivateKeyDER(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate EC key: %v", err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal EC key: %v", err)
+	}
+
+	der, err := PrivateKeyDER(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))
+	if err != nil {
+		t.Fatalf("PrivateKeyDER failed: %v", err)
+	}
+	if _, err := x509.ParsePKCS8PrivateKey(der); err != nil {
+		t.Errorf("DER key does not parse as PKCS#8: %v", err)
+	}
+
+	if _, err := PrivateKeyDER([]byte("not pem")); err == nil {
+		t.Error("Expected an error for data without a private key")
+	}
+}
+
+func TestConvertPrivateKeyPassthrough(t *testing.T) {
+	input := []byte("not even a key")
+
+	output, err := ConvertPrivateKey(input, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if string(output) != string(input) {
+		t.Errorf("Expected key to be passed through unchanged, got %q", output)
+	}
+}
+
+func TestConvertPrivateKeyErrors(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal Ed25519 key: %v", err)
+	}
+	edPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER})
+
+	testCases := []struct {
+		name   string
+		input  []byte
+		format string
+	}{
+		{"unknown format", edPEM, "der"},
+		{"not a key", []byte("garbage"), KeyFormatPKCS8},
+		{"corrupt key", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("junk")}), KeyFormatPKCS8},
+		{"Ed25519 has no PKCS#1 form", edPEM, KeyFormatPKCS1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ConvertPrivateKey(tc.input, tc.format); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestDescribePrivateKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate EC key: %v", err)
+	}
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal EC key: %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+	edPKCS8, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal Ed25519 key: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		input    []byte
+		keyType  string
+		expected string
+	}{
+		{"RSA", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}), KeyTypeRSA, "RSA 2048-bit"},
+		{"EC", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER}), KeyTypeEC, "EC P-384"},
+		{"Ed25519", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edPKCS8}), KeyTypeEd25519, "Ed25519"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			info, err := DescribePrivateKey(tc.input)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if info.Type != tc.keyType {
+				t.Errorf("Expected type %s, got %s", tc.keyType, info.Type)
+			}
+			if info.String() != tc.expected {
+				t.Errorf("Expected label %q, got %q", tc.expected, info.String())
+			}
+		})
+	}
+
+	if _, err := DescribePrivateKey([]byte("not a key")); err == nil {
+		t.Error("Expected error for invalid PEM")
+	}
+}
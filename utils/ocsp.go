@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// ErrNoOCSPServer is returned when a certificate does not list an OCSP responder
+var ErrNoOCSPServer = errors.New("certificate does not list an OCSP responder")
+
+// maxOCSPResponseSize bounds the OCSP response read from a responder
+const maxOCSPResponseSize = 1 << 20
+
+// OCSPResult is the revocation status reported by an OCSP responder
+type OCSPResult struct {
+	Status     string // "good", "revoked" or "unknown"
+	Responder  string
+	ThisUpdate time.Time
+	NextUpdate time.Time // Zero if the responder did not set one
+	RevokedAt  time.Time // Only set for revoked certificates
+}
+
+// LeafAndIssuer parses the leaf of a PEM chain and the certificate in the chain that signed it
+func LeafAndIssuer(pemData []byte) (leaf, issuer *x509.Certificate, err error) {
+	var certs []*x509.Certificate
+	rest := pemData
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, nil, fmt.Errorf("no PEM certificate found")
+	}
+
+	leaf = certs[0]
+	for _, candidate := range certs[1:] {
+		if issuedBy(leaf, candidate) {
+			return leaf, candidate, nil
+		}
+	}
+	return leaf, nil, fmt.Errorf("issuer of %q is not in the chain", leaf.Subject.CommonName)
+}
+
+// CheckOCSP asks the first OCSP responder listed in leaf for its revocation status.
+// It returns ErrNoOCSPServer if the certificate lists none.
+func CheckOCSP(ctx context.Context, client *http.Client, leaf, issuer *x509.Certificate) (*OCSPResult, error) {
+	if len(leaf.OCSPServer) == 0 {
+		return nil, ErrNoOCSPServer
+	}
+	responder := leaf.OCSPServer[0]
+
+	request, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OCSP request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, responder, bytes.NewReader(request))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCSP responder URL %q: %w", responder, err)
+	}
+	httpReq.Header.Set("Content-Type", "application/ocsp-request")
+	httpReq.Header.Set("Accept", "application/ocsp-response")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("OCSP request to %s failed: %w", responder, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OCSP responder %s returned HTTP %d", responder, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOCSPResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OCSP response: %w", err)
+	}
+
+	parsed, err := ocsp.ParseResponseForCert(body, leaf, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCSP response from %s: %w", responder, err)
+	}
+
+	result := &OCSPResult{
+		Responder:  responder,
+		ThisUpdate: parsed.ThisUpdate,
+		NextUpdate: parsed.NextUpdate,
+	}
+	switch parsed.Status {
+	case ocsp.Good:
+		result.Status = "good"
+	case ocsp.Revoked:
+		result.Status = "revoked"
+		result.RevokedAt = parsed.RevokedAt
+	default:
+		result.Status = "unknown"
+	}
+
+	return result, nil
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+func newTestOCSPResponder(t *testing.T, issuer *testCA, status int) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Failed to read OCSP request: %v", err)
+			return
+		}
+		req, err := ocsp.ParseRequest(body)
+		if err != nil {
+			t.Errorf("Failed to parse OCSP request: %v", err)
+			return
+		}
+
+		now := time.Now().Truncate(time.Minute)
+		template := ocsp.Response{
+			Status:       status,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   now,
+			NextUpdate:   now.Add(24 * time.Hour),
+		}
+		if status == ocsp.Revoked {
+			template.RevokedAt = now.Add(-time.Hour)
+		}
+
+		response, err := ocsp.CreateResponse(issuer.cert, issuer.cert, template, issuer.key)
+		if err != nil {
+			t.Errorf("Failed to create OCSP response: %v", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		w.Write(response)
+	}))
+}
+
+func TestCheckOCSP(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   int
+		expected string
+	}{
+		{"good", ocsp.Good, "good"},
+		{"revoked", ocsp.Revoked, "revoked"},
+		{"unknown", ocsp.Unknown, "unknown"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := issueTestCert(t, "Root CA", true, nil)
+			leaf := issueTestCert(t, "example.com", false, root)
+
+			server := newTestOCSPResponder(t, root, tc.status)
+			defer server.Close()
+			leaf.cert.OCSPServer = []string{server.URL}
+
+			result, err := CheckOCSP(context.Background(), server.Client(), leaf.cert, root.cert)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if result.Status != tc.expected {
+				t.Errorf("Expected status %s, got %s", tc.expected, result.Status)
+			}
+			if result.NextUpdate.IsZero() {
+				t.Error("Expected next update time to be set")
+			}
+			if tc.status == ocsp.Revoked && result.RevokedAt.IsZero() {
+				t.Error("Expected revocation time to be set")
+			}
+		})
+	}
+}
+
+func TestCheckOCSPNoResponder(t *testing.T) {
+	root := issueTestCert(t, "Root CA", true, nil)
+	leaf := issueTestCert(t, "example.com", false, root)
+
+	_, err := CheckOCSP(context.Background(), http.DefaultClient, leaf.cert, root.cert)
+	if !errors.Is(err, ErrNoOCSPServer) {
+		t.Errorf("Expected ErrNoOCSPServer, got %v", err)
+	}
+}
+
+func TestLeafAndIssuer(t *testing.T) {
+	root := issueTestCert(t, "Root CA", true, nil)
+	intermediate := issueTestCert(t, "Intermediate CA", true, root)
+	leaf := issueTestCert(t, "example.com", false, intermediate)
+
+	gotLeaf, gotIssuer, err := LeafAndIssuer(encodeTestChain(leaf, root, intermediate))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if gotLeaf.Subject.CommonName != "example.com" || gotIssuer.Subject.CommonName != "Intermediate CA" {
+		t.Errorf("Expected example.com issued by Intermediate CA, got %s issued by %s",
+			gotLeaf.Subject.CommonName, gotIssuer.Subject.CommonName)
+	}
+
+	if _, _, err := LeafAndIssuer(encodeTestChain(leaf)); err == nil {
+		t.Error("Expected error when the issuer is missing from the chain")
+	}
+}